@@ -0,0 +1,268 @@
+// Package account exposes every Bunny.net service from one object that
+// shares HTTP, logging, tracing, rate limiting and user-agent settings.
+//
+//	creds, err := account.LoadCredentials("")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	acct := account.New(creds, account.WithLogger(slog.Default()))
+//	zones, err := acct.Storage().Zones().List(ctx, nil)
+//	files, err := acct.Files("assets")
+package account
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/geraldo/bunny-sdk-go"
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/credential"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+// Endpoints overrides the API base URLs, for example to point an Account at
+// a bunnytest server. Empty fields keep the defaults.
+type Endpoints struct {
+	API        string // account API, default https://api.bunny.net
+	Video      string // Stream video API, default https://video.bunnycdn.com
+	Storage    string // file API for every zone, default depends on the zone region
+	Containers string // Magic Containers API, default https://api.bunny.net/mc
+}
+
+// Account is a facade over all service clients for one account.
+type Account struct {
+	creds Credentials
+	cfg   config
+
+	core       *bunny.Client
+	storage    *storage.Client
+	stream     *stream.Client
+	shield     *shield.Client
+	scripting  *scripting.Client
+	containers *containers.Client
+	resolver   *Resolver
+
+	mu        sync.Mutex
+	files     map[string]storage.FileService
+	libraries map[int64]*Library
+}
+
+type config struct {
+	httpClient  middleware.Doer
+	userAgent   string
+	endpoints   Endpoints
+	middlewares []middleware.Middleware
+	logger      *slog.Logger
+	logOptions  []middleware.LogOption
+	tracer      middleware.Tracer
+	metrics     middleware.Metrics
+	limiter     *middleware.RateLimiter
+}
+
+// Option configures an Account.
+type Option func(*config)
+
+// WithHTTPClient sets the HTTP client shared by all services.
+func WithHTTPClient(hc middleware.Doer) Option {
+	return func(c *config) {
+		c.httpClient = hc
+	}
+}
+
+// WithUserAgent sets the user agent sent by all services.
+func WithUserAgent(ua string) Option {
+	return func(c *config) {
+		c.userAgent = ua
+	}
+}
+
+// WithEndpoints overrides the API base URLs.
+func WithEndpoints(e Endpoints) Option {
+	return func(c *config) {
+		c.endpoints = e
+	}
+}
+
+// WithMiddleware adds middlewares to every service. The first one added is
+// the outermost.
+func WithMiddleware(mws ...middleware.Middleware) Option {
+	return func(c *config) {
+		c.middlewares = append(c.middlewares, mws...)
+	}
+}
+
+// WithLogger logs every request made by any service to l.
+func WithLogger(l *slog.Logger, opts ...middleware.LogOption) Option {
+	return func(c *config) {
+		c.logger = l
+		c.logOptions = opts
+	}
+}
+
+// WithTracer starts a span for every operation of any service.
+func WithTracer(t middleware.Tracer) Option {
+	return func(c *config) {
+		c.tracer = t
+	}
+}
+
+// WithMetrics records metrics for every request of any service.
+func WithMetrics(m middleware.Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
+
+// WithRateLimiter throttles all services with one shared limiter and
+// retries 429 responses.
+func WithRateLimiter(l *middleware.RateLimiter) Option {
+	return func(c *config) {
+		c.limiter = l
+	}
+}
+
+// New returns an Account for creds.
+func New(creds *Credentials, opts ...Option) *Account {
+	a := &Account{
+		cfg:       config{httpClient: http.DefaultClient},
+		files:     make(map[string]storage.FileService),
+		libraries: make(map[int64]*Library),
+	}
+	if creds != nil {
+		a.creds = *creds
+	}
+	for _, opt := range opts {
+		opt(&a.cfg)
+	}
+
+	a.core = bunny.NewClient(a.creds.APIKey, a.coreOptions()...)
+	a.storage = storage.NewClient(a.creds.APIKey, a.storageOptions()...)
+	a.stream = stream.NewClient(a.creds.APIKey, a.streamOptions()...)
+	a.shield = shield.NewClient(a.creds.APIKey, a.shieldOptions()...)
+	a.scripting = scripting.NewClient(a.creds.APIKey, a.scriptingOptions()...)
+	a.containers = containers.NewClient(a.creds.APIKey, a.containersOptions()...)
+	a.resolver = NewResolver(a.storage.Zones(), a.stream.Libraries())
+	return a
+}
+
+// NewFromProfile loads credentials with LoadCredentials and returns an
+// Account for them.
+func NewFromProfile(profile string, opts ...Option) (*Account, error) {
+	creds, err := LoadCredentials(profile)
+	if err != nil {
+		return nil, err
+	}
+	if creds.APIKey == "" {
+		return nil, fmt.Errorf("%w: set BUNNY_API_KEY or apiKey in the profile", ErrNoCredentials)
+	}
+	return New(creds, opts...), nil
+}
+
+// Core returns the root package client.
+func (a *Account) Core() *bunny.Client { return a.core }
+
+// Storage returns the storage zone management client.
+func (a *Account) Storage() *storage.Client { return a.storage }
+
+// Stream returns the Stream client authenticated with the account API key,
+// for library management.
+func (a *Account) Stream() *stream.Client { return a.stream }
+
+// Shield returns the Shield/WAF client.
+func (a *Account) Shield() *shield.Client { return a.shield }
+
+// Scripting returns the Edge Scripting client.
+func (a *Account) Scripting() *scripting.Client { return a.scripting }
+
+// Containers returns the Magic Containers client.
+func (a *Account) Containers() *containers.Client { return a.containers }
+
+// Resolver returns the resolver that looks up credentials which were not
+// configured.
+func (a *Account) Resolver() *Resolver { return a.resolver }
+
+// Files returns the file service for a storage zone. It uses the configured
+// password and region, or looks them up with the account API key on first
+// use when none were configured.
+func (a *Account) Files(zone string) (storage.FileService, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if fs, ok := a.files[zone]; ok {
+		return fs, nil
+	}
+	creds, ok := a.creds.StorageZones[zone]
+	if !ok || creds.Password == "" {
+		fs := &resolvedFiles{resolver: a.resolver, zone: zone, options: a.fileOptions()}
+		a.files[zone] = fs
+		return fs, nil
+	}
+	region := creds.Region
+	if region == "" {
+		region = storage.RegionFalkenstein
+	}
+	fs := storage.NewFileService(zone, creds.Password, region, a.fileOptions()...)
+	a.files[zone] = fs
+	return fs, nil
+}
+
+// Library is the part of the Stream API that is authenticated with the API
+// key of one video library: its videos and collections.
+type Library struct {
+	id     int64
+	client *stream.Client
+}
+
+// ID returns the video library ID.
+func (l *Library) ID() int64 { return l.id }
+
+// Videos returns the video service of the library.
+func (l *Library) Videos() stream.VideoService { return l.client.Videos(l.id) }
+
+// Collections returns the collection service of the library.
+func (l *Library) Collections() stream.CollectionService { return l.client.Collections(l.id) }
+
+// Library returns the videos and collections of a video library. The
+// library API key is looked up with the account API key on first use when
+// none was configured, and again when the API rejects it.
+func (a *Account) Library(libraryID int64) (*Library, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if l, ok := a.libraries[libraryID]; ok {
+		return l, nil
+	}
+	var c *stream.Client
+	if key := a.creds.StreamLibraries[libraryID]; key != "" {
+		c = stream.NewClient(key, a.streamOptions()...)
+	} else {
+		key := a.resolver.librarySecret(libraryID)
+		opts := append(a.streamOptions(), stream.WithMiddleware(credential.Header("AccessKey", key)))
+		c = stream.NewClient("", opts...)
+	}
+	l := &Library{id: libraryID, client: c}
+	a.libraries[libraryID] = l
+	return l, nil
+}
+
+// Videos returns the video service of a library.
+func (a *Account) Videos(libraryID int64) (stream.VideoService, error) {
+	l, err := a.Library(libraryID)
+	if err != nil {
+		return nil, err
+	}
+	return l.Videos(), nil
+}
+
+// Collections returns the collection service of a library.
+func (a *Account) Collections(libraryID int64) (stream.CollectionService, error) {
+	l, err := a.Library(libraryID)
+	if err != nil {
+		return nil, err
+	}
+	return l.Collections(), nil
+}
@@ -0,0 +1,190 @@
+package account_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/account"
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+const configFile = `
+# comments and blank lines are ignored
+profiles:
+  default:
+    apiKey: default-key
+    storageZone: assets
+    storagePassword: zone-pw
+    storageRegion: NY
+    streamLibraryId: 42
+    streamApiKey: library-key
+    storageZones:
+      backups: {password: backups-pw, region: de}
+    streamLibraries:
+      43: {apiKey: other-library-key}
+  staging:
+    apiKey: staging-key
+`
+
+func TestParseCredentials(t *testing.T) {
+	creds, err := account.ParseCredentials([]byte(configFile), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.APIKey != "default-key" || creds.StorageZone != "assets" || creds.StreamLibraryID != 42 {
+		t.Errorf("creds = %+v", creds)
+	}
+	if z := creds.StorageZones["assets"]; z.Password != "zone-pw" || z.Region != storage.RegionNewYork {
+		t.Errorf("assets = %+v", z)
+	}
+	if z := creds.StorageZones["backups"]; z.Password != "backups-pw" || z.Region != storage.RegionFalkenstein {
+		t.Errorf("backups = %+v", z)
+	}
+	if creds.StreamLibraries[42] != "library-key" || creds.StreamLibraries[43] != "other-library-key" {
+		t.Errorf("libraries = %v", creds.StreamLibraries)
+	}
+
+	staging, err := account.ParseCredentials([]byte(configFile), "staging")
+	if err != nil || staging.APIKey != "staging-key" || len(staging.StorageZones) != 0 {
+		t.Errorf("staging = %+v, %v", staging, err)
+	}
+	if _, err := account.ParseCredentials([]byte(configFile), "prod"); err == nil {
+		t.Error("expected error for missing profile")
+	}
+	if _, err := account.ParseCredentials([]byte("profiles: [default"), "default"); err == nil {
+		t.Error("expected error for malformed YAML")
+	}
+}
+
+func TestLoadCredentialsEnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(configFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"BUNNY_CONFIG":            path,
+		"BUNNY_API_KEY":           "env-key",
+		"BUNNY_STORAGE_ZONE":      "backups",
+		"BUNNY_STORAGE_PASSWORD":  "env-pw",
+		"BUNNY_STORAGE_REGION":    "SG",
+		"BUNNY_STREAM_LIBRARY_ID": "7",
+		"BUNNY_STREAM_API_KEY":    "env-library-key",
+	}
+	getenv := account.WithGetenv(func(k string) string { return env[k] })
+
+	creds, err := account.LoadCredentials("", getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.APIKey != "env-key" || creds.StorageZone != "backups" || creds.StreamLibraryID != 7 {
+		t.Errorf("creds = %+v", creds)
+	}
+	if z := creds.StorageZones["backups"]; z.Password != "env-pw" || z.Region != storage.RegionSingapore {
+		t.Errorf("backups = %+v", z)
+	}
+	if z := creds.StorageZones["assets"]; z.Password != "zone-pw" {
+		t.Errorf("assets = %+v", z)
+	}
+	if creds.StreamLibraries[7] != "env-library-key" || creds.StreamLibraries[42] != "library-key" {
+		t.Errorf("libraries = %v", creds.StreamLibraries)
+	}
+
+	if _, err := account.LoadCredentials("prod", getenv); err == nil {
+		t.Error("expected error for missing profile")
+	}
+	missing := account.WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := account.LoadCredentials("", getenv, missing); err == nil {
+		t.Error("expected error for missing explicit config file")
+	}
+}
+
+func TestAccountSharesSettings(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	var agents []string
+	record := func(next middleware.RoundTripFunc) middleware.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			agents = append(agents, req.Header.Get("User-Agent"))
+			return next(req)
+		}
+	}
+	endpoints := account.Endpoints{
+		API:        srv.URL,
+		Video:      srv.StreamURL(),
+		Storage:    srv.StorageURL(),
+		Containers: srv.ContainersURL(),
+	}
+	opts := []account.Option{
+		account.WithHTTPClient(srv.Client()),
+		account.WithEndpoints(endpoints),
+		account.WithUserAgent("batch-job/2.0"),
+		account.WithMiddleware(record),
+	}
+
+	acct := account.New(&account.Credentials{APIKey: "key"}, opts...)
+	zone, err := acct.Storage().Zones().Create(ctx, &storage.CreateZoneRequest{Name: "assets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib, err := acct.Stream().Libraries().Create(ctx, &stream.CreateLibraryRequest{Name: "courses"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acct.Shield().Zones().List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acct.Scripting().Scripts().List(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acct.Containers().Applications().List(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	acct = account.New(&account.Credentials{
+		APIKey:          "key",
+		StorageZones:    map[string]account.StorageZoneCredentials{"assets": {Password: zone.Password}},
+		StreamLibraries: map[int64]string{lib.LibraryID: "library-key"},
+	}, opts...)
+	files, err := acct.Files("assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := files.Upload(ctx, "hello.txt", strings.NewReader("hi"), nil); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := files.Download(ctx, "hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(body) != "hi" {
+		t.Errorf("downloaded %q", body)
+	}
+	videos, err := acct.Videos(lib.LibraryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := videos.Create(ctx, &stream.CreateVideoRequest{Title: "Lesson"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(agents) != 8 {
+		t.Errorf("middleware saw %d requests, want 8", len(agents))
+	}
+	for _, ua := range agents {
+		if ua != "batch-job/2.0" {
+			t.Errorf("User-Agent = %q", ua)
+		}
+	}
+}
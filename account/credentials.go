@@ -0,0 +1,251 @@
+package account
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/internal/yaml"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+// ErrNoCredentials is returned when a service needs credentials that were
+// not configured.
+var ErrNoCredentials = errors.New("account: no credentials")
+
+// Credentials are the keys for one Bunny.net account.
+type Credentials struct {
+	// APIKey is the account API key used by every management API.
+	APIKey string
+	// StorageZone names the profile's default storage zone, if any.
+	StorageZone string
+	// StreamLibraryID is the profile's default stream library, if any.
+	StreamLibraryID int64
+	// StorageZones holds file API credentials keyed by storage zone name.
+	StorageZones map[string]StorageZoneCredentials
+	// StreamLibraries holds Stream API keys keyed by video library ID.
+	StreamLibraries map[int64]string
+}
+
+// StorageZoneCredentials are the file API credentials for one storage zone.
+type StorageZoneCredentials struct {
+	Password string
+	Region   storage.Region // defaults to storage.RegionFalkenstein
+}
+
+// CredentialsOption is a functional option for LoadCredentials.
+type CredentialsOption func(*credentialsConfig)
+
+type credentialsConfig struct {
+	path   string
+	getenv func(string) string
+}
+
+// WithConfigFile reads profiles from path instead of $BUNNY_CONFIG or
+// ~/.bunny/config.yaml. A named file must exist.
+func WithConfigFile(path string) CredentialsOption {
+	return func(c *credentialsConfig) {
+		c.path = path
+	}
+}
+
+// WithGetenv sets the function used to read environment variables.
+// Defaults to os.Getenv.
+func WithGetenv(getenv func(string) string) CredentialsOption {
+	return func(c *credentialsConfig) {
+		c.getenv = getenv
+	}
+}
+
+// LoadCredentials resolves credentials for profile from the config file and
+// the environment. An empty profile means $BUNNY_PROFILE, or "default".
+//
+// The config file is $BUNNY_CONFIG, or ~/.bunny/config.yaml, and may be
+// missing unless named explicitly. It is the file the bunny command reads;
+// each profile has a default storage zone and stream library and may list
+// more:
+//
+//	profiles:
+//	  default:
+//	    apiKey: ...
+//	    storageZone: assets
+//	    storagePassword: ...
+//	    storageRegion: ny
+//	    streamLibraryId: 12345
+//	    streamApiKey: ...
+//	    storageZones:
+//	      backups: {password: ..., region: de}
+//	    streamLibraries:
+//	      67890: {apiKey: ...}
+//
+// Environment variables take precedence over the file: BUNNY_API_KEY,
+// BUNNY_STORAGE_ZONE with BUNNY_STORAGE_PASSWORD and BUNNY_STORAGE_REGION,
+// and BUNNY_STREAM_LIBRARY_ID with BUNNY_STREAM_API_KEY. They select and
+// override the default zone and library.
+func LoadCredentials(profile string, opts ...CredentialsOption) (*Credentials, error) {
+	cfg := credentialsConfig{getenv: os.Getenv}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	getenv := cfg.getenv
+
+	explicit := profile != ""
+	if profile == "" {
+		profile = getenv("BUNNY_PROFILE")
+		explicit = profile != ""
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	path := cmp.Or(cfg.path, getenv("BUNNY_CONFIG"))
+	explicitPath := path != ""
+	if path == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".bunny", "config.yaml")
+		}
+	}
+
+	creds := &Credentials{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && !explicitPath:
+		case err != nil:
+			return nil, err
+		default:
+			parsed, found, err := parseCredentials(data, profile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if !found && explicit {
+				return nil, fmt.Errorf("%s: profile %q not found", path, profile)
+			}
+			creds = parsed
+		}
+	}
+	if err := creds.applyEnv(getenv); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// ParseCredentials reads the credentials for profile from the contents of a
+// config file. See LoadCredentials for the format.
+func ParseCredentials(data []byte, profile string) (*Credentials, error) {
+	creds, found, err := parseCredentials(data, profile)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("profile %q not found", profile)
+	}
+	return creds, nil
+}
+
+// profileFile is one profile of the config file.
+type profileFile struct {
+	APIKey          string                      `json:"apiKey"`
+	StorageZone     string                      `json:"storageZone"`
+	StoragePassword string                      `json:"storagePassword"`
+	StorageRegion   string                      `json:"storageRegion"`
+	StreamLibraryID int64                       `json:"streamLibraryId"`
+	StreamAPIKey    string                      `json:"streamApiKey"`
+	StorageZones    map[string]storageZoneFile  `json:"storageZones"`
+	StreamLibraries map[int64]streamLibraryFile `json:"streamLibraries"`
+}
+
+type storageZoneFile struct {
+	Password string `json:"password"`
+	Region   string `json:"region"`
+}
+
+type streamLibraryFile struct {
+	APIKey string `json:"apiKey"`
+}
+
+func parseCredentials(data []byte, profile string) (*Credentials, bool, error) {
+	var file struct {
+		Profiles map[string]profileFile `json:"profiles"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, false, err
+	}
+	p, found := file.Profiles[profile]
+	creds := &Credentials{APIKey: p.APIKey, StorageZone: p.StorageZone, StreamLibraryID: p.StreamLibraryID}
+	for name, z := range p.StorageZones {
+		creds.setStorageZone(name, StorageZoneCredentials{Password: z.Password, Region: storageRegion(z.Region)})
+	}
+	for id, l := range p.StreamLibraries {
+		creds.setStreamLibrary(id, l.APIKey)
+	}
+	if p.StorageZone != "" {
+		zone := creds.StorageZones[p.StorageZone]
+		zone.Password = cmp.Or(p.StoragePassword, zone.Password)
+		zone.Region = cmp.Or(storageRegion(p.StorageRegion), zone.Region)
+		creds.setStorageZone(p.StorageZone, zone)
+	}
+	if p.StreamLibraryID != 0 {
+		creds.setStreamLibrary(p.StreamLibraryID, cmp.Or(p.StreamAPIKey, creds.StreamLibraries[p.StreamLibraryID]))
+	}
+	return creds, found, nil
+}
+
+// applyEnv overrides the file with environment variables. A zone or library
+// named only in the environment starts from the credentials of the
+// profile's default one.
+func (c *Credentials) applyEnv(getenv func(string) string) error {
+	if v := getenv("BUNNY_API_KEY"); v != "" {
+		c.APIKey = v
+	}
+	if name := cmp.Or(getenv("BUNNY_STORAGE_ZONE"), c.StorageZone); name != "" {
+		zone, ok := c.StorageZones[name]
+		if !ok {
+			zone = c.StorageZones[c.StorageZone]
+		}
+		zone.Password = cmp.Or(getenv("BUNNY_STORAGE_PASSWORD"), zone.Password)
+		zone.Region = cmp.Or(storageRegion(getenv("BUNNY_STORAGE_REGION")), zone.Region)
+		c.StorageZone = name
+		c.setStorageZone(name, zone)
+	}
+	id := c.StreamLibraryID
+	if v := getenv("BUNNY_STREAM_LIBRARY_ID"); v != "" {
+		var err error
+		if id, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("BUNNY_STREAM_LIBRARY_ID: %w", err)
+		}
+	}
+	if id != 0 {
+		key, ok := c.StreamLibraries[id]
+		if !ok {
+			key = c.StreamLibraries[c.StreamLibraryID]
+		}
+		c.StreamLibraryID = id
+		c.setStreamLibrary(id, cmp.Or(getenv("BUNNY_STREAM_API_KEY"), key))
+	}
+	return nil
+}
+
+// storageRegion lowercases a region code the way the storage endpoints
+// expect it.
+func storageRegion(s string) storage.Region {
+	return storage.Region(strings.ToLower(s))
+}
+
+func (c *Credentials) setStorageZone(name string, zone StorageZoneCredentials) {
+	if c.StorageZones == nil {
+		c.StorageZones = make(map[string]StorageZoneCredentials)
+	}
+	c.StorageZones[name] = zone
+}
+
+func (c *Credentials) setStreamLibrary(id int64, key string) {
+	if c.StreamLibraries == nil {
+		c.StreamLibraries = make(map[int64]string)
+	}
+	c.StreamLibraries[id] = key
+}
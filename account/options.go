@@ -0,0 +1,188 @@
+package account
+
+import (
+	"github.com/geraldo/bunny-sdk-go"
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+// The builders below translate the shared config into each package's own
+// options. Options left at their zero value are not passed, so package
+// defaults apply.
+
+func (a *Account) coreOptions() []bunny.Option {
+	c := a.cfg
+	opts := []bunny.Option{bunny.WithHTTPClient(c.httpClient), bunny.WithMiddleware(c.middlewares...)}
+	if c.userAgent != "" {
+		opts = append(opts, bunny.WithUserAgent(c.userAgent))
+	}
+	if c.endpoints.API != "" {
+		opts = append(opts, bunny.WithStorageBaseURL(c.endpoints.API))
+	}
+	if c.endpoints.Video != "" {
+		opts = append(opts, bunny.WithStreamBaseURL(c.endpoints.Video))
+	}
+	if c.logger != nil {
+		opts = append(opts, bunny.WithLogger(c.logger, c.logOptions...))
+	}
+	if c.tracer != nil {
+		opts = append(opts, bunny.WithTracer(c.tracer))
+	}
+	if c.metrics != nil {
+		opts = append(opts, bunny.WithMetrics(c.metrics))
+	}
+	if c.limiter != nil {
+		opts = append(opts, bunny.WithRateLimiter(c.limiter))
+	}
+	return opts
+}
+
+func (a *Account) storageOptions() []storage.Option {
+	c := a.cfg
+	opts := []storage.Option{storage.WithHTTPClient(c.httpClient), storage.WithMiddleware(c.middlewares...)}
+	if c.userAgent != "" {
+		opts = append(opts, storage.WithUserAgent(c.userAgent))
+	}
+	if c.endpoints.API != "" {
+		opts = append(opts, storage.WithBaseURL(c.endpoints.API))
+	}
+	if c.logger != nil {
+		opts = append(opts, storage.WithLogger(c.logger, c.logOptions...))
+	}
+	if c.tracer != nil {
+		opts = append(opts, storage.WithTracer(c.tracer))
+	}
+	if c.metrics != nil {
+		opts = append(opts, storage.WithMetrics(c.metrics))
+	}
+	if c.limiter != nil {
+		opts = append(opts, storage.WithRateLimiter(c.limiter))
+	}
+	return opts
+}
+
+func (a *Account) fileOptions() []storage.FileServiceOption {
+	c := a.cfg
+	opts := []storage.FileServiceOption{storage.WithFileHTTPClient(c.httpClient), storage.WithFileMiddleware(c.middlewares...)}
+	if c.userAgent != "" {
+		opts = append(opts, storage.WithFileUserAgent(c.userAgent))
+	}
+	if c.endpoints.Storage != "" {
+		opts = append(opts, storage.WithFileBaseURL(c.endpoints.Storage))
+	}
+	if c.logger != nil {
+		opts = append(opts, storage.WithFileLogger(c.logger, c.logOptions...))
+	}
+	if c.tracer != nil {
+		opts = append(opts, storage.WithFileTracer(c.tracer))
+	}
+	if c.metrics != nil {
+		opts = append(opts, storage.WithFileMetrics(c.metrics))
+	}
+	if c.limiter != nil {
+		opts = append(opts, storage.WithFileRateLimiter(c.limiter))
+	}
+	return opts
+}
+
+func (a *Account) streamOptions() []stream.Option {
+	c := a.cfg
+	opts := []stream.Option{stream.WithHTTPClient(c.httpClient), stream.WithMiddleware(c.middlewares...)}
+	if c.userAgent != "" {
+		opts = append(opts, stream.WithUserAgent(c.userAgent))
+	}
+	if c.endpoints.API != "" {
+		opts = append(opts, stream.WithBaseAPIURL(c.endpoints.API))
+	}
+	if c.endpoints.Video != "" {
+		opts = append(opts, stream.WithStreamAPIURL(c.endpoints.Video))
+	}
+	if c.logger != nil {
+		opts = append(opts, stream.WithLogger(c.logger, c.logOptions...))
+	}
+	if c.tracer != nil {
+		opts = append(opts, stream.WithTracer(c.tracer))
+	}
+	if c.metrics != nil {
+		opts = append(opts, stream.WithMetrics(c.metrics))
+	}
+	if c.limiter != nil {
+		opts = append(opts, stream.WithRateLimiter(c.limiter))
+	}
+	return opts
+}
+
+func (a *Account) shieldOptions() []shield.Option {
+	c := a.cfg
+	opts := []shield.Option{shield.WithHTTPClient(c.httpClient), shield.WithMiddleware(c.middlewares...)}
+	if c.userAgent != "" {
+		opts = append(opts, shield.WithUserAgent(c.userAgent))
+	}
+	if c.endpoints.API != "" {
+		opts = append(opts, shield.WithBaseURL(c.endpoints.API))
+	}
+	if c.logger != nil {
+		opts = append(opts, shield.WithLogger(c.logger, c.logOptions...))
+	}
+	if c.tracer != nil {
+		opts = append(opts, shield.WithTracer(c.tracer))
+	}
+	if c.metrics != nil {
+		opts = append(opts, shield.WithMetrics(c.metrics))
+	}
+	if c.limiter != nil {
+		opts = append(opts, shield.WithRateLimiter(c.limiter))
+	}
+	return opts
+}
+
+func (a *Account) scriptingOptions() []scripting.Option {
+	c := a.cfg
+	opts := []scripting.Option{scripting.WithHTTPClient(c.httpClient), scripting.WithMiddleware(c.middlewares...)}
+	if c.userAgent != "" {
+		opts = append(opts, scripting.WithUserAgent(c.userAgent))
+	}
+	if c.endpoints.API != "" {
+		opts = append(opts, scripting.WithBaseURL(c.endpoints.API))
+	}
+	if c.logger != nil {
+		opts = append(opts, scripting.WithLogger(c.logger, c.logOptions...))
+	}
+	if c.tracer != nil {
+		opts = append(opts, scripting.WithTracer(c.tracer))
+	}
+	if c.metrics != nil {
+		opts = append(opts, scripting.WithMetrics(c.metrics))
+	}
+	if c.limiter != nil {
+		opts = append(opts, scripting.WithRateLimiter(c.limiter))
+	}
+	return opts
+}
+
+func (a *Account) containersOptions() []containers.Option {
+	c := a.cfg
+	opts := []containers.Option{containers.WithHTTPClient(c.httpClient), containers.WithMiddleware(c.middlewares...)}
+	if c.userAgent != "" {
+		opts = append(opts, containers.WithUserAgent(c.userAgent))
+	}
+	if c.endpoints.Containers != "" {
+		opts = append(opts, containers.WithBaseURL(c.endpoints.Containers))
+	}
+	if c.logger != nil {
+		opts = append(opts, containers.WithLogger(c.logger, c.logOptions...))
+	}
+	if c.tracer != nil {
+		opts = append(opts, containers.WithTracer(c.tracer))
+	}
+	if c.metrics != nil {
+		opts = append(opts, containers.WithMetrics(c.metrics))
+	}
+	if c.limiter != nil {
+		opts = append(opts, containers.WithRateLimiter(c.limiter))
+	}
+	return opts
+}
@@ -0,0 +1,272 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/geraldo/bunny-sdk-go/credential"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+// Resolver looks up storage zone passwords and stream library API keys with
+// the account API key and caches them. Cached credentials are dropped when
+// the API rejects them, so a password reset elsewhere is picked up on the
+// next call.
+type Resolver struct {
+	zones     storage.ZoneService
+	libraries stream.LibraryService
+
+	mu       sync.Mutex
+	zoneIDs  map[string]int64
+	zoneKeys map[string]StorageZoneCredentials
+	libKeys  map[int64]string
+}
+
+// NewResolver returns a Resolver that uses zones and libraries, which must
+// be authenticated with the account API key.
+func NewResolver(zones storage.ZoneService, libraries stream.LibraryService) *Resolver {
+	return &Resolver{
+		zones:     zones,
+		libraries: libraries,
+		zoneIDs:   make(map[string]int64),
+		zoneKeys:  make(map[string]StorageZoneCredentials),
+		libKeys:   make(map[int64]string),
+	}
+}
+
+// StorageZone returns the password and region of the named storage zone.
+func (r *Resolver) StorageZone(ctx context.Context, name string) (StorageZoneCredentials, error) {
+	r.mu.Lock()
+	creds, ok := r.zoneKeys[name]
+	r.mu.Unlock()
+	if ok {
+		return creds, nil
+	}
+
+	zone, err := r.findZone(ctx, name)
+	if err != nil {
+		return StorageZoneCredentials{}, err
+	}
+	creds = StorageZoneCredentials{Password: zone.Password, Region: storage.Region(strings.ToLower(zone.Region))}
+	if creds.Password == "" {
+		return creds, fmt.Errorf("%w: storage zone %q has no password in the API response", ErrNoCredentials, name)
+	}
+	r.mu.Lock()
+	r.zoneIDs[name] = zone.ID
+	r.zoneKeys[name] = creds
+	r.mu.Unlock()
+	return creds, nil
+}
+
+// LibraryKey returns the API key of a stream library.
+func (r *Resolver) LibraryKey(ctx context.Context, libraryID int64) (string, error) {
+	r.mu.Lock()
+	key, ok := r.libKeys[libraryID]
+	r.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	lib, err := r.libraries.Get(ctx, libraryID)
+	if err != nil {
+		return "", fmt.Errorf("stream library %d: %w", libraryID, err)
+	}
+	if lib.APIKey == "" {
+		return "", fmt.Errorf("%w: stream library %d has no API key in the API response", ErrNoCredentials, libraryID)
+	}
+	r.mu.Lock()
+	r.libKeys[libraryID] = lib.APIKey
+	r.mu.Unlock()
+	return lib.APIKey, nil
+}
+
+// ResetPassword resets the password of the named storage zone and returns
+// and caches the new one, along with the zone's region. When the reset
+// response omits the password, the zone is fetched again to read it.
+func (r *Resolver) ResetPassword(ctx context.Context, name string) (string, error) {
+	r.mu.Lock()
+	id, ok := r.zoneIDs[name]
+	region := r.zoneKeys[name].Region
+	r.mu.Unlock()
+	if !ok || region == "" {
+		zone, err := r.findZone(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		id, region = zone.ID, storage.Region(strings.ToLower(zone.Region))
+	}
+	resp, err := r.zones.ResetPassword(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	password := resp.Password
+	if password == "" {
+		// The new password is only visible by fetching the zone again.
+		zone, err := r.zones.Get(ctx, id)
+		if err != nil {
+			r.ForgetStorageZone(name)
+			return "", fmt.Errorf("storage zone %q: password reset, reading the new password: %w", name, err)
+		}
+		if password = zone.Password; password == "" {
+			r.ForgetStorageZone(name)
+			return "", fmt.Errorf("%w: storage zone %q has no password in the API response", ErrNoCredentials, name)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.zoneIDs[name] = id
+	r.zoneKeys[name] = StorageZoneCredentials{Password: password, Region: region}
+	return password, nil
+}
+
+// ForgetStorageZone drops the cached credentials of a storage zone.
+func (r *Resolver) ForgetStorageZone(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.zoneKeys, name)
+}
+
+// ForgetLibrary drops the cached API key of a stream library.
+func (r *Resolver) ForgetLibrary(libraryID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.libKeys, libraryID)
+}
+
+// findZone pages through the zones matching name and returns the exact match.
+func (r *Resolver) findZone(ctx context.Context, name string) (*storage.Zone, error) {
+	seen := 0
+	for page := 1; ; page++ {
+		resp, err := r.zones.List(ctx, &storage.ZoneListOptions{Page: page, PerPage: 100, Search: name})
+		if err != nil {
+			return nil, fmt.Errorf("storage zone %q: %w", name, err)
+		}
+		for i := range resp.Items {
+			if z := &resp.Items[i]; z.Name == name && !z.Deleted {
+				return z, nil
+			}
+		}
+		seen += len(resp.Items)
+		if len(resp.Items) == 0 || !resp.HasMore() || seen >= resp.TotalItems {
+			return nil, fmt.Errorf("storage zone %q not found", name)
+		}
+	}
+}
+
+// zoneSecret returns a zone password that is looked up by r on first use
+// and again when the API rejects it.
+func (r *Resolver) zoneSecret(name string) *credential.Secret {
+	return credential.New("", credential.WithRefresh(func(ctx context.Context, rejected string) (string, error) {
+		if rejected != "" {
+			r.ForgetStorageZone(name)
+		}
+		creds, err := r.StorageZone(ctx, name)
+		return creds.Password, err
+	}))
+}
+
+// librarySecret returns a library API key that is looked up by r on first
+// use and again when the API rejects it.
+func (r *Resolver) librarySecret(libraryID int64) *credential.Secret {
+	return credential.New("", credential.WithRefresh(func(ctx context.Context, rejected string) (string, error) {
+		if rejected != "" {
+			r.ForgetLibrary(libraryID)
+		}
+		return r.LibraryKey(ctx, libraryID)
+	}))
+}
+
+// resolvedFiles is a FileService for a zone whose password is resolved on
+// demand. The service is built on first use, once the zone region is known;
+// its password secret handles lookups and 401 retries.
+type resolvedFiles struct {
+	resolver *Resolver
+	zone     string
+	options  []storage.FileServiceOption
+
+	mu    sync.Mutex
+	files storage.FileService
+}
+
+func (f *resolvedFiles) service(ctx context.Context) (storage.FileService, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.files != nil {
+		return f.files, nil
+	}
+	creds, err := f.resolver.StorageZone(ctx, f.zone)
+	if err != nil {
+		return nil, err
+	}
+	region := creds.Region
+	if region == "" {
+		region = storage.RegionFalkenstein
+	}
+	opts := append(slices.Clip(f.options), storage.WithFileCredential(f.resolver.zoneSecret(f.zone)))
+	f.files = storage.NewFileService(f.zone, "", region, opts...)
+	return f.files, nil
+}
+
+func (f *resolvedFiles) Upload(ctx context.Context, path string, reader io.Reader, opts *storage.UploadOptions) error {
+	files, err := f.service(ctx)
+	if err != nil {
+		return err
+	}
+	return files.Upload(ctx, path, reader, opts)
+}
+
+func (f *resolvedFiles) Download(ctx context.Context, path string) (io.ReadCloser, error) {
+	files, err := f.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return files.Download(ctx, path)
+}
+
+func (f *resolvedFiles) List(ctx context.Context, path string) ([]storage.File, error) {
+	files, err := f.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return files.List(ctx, path)
+}
+
+// ListIter streams the listing of the underlying file service.
+func (f *resolvedFiles) ListIter(ctx context.Context, path string) iter.Seq2[storage.File, error] {
+	return func(yield func(storage.File, error) bool) {
+		files, err := f.service(ctx)
+		if err != nil {
+			yield(storage.File{}, err)
+			return
+		}
+		for file, err := range storage.ListIter(ctx, files, path) {
+			if !yield(file, err) {
+				return
+			}
+		}
+	}
+}
+
+func (f *resolvedFiles) Delete(ctx context.Context, path string) error {
+	files, err := f.service(ctx)
+	if err != nil {
+		return err
+	}
+	return files.Delete(ctx, path)
+}
+
+func (f *resolvedFiles) DeleteDirectory(ctx context.Context, path string) error {
+	files, err := f.service(ctx)
+	if err != nil {
+		return err
+	}
+	return files.DeleteDirectory(ctx, path)
+}
@@ -0,0 +1,188 @@
+package account_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/account"
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+func TestAccountResolvesCredentials(t *testing.T) {
+	srv := bunnytest.NewServer(bunnytest.WithAccessKey("key"))
+	defer srv.Close()
+	ctx := context.Background()
+
+	acct := account.New(&account.Credentials{APIKey: "key"},
+		account.WithHTTPClient(srv.Client()),
+		account.WithEndpoints(account.Endpoints{
+			API:     srv.URL,
+			Video:   srv.StreamURL(),
+			Storage: srv.StorageURL(),
+		}),
+	)
+	zone, err := acct.Storage().Zones().Create(ctx, &storage.CreateZoneRequest{Name: "assets", Region: "NY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib, err := acct.Stream().Libraries().Create(ctx, &stream.CreateLibraryRequest{Name: "courses"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := acct.Files("assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := files.Upload(ctx, "a.txt", strings.NewReader("a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := acct.Resolver().StorageZone(ctx, "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Password != zone.Password || creds.Region != storage.RegionNewYork {
+		t.Errorf("resolved %+v, want password %q in ny", creds, zone.Password)
+	}
+
+	// A reset through the resolver updates the cached password.
+	pw, err := acct.Resolver().ResetPassword(ctx, "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pw == "" || pw == zone.Password {
+		t.Errorf("ResetPassword = %q", pw)
+	}
+	if _, err := files.List(ctx, "/"); err != nil {
+		t.Fatalf("List after ResetPassword: %v", err)
+	}
+
+	// A reset made elsewhere is picked up when the old password is rejected,
+	// and a seekable upload body is replayed.
+	if _, err := acct.Storage().Zones().ResetPassword(ctx, zone.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := files.Upload(ctx, "b.txt", bytes.NewReader([]byte("b")), nil); err != nil {
+		t.Fatalf("Upload after external reset: %v", err)
+	}
+	rc, err := files.Download(ctx, "b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(body) != "b" {
+		t.Errorf("downloaded %q", body)
+	}
+
+	videos, err := acct.Videos(lib.LibraryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := videos.Create(ctx, &stream.CreateVideoRequest{Title: "Lesson"}); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := acct.Resolver().LibraryKey(ctx, lib.LibraryID); err != nil || key != lib.APIKey {
+		t.Errorf("LibraryKey = %q, %v; want %q", key, err, lib.APIKey)
+	}
+
+	missing, err := acct.Files("missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := missing.List(ctx, "/"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("List on unknown zone: err = %v", err)
+	}
+}
+
+// fakeZones serves a fixed zone list and password resets.
+type fakeZones struct {
+	storage.ZoneService
+	zones []storage.Zone
+
+	silentReset bool  // the reset response omits the new password
+	getErr      error // returned by Get
+}
+
+func (f *fakeZones) List(_ context.Context, _ *storage.ZoneListOptions) (*storage.ZoneListResponse, error) {
+	return &storage.ZoneListResponse{Items: f.zones, TotalItems: len(f.zones), PageSize: 100}, nil
+}
+
+func (f *fakeZones) Get(_ context.Context, zoneID int64) (*storage.Zone, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	for _, z := range f.zones {
+		if z.ID == zoneID {
+			return &z, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeZones) ResetPassword(_ context.Context, zoneID int64) (*storage.ResetPasswordResponse, error) {
+	if f.silentReset {
+		for i := range f.zones {
+			if f.zones[i].ID == zoneID {
+				f.zones[i].Password = "reset"
+			}
+		}
+		return &storage.ResetPasswordResponse{ID: zoneID, Success: true}, nil
+	}
+	return &storage.ResetPasswordResponse{ID: zoneID, Password: "reset", Success: true}, nil
+}
+
+func TestResolverLooksUpOrFails(t *testing.T) {
+	ctx := context.Background()
+	r := account.NewResolver(&fakeZones{zones: []storage.Zone{
+		{ID: 1, Name: "assets", Region: "NY", Password: "pw"},
+		{ID: 2, Name: "locked", Region: "DE"},
+	}}, nil)
+
+	// A reset before any lookup keeps the zone's region.
+	if pw, err := r.ResetPassword(ctx, "assets"); err != nil || pw != "reset" {
+		t.Fatalf("ResetPassword = %q, %v", pw, err)
+	}
+	creds, err := r.StorageZone(ctx, "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Password != "reset" || creds.Region != storage.RegionNewYork {
+		t.Errorf("resolved %+v after reset, want the new password in ny", creds)
+	}
+
+	if _, err := r.StorageZone(ctx, "locked"); !errors.Is(err, account.ErrNoCredentials) {
+		t.Errorf("StorageZone without password: err = %v", err)
+	}
+	if _, err := r.StorageZone(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("StorageZone on unknown zone: err = %v", err)
+	}
+}
+
+func TestResolverResetReadsPasswordFromZone(t *testing.T) {
+	ctx := context.Background()
+	zones := &fakeZones{silentReset: true, zones: []storage.Zone{
+		{ID: 1, Name: "assets", Region: "NY", Password: "pw"},
+		{ID: 2, Name: "locked", Region: "DE"},
+	}}
+	r := account.NewResolver(zones, nil)
+
+	if pw, err := r.ResetPassword(ctx, "assets"); err != nil || pw != "reset" {
+		t.Fatalf("ResetPassword = %q, %v", pw, err)
+	}
+	if creds, err := r.StorageZone(ctx, "assets"); err != nil || creds.Password != "reset" {
+		t.Errorf("resolved %+v, %v after reset", creds, err)
+	}
+
+	// A new password that cannot be read back is an error, not "".
+	zones.getErr = errors.New("unavailable")
+	if pw, err := r.ResetPassword(ctx, "assets"); err == nil || pw != "" {
+		t.Errorf("ResetPassword = %q, %v; want an error", pw, err)
+	}
+}
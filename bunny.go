@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/internal/pipeline"
 )
 
 const (
@@ -41,6 +42,7 @@ type Client struct {
 	userAgent      string
 	streamBaseURL  string
 	storageBaseURL string
+	pipeline       pipeline.Config
 }
 
 // NewClient creates a new Bunny.net management API client.
@@ -56,6 +58,7 @@ func NewClient(apiKey string, opts ...Option) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.httpClient = c.pipeline.Wrap(c.httpClient)
 	return c
 }
 
@@ -82,7 +85,7 @@ func NewStreamClient(apiKey string, opts ...Option) *StreamClient {
 
 	return &StreamClient{
 		apiKey:     apiKey,
-		httpClient: c.httpClient,
+		httpClient: c.pipeline.Wrap(c.httpClient),
 		userAgent:  c.userAgent,
 		baseURL:    c.streamBaseURL,
 	}
@@ -129,7 +132,7 @@ func NewStorageClient(zoneName, accessKey string, region StorageRegion, opts ...
 	return &StorageClient{
 		zoneName:   zoneName,
 		accessKey:  accessKey,
-		httpClient: c.httpClient,
+		httpClient: c.pipeline.Wrap(c.httpClient),
 		userAgent:  c.userAgent,
 		baseURL:    baseURL,
 	}
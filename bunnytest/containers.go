@@ -0,0 +1,216 @@
+package bunnytest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+)
+
+func (s *Server) registerContainers(mux *http.ServeMux) {
+	apps := containersPrefix + "/apps"
+	mux.HandleFunc("GET "+apps, s.api(s.listApps))
+	mux.HandleFunc("POST "+apps, s.api(s.createApp))
+	mux.HandleFunc("GET "+apps+"/{id}", s.api(s.getApp))
+	mux.HandleFunc("PUT "+apps+"/{id}", s.api(s.updateApp))
+	mux.HandleFunc("PATCH "+apps+"/{id}", s.api(s.patchApp))
+	mux.HandleFunc("DELETE "+apps+"/{id}", s.api(s.deleteApp))
+	mux.HandleFunc("POST "+apps+"/{id}/deploy", s.api(s.setAppStatus(containers.ApplicationStatusActive)))
+	mux.HandleFunc("POST "+apps+"/{id}/undeploy", s.api(s.setAppStatus(containers.ApplicationStatusInactive)))
+	mux.HandleFunc("POST "+apps+"/{id}/restart", s.api(s.restartApp))
+}
+
+func (s *Server) lookupApp(w http.ResponseWriter, r *http.Request) *containers.Application {
+	app, ok := s.apps[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "application %s not found", r.PathValue("id"))
+		return nil
+	}
+	return app
+}
+
+// listApps pages through applications in creation order. The cursor is the
+// offset of the next item.
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("nextCursor"))
+	limit := queryInt(r, "limit", 20)
+
+	resp := containers.ApplicationListResponse{
+		Items: []containers.ApplicationListItem{},
+		Meta:  containers.PaginationMeta{TotalItems: len(s.appOrder)},
+	}
+	for i := offset; i < len(s.appOrder) && i < offset+limit; i++ {
+		app := s.apps[s.appOrder[i]]
+		resp.Items = append(resp.Items, containers.ApplicationListItem{
+			ID:              app.ID,
+			Name:            app.Name,
+			DisplayEndpoint: app.DisplayEndpoint,
+			Status:          app.Status,
+		})
+	}
+	if offset+limit < len(s.appOrder) {
+		resp.Cursor = strconv.Itoa(offset + limit)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request) {
+	var req containers.CreateApplicationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	autoScaling := req.AutoScaling
+	app := &containers.Application{
+		ID:                 newUUID(),
+		Name:               req.Name,
+		Status:             containers.ApplicationStatusInactive,
+		RuntimeType:        req.RuntimeType,
+		RegionSettings:     regionSettings(req.RegionSettings),
+		ContainerTemplates: containerTemplates(req.ContainerTemplates),
+		Volumes:            volumes(req.Volumes),
+		AutoScaling:        &autoScaling,
+	}
+	s.apps[app.ID] = app
+	s.appOrder = append(s.appOrder, app.ID)
+	writeJSON(w, http.StatusCreated, containers.ApplicationIDResponse{ID: app.ID})
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request) {
+	if app := s.lookupApp(w, r); app != nil {
+		writeJSON(w, http.StatusOK, app)
+	}
+}
+
+// updateApp replaces the application's configuration, keeping its ID and status.
+func (s *Server) updateApp(w http.ResponseWriter, r *http.Request) {
+	app := s.lookupApp(w, r)
+	if app == nil {
+		return
+	}
+	var req containers.UpdateApplicationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	autoScaling := req.AutoScaling
+	*app = containers.Application{
+		ID:                 app.ID,
+		Name:               req.Name,
+		Status:             app.Status,
+		RuntimeType:        req.RuntimeType,
+		DisplayEndpoint:    app.DisplayEndpoint,
+		RegionSettings:     regionSettings(req.RegionSettings),
+		ContainerTemplates: containerTemplates(req.ContainerTemplates),
+		Volumes:            volumes(req.Volumes),
+		AutoScaling:        &autoScaling,
+	}
+	writeJSON(w, http.StatusOK, containers.ApplicationIDResponse{ID: app.ID})
+}
+
+func (s *Server) patchApp(w http.ResponseWriter, r *http.Request) {
+	app := s.lookupApp(w, r)
+	if app == nil {
+		return
+	}
+	var req containers.PatchApplicationRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		app.Name = req.Name
+	}
+	if req.RuntimeType != "" {
+		app.RuntimeType = req.RuntimeType
+	}
+	if req.AutoScaling != nil {
+		autoScaling := *req.AutoScaling
+		app.AutoScaling = &autoScaling
+	}
+	if req.RegionSettings != nil {
+		app.RegionSettings = regionSettings(*req.RegionSettings)
+	}
+	if req.ContainerTemplates != nil {
+		app.ContainerTemplates = containerTemplates(req.ContainerTemplates)
+	}
+	if req.Volumes != nil {
+		app.Volumes = volumes(req.Volumes)
+	}
+	writeJSON(w, http.StatusOK, containers.ApplicationIDResponse{ID: app.ID})
+}
+
+func (s *Server) deleteApp(w http.ResponseWriter, r *http.Request) {
+	app := s.lookupApp(w, r)
+	if app == nil {
+		return
+	}
+	delete(s.apps, app.ID)
+	for i, id := range s.appOrder {
+		if id == app.ID {
+			s.appOrder = append(s.appOrder[:i], s.appOrder[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) setAppStatus(status containers.ApplicationStatus) handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if app := s.lookupApp(w, r); app != nil {
+			app.Status = status
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// restartApp fails for applications that are not deployed.
+func (s *Server) restartApp(w http.ResponseWriter, r *http.Request) {
+	app := s.lookupApp(w, r)
+	if app == nil {
+		return
+	}
+	if app.Status != containers.ApplicationStatusActive {
+		writeError(w, http.StatusBadRequest, "application %s is not deployed", app.ID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func regionSettings(req containers.CreateRegionSettingsRequest) *containers.RegionSettings {
+	return &containers.RegionSettings{
+		AllowedRegionIds:  req.AllowedRegionIds,
+		RequiredRegionIds: req.RequiredRegionIds,
+		MaxAllowedRegions: req.MaxAllowedRegions,
+	}
+}
+
+func containerTemplates(reqs []containers.CreateContainerTemplateRequest) []containers.ContainerTemplate {
+	out := make([]containers.ContainerTemplate, len(reqs))
+	for i, t := range reqs {
+		out[i] = containers.ContainerTemplate{
+			ID:                   newUUID(),
+			Name:                 t.Name,
+			Image:                t.Image,
+			ImageName:            t.ImageName,
+			ImageNamespace:       t.ImageNamespace,
+			ImageTag:             t.ImageTag,
+			ImageRegistryID:      t.ImageRegistryID,
+			ImageDigest:          t.ImageDigest,
+			ImagePullPolicy:      t.ImagePullPolicy,
+			EntryPoint:           t.EntryPoint,
+			Probes:               t.Probes,
+			EnvironmentVariables: t.EnvironmentVariables,
+		}
+	}
+	return out
+}
+
+func volumes(reqs []containers.VolumeRequest) []containers.Volume {
+	out := make([]containers.Volume, len(reqs))
+	for i, v := range reqs {
+		out[i] = containers.Volume{ID: newUUID(), Name: v.Name, Size: v.Size}
+	}
+	return out
+}
@@ -0,0 +1,216 @@
+package bunnytest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/scripting"
+)
+
+type script struct {
+	script       scripting.EdgeScript
+	code         string
+	codeModified string
+	releases     []scripting.EdgeScriptRelease // newest first
+}
+
+func (s *Server) registerScripting(mux *http.ServeMux) {
+	mux.HandleFunc("GET /compute/script", s.api(s.listScripts))
+	mux.HandleFunc("POST /compute/script", s.api(s.createScript))
+	mux.HandleFunc("GET /compute/script/{id}", s.api(s.getScript))
+	mux.HandleFunc("POST /compute/script/{id}", s.api(s.updateScript))
+	mux.HandleFunc("DELETE /compute/script/{id}", s.api(s.deleteScript))
+	mux.HandleFunc("POST /compute/script/{id}/deploymentKey/rotate", s.api(s.rotateDeploymentKey))
+	mux.HandleFunc("GET /compute/script/{id}/code", s.api(s.getScriptCode))
+	mux.HandleFunc("POST /compute/script/{id}/code", s.api(s.setScriptCode))
+	mux.HandleFunc("GET /compute/script/{id}/releases", s.api(s.listReleases))
+	mux.HandleFunc("GET /compute/script/{id}/releases/active", s.api(s.getActiveRelease))
+	mux.HandleFunc("POST /compute/script/{id}/publish", s.api(s.publishScript))
+}
+
+func (s *Server) lookupScript(w http.ResponseWriter, r *http.Request) *script {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return nil
+	}
+	sc, ok := s.scripts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "edge script %d not found", id)
+		return nil
+	}
+	return sc
+}
+
+func (s *Server) listScripts(w http.ResponseWriter, r *http.Request) {
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	scripts := []scripting.EdgeScript{}
+	for _, sc := range s.scripts {
+		if search != "" && (sc.script.Name == nil || !strings.Contains(strings.ToLower(*sc.script.Name), search)) {
+			continue
+		}
+		scripts = append(scripts, sc.script)
+	}
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].ID < scripts[j].ID })
+
+	p, perPage := queryInt(r, "page", 1), queryInt(r, "perPage", 1000)
+	writeJSON(w, http.StatusOK, scripting.ScriptListResponse{
+		Items:        page(scripts, p, perPage),
+		CurrentPage:  p,
+		TotalItems:   len(scripts),
+		HasMoreItems: p*perPage < len(scripts),
+	})
+}
+
+func (s *Server) createScript(w http.ResponseWriter, r *http.Request) {
+	var req scripting.CreateScriptRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	scriptType := req.ScriptType
+	if scriptType == "" {
+		scriptType = scripting.ScriptTypeCDN
+	}
+	id := s.newID()
+	name, key := req.Name, newSecret(16)
+	hostname := strings.ToLower(name) + ".b-cdn.net"
+	sc := &script{
+		script: scripting.EdgeScript{
+			ID:              id,
+			Name:            &name,
+			LastModified:    s.dateString(),
+			ScriptType:      scriptType,
+			DefaultHostname: &hostname,
+			DeploymentKey:   &key,
+		},
+		code:         req.Code,
+		codeModified: s.dateString(),
+	}
+	s.scripts[id] = sc
+	writeJSON(w, http.StatusCreated, sc.script)
+}
+
+func (s *Server) getScript(w http.ResponseWriter, r *http.Request) {
+	if sc := s.lookupScript(w, r); sc != nil {
+		writeJSON(w, http.StatusOK, sc.script)
+	}
+}
+
+func (s *Server) updateScript(w http.ResponseWriter, r *http.Request) {
+	sc := s.lookupScript(w, r)
+	if sc == nil {
+		return
+	}
+	var req scripting.UpdateScriptRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		name := req.Name
+		sc.script.Name = &name
+	}
+	if req.ScriptType != "" {
+		sc.script.ScriptType = req.ScriptType
+	}
+	sc.script.LastModified = s.dateString()
+	writeJSON(w, http.StatusOK, sc.script)
+}
+
+func (s *Server) deleteScript(w http.ResponseWriter, r *http.Request) {
+	if sc := s.lookupScript(w, r); sc != nil {
+		delete(s.scripts, sc.script.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) rotateDeploymentKey(w http.ResponseWriter, r *http.Request) {
+	if sc := s.lookupScript(w, r); sc != nil {
+		key := newSecret(16)
+		sc.script.DeploymentKey = &key
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) getScriptCode(w http.ResponseWriter, r *http.Request) {
+	if sc := s.lookupScript(w, r); sc != nil {
+		code := sc.code
+		writeJSON(w, http.StatusOK, scripting.EdgeScriptCode{Code: &code, LastModified: sc.codeModified})
+	}
+}
+
+func (s *Server) setScriptCode(w http.ResponseWriter, r *http.Request) {
+	sc := s.lookupScript(w, r)
+	if sc == nil {
+		return
+	}
+	var req scripting.UpdateCodeRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	sc.code, sc.codeModified = req.Code, s.dateString()
+	sc.script.LastModified = sc.codeModified
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listReleases(w http.ResponseWriter, r *http.Request) {
+	sc := s.lookupScript(w, r)
+	if sc == nil {
+		return
+	}
+	p, perPage := queryInt(r, "page", 1), queryInt(r, "perPage", 20)
+	writeJSON(w, http.StatusOK, scripting.ReleaseListResponse{
+		Items:        page(sc.releases, p, perPage),
+		CurrentPage:  p,
+		TotalItems:   len(sc.releases),
+		HasMoreItems: p*perPage < len(sc.releases),
+	})
+}
+
+func (s *Server) getActiveRelease(w http.ResponseWriter, r *http.Request) {
+	sc := s.lookupScript(w, r)
+	if sc == nil {
+		return
+	}
+	if len(sc.releases) == 0 {
+		writeError(w, http.StatusNotFound, "edge script %d has no releases", sc.script.ID)
+		return
+	}
+	writeJSON(w, http.StatusOK, sc.releases[0])
+}
+
+// publishScript releases the current code. The previous live release is archived.
+func (s *Server) publishScript(w http.ResponseWriter, r *http.Request) {
+	sc := s.lookupScript(w, r)
+	if sc == nil {
+		return
+	}
+	var req scripting.PublishReleaseRequest
+	if r.ContentLength != 0 && !decode(w, r, &req) {
+		return
+	}
+	archived := scripting.ReleaseStatusArchived
+	for i := range sc.releases {
+		sc.releases[i].Status = &archived
+	}
+
+	code, uuid, live, now := sc.code, newUUID(), scripting.ReleaseStatusLive, s.dateString()
+	release := scripting.EdgeScriptRelease{
+		ID:            s.newID(),
+		Code:          &code,
+		UUID:          &uuid,
+		Status:        &live,
+		DateReleased:  now,
+		DatePublished: now,
+	}
+	if req.Note != "" {
+		note := req.Note
+		release.Note = &note
+	}
+	sc.releases = append([]scripting.EdgeScriptRelease{release}, sc.releases...)
+	sc.script.CurrentReleaseID = release.ID
+	w.WriteHeader(http.StatusNoContent)
+}
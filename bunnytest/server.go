@@ -0,0 +1,252 @@
+// Package bunnytest provides an in-process fake of the Bunny.net APIs for
+// integration tests.
+//
+// The fake keeps storage zones and files, stream libraries, videos and
+// collections, shield zones with custom WAF rules, rate limits, access
+// lists and security events, edge scripts and releases,
+// and Magic Containers applications in memory. Point the SDK clients at it
+// with their base URL options:
+//
+//	srv := bunnytest.NewServer()
+//	defer srv.Close()
+//
+//	zones := storage.NewClient("key", storage.WithBaseURL(srv.URL))
+//	files := storage.NewFileService(zone.Name, zone.Password, storage.RegionFalkenstein,
+//		storage.WithFileBaseURL(srv.StorageURL()))
+//	videos := stream.NewClient("key", stream.WithBaseAPIURL(srv.URL),
+//		stream.WithStreamAPIURL(srv.StreamURL())).Videos(libraryID)
+//	apps := containers.NewClient("key", containers.WithBaseURL(srv.ContainersURL()))
+//
+// Endpoints the fake does not model answer 501 Not Implemented so a missing
+// feature is never mistaken for a missing resource.
+package bunnytest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+// Path prefixes that stand in for the separate Bunny.net hosts.
+const (
+	storagePrefix    = "/storage"
+	streamPrefix     = "/stream"
+	containersPrefix = "/mc"
+)
+
+// Server is a fake Bunny.net API backed by in-memory state.
+type Server struct {
+	// URL is the base URL of the management API (api.bunny.net).
+	URL string
+
+	srv       *httptest.Server
+	accessKey string
+	now       func() time.Time
+
+	accessListMax        int
+	accessListMaxRequest int
+
+	mu           sync.Mutex
+	nextID       int64
+	storageZones map[int64]*storage.Zone
+	files        map[string]map[string]*storedFile // zone name -> path -> file
+	libraries    map[int64]*library
+	shieldZones  map[string]*shield.ShieldZone
+	customRules  map[string]*shield.CustomRule
+	rateLimits   map[string]*shield.RateLimit
+	accessLists  map[string][]shield.AccessListEntry // shield zone ID -> entries
+	eventLogs    []shield.EventLog
+	scripts      map[int64]*script
+	apps         map[string]*containers.Application
+	appOrder     []string
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAccessKey makes the management, stream and containers endpoints reject
+// requests whose AccessKey header is not key. By default any key is accepted.
+// Storage file endpoints always require the zone password.
+func WithAccessKey(key string) Option {
+	return func(s *Server) {
+		s.accessKey = key
+	}
+}
+
+// WithClock sets the time source used for creation and modification dates.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithAccessListLimits caps each shield zone's access list at maxEntries
+// entries and each batch update or delete at maxPerRequest entries. Both are
+// reported by the access list enums endpoint; zero means no limit.
+func WithAccessListLimits(maxEntries, maxPerRequest int) Option {
+	return func(s *Server) {
+		s.accessListMax = maxEntries
+		s.accessListMaxRequest = maxPerRequest
+	}
+}
+
+// NewServer starts a fake server. Callers must Close it.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		now:          time.Now,
+		storageZones: make(map[int64]*storage.Zone),
+		files:        make(map[string]map[string]*storedFile),
+		libraries:    make(map[int64]*library),
+		shieldZones:  make(map[string]*shield.ShieldZone),
+		customRules:  make(map[string]*shield.CustomRule),
+		rateLimits:   make(map[string]*shield.RateLimit),
+		accessLists:  make(map[string][]shield.AccessListEntry),
+		scripts:      make(map[int64]*script),
+		apps:         make(map[string]*containers.Application),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	s.registerStorage(mux)
+	s.registerStream(mux)
+	s.registerShield(mux)
+	s.registerScripting(mux)
+	s.registerContainers(mux)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotImplemented, "bunnytest: %s %s is not implemented", r.Method, r.URL.Path)
+	})
+
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns an HTTP client for the server.
+func (s *Server) Client() *http.Client {
+	return s.srv.Client()
+}
+
+// StorageURL is the base URL for storage file operations, for storage.WithFileBaseURL.
+func (s *Server) StorageURL() string {
+	return s.URL + storagePrefix
+}
+
+// StreamURL is the base URL for video and collection operations, for stream.WithStreamAPIURL.
+func (s *Server) StreamURL() string {
+	return s.URL + streamPrefix
+}
+
+// ContainersURL is the base URL of the Magic Containers API, for containers.WithBaseURL.
+func (s *Server) ContainersURL() string {
+	return s.URL + containersPrefix
+}
+
+// handler is an endpoint that runs with the state lock held.
+type handler func(w http.ResponseWriter, r *http.Request)
+
+// api wraps an endpoint that requires the account access key.
+func (s *Server) api(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.accessKey != "" && r.Header.Get("AccessKey") != s.accessKey {
+			writeError(w, http.StatusUnauthorized, "invalid AccessKey")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		h(w, r)
+	}
+}
+
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) timestamp() internal.BunnyTime {
+	return internal.BunnyTime{Time: s.now().UTC().Truncate(time.Second)}
+}
+
+func (s *Server) dateString() string {
+	return s.now().UTC().Format(time.RFC3339)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, internal.ErrorResponse{Message: fmt.Sprintf(format, args...)})
+}
+
+// decode reads a JSON request body into v, answering 400 on failure.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return false
+	}
+	return true
+}
+
+// pathInt parses a numeric path wildcard, answering 404 when it is not a number.
+func pathInt(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "%s %q not found", name, r.PathValue(name))
+		return 0, false
+	}
+	return id, true
+}
+
+// queryInt returns a positive integer query parameter or def.
+func queryInt(r *http.Request, name string, def int) int {
+	n, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// page returns the 1-based page of items.
+func page[T any](items []T, n, perPage int) []T {
+	start := (n - 1) * perPage
+	if start >= len(items) {
+		return []T{}
+	}
+	end := min(start+perPage, len(items))
+	return items[start:end]
+}
+
+func newSecret(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
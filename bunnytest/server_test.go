@@ -0,0 +1,341 @@
+package bunnytest_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+func TestStorageZonesAndFiles(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	zones := storage.NewClient("key", storage.WithBaseURL(srv.URL)).Zones()
+	zone, err := zones.Create(ctx, &storage.CreateZoneRequest{Name: "assets", Region: "NY"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if zone.Password == "" || zone.Region != "NY" {
+		t.Fatalf("zone = %+v", zone)
+	}
+	if _, err := zones.Create(ctx, &storage.CreateZoneRequest{Name: "assets"}); err == nil {
+		t.Error("expected duplicate zone name to fail")
+	}
+
+	files := storage.NewFileService(zone.Name, zone.Password, storage.RegionNewYork, storage.WithFileBaseURL(srv.StorageURL()))
+	body := "hello world"
+	sum := sha256.Sum256([]byte(body))
+	if err := files.Upload(ctx, "docs/readme.txt", strings.NewReader(body), &storage.UploadOptions{Checksum: strings.ToUpper(hex.EncodeToString(sum[:]))}); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if err := files.Upload(ctx, "docs/img/logo.png", strings.NewReader("png"), nil); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if err := files.Upload(ctx, "bad.txt", strings.NewReader("x"), &storage.UploadOptions{Checksum: "00"}); err == nil {
+		t.Error("expected checksum mismatch to fail")
+	}
+
+	list, err := files.List(ctx, "docs")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || !list[0].IsDirectory || list[0].ObjectName != "img" || list[1].ObjectName != "readme.txt" || list[1].Length != int64(len(body)) {
+		t.Errorf("List = %+v", list)
+	}
+
+	rc, err := files.Download(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != body {
+		t.Errorf("Download = %q", got)
+	}
+
+	z, err := zones.Get(ctx, zone.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if z.FilesStored != 2 || z.StorageUsed != int64(len(body)+3) {
+		t.Errorf("usage = %d files, %d bytes", z.FilesStored, z.StorageUsed)
+	}
+
+	if err := files.DeleteDirectory(ctx, "docs/img"); err != nil {
+		t.Fatalf("DeleteDirectory: %v", err)
+	}
+	_, err = files.Download(ctx, "docs/img/logo.png")
+	var apiErr *storage.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Download deleted file: err = %v", err)
+	}
+
+	wrong := storage.NewFileService(zone.Name, "wrong", storage.RegionNewYork, storage.WithFileBaseURL(srv.StorageURL()))
+	if _, err := wrong.List(ctx, ""); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("List with wrong password: err = %v", err)
+	}
+	readOnly := storage.NewFileService(zone.Name, zone.ReadOnlyPassword, storage.RegionNewYork, storage.WithFileBaseURL(srv.StorageURL()))
+	if _, err := readOnly.List(ctx, ""); err != nil {
+		t.Errorf("List with read-only password: %v", err)
+	}
+	if err := readOnly.Delete(ctx, "docs/readme.txt"); err == nil {
+		t.Error("expected delete with read-only password to fail")
+	}
+}
+
+func TestStreamLibrariesVideosAndCollections(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := stream.NewClient("key", stream.WithBaseAPIURL(srv.URL), stream.WithStreamAPIURL(srv.StreamURL()))
+	lib, err := client.Libraries().Create(ctx, &stream.CreateLibraryRequest{Name: "courses"})
+	if err != nil {
+		t.Fatalf("Create library: %v", err)
+	}
+
+	collection, err := client.Collections(lib.LibraryID).Create(ctx, &stream.CreateCollectionRequest{Name: "intro"})
+	if err != nil {
+		t.Fatalf("Create collection: %v", err)
+	}
+	videos := client.Videos(lib.LibraryID)
+	v, err := videos.Create(ctx, &stream.CreateVideoRequest{Title: "Lesson 1", CollectionID: collection.GUID})
+	if err != nil {
+		t.Fatalf("Create video: %v", err)
+	}
+	if v.State != stream.VideoStateCreated {
+		t.Errorf("state = %s", v.State)
+	}
+	if err := videos.Upload(ctx, v.VideoID, strings.NewReader("mp4 bytes")); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if got, _ := videos.Get(ctx, v.VideoID); got.State != stream.VideoStateFinished {
+		t.Errorf("state after upload = %s", got.State)
+	}
+	if _, err := videos.Create(ctx, &stream.CreateVideoRequest{Title: "Other"}); err != nil {
+		t.Fatalf("Create video: %v", err)
+	}
+
+	resp, err := videos.List(ctx, &stream.VideoListOptions{Collection: collection.GUID})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if resp.TotalItems != 1 || resp.Items[0].VideoID != v.VideoID {
+		t.Errorf("List by collection = %+v", resp)
+	}
+	c, err := client.Collections(lib.LibraryID).Get(ctx, collection.GUID)
+	if err != nil {
+		t.Fatalf("Get collection: %v", err)
+	}
+	if c.VideoCount != 1 || c.TotalSize != int64(len("mp4 bytes")) {
+		t.Errorf("collection = %+v", c)
+	}
+	l, err := client.Libraries().Get(ctx, lib.LibraryID)
+	if err != nil {
+		t.Fatalf("Get library: %v", err)
+	}
+	if l.VideoCount != 2 || l.Collections != 1 {
+		t.Errorf("library = %+v", l)
+	}
+
+	if _, err := client.Videos(999).List(ctx, nil); err == nil {
+		t.Error("expected unknown library to fail")
+	}
+}
+
+func TestStreamLibraryKeys(t *testing.T) {
+	srv := bunnytest.NewServer(bunnytest.WithAccessKey("key"))
+	defer srv.Close()
+	ctx := context.Background()
+
+	opts := []stream.Option{stream.WithBaseAPIURL(srv.URL), stream.WithStreamAPIURL(srv.StreamURL())}
+	lib, err := stream.NewClient("key", opts...).Libraries().Create(ctx, &stream.CreateLibraryRequest{Name: "courses"})
+	if err != nil {
+		t.Fatalf("Create library: %v", err)
+	}
+	if lib.APIKey == "" || lib.ReadOnlyAPIKey == "" {
+		t.Fatalf("library keys = %q, %q", lib.APIKey, lib.ReadOnlyAPIKey)
+	}
+
+	var apiErr *stream.APIError
+	_, err = stream.NewClient("key", opts...).Videos(lib.LibraryID).List(ctx, nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("account key on videos: err = %v", err)
+	}
+	readOnly := stream.NewClient(lib.ReadOnlyAPIKey, opts...).Videos(lib.LibraryID)
+	if _, err := readOnly.List(ctx, nil); err != nil {
+		t.Errorf("read-only key List: %v", err)
+	}
+	if _, err := readOnly.Create(ctx, &stream.CreateVideoRequest{Title: "x"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("read-only key Create: err = %v", err)
+	}
+	if _, err := stream.NewClient(lib.APIKey, opts...).Videos(lib.LibraryID).Create(ctx, &stream.CreateVideoRequest{Title: "x"}); err != nil {
+		t.Errorf("library key Create: %v", err)
+	}
+}
+
+func TestShieldCustomRules(t *testing.T) {
+	srv := bunnytest.NewServer(bunnytest.WithAccessKey("key"))
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := shield.NewClient("key", shield.WithBaseURL(srv.URL))
+	zone, err := client.Zones().Create(ctx, &shield.CreateZoneRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("Create zone: %v", err)
+	}
+	rule, err := client.WAF().CreateCustomRule(ctx, &shield.CreateCustomRuleRequest{Name: "block-admin", Pattern: "/admin", Action: "block", ShieldZoneID: zone.ID, IsActive: true})
+	if err != nil {
+		t.Fatalf("CreateCustomRule: %v", err)
+	}
+	inactive := false
+	updated, err := client.WAF().UpdateCustomRule(ctx, rule.ID, &shield.UpdateCustomRuleRequest{IsActive: &inactive})
+	if err != nil {
+		t.Fatalf("UpdateCustomRule: %v", err)
+	}
+	if updated.IsActive || updated.Pattern != "/admin" {
+		t.Errorf("updated = %+v", updated)
+	}
+	if err := client.WAF().DeleteCustomRule(ctx, rule.ID); err != nil {
+		t.Fatalf("DeleteCustomRule: %v", err)
+	}
+	list, err := client.WAF().ListCustomRules(ctx)
+	if err != nil {
+		t.Fatalf("ListCustomRules: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("rules = %+v", list.Items)
+	}
+
+	_, err = shield.NewClient("other", shield.WithBaseURL(srv.URL)).Zones().List(ctx)
+	var apiErr *shield.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong key: err = %v", err)
+	}
+}
+
+func TestScriptingReleases(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv := bunnytest.NewServer(bunnytest.WithClock(func() time.Time { return now }))
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := scripting.NewClient("key", scripting.WithBaseURL(srv.URL))
+	s, err := client.Scripts().Create(ctx, &scripting.CreateScriptRequest{Name: "router", Code: "v1"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	releases := client.Releases(s.ID)
+	if err := releases.Publish(ctx, &scripting.PublishReleaseRequest{Note: "first"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := client.Code(s.ID).Set(ctx, &scripting.UpdateCodeRequest{Code: "v2"}); err != nil {
+		t.Fatalf("Set code: %v", err)
+	}
+	if err := releases.Publish(ctx, nil); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	active, err := releases.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("GetActive: %v", err)
+	}
+	if *active.Code != "v2" || *active.Status != scripting.ReleaseStatusLive || active.DatePublished != "2026-03-01T12:00:00Z" {
+		t.Errorf("active = %+v", active)
+	}
+	list, err := releases.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if list.TotalItems != 2 || *list.Items[1].Status != scripting.ReleaseStatusArchived || *list.Items[1].Note != "first" {
+		t.Errorf("releases = %+v", list.Items)
+	}
+	got, err := client.Scripts().Get(ctx, s.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.CurrentReleaseID != active.ID {
+		t.Errorf("CurrentReleaseID = %d, want %d", got.CurrentReleaseID, active.ID)
+	}
+}
+
+func TestContainerApps(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	apps := containers.NewClient("key", containers.WithBaseURL(srv.ContainersURL())).Applications()
+	var ids []string
+	for _, name := range []string{"api", "worker", "cron"} {
+		resp, err := apps.Create(ctx, &containers.CreateApplicationRequest{
+			Name:               name,
+			AutoScaling:        containers.AutoScaling{Min: 1, Max: 2},
+			ContainerTemplates: []containers.CreateContainerTemplateRequest{{Name: "app", ImageName: name, ImageTag: "1.0"}},
+		})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids = append(ids, resp.ID)
+	}
+
+	var names []string
+	opts := &containers.ListOptions{Limit: 2}
+	for {
+		resp, err := apps.List(ctx, opts)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, item := range resp.Items {
+			names = append(names, item.Name)
+		}
+		if resp.Cursor == "" {
+			break
+		}
+		opts = &containers.ListOptions{Limit: 2, NextCursor: resp.Cursor}
+	}
+	if strings.Join(names, ",") != "api,worker,cron" {
+		t.Errorf("listed %v", names)
+	}
+
+	if err := apps.Restart(ctx, ids[0]); err == nil {
+		t.Error("expected restart of an undeployed app to fail")
+	}
+	if err := apps.Deploy(ctx, ids[0]); err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+	if _, err := apps.Patch(ctx, ids[0], &containers.PatchApplicationRequest{AutoScaling: &containers.AutoScaling{Min: 2, Max: 5}}); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	app, err := apps.Get(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if app.Status != containers.ApplicationStatusActive || app.AutoScaling.Max != 5 || len(app.ContainerTemplates) != 1 || app.ContainerTemplates[0].ImageName != "api" {
+		t.Errorf("app = %+v", app)
+	}
+}
+
+func TestUnimplementedEndpoint(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+
+	_, err := shield.NewClient("key", shield.WithBaseURL(srv.URL)).Metrics().GetOverview(context.Background(), nil)
+	var apiErr *shield.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,527 @@
+package bunnytest
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func (s *Server) registerShield(mux *http.ServeMux) {
+	mux.HandleFunc("GET /shield/zones", s.api(s.listShieldZones))
+	mux.HandleFunc("POST /shield/zone", s.api(s.createShieldZone))
+	mux.HandleFunc("GET /shield/zone/{id}", s.api(s.getShieldZone))
+	mux.HandleFunc("PATCH /shield/zone/{id}", s.api(s.updateShieldZone))
+
+	mux.HandleFunc("GET /shield/zone/{id}/access-lists", s.api(s.getAccessList))
+	mux.HandleFunc("POST /shield/zone/{id}/access-lists", s.api(s.addAccessListEntry))
+	mux.HandleFunc("PATCH /shield/zone/{id}/access-lists", s.api(s.updateAccessListEntries))
+	mux.HandleFunc("DELETE /shield/zone/{id}/access-lists", s.api(s.deleteAccessListEntries))
+	mux.HandleFunc("GET /shield/zone/{id}/access-lists/enums", s.api(s.getAccessListEnums))
+
+	mux.HandleFunc("GET /shield/waf/custom-rules", s.api(s.listCustomRules))
+	mux.HandleFunc("POST /shield/waf/custom-rule", s.api(s.createCustomRule))
+	mux.HandleFunc("GET /shield/waf/custom-rule/{id}", s.api(s.getCustomRule))
+	mux.HandleFunc("PATCH /shield/waf/custom-rule/{id}", s.api(s.updateCustomRule))
+	mux.HandleFunc("PUT /shield/waf/custom-rule/{id}", s.api(s.replaceCustomRule))
+	mux.HandleFunc("DELETE /shield/waf/custom-rule/{id}", s.api(s.deleteCustomRule))
+	mux.HandleFunc("GET /shield/waf/enums", s.api(s.getWAFEnums))
+
+	mux.HandleFunc("GET /shield/event-logs", s.api(s.listEventLogs))
+
+	mux.HandleFunc("GET /shield/rate-limits", s.api(s.listRateLimits))
+	mux.HandleFunc("POST /shield/rate-limit", s.api(s.createRateLimit))
+	mux.HandleFunc("GET /shield/rate-limit/{id}", s.api(s.getRateLimit))
+	mux.HandleFunc("PATCH /shield/rate-limit/{id}", s.api(s.updateRateLimit))
+	mux.HandleFunc("DELETE /shield/rate-limit/{id}", s.api(s.deleteRateLimit))
+}
+
+func (s *Server) getWAFEnums(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, shield.WAFEnums{
+		RuleActions: []string{
+			string(shield.RuleActionBlock), string(shield.RuleActionAllow),
+			string(shield.RuleActionLog), string(shield.RuleActionChallenge),
+		},
+	})
+}
+
+func (s *Server) lookupShieldZone(w http.ResponseWriter, r *http.Request) *shield.ShieldZone {
+	z, ok := s.shieldZones[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "shield zone %s not found", r.PathValue("id"))
+		return nil
+	}
+	return z
+}
+
+func (s *Server) listShieldZones(w http.ResponseWriter, r *http.Request) {
+	zones := []shield.ShieldZone{}
+	for _, z := range s.shieldZones {
+		zones = append(zones, *z)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+	writeJSON(w, http.StatusOK, shield.ZoneListResponse{Items: zones, TotalCount: len(zones)})
+}
+
+func (s *Server) createShieldZone(w http.ResponseWriter, r *http.Request) {
+	var req shield.CreateZoneRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	z := &shield.ShieldZone{
+		ID:          strconv.FormatInt(s.newID(), 10),
+		Name:        req.Name,
+		HostNames:   req.HostNames,
+		DateCreated: s.dateString(),
+	}
+	s.shieldZones[z.ID] = z
+	writeJSON(w, http.StatusOK, z)
+}
+
+func (s *Server) getShieldZone(w http.ResponseWriter, r *http.Request) {
+	if z := s.lookupShieldZone(w, r); z != nil {
+		writeJSON(w, http.StatusOK, z)
+	}
+}
+
+func (s *Server) updateShieldZone(w http.ResponseWriter, r *http.Request) {
+	z := s.lookupShieldZone(w, r)
+	if z == nil {
+		return
+	}
+	var req shield.UpdateZoneRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		z.Name = req.Name
+	}
+	if req.HostNames != nil {
+		z.HostNames = req.HostNames
+	}
+	writeJSON(w, http.StatusOK, z)
+}
+
+func (s *Server) lookupCustomRule(w http.ResponseWriter, r *http.Request) *shield.CustomRule {
+	rule, ok := s.customRules[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "custom rule %s not found", r.PathValue("id"))
+		return nil
+	}
+	return rule
+}
+
+func (s *Server) listCustomRules(w http.ResponseWriter, r *http.Request) {
+	rules := []shield.CustomRule{}
+	for _, rule := range s.customRules {
+		rules = append(rules, *rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		a, _ := strconv.ParseInt(rules[i].ID, 10, 64)
+		b, _ := strconv.ParseInt(rules[j].ID, 10, 64)
+		return a < b
+	})
+	writeJSON(w, http.StatusOK, shield.CustomRuleListResponse{Items: rules, TotalCount: len(rules)})
+}
+
+func (s *Server) createCustomRule(w http.ResponseWriter, r *http.Request) {
+	var req shield.CreateCustomRuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if !s.validCustomRule(w, req.Name, req.ShieldZoneID) {
+		return
+	}
+	rule := &shield.CustomRule{
+		ID:           strconv.FormatInt(s.newID(), 10),
+		Name:         req.Name,
+		Description:  req.Description,
+		Pattern:      req.Pattern,
+		Action:       req.Action,
+		ShieldZoneID: req.ShieldZoneID,
+		IsActive:     req.IsActive,
+		DateCreated:  s.dateString(),
+	}
+	s.customRules[rule.ID] = rule
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// validCustomRule checks the fields every rule needs, answering 400 when one is missing.
+func (s *Server) validCustomRule(w http.ResponseWriter, name, zoneID string) bool {
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Name is required")
+		return false
+	}
+	if zoneID != "" && s.shieldZones[zoneID] == nil {
+		writeError(w, http.StatusBadRequest, "shield zone %s not found", zoneID)
+		return false
+	}
+	return true
+}
+
+func (s *Server) getCustomRule(w http.ResponseWriter, r *http.Request) {
+	if rule := s.lookupCustomRule(w, r); rule != nil {
+		writeJSON(w, http.StatusOK, rule)
+	}
+}
+
+func (s *Server) updateCustomRule(w http.ResponseWriter, r *http.Request) {
+	rule := s.lookupCustomRule(w, r)
+	if rule == nil {
+		return
+	}
+	var req shield.UpdateCustomRuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.Description != "" {
+		rule.Description = req.Description
+	}
+	if req.Pattern != "" {
+		rule.Pattern = req.Pattern
+	}
+	if req.Action != "" {
+		rule.Action = req.Action
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) replaceCustomRule(w http.ResponseWriter, r *http.Request) {
+	rule := s.lookupCustomRule(w, r)
+	if rule == nil {
+		return
+	}
+	var req shield.ReplaceCustomRuleRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if !s.validCustomRule(w, req.Name, req.ShieldZoneID) {
+		return
+	}
+	*rule = shield.CustomRule{
+		ID:           rule.ID,
+		Name:         req.Name,
+		Description:  req.Description,
+		Pattern:      req.Pattern,
+		Action:       req.Action,
+		ShieldZoneID: req.ShieldZoneID,
+		IsActive:     req.IsActive,
+		DateCreated:  rule.DateCreated,
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) deleteCustomRule(w http.ResponseWriter, r *http.Request) {
+	if rule := s.lookupCustomRule(w, r); rule != nil {
+		delete(s.customRules, rule.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) lookupRateLimit(w http.ResponseWriter, r *http.Request) *shield.RateLimit {
+	limit, ok := s.rateLimits[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "rate limit %s not found", r.PathValue("id"))
+		return nil
+	}
+	return limit
+}
+
+func (s *Server) listRateLimits(w http.ResponseWriter, r *http.Request) {
+	limits := []shield.RateLimit{}
+	for _, limit := range s.rateLimits {
+		limits = append(limits, *limit)
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		a, _ := strconv.ParseInt(limits[i].ID, 10, 64)
+		b, _ := strconv.ParseInt(limits[j].ID, 10, 64)
+		return a < b
+	})
+	writeJSON(w, http.StatusOK, shield.RateLimitListResponse{Items: limits, TotalCount: len(limits)})
+}
+
+func (s *Server) createRateLimit(w http.ResponseWriter, r *http.Request) {
+	var req shield.CreateRateLimitRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if !s.validCustomRule(w, req.Name, req.ShieldZoneID) {
+		return
+	}
+	limit := &shield.RateLimit{
+		ID:                strconv.FormatInt(s.newID(), 10),
+		Name:              req.Name,
+		Path:              req.Path,
+		RequestsPerSecond: req.RequestsPerSecond,
+		RequestsPerMinute: req.RequestsPerMinute,
+		Action:            req.Action,
+		ShieldZoneID:      req.ShieldZoneID,
+		IsActive:          req.IsActive,
+		DateCreated:       s.dateString(),
+	}
+	s.rateLimits[limit.ID] = limit
+	writeJSON(w, http.StatusOK, limit)
+}
+
+func (s *Server) getRateLimit(w http.ResponseWriter, r *http.Request) {
+	if limit := s.lookupRateLimit(w, r); limit != nil {
+		writeJSON(w, http.StatusOK, limit)
+	}
+}
+
+func (s *Server) updateRateLimit(w http.ResponseWriter, r *http.Request) {
+	limit := s.lookupRateLimit(w, r)
+	if limit == nil {
+		return
+	}
+	var req shield.UpdateRateLimitRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		limit.Name = req.Name
+	}
+	if req.Path != "" {
+		limit.Path = req.Path
+	}
+	if req.RequestsPerSecond != nil {
+		limit.RequestsPerSecond = *req.RequestsPerSecond
+	}
+	if req.RequestsPerMinute != nil {
+		limit.RequestsPerMinute = *req.RequestsPerMinute
+	}
+	if req.Action != "" {
+		limit.Action = req.Action
+	}
+	if req.IsActive != nil {
+		limit.IsActive = *req.IsActive
+	}
+	writeJSON(w, http.StatusOK, limit)
+}
+
+func (s *Server) deleteRateLimit(w http.ResponseWriter, r *http.Request) {
+	if limit := s.lookupRateLimit(w, r); limit != nil {
+		delete(s.rateLimits, limit.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+var (
+	accessListTypes = []string{
+		shield.AccessListTypeIP, shield.AccessListTypeCIDR, shield.AccessListTypeASN, shield.AccessListTypeCountry,
+	}
+	accessListActions = []string{
+		shield.AccessListActionAllow, shield.AccessListActionBlock, shield.AccessListActionChallenge,
+	}
+)
+
+func (s *Server) getAccessListEnums(w http.ResponseWriter, r *http.Request) {
+	if s.lookupShieldZone(w, r) == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, shield.AccessListEnums{
+		Types:                accessListTypes,
+		Actions:              accessListActions,
+		MaxEntries:           s.accessListMax,
+		MaxEntriesPerRequest: s.accessListMaxRequest,
+	})
+}
+
+func (s *Server) getAccessList(w http.ResponseWriter, r *http.Request) {
+	z := s.lookupShieldZone(w, r)
+	if z == nil {
+		return
+	}
+	list := shield.AccessList{
+		Allowed:    []shield.AccessListEntry{},
+		Blocked:    []shield.AccessListEntry{},
+		Challenged: []shield.AccessListEntry{},
+	}
+	for _, e := range s.accessLists[z.ID] {
+		switch e.Action {
+		case shield.AccessListActionAllow:
+			list.Allowed = append(list.Allowed, e)
+		case shield.AccessListActionBlock:
+			list.Blocked = append(list.Blocked, e)
+		default:
+			list.Challenged = append(list.Challenged, e)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// findAccessEntry returns the index of the zone's entry with the given type
+// and value, or -1.
+func (s *Server) findAccessEntry(zoneID, typ, value string) int {
+	return slices.IndexFunc(s.accessLists[zoneID], func(e shield.AccessListEntry) bool {
+		return strings.EqualFold(e.Type, typ) && e.Value == value
+	})
+}
+
+// canonicalEnum returns the spelling of v in allowed, matched case-insensitively.
+func canonicalEnum(allowed []string, v string) (string, bool) {
+	i := slices.IndexFunc(allowed, func(a string) bool { return strings.EqualFold(a, v) })
+	if i < 0 {
+		return "", false
+	}
+	return allowed[i], true
+}
+
+func (s *Server) addAccessListEntry(w http.ResponseWriter, r *http.Request) {
+	z := s.lookupShieldZone(w, r)
+	if z == nil {
+		return
+	}
+	var req shield.AddAccessListEntryRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	typ, ok := canonicalEnum(accessListTypes, req.Type)
+	if !ok || req.Value == "" {
+		writeError(w, http.StatusBadRequest, "invalid access list entry %s %q", req.Type, req.Value)
+		return
+	}
+	action, ok := canonicalEnum(accessListActions, req.Action)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid access list action %q", req.Action)
+		return
+	}
+	if s.findAccessEntry(z.ID, typ, req.Value) >= 0 {
+		writeError(w, http.StatusConflict, "access list entry %s already exists", req.Value)
+		return
+	}
+	if s.accessListMax > 0 && len(s.accessLists[z.ID]) >= s.accessListMax {
+		writeError(w, http.StatusBadRequest, "access list is limited to %d entries", s.accessListMax)
+		return
+	}
+	e := shield.AccessListEntry{Type: typ, Value: req.Value, Action: action, Comment: req.Comment, DateAdded: s.dateString()}
+	s.accessLists[z.ID] = append(s.accessLists[z.ID], e)
+	writeJSON(w, http.StatusOK, e)
+}
+
+// batchAllowed answers 400 when a batch request carries more entries than allowed.
+func (s *Server) batchAllowed(w http.ResponseWriter, n int) bool {
+	if s.accessListMaxRequest > 0 && n > s.accessListMaxRequest {
+		writeError(w, http.StatusBadRequest, "at most %d entries per request", s.accessListMaxRequest)
+		return false
+	}
+	return true
+}
+
+func (s *Server) updateAccessListEntries(w http.ResponseWriter, r *http.Request) {
+	z := s.lookupShieldZone(w, r)
+	if z == nil {
+		return
+	}
+	var req shield.UpdateAccessListEntriesRequest
+	if !decode(w, r, &req) || !s.batchAllowed(w, len(req.Updates)) {
+		return
+	}
+	for _, u := range req.Updates {
+		i := s.findAccessEntry(z.ID, u.Type, u.Value)
+		if i < 0 {
+			writeError(w, http.StatusNotFound, "access list entry %s not found", u.Value)
+			return
+		}
+		if u.Action != "" {
+			if _, ok := canonicalEnum(accessListActions, u.Action); !ok {
+				writeError(w, http.StatusBadRequest, "invalid access list action %q", u.Action)
+				return
+			}
+		}
+	}
+	for _, u := range req.Updates {
+		e := &s.accessLists[z.ID][s.findAccessEntry(z.ID, u.Type, u.Value)]
+		if u.Action != "" {
+			e.Action, _ = canonicalEnum(accessListActions, u.Action)
+		}
+		if u.Comment != "" {
+			e.Comment = u.Comment
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteAccessListEntries(w http.ResponseWriter, r *http.Request) {
+	z := s.lookupShieldZone(w, r)
+	if z == nil {
+		return
+	}
+	var req shield.DeleteAccessListEntriesRequest
+	if !decode(w, r, &req) || !s.batchAllowed(w, len(req.Entries)) {
+		return
+	}
+	for _, id := range req.Entries {
+		if i := s.findAccessEntry(z.ID, id.Type, id.Value); i >= 0 {
+			s.accessLists[z.ID] = slices.Delete(s.accessLists[z.ID], i, i+1)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddEventLogs records security events for the event log endpoint. Events
+// without an ID get one, and events without a timestamp get the current time.
+func (s *Server) AddEventLogs(events ...shield.EventLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range events {
+		if e.ID == "" {
+			e.ID = strconv.FormatInt(s.newID(), 10)
+		}
+		if e.Timestamp == "" {
+			e.Timestamp = s.dateString()
+		}
+		s.eventLogs = append(s.eventLogs, e)
+	}
+}
+
+// listEventLogs answers newest first. From and To are inclusive and accept
+// RFC 3339 timestamps or plain dates.
+func (s *Server) listEventLogs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, okFrom := parseQueryTime(q.Get("from"))
+	to, okTo := parseQueryTime(q.Get("to"))
+	events := []shield.EventLog{}
+	for _, e := range s.eventLogs {
+		at, _ := parseQueryTime(e.Timestamp)
+		if zone := q.Get("zoneId"); zone != "" && e.ZoneID != zone ||
+			okFrom && at.Before(from) || okTo && at.After(to) {
+			continue
+		}
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		a, _ := parseQueryTime(events[i].Timestamp)
+		b, _ := parseQueryTime(events[j].Timestamp)
+		return a.After(b)
+	})
+
+	total := len(events)
+	offset := min(queryInt(r, "offset", 0), total)
+	limit := queryInt(r, "limit", 100)
+	events = events[offset:min(offset+limit, total)]
+	writeJSON(w, http.StatusOK, shield.EventLogListResponse{Items: events, TotalCount: total})
+}
+
+func parseQueryTime(v string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,323 @@
+package bunnytest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+type storedFile struct {
+	guid        string
+	data        []byte
+	contentType string
+	created     internal.BunnyTime
+	changed     internal.BunnyTime
+}
+
+func (s *Server) registerStorage(mux *http.ServeMux) {
+	mux.HandleFunc("GET /storagezone", s.api(s.listStorageZones))
+	mux.HandleFunc("POST /storagezone", s.api(s.createStorageZone))
+	mux.HandleFunc("GET /storagezone/{id}", s.api(s.getStorageZone))
+	mux.HandleFunc("POST /storagezone/{id}", s.api(s.updateStorageZone))
+	mux.HandleFunc("DELETE /storagezone/{id}", s.api(s.deleteStorageZone))
+	mux.HandleFunc("GET /storagezone/checkavailability/{name}", s.api(s.checkStorageZoneName))
+	mux.HandleFunc("POST /storagezone/{id}/resetPassword", s.api(s.resetStorageZonePassword))
+	mux.HandleFunc("POST /storagezone/{id}/resetReadOnlyPassword", s.api(s.resetStorageZoneReadOnlyPassword))
+
+	mux.HandleFunc("PUT "+storagePrefix+"/{zone}/{path...}", s.storageFiles(s.putFile, false))
+	mux.HandleFunc("GET "+storagePrefix+"/{zone}/{path...}", s.storageFiles(s.getFile, true))
+	mux.HandleFunc("DELETE "+storagePrefix+"/{zone}/{path...}", s.storageFiles(s.deleteFile, false))
+}
+
+// storageFiles wraps a file endpoint with zone password authentication.
+// Read-only endpoints also accept the zone's read-only password.
+func (s *Server) storageFiles(h func(w http.ResponseWriter, r *http.Request, zone *storage.Zone), readOnly bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		zone := s.storageZoneByName(r.PathValue("zone"))
+		key := r.Header.Get("AccessKey")
+		if zone == nil || key == "" || (key != zone.Password && !(readOnly && key == zone.ReadOnlyPassword)) {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		h(w, r, zone)
+	}
+}
+
+func (s *Server) storageZoneByName(name string) *storage.Zone {
+	for _, z := range s.storageZones {
+		if z.Name == name {
+			return z
+		}
+	}
+	return nil
+}
+
+// storageZone returns a copy of the zone with its usage totals filled in.
+func (s *Server) storageZone(z *storage.Zone) storage.Zone {
+	out := *z
+	out.ReplicationRegions = append([]string(nil), z.ReplicationRegions...)
+	out.FilesStored, out.StorageUsed = 0, 0
+	for _, f := range s.files[z.Name] {
+		out.FilesStored++
+		out.StorageUsed += int64(len(f.data))
+	}
+	return out
+}
+
+func (s *Server) lookupStorageZone(w http.ResponseWriter, r *http.Request) *storage.Zone {
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return nil
+	}
+	z, ok := s.storageZones[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "storage zone %d not found", id)
+		return nil
+	}
+	return z
+}
+
+func (s *Server) listStorageZones(w http.ResponseWriter, r *http.Request) {
+	search := strings.ToLower(r.URL.Query().Get("search"))
+	var zones []storage.Zone
+	for _, z := range s.storageZones {
+		if search == "" || strings.Contains(strings.ToLower(z.Name), search) {
+			zones = append(zones, s.storageZone(z))
+		}
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].ID < zones[j].ID })
+
+	p, perPage := queryInt(r, "page", 1), queryInt(r, "perPage", 1000)
+	writeJSON(w, http.StatusOK, storage.ZoneListResponse{
+		Items:       page(zones, p, perPage),
+		TotalItems:  len(zones),
+		CurrentPage: p,
+		PageSize:    perPage,
+	})
+}
+
+func (s *Server) createStorageZone(w http.ResponseWriter, r *http.Request) {
+	var req storage.CreateZoneRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+	if s.storageZoneByName(req.Name) != nil {
+		writeError(w, http.StatusBadRequest, "storage zone name %q is already taken", req.Name)
+		return
+	}
+	region := req.Region
+	if region == "" {
+		region = "DE"
+	}
+	z := &storage.Zone{
+		ID:                 s.newID(),
+		Name:               req.Name,
+		Password:           newUUID(),
+		ReadOnlyPassword:   newUUID(),
+		Region:             strings.ToUpper(region),
+		ReplicationRegions: req.ReplicationRegions,
+		OriginURL:          req.OriginURL,
+		DateModified:       s.timestamp(),
+	}
+	s.storageZones[z.ID] = z
+	s.files[z.Name] = make(map[string]*storedFile)
+	writeJSON(w, http.StatusCreated, s.storageZone(z))
+}
+
+func (s *Server) getStorageZone(w http.ResponseWriter, r *http.Request) {
+	if z := s.lookupStorageZone(w, r); z != nil {
+		writeJSON(w, http.StatusOK, s.storageZone(z))
+	}
+}
+
+func (s *Server) updateStorageZone(w http.ResponseWriter, r *http.Request) {
+	z := s.lookupStorageZone(w, r)
+	if z == nil {
+		return
+	}
+	var req storage.UpdateZoneRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.ReplicationRegions != nil {
+		z.ReplicationRegions = req.ReplicationRegions
+	}
+	if req.OriginURL != "" {
+		z.OriginURL = req.OriginURL
+	}
+	if req.Custom404FilePath != "" {
+		z.Custom404FilePath = req.Custom404FilePath
+	}
+	if req.Rewrite404To200 != nil {
+		z.Rewrite404To200 = *req.Rewrite404To200
+	}
+	z.DateModified = s.timestamp()
+	writeJSON(w, http.StatusOK, s.storageZone(z))
+}
+
+func (s *Server) deleteStorageZone(w http.ResponseWriter, r *http.Request) {
+	z := s.lookupStorageZone(w, r)
+	if z == nil {
+		return
+	}
+	delete(s.storageZones, z.ID)
+	delete(s.files, z.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) checkStorageZoneName(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	writeJSON(w, http.StatusOK, storage.AvailabilityResponse{Available: s.storageZoneByName(name) == nil, Name: name})
+}
+
+func (s *Server) resetStorageZonePassword(w http.ResponseWriter, r *http.Request) {
+	if z := s.lookupStorageZone(w, r); z != nil {
+		z.Password = newUUID()
+		writeJSON(w, http.StatusOK, storage.ResetPasswordResponse{ID: z.ID, Password: z.Password, Success: true})
+	}
+}
+
+func (s *Server) resetStorageZoneReadOnlyPassword(w http.ResponseWriter, r *http.Request) {
+	if z := s.lookupStorageZone(w, r); z != nil {
+		z.ReadOnlyPassword = newUUID()
+		writeJSON(w, http.StatusOK, storage.ResetReadOnlyPasswordResponse{ID: z.ID, ReadOnlyPassword: z.ReadOnlyPassword, Success: true})
+	}
+}
+
+func (s *Server) putFile(w http.ResponseWriter, r *http.Request, zone *storage.Zone) {
+	p := r.PathValue("path")
+	if p == "" || strings.HasSuffix(p, "/") {
+		writeError(w, http.StatusBadRequest, "a file name is required")
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "read body: %v", err)
+		return
+	}
+	if want := r.Header.Get("Checksum"); want != "" {
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(want, hex.EncodeToString(sum[:])) {
+			writeError(w, http.StatusBadRequest, "checksum mismatch")
+			return
+		}
+	}
+
+	now := s.timestamp()
+	files := s.files[zone.Name]
+	f, ok := files[p]
+	if !ok {
+		f = &storedFile{guid: newUUID(), created: now}
+		files[p] = f
+	}
+	f.data, f.contentType, f.changed = data, r.Header.Get("Content-Type"), now
+	writeJSON(w, http.StatusCreated, map[string]any{"HttpCode": http.StatusCreated, "Message": "File uploaded."})
+}
+
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request, zone *storage.Zone) {
+	p := r.PathValue("path")
+	if p == "" || strings.HasSuffix(p, "/") {
+		s.listFiles(w, zone, strings.TrimSuffix(p, "/"))
+		return
+	}
+	f, ok := s.files[zone.Name][p]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Object Not Found")
+		return
+	}
+	if f.contentType != "" {
+		w.Header().Set("Content-Type", f.contentType)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(f.data)
+}
+
+// listFiles writes the direct children of dir, synthesizing directory entries.
+func (s *Server) listFiles(w http.ResponseWriter, zone *storage.Zone, dir string) {
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	entryPath := "/" + zone.Name + "/" + prefix
+
+	out := []storage.File{}
+	seenDirs := make(map[string]bool)
+	for p, f := range s.files[zone.Name] {
+		rest, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+		if name, _, isDir := strings.Cut(rest, "/"); isDir {
+			if !seenDirs[name] {
+				seenDirs[name] = true
+				out = append(out, storage.File{
+					GUID:            newUUID(),
+					StorageZoneName: zone.Name,
+					Path:            entryPath,
+					ObjectName:      name,
+					IsDirectory:     true,
+					LastChanged:     f.changed,
+					DateCreated:     f.created,
+					StorageZoneID:   zone.ID,
+				})
+			}
+			continue
+		}
+		out = append(out, storage.File{
+			GUID:            f.guid,
+			StorageZoneName: zone.Name,
+			Path:            entryPath,
+			ObjectName:      rest,
+			Length:          int64(len(f.data)),
+			LastChanged:     f.changed,
+			DateCreated:     f.created,
+			StorageZoneID:   zone.ID,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ObjectName < out[j].ObjectName })
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request, zone *storage.Zone) {
+	p := r.PathValue("path")
+	files := s.files[zone.Name]
+	if p == "" || strings.HasSuffix(p, "/") {
+		prefix := path.Clean(p) + "/"
+		if p == "" || prefix == "./" {
+			prefix = ""
+		}
+		deleted := 0
+		for name := range files {
+			if strings.HasPrefix(name, prefix) {
+				delete(files, name)
+				deleted++
+			}
+		}
+		if deleted == 0 {
+			writeError(w, http.StatusNotFound, "Object Not Found")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if _, ok := files[p]; !ok {
+		writeError(w, http.StatusNotFound, "Object Not Found")
+		return
+	}
+	delete(files, p)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,392 @@
+package bunnytest
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+type library struct {
+	lib         stream.Library
+	videos      map[string]*video
+	collections map[string]*stream.Collection
+}
+
+type video struct {
+	stream.Video
+	size int64
+}
+
+func (s *Server) registerStream(mux *http.ServeMux) {
+	mux.HandleFunc("GET /videolibrary", s.api(s.listLibraries))
+	mux.HandleFunc("POST /videolibrary", s.api(s.createLibrary))
+	mux.HandleFunc("GET /videolibrary/{id}", s.api(s.getLibrary))
+	mux.HandleFunc("POST /videolibrary/{id}", s.api(s.updateLibrary))
+	mux.HandleFunc("DELETE /videolibrary/{id}", s.api(s.deleteLibrary))
+
+	videos := streamPrefix + "/library/{library}/videos"
+	mux.HandleFunc("GET "+videos, s.libraryAPI(s.listVideos))
+	mux.HandleFunc("POST "+videos, s.libraryAPI(s.createVideo))
+	mux.HandleFunc("GET "+videos+"/{id}", s.libraryAPI(s.getVideo))
+	mux.HandleFunc("POST "+videos+"/{id}", s.libraryAPI(s.updateVideo))
+	mux.HandleFunc("PUT "+videos+"/{id}", s.libraryAPI(s.uploadVideo))
+	mux.HandleFunc("DELETE "+videos+"/{id}", s.libraryAPI(s.deleteVideo))
+
+	collections := streamPrefix + "/library/{library}/collections"
+	mux.HandleFunc("GET "+collections, s.libraryAPI(s.listCollections))
+	mux.HandleFunc("POST "+collections, s.libraryAPI(s.createCollection))
+	mux.HandleFunc("GET "+collections+"/{id}", s.libraryAPI(s.getCollection))
+	mux.HandleFunc("POST "+collections+"/{id}", s.libraryAPI(s.updateCollection))
+	mux.HandleFunc("DELETE "+collections+"/{id}", s.libraryAPI(s.deleteCollection))
+}
+
+// libraryAPI wraps a video or collection endpoint. When an access key is
+// configured, requests must carry the library's API key, or its read-only
+// key for GET requests, as the real Stream API requires.
+func (s *Server) libraryAPI(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.accessKey != "" {
+			id, _ := strconv.ParseInt(r.PathValue("library"), 10, 64)
+			if l, ok := s.libraries[id]; ok {
+				key := r.Header.Get("AccessKey")
+				if key == "" || (key != l.lib.APIKey && !(r.Method == http.MethodGet && key == l.lib.ReadOnlyAPIKey)) {
+					writeError(w, http.StatusUnauthorized, "invalid library AccessKey")
+					return
+				}
+			}
+		}
+		h(w, r)
+	}
+}
+
+// snapshot returns a copy of the library with its counters filled in.
+func (l *library) snapshot() stream.Library {
+	out := l.lib
+	out.ReplicationRegions = append([]string(nil), l.lib.ReplicationRegions...)
+	out.VideoCount, out.Collections, out.StorageUsed = len(l.videos), len(l.collections), 0
+	for _, v := range l.videos {
+		out.StorageUsed += v.size
+	}
+	return out
+}
+
+// collection returns a copy of the collection with its counters filled in.
+func (l *library) collection(c *stream.Collection) stream.Collection {
+	out := *c
+	out.VideoCount, out.TotalSize = 0, 0
+	for _, v := range l.videos {
+		if v.CollectionID == c.GUID {
+			out.VideoCount++
+			out.TotalSize += v.size
+		}
+	}
+	return out
+}
+
+func (s *Server) lookupLibrary(w http.ResponseWriter, r *http.Request, param string) *library {
+	id, ok := pathInt(w, r, param)
+	if !ok {
+		return nil
+	}
+	l, ok := s.libraries[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "video library %d not found", id)
+		return nil
+	}
+	return l
+}
+
+func (s *Server) listLibraries(w http.ResponseWriter, r *http.Request) {
+	libs := []stream.Library{}
+	for _, l := range s.libraries {
+		libs = append(libs, l.snapshot())
+	}
+	sort.Slice(libs, func(i, j int) bool { return libs[i].LibraryID < libs[j].LibraryID })
+
+	p, perPage := queryInt(r, "page", 1), queryInt(r, "itemsPerPage", 100)
+	writeJSON(w, http.StatusOK, stream.LibraryListResponse{
+		ItemsPerPage: perPage,
+		CurrentPage:  p,
+		TotalItems:   len(libs),
+		Items:        page(libs, p, perPage),
+	})
+}
+
+func (s *Server) createLibrary(w http.ResponseWriter, r *http.Request) {
+	var req stream.CreateLibraryRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	l := &library{
+		lib: stream.Library{
+			LibraryID:                s.newID(),
+			Name:                     req.Name,
+			DateCreated:              s.timestamp(),
+			VideoCacheExpirationDays: req.VideoCacheExpirationDays,
+			APIKey:                   newUUID(),
+			ReadOnlyAPIKey:           newUUID(),
+		},
+		videos:      make(map[string]*video),
+		collections: make(map[string]*stream.Collection),
+	}
+	s.libraries[l.lib.LibraryID] = l
+	writeJSON(w, http.StatusCreated, l.snapshot())
+}
+
+func (s *Server) getLibrary(w http.ResponseWriter, r *http.Request) {
+	if l := s.lookupLibrary(w, r, "id"); l != nil {
+		writeJSON(w, http.StatusOK, l.snapshot())
+	}
+}
+
+func (s *Server) updateLibrary(w http.ResponseWriter, r *http.Request) {
+	l := s.lookupLibrary(w, r, "id")
+	if l == nil {
+		return
+	}
+	var req stream.UpdateLibraryRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		l.lib.Name = req.Name
+	}
+	if req.VideoCacheExpirationDays != nil {
+		l.lib.VideoCacheExpirationDays = *req.VideoCacheExpirationDays
+	}
+	writeJSON(w, http.StatusOK, l.snapshot())
+}
+
+func (s *Server) deleteLibrary(w http.ResponseWriter, r *http.Request) {
+	if l := s.lookupLibrary(w, r, "id"); l != nil {
+		delete(s.libraries, l.lib.LibraryID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) lookupVideo(w http.ResponseWriter, r *http.Request) (*library, *video) {
+	l := s.lookupLibrary(w, r, "library")
+	if l == nil {
+		return nil, nil
+	}
+	v, ok := l.videos[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "video %s not found", r.PathValue("id"))
+		return nil, nil
+	}
+	return l, v
+}
+
+func (s *Server) listVideos(w http.ResponseWriter, r *http.Request) {
+	l := s.lookupLibrary(w, r, "library")
+	if l == nil {
+		return
+	}
+	q := r.URL.Query()
+	search, collection := strings.ToLower(q.Get("search")), q.Get("collection")
+	videos := []stream.Video{}
+	for _, v := range l.videos {
+		if search != "" && !strings.Contains(strings.ToLower(v.Title), search) {
+			continue
+		}
+		if collection != "" && v.CollectionID != collection {
+			continue
+		}
+		videos = append(videos, v.Video)
+	}
+	sort.Slice(videos, func(i, j int) bool {
+		if !videos[i].UploadDate.Equal(videos[j].UploadDate.Time) {
+			return videos[i].UploadDate.After(videos[j].UploadDate.Time)
+		}
+		return videos[i].VideoID < videos[j].VideoID
+	})
+
+	p, perPage := queryInt(r, "page", 1), queryInt(r, "itemsPerPage", 100)
+	writeJSON(w, http.StatusOK, stream.VideoListResponse{
+		ItemsPerPage: perPage,
+		CurrentPage:  p,
+		TotalItems:   len(videos),
+		Items:        page(videos, p, perPage),
+	})
+}
+
+func (s *Server) createVideo(w http.ResponseWriter, r *http.Request) {
+	l := s.lookupLibrary(w, r, "library")
+	if l == nil {
+		return
+	}
+	var req stream.CreateVideoRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+	if req.CollectionID != "" && l.collections[req.CollectionID] == nil {
+		writeError(w, http.StatusBadRequest, "collection %s not found", req.CollectionID)
+		return
+	}
+	v := &video{Video: stream.Video{
+		VideoID:        newUUID(),
+		VideoLibraryID: l.lib.LibraryID,
+		Title:          req.Title,
+		UploadDate:     s.timestamp(),
+		State:          stream.VideoStateCreated,
+		CollectionID:   req.CollectionID,
+	}}
+	l.videos[v.VideoID] = v
+	writeJSON(w, http.StatusOK, v.Video)
+}
+
+func (s *Server) getVideo(w http.ResponseWriter, r *http.Request) {
+	if _, v := s.lookupVideo(w, r); v != nil {
+		writeJSON(w, http.StatusOK, v.Video)
+	}
+}
+
+func (s *Server) updateVideo(w http.ResponseWriter, r *http.Request) {
+	l, v := s.lookupVideo(w, r)
+	if v == nil {
+		return
+	}
+	var req stream.UpdateVideoRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.CollectionID != "" && l.collections[req.CollectionID] == nil {
+		writeError(w, http.StatusBadRequest, "collection %s not found", req.CollectionID)
+		return
+	}
+	if req.Title != "" {
+		v.Title = req.Title
+	}
+	if req.CollectionID != "" {
+		v.CollectionID = req.CollectionID
+	}
+	writeJSON(w, http.StatusOK, v.Video)
+}
+
+// uploadVideo stores the upload size and marks the video as encoded immediately.
+func (s *Server) uploadVideo(w http.ResponseWriter, r *http.Request) {
+	_, v := s.lookupVideo(w, r)
+	if v == nil {
+		return
+	}
+	n, err := io.Copy(io.Discard, r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "read body: %v", err)
+		return
+	}
+	if n == 0 {
+		writeError(w, http.StatusBadRequest, "empty upload")
+		return
+	}
+	v.size = n
+	v.State = stream.VideoStateFinished
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "message": "OK", "statusCode": http.StatusOK})
+}
+
+func (s *Server) deleteVideo(w http.ResponseWriter, r *http.Request) {
+	if l, v := s.lookupVideo(w, r); v != nil {
+		delete(l.videos, v.VideoID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) lookupCollection(w http.ResponseWriter, r *http.Request) (*library, *stream.Collection) {
+	l := s.lookupLibrary(w, r, "library")
+	if l == nil {
+		return nil, nil
+	}
+	c, ok := l.collections[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "collection %s not found", r.PathValue("id"))
+		return nil, nil
+	}
+	return l, c
+}
+
+func (s *Server) listCollections(w http.ResponseWriter, r *http.Request) {
+	l := s.lookupLibrary(w, r, "library")
+	if l == nil {
+		return
+	}
+	collections := []stream.Collection{}
+	for _, c := range l.collections {
+		collections = append(collections, l.collection(c))
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+
+	p, perPage := queryInt(r, "page", 1), queryInt(r, "itemsPerPage", 100)
+	writeJSON(w, http.StatusOK, stream.CollectionListResponse{
+		ItemsPerPage: perPage,
+		CurrentPage:  p,
+		TotalItems:   len(collections),
+		Items:        page(collections, p, perPage),
+	})
+}
+
+func (s *Server) createCollection(w http.ResponseWriter, r *http.Request) {
+	l := s.lookupLibrary(w, r, "library")
+	if l == nil {
+		return
+	}
+	var req stream.CreateCollectionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	c := &stream.Collection{VideoLibraryID: l.lib.LibraryID, GUID: newUUID(), Name: req.Name}
+	l.collections[c.GUID] = c
+	writeJSON(w, http.StatusOK, l.collection(c))
+}
+
+func (s *Server) getCollection(w http.ResponseWriter, r *http.Request) {
+	if l, c := s.lookupCollection(w, r); c != nil {
+		writeJSON(w, http.StatusOK, l.collection(c))
+	}
+}
+
+func (s *Server) updateCollection(w http.ResponseWriter, r *http.Request) {
+	l, c := s.lookupCollection(w, r)
+	if c == nil {
+		return
+	}
+	var req stream.UpdateCollectionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		c.Name = req.Name
+	}
+	writeJSON(w, http.StatusOK, l.collection(c))
+}
+
+// deleteCollection removes the collection; its videos stay in the library.
+func (s *Server) deleteCollection(w http.ResponseWriter, r *http.Request) {
+	l, c := s.lookupCollection(w, r)
+	if c == nil {
+		return
+	}
+	for _, v := range l.videos {
+		if v.CollectionID == c.GUID {
+			v.CollectionID = ""
+		}
+	}
+	delete(l.collections, c.GUID)
+	w.WriteHeader(http.StatusNoContent)
+}
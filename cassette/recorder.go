@@ -0,0 +1,293 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrNoMatch is returned in ModeReplayOnly when no unused interaction matches a request.
+var ErrNoMatch = errors.New("cassette: no matching interaction")
+
+// Mode selects whether requests reach the network.
+type Mode int
+
+const (
+	// ModeReplayOrRecord replays matching interactions and records requests
+	// that have no match. This is the default.
+	ModeReplayOrRecord Mode = iota
+	// ModeReplayOnly replays matching interactions and fails with ErrNoMatch
+	// for anything else. Nothing reaches the network.
+	ModeReplayOnly
+	// ModeRecord sends every request to the network and replaces the cassette.
+	ModeRecord
+)
+
+// Match selects the request attributes compared during replay.
+type Match uint8
+
+const (
+	MatchMethod Match = 1 << iota
+	MatchPath
+	MatchQuery
+	MatchBody
+
+	// MatchDefault compares method, path and query.
+	MatchDefault = MatchMethod | MatchPath | MatchQuery
+)
+
+// HTTPClient is the interface the recorder wraps and implements.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Recorder is an HTTPClient that records and replays interactions.
+type Recorder struct {
+	path     string
+	mode     Mode
+	match    Match
+	client   HTTPClient
+	redactor *redactor
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	dirty    bool
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithMode sets the recording mode.
+func WithMode(m Mode) Option {
+	return func(r *Recorder) {
+		r.mode = m
+	}
+}
+
+// WithMatch sets the request attributes compared during replay.
+func WithMatch(m Match) Option {
+	return func(r *Recorder) {
+		r.match = m
+	}
+}
+
+// WithHTTPClient sets the client used to reach the network. Defaults to http.DefaultClient.
+func WithHTTPClient(hc HTTPClient) Option {
+	return func(r *Recorder) {
+		r.client = hc
+	}
+}
+
+// WithRedactedHeaders adds header names whose values are replaced before saving.
+func WithRedactedHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		for _, n := range names {
+			r.redactor.headers[http.CanonicalHeaderKey(n)] = true
+		}
+	}
+}
+
+// WithRedactedFields adds JSON field names, matched case-insensitively at any
+// depth, whose values are replaced in request and response bodies before saving.
+func WithRedactedFields(names ...string) Option {
+	return func(r *Recorder) {
+		for _, n := range names {
+			r.redactor.fields[strings.ToLower(n)] = true
+		}
+	}
+}
+
+// New returns a Recorder for the cassette at path. The file may be missing
+// unless the mode is ModeReplayOnly. In ModeRecord an existing cassette is
+// ignored and overwritten by Save.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:     path,
+		match:    MatchDefault,
+		client:   http.DefaultClient,
+		redactor: newRedactor(),
+		cassette: &Cassette{Version: FormatVersion},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode != ModeRecord {
+		c, err := Load(path)
+		switch {
+		case err == nil:
+			r.cassette = c
+		case errors.Is(err, fs.ErrNotExist) && r.mode == ModeReplayOrRecord:
+		default:
+			return nil, err
+		}
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Do replays a recorded response or performs and records the request,
+// depending on the mode.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.mode != ModeRecord {
+		if i := r.find(req, body); i >= 0 {
+			r.used[i] = true
+			resp := r.cassette.Interactions[i].Response
+			r.mu.Unlock()
+			return resp.toHTTP(req), nil
+		}
+		if r.mode == ModeReplayOnly {
+			r.mu.Unlock()
+			return nil, fmt.Errorf("%w for %s %s", ErrNoMatch, req.Method, req.URL.RequestURI())
+		}
+	}
+	r.mu.Unlock()
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: r.redactor.header(req.Header),
+			Body:    r.redactor.body(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    r.redactor.header(resp.Header),
+			Body:       r.redactor.body(respBody),
+		},
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.used = append(r.used, true)
+	r.dirty = true
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Save writes the cassette if anything was recorded.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.dirty {
+		return nil
+	}
+	if err := r.cassette.Save(r.path); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+// Unused returns the recorded interactions that have not been replayed,
+// which usually means the code under test stopped making a call.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Interaction
+	for i, used := range r.used {
+		if !used {
+			out = append(out, r.cassette.Interactions[i])
+		}
+	}
+	return out
+}
+
+// find returns the first unused interaction matching the request, or -1.
+// Interactions are replayed once each and in recorded order, so repeated
+// calls such as status polling see the same sequence of responses.
+func (r *Recorder) find(req *http.Request, body []byte) int {
+	for i, in := range r.cassette.Interactions {
+		if !r.used[i] && r.matches(req, body, in.Request) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Recorder) matches(req *http.Request, body []byte, rec Request) bool {
+	recURL, err := url.Parse(rec.URL)
+	if err != nil {
+		return false
+	}
+	if r.match&MatchMethod != 0 && req.Method != rec.Method {
+		return false
+	}
+	if r.match&MatchPath != 0 && req.URL.EscapedPath() != recURL.EscapedPath() {
+		return false
+	}
+	if r.match&MatchQuery != 0 && req.URL.Query().Encode() != recURL.Query().Encode() {
+		return false
+	}
+	if r.match&MatchBody != 0 && !sameBody(r.redactor.body(body), rec.Body) {
+		return false
+	}
+	return true
+}
+
+// sameBody compares JSON bodies semantically and anything else byte for byte.
+func sameBody(a, b []byte) bool {
+	var av, bv any
+	if json.Unmarshal(a, &av) == nil && json.Unmarshal(b, &bv) == nil {
+		ac, _ := json.Marshal(av)
+		bc, _ := json.Marshal(bv)
+		return bytes.Equal(ac, bc)
+	}
+	return bytes.Equal(a, b)
+}
+
+// readBody consumes the request body and restores it so it can be sent on.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (resp Response) toHTTP(req *http.Request) *http.Response {
+	header := resp.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}
+}
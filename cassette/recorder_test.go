@@ -0,0 +1,174 @@
+package cassette_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/cassette"
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zones.json")
+	ctx := context.Background()
+
+	srv := bunnytest.NewServer(bunnytest.WithAccessKey("secret-api-key"))
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	zones := storage.NewClient("secret-api-key", storage.WithBaseURL(srv.URL), storage.WithHTTPClient(rec)).Zones()
+	created, err := zones.Create(ctx, &storage.CreateZoneRequest{Name: "assets"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := zones.Get(ctx, created.ID); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-api-key", created.Password, created.ReadOnlyPassword} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("cassette contains secret %q", secret)
+		}
+	}
+	if !bytes.Contains(data, []byte(cassette.Redacted)) {
+		t.Errorf("cassette has no redaction markers:\n%s", data)
+	}
+
+	replay, err := cassette.New(path, cassette.WithMode(cassette.ModeReplayOnly))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	zones = storage.NewClient("other-key", storage.WithBaseURL("https://api.example"), storage.WithHTTPClient(replay)).Zones()
+	again, err := zones.Create(ctx, &storage.CreateZoneRequest{Name: "assets"})
+	if err != nil {
+		t.Fatalf("replayed Create: %v", err)
+	}
+	if again.ID != created.ID || again.Password != cassette.Redacted {
+		t.Errorf("replayed zone = %+v", again)
+	}
+	if len(replay.Unused()) != 1 {
+		t.Errorf("unused = %d, want 1", len(replay.Unused()))
+	}
+	if _, err := zones.Get(ctx, created.ID); err != nil {
+		t.Fatalf("replayed Get: %v", err)
+	}
+	_, err = zones.Get(ctx, created.ID)
+	if !errors.Is(err, cassette.ErrNoMatch) {
+		t.Errorf("third Get: err = %v, want ErrNoMatch", err)
+	}
+}
+
+func TestReplayOrRecordAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	calls := 0
+	upstream := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		calls++
+		return testutil.NewMockResponse(200, `{"Id":1,"Name":"z"}`), nil
+	}}
+
+	for i := 0; i < 2; i++ {
+		rec, err := cassette.New(path, cassette.WithHTTPClient(upstream))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		zones := storage.NewClient("k", storage.WithHTTPClient(rec)).Zones()
+		if _, err := zones.Get(context.Background(), 1); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if err := rec.Save(); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("upstream calls = %d, want 1", calls)
+	}
+}
+
+func TestMatchBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	c := &cassette.Cassette{Interactions: []cassette.Interaction{{
+		Request:  cassette.Request{Method: "POST", URL: "https://api.bunny.net/storagezone", Body: cassette.Body(`{"Name":"a", "Region":"DE"}`)},
+		Response: cassette.Response{StatusCode: 201, Body: cassette.Body(`{"Id":7,"Name":"a"}`)},
+	}}}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeReplayOnly), cassette.WithMatch(cassette.MatchDefault|cassette.MatchBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zones := storage.NewClient("k", storage.WithHTTPClient(rec)).Zones()
+	if _, err := zones.Create(context.Background(), &storage.CreateZoneRequest{Name: "b", Region: "DE"}); !errors.Is(err, cassette.ErrNoMatch) {
+		t.Errorf("different body: err = %v", err)
+	}
+	zone, err := zones.Create(context.Background(), &storage.CreateZoneRequest{Name: "a", Region: "DE"})
+	if err != nil {
+		t.Fatalf("same body: %v", err)
+	}
+	if zone.ID != 7 {
+		t.Errorf("zone = %+v", zone)
+	}
+}
+
+func TestBinaryBodyRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	payload := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+	upstream := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Header: make(http.Header), Body: io.NopCloser(bytes.NewReader(payload))}, nil
+	}}
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord), cassette.WithHTTPClient(upstream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := storage.NewFileService("zone", "pw", storage.RegionFalkenstein, storage.WithFileHTTPClient(rec))
+	if _, err := files.Download(context.Background(), "logo.png"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := cassette.New(path, cassette.WithMode(cassette.ModeReplayOnly))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = storage.NewFileService("zone", "pw", storage.RegionFalkenstein, storage.WithFileHTTPClient(replay))
+	rc, err := files.Download(context.Background(), "logo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	got, _ := io.ReadAll(rc)
+	if !bytes.Equal(got, payload) {
+		t.Errorf("body = %v, want %v", got, payload)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"base64"`) {
+		t.Errorf("binary body not base64 encoded:\n%s", data)
+	}
+}
+
+func TestReplayOnlyRequiresCassette(t *testing.T) {
+	if _, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.WithMode(cassette.ModeReplayOnly)); err == nil {
+		t.Error("expected error for missing cassette")
+	}
+}
@@ -0,0 +1,39 @@
+package cassette
+
+import (
+	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/internal/redact"
+)
+
+// Redacted replaces secret values in saved cassettes.
+const Redacted = redact.Placeholder
+
+// redactor strips credentials from recorded headers and JSON bodies.
+type redactor struct {
+	headers map[string]bool // canonical header names
+	fields  map[string]bool // lower-case JSON field names
+}
+
+func newRedactor() *redactor {
+	return &redactor{
+		headers: map[string]bool{"Accesskey": true, "Authorization": true},
+		fields:  redact.Fields(),
+	}
+}
+
+func (r *redactor) header(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if r.headers[http.CanonicalHeaderKey(name)] {
+			out[name] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// body redacts JSON bodies. Other bodies are returned unchanged.
+func (r *redactor) body(b []byte) Body {
+	out, _ := redact.JSON(b, r.fields)
+	return out
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/geraldo/bunny-sdk-go/account"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+// profile holds the credentials of the active profile. They are loaded with
+// account.LoadCredentials, so the CLI and the SDK read the same config file
+// and environment variables.
+type profile struct {
+	APIKey          string
+	StorageZone     string
+	StoragePassword string
+	StorageRegion   storage.Region
+	StreamLibraryID int64
+	StreamAPIKey    string
+}
+
+// loadProfile resolves the active profile from the config file and environment.
+// A missing default config file is not an error.
+func loadProfile(path, name string, getenv func(string) string) (profile, error) {
+	creds, err := account.LoadCredentials(name, account.WithConfigFile(path), account.WithGetenv(getenv))
+	if err != nil {
+		return profile{}, err
+	}
+	zone := creds.StorageZones[creds.StorageZone]
+	return profile{
+		APIKey:          creds.APIKey,
+		StorageZone:     creds.StorageZone,
+		StoragePassword: zone.Password,
+		StorageRegion:   zone.Region,
+		StreamLibraryID: creds.StreamLibraryID,
+		StreamAPIKey:    creds.StreamLibraries[creds.StreamLibraryID],
+	}, nil
+}
+
+func (p profile) requireAPIKey() error {
+	if p.APIKey == "" {
+		return errors.New("no API key: set BUNNY_API_KEY or apiKey in the profile")
+	}
+	return nil
+}
+
+func (p profile) requireStorageZone() error {
+	if p.StorageZone == "" || p.StoragePassword == "" {
+		return errors.New("no storage zone credentials: set BUNNY_STORAGE_ZONE and BUNNY_STORAGE_PASSWORD or storageZone and storagePassword in the profile")
+	}
+	return nil
+}
+
+func (p profile) requireStreamLibrary() error {
+	if p.StreamLibraryID == 0 || p.StreamAPIKey == "" {
+		return errors.New("no stream library credentials: set BUNNY_STREAM_LIBRARY_ID and BUNNY_STREAM_API_KEY or streamLibraryId and streamApiKey in the profile")
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+)
+
+func containersCommand() *command {
+	return &command{
+		name:    "containers",
+		summary: "Magic Containers applications",
+		commands: []*command{
+			{
+				name:    "apps",
+				summary: "Applications",
+				commands: []*command{
+					{name: "list", summary: "List applications", run: containersAppsList},
+					{name: "get", args: "<app-id>", summary: "Show an application", run: containersAppsGet},
+				},
+			},
+			{name: "deploy", args: "<app-id>", summary: "Deploy an application", run: containersAction("deploy")},
+			{name: "undeploy", args: "<app-id>", summary: "Undeploy an application", run: containersAction("undeploy")},
+			{name: "restart", args: "<app-id>", summary: "Restart an application", run: containersAction("restart")},
+			{
+				name:    "logs",
+				summary: "Log forwarding configuration",
+				commands: []*command{
+					{name: "list", summary: "List log forwarding configurations", run: containersLogsList},
+					{name: "get", args: "<app-id>", summary: "Show an application's log forwarding", run: containersLogsGet},
+					{name: "set", args: "-endpoint host -port n [-type SyslogUdp] [-format SyslogRfc5424] [-token t] [-disable] <app-id>", summary: "Create or update log forwarding", run: containersLogsSet},
+				},
+			},
+		},
+	}
+}
+
+func (a *app) containersClient() (*containers.Client, error) {
+	if err := a.profile.requireAPIKey(); err != nil {
+		return nil, err
+	}
+	var opts []containers.Option
+	if a.http != nil {
+		opts = append(opts, containers.WithHTTPClient(a.http))
+	}
+	return containers.NewClient(a.profile.APIKey, opts...), nil
+}
+
+func containersAppsList(ctx context.Context, a *app, args []string) error {
+	if _, err := parse(a.flags("bunny containers apps list", ""), args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.containersClient()
+	if err != nil {
+		return err
+	}
+	var apps []containers.ApplicationListItem
+	opts := &containers.ListOptions{}
+	for {
+		resp, err := client.Applications().List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		apps = append(apps, resp.Items...)
+		if resp.Cursor == "" || len(resp.Items) == 0 {
+			break
+		}
+		opts = &containers.ListOptions{NextCursor: resp.Cursor}
+	}
+
+	t := &table{header: []string{"ID", "NAME", "STATUS"}}
+	for _, app := range apps {
+		t.add(app.ID, app.Name, app.Status)
+	}
+	return a.print(apps, t)
+}
+
+func containersAppsGet(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny containers apps get", "<app-id>"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.containersClient()
+	if err != nil {
+		return err
+	}
+	app, err := client.Applications().Get(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"CONTAINER", "IMAGE", "TAG"}}
+	for _, c := range app.ContainerTemplates {
+		t.add(c.Name, imageName(c.ImageNamespace, c.ImageName), c.ImageTag)
+	}
+	if a.format == "table" {
+		a.status("%s  %s  %s", app.ID, app.Name, app.Status)
+		if app.AutoScaling != nil {
+			a.status("autoscaling: %d-%d instances", app.AutoScaling.Min, app.AutoScaling.Max)
+		}
+	}
+	return a.print(app, t)
+}
+
+func imageName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// containersAction returns a command that runs a lifecycle action on an application.
+func containersAction(action string) func(ctx context.Context, a *app, args []string) error {
+	return func(ctx context.Context, a *app, args []string) error {
+		pos, err := parse(a.flags("bunny containers "+action, "<app-id>"), args, 1, 1)
+		if err != nil {
+			return err
+		}
+		client, err := a.containersClient()
+		if err != nil {
+			return err
+		}
+		apps := client.Applications()
+		switch action {
+		case "deploy":
+			err = apps.Deploy(ctx, pos[0])
+		case "undeploy":
+			err = apps.Undeploy(ctx, pos[0])
+		case "restart":
+			err = apps.Restart(ctx, pos[0])
+		}
+		if err != nil {
+			return err
+		}
+		a.status("%s requested for %s", action, pos[0])
+		return nil
+	}
+}
+
+func containersLogsList(ctx context.Context, a *app, args []string) error {
+	if _, err := parse(a.flags("bunny containers logs list", ""), args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.containersClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.LogForwarding().List(ctx)
+	if err != nil {
+		return err
+	}
+	return a.print(redactLogTokens(resp.Items), logsTable(resp.Items))
+}
+
+func containersLogsGet(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny containers logs get", "<app-id>"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.containersClient()
+	if err != nil {
+		return err
+	}
+	cfg, err := client.LogForwarding().Get(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	items := redactLogTokens([]containers.LogForwardingConfig{*cfg})
+	return a.print(items[0], logsTable(items))
+}
+
+func containersLogsSet(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny containers logs set", "-endpoint host -port n [-type SyslogUdp] [-format SyslogRfc5424] [-token t] [-disable] <app-id>")
+	endpoint := set.String("endpoint", "", "syslog host (required)")
+	port := set.Int("port", 0, "syslog port (required)")
+	typ := set.String("type", string(containers.LogForwardingTypeSyslogUDP), "SyslogUdp or SyslogTcp")
+	format := set.String("format", string(containers.LogForwardingFormatRfc5424), "SyslogRfc3164 or SyslogRfc5424")
+	token := set.String("token", "", "optional authentication token")
+	disable := set.Bool("disable", false, "keep the configuration but stop forwarding")
+	pos, err := parse(set, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *endpoint == "" || *port == 0 {
+		return errors.New("-endpoint and -port are required")
+	}
+	client, err := a.containersClient()
+	if err != nil {
+		return err
+	}
+	logs := client.LogForwarding()
+
+	appID := pos[0]
+	_, err = logs.Get(ctx, appID)
+	var apiErr *containers.APIError
+	switch {
+	case err == nil:
+		_, err = logs.Update(ctx, appID, &containers.UpdateLogForwardingRequest{
+			App:      appID,
+			Type:     containers.LogForwardingType(*typ),
+			Endpoint: *endpoint,
+			Port:     *port,
+			Token:    *token,
+			Format:   containers.LogForwardingFormat(*format),
+			Enabled:  !*disable,
+		})
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		_, err = logs.Create(ctx, &containers.CreateLogForwardingRequest{
+			App:      appID,
+			Type:     containers.LogForwardingType(*typ),
+			Endpoint: *endpoint,
+			Port:     *port,
+			Token:    *token,
+			Format:   containers.LogForwardingFormat(*format),
+			Enabled:  !*disable,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	a.status("log forwarding for %s -> %s:%d", appID, *endpoint, *port)
+	return nil
+}
+
+func logsTable(items []containers.LogForwardingConfig) *table {
+	t := &table{header: []string{"APP", "TYPE", "ENDPOINT", "PORT", "FORMAT", "ENABLED"}}
+	for _, c := range items {
+		t.add(c.App, c.Type, c.Endpoint, c.Port, c.Format, c.Enabled)
+	}
+	return t
+}
+
+func redactLogTokens(items []containers.LogForwardingConfig) []containers.LogForwardingConfig {
+	out := make([]containers.LogForwardingConfig, len(items))
+	for i, c := range items {
+		if c.Token != "" {
+			c.Token = "<redacted>"
+		}
+		out[i] = c
+	}
+	return out
+}
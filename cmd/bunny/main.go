@@ -0,0 +1,166 @@
+// Command bunny is a command-line client for the Bunny.net APIs.
+//
+// Usage:
+//
+//	bunny [-profile name] [-o table|json|yaml] <service> <command> [flags] [args]
+//
+// Credentials are read from environment variables or from a profile in
+// ~/.bunny/config.yaml; see account.LoadCredentials for the supported keys.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// errUsage signals that usage has already been printed.
+var errUsage = errors.New("usage")
+
+// doer is satisfied by every package's HTTPClient interface.
+type doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// app holds the state shared by all commands.
+type app struct {
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	getenv  func(string) string
+	profile profile
+	format  string
+	http    doer // overrides the default HTTP client when set
+}
+
+// command is a node in the command tree. Leaf commands have run; groups have commands.
+type command struct {
+	name     string
+	args     string
+	summary  string
+	run      func(ctx context.Context, a *app, args []string) error
+	commands []*command
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(a.main(ctx, os.Args[1:]))
+}
+
+func (a *app) main(ctx context.Context, args []string) int {
+	root := rootCommand()
+
+	fs := flag.NewFlagSet("bunny", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	profileName := fs.String("profile", "", "credentials profile (default $BUNNY_PROFILE or \"default\")")
+	configPath := fs.String("config", "", "config file (default $BUNNY_CONFIG or ~/.bunny/config.yaml)")
+	fs.StringVar(&a.format, "o", "table", "output format: table, json or yaml")
+	fs.Usage = func() { a.usage(root, "bunny", fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	switch a.format {
+	case "table", "json", "yaml":
+	default:
+		fmt.Fprintf(a.stderr, "bunny: unknown output format %q\n", a.format)
+		return 2
+	}
+
+	p, err := loadProfile(*configPath, *profileName, a.getenv)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "bunny: %v\n", err)
+		return 1
+	}
+	a.profile = p
+
+	if err := a.execute(ctx, root, "bunny", fs.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(a.stderr, "bunny: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (a *app) execute(ctx context.Context, c *command, path string, args []string) error {
+	if c.run != nil {
+		return c.run(ctx, a, args)
+	}
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.usage(c, path, nil)
+		return errUsage
+	}
+	for _, sub := range c.commands {
+		if sub.name == args[0] {
+			return a.execute(ctx, sub, path+" "+sub.name, args[1:])
+		}
+	}
+	a.usage(c, path, nil)
+	return fmt.Errorf("unknown command %q", path+" "+args[0])
+}
+
+func (a *app) usage(c *command, path string, fs *flag.FlagSet) {
+	fmt.Fprintf(a.stderr, "Usage: %s <command>\n\nCommands:\n", path)
+	for _, sub := range c.commands {
+		fmt.Fprintf(a.stderr, "  %-34s %s\n", strings.TrimSpace(sub.name+" "+sub.args), sub.summary)
+	}
+	if fs != nil {
+		fmt.Fprintln(a.stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+}
+
+func rootCommand() *command {
+	return &command{
+		commands: []*command{
+			storageCommand(),
+			streamCommand(),
+			shieldCommand(),
+			scriptingCommand(),
+			containersCommand(),
+		},
+	}
+}
+
+// flags returns a flag set for a leaf command.
+func (a *app) flags(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: %s %s\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses flags that may appear before or after positional arguments
+// and checks the number of positional arguments.
+func parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
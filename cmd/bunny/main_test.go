@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+)
+
+func newTestApp(env map[string]string, do func(req *http.Request) (*http.Response, error)) (*app, *bytes.Buffer, *bytes.Buffer) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return &app{
+		stdin:  strings.NewReader(""),
+		stdout: stdout,
+		stderr: stderr,
+		getenv: func(k string) string { return env[k] },
+		http:   &testutil.MockHTTPClient{DoFunc: do},
+	}, stdout, stderr
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := `profiles:
+  default:
+    apiKey: default-key
+  prod:
+    apiKey: prod-key
+    storageZone: assets
+    storagePassword: secret
+    streamLibraryId: 42
+`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"BUNNY_PROFILE": "prod", "BUNNY_STORAGE_ZONE": "override"}
+	p, err := loadProfile(path, "", func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("loadProfile: %v", err)
+	}
+	if p.APIKey != "prod-key" || p.StorageZone != "override" || p.StoragePassword != "secret" || p.StreamLibraryID != 42 {
+		t.Errorf("profile = %+v", p)
+	}
+
+	if _, err := loadProfile(path, "missing", func(string) string { return "" }); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+	if _, err := loadProfile(filepath.Join(t.TempDir(), "none.yaml"), "", func(string) string { return "" }); err == nil {
+		t.Error("expected error for missing explicit config file")
+	}
+}
+
+func TestShieldZonesJSON(t *testing.T) {
+	a, stdout, _ := newTestApp(map[string]string{"BUNNY_API_KEY": "key"}, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("AccessKey") != "key" {
+			t.Errorf("AccessKey = %q", req.Header.Get("AccessKey"))
+		}
+		return testutil.NewMockResponse(200, `{"Items":[{"Id":"7","Name":"web"}],"TotalCount":1}`), nil
+	})
+
+	if code := a.main(context.Background(), []string{"-o", "json", "shield", "zones"}); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	var zones []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &zones); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout)
+	}
+	if len(zones) != 1 {
+		t.Errorf("got %d zones", len(zones))
+	}
+}
+
+func TestStorageZonesTable(t *testing.T) {
+	a, stdout, _ := newTestApp(map[string]string{"BUNNY_API_KEY": "key"}, func(req *http.Request) (*http.Response, error) {
+		return testutil.NewMockResponse(200, `{"Items":[{"Id":1,"Name":"assets","Region":"DE","Password":"pw","StorageUsed":2048}],"CurrentPage":0,"PageSize":1000,"TotalItems":1}`), nil
+	})
+
+	if code := a.main(context.Background(), []string{"storage", "zones"}); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "assets") || !strings.Contains(out, "2.0 KiB") {
+		t.Errorf("table output = %q", out)
+	}
+	if strings.Contains(out, "pw") {
+		t.Errorf("password leaked: %q", out)
+	}
+}
+
+func TestScriptingSecretsRemove(t *testing.T) {
+	var deleted string
+	a, _, _ := newTestApp(map[string]string{"BUNNY_API_KEY": "key"}, func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodDelete {
+			deleted = req.URL.Path
+			return testutil.NewMockResponse(204, ""), nil
+		}
+		return testutil.NewMockResponse(200, `{"Secrets":[{"Id":3,"Name":"OTHER"},{"Id":9,"Name":"TOKEN"}]}`), nil
+	})
+
+	if code := a.main(context.Background(), []string{"scripting", "secrets", "rm", "12", "TOKEN"}); code != 0 {
+		t.Fatalf("exit code = %d", code)
+	}
+	if deleted != "/compute/script/12/secrets/9" {
+		t.Errorf("deleted %q", deleted)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	a, _, stderr := newTestApp(nil, nil)
+	if code := a.main(context.Background(), []string{"storage"}); code != 2 {
+		t.Errorf("group without subcommand: exit code = %d", code)
+	}
+	if !strings.Contains(stderr.String(), "sync") {
+		t.Errorf("usage = %q", stderr)
+	}
+
+	a, _, stderr = newTestApp(nil, nil)
+	if code := a.main(context.Background(), []string{"shield", "zones"}); code != 1 {
+		t.Errorf("missing key: exit code = %d", code)
+	}
+	if !strings.Contains(stderr.String(), "BUNNY_API_KEY") {
+		t.Errorf("stderr = %q", stderr)
+	}
+
+	a, _, _ = newTestApp(nil, nil)
+	if code := a.main(context.Background(), []string{"-o", "xml", "storage", "zones"}); code != 2 {
+		t.Errorf("bad format: exit code = %d", code)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal/yaml"
+)
+
+// table is the tabular rendering of a result.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(cells ...any) {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = cell(c)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// print writes v in the selected output format.
+// The table form is used for "table" output; JSON and YAML render v itself.
+func (a *app) print(v any, t *table) error {
+	switch a.format {
+	case "json":
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = a.stdout.Write(b)
+		return err
+	}
+
+	if t == nil {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = a.stdout.Write(b)
+		return err
+	}
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// status prints a progress or confirmation message. It goes to stderr for
+// machine-readable output so stdout stays parseable.
+func (a *app) status(format string, args ...any) {
+	if a.format == "table" {
+		fmt.Fprintf(a.stdout, format+"\n", args...)
+		return
+	}
+	fmt.Fprintf(a.stderr, format+"\n", args...)
+}
+
+func cell(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if t == "" {
+			return "-"
+		}
+		return t
+	case *string:
+		if t == nil || *t == "" {
+			return "-"
+		}
+		return *t
+	case time.Time:
+		if t.IsZero() {
+			return "-"
+		}
+		return t.UTC().Format(time.RFC3339)
+	case []string:
+		if len(t) == 0 {
+			return "-"
+		}
+		return strings.Join(t, ",")
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// humanBytes formats a byte count with binary units.
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/scripting"
+)
+
+func scriptingCommand() *command {
+	return &command{
+		name:    "scripting",
+		summary: "Edge scripts, releases and secrets",
+		commands: []*command{
+			{name: "list", summary: "List edge scripts", run: scriptingList},
+			{name: "deploy", args: "<script-id> <file>", summary: "Upload script code (use - for stdin)", run: scriptingDeploy},
+			{name: "publish", args: "[-note text] <script-id>", summary: "Publish the current code as a release", run: scriptingPublish},
+			{
+				name:    "secrets",
+				summary: "Edge script secrets",
+				commands: []*command{
+					{name: "list", args: "<script-id>", summary: "List secret names", run: scriptingSecretsList},
+					{name: "set", args: "<script-id> <name> [value]", summary: "Create or update a secret (value from stdin if omitted)", run: scriptingSecretsSet},
+					{name: "rm", args: "<script-id> <name>", summary: "Delete a secret", run: scriptingSecretsRemove},
+				},
+			},
+		},
+	}
+}
+
+func (a *app) scriptingClient() (*scripting.Client, error) {
+	if err := a.profile.requireAPIKey(); err != nil {
+		return nil, err
+	}
+	var opts []scripting.Option
+	if a.http != nil {
+		opts = append(opts, scripting.WithHTTPClient(a.http))
+	}
+	return scripting.NewClient(a.profile.APIKey, opts...), nil
+}
+
+func parseScriptID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid script ID %q", s)
+	}
+	return id, nil
+}
+
+func scriptingList(ctx context.Context, a *app, args []string) error {
+	if _, err := parse(a.flags("bunny scripting list", ""), args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.scriptingClient()
+	if err != nil {
+		return err
+	}
+	var scripts []scripting.EdgeScript
+	for page := 1; ; page++ {
+		resp, err := client.Scripts().List(ctx, &scripting.ScriptListOptions{Page: page, PerPage: 1000})
+		if err != nil {
+			return err
+		}
+		scripts = append(scripts, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMoreItems {
+			break
+		}
+	}
+
+	t := &table{header: []string{"ID", "NAME", "TYPE", "RELEASE", "HOSTNAME", "MODIFIED"}}
+	for i, s := range scripts {
+		scripts[i].DeploymentKey = nil
+		t.add(s.ID, s.Name, s.ScriptType, s.CurrentReleaseID, s.DefaultHostname, s.LastModified)
+	}
+	return a.print(scripts, t)
+}
+
+func scriptingDeploy(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny scripting deploy", "<script-id> <file>"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseScriptID(pos[0])
+	if err != nil {
+		return err
+	}
+	var code []byte
+	if pos[1] == "-" {
+		code, err = io.ReadAll(a.stdin)
+	} else {
+		code, err = os.ReadFile(pos[1])
+	}
+	if err != nil {
+		return err
+	}
+	client, err := a.scriptingClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Code(id).Set(ctx, &scripting.UpdateCodeRequest{Code: string(code)}); err != nil {
+		return err
+	}
+	a.status("deployed %d bytes to script %d; run publish to release it", len(code), id)
+	return nil
+}
+
+func scriptingPublish(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny scripting publish", "[-note text] <script-id>")
+	note := set.String("note", "", "release note")
+	pos, err := parse(set, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseScriptID(pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.scriptingClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Releases(id).Publish(ctx, &scripting.PublishReleaseRequest{Note: *note}); err != nil {
+		return err
+	}
+	a.status("published script %d", id)
+	return nil
+}
+
+func scriptingSecretsList(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny scripting secrets list", "<script-id>"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseScriptID(pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.scriptingClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Secrets(id).List(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"ID", "NAME", "MODIFIED"}}
+	for _, s := range resp.Secrets {
+		t.add(s.ID, s.Name, s.LastModified)
+	}
+	return a.print(resp.Secrets, t)
+}
+
+func scriptingSecretsSet(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny scripting secrets set", "<script-id> <name> [value]"), args, 2, 3)
+	if err != nil {
+		return err
+	}
+	id, err := parseScriptID(pos[0])
+	if err != nil {
+		return err
+	}
+	var value string
+	if len(pos) == 3 {
+		value = pos[2]
+	} else {
+		b, err := io.ReadAll(a.stdin)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimRight(string(b), "\r\n")
+	}
+	client, err := a.scriptingClient()
+	if err != nil {
+		return err
+	}
+	if _, err := client.Secrets(id).Upsert(ctx, &scripting.UpsertSecretRequest{Name: pos[1], Secret: value}); err != nil {
+		return err
+	}
+	a.status("set secret %s on script %d", pos[1], id)
+	return nil
+}
+
+func scriptingSecretsRemove(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny scripting secrets rm", "<script-id> <name>"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseScriptID(pos[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.scriptingClient()
+	if err != nil {
+		return err
+	}
+	secrets := client.Secrets(id)
+	resp, err := secrets.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range resp.Secrets {
+		if s.Name != nil && *s.Name == pos[1] {
+			if err := secrets.Delete(ctx, s.ID); err != nil {
+				return err
+			}
+			a.status("deleted secret %s from script %d", pos[1], id)
+			return nil
+		}
+	}
+	return fmt.Errorf("script %d has no secret named %q", id, pos[1])
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func shieldCommand() *command {
+	return &command{
+		name:    "shield",
+		summary: "Shield zones, WAF rules, access lists and metrics",
+		commands: []*command{
+			{name: "zones", summary: "List shield zones", run: shieldZones},
+			{
+				name:    "rules",
+				summary: "Custom WAF rules",
+				commands: []*command{
+					{name: "list", args: "[-zone id]", summary: "List custom rules", run: shieldRulesList},
+					{name: "delete", args: "<rule-id>", summary: "Delete a custom rule", run: shieldRulesDelete},
+				},
+			},
+			{
+				name:    "access-list",
+				summary: "Zone access lists",
+				commands: []*command{
+					{name: "list", args: "-zone id", summary: "List access list entries", run: shieldAccessList},
+					{name: "add", args: "-zone id -type ip -action block <value>", summary: "Add an entry", run: shieldAccessAdd},
+					{name: "rm", args: "-zone id -type ip <value>", summary: "Remove an entry", run: shieldAccessRemove},
+				},
+			},
+			{name: "metrics", args: "[-from date] [-to date]", summary: "Show the metrics overview", run: shieldMetrics},
+		},
+	}
+}
+
+func (a *app) shieldClient() (*shield.Client, error) {
+	if err := a.profile.requireAPIKey(); err != nil {
+		return nil, err
+	}
+	var opts []shield.Option
+	if a.http != nil {
+		opts = append(opts, shield.WithHTTPClient(a.http))
+	}
+	return shield.NewClient(a.profile.APIKey, opts...), nil
+}
+
+func shieldZones(ctx context.Context, a *app, args []string) error {
+	if _, err := parse(a.flags("bunny shield zones", ""), args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.shieldClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Zones().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"ID", "NAME", "HOSTNAMES"}}
+	for _, z := range resp.Items {
+		t.add(z.ID, z.Name, z.HostNames)
+	}
+	return a.print(resp.Items, t)
+}
+
+func shieldRulesList(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny shield rules list", "[-zone id]")
+	zone := set.String("zone", "", "only rules of this shield zone")
+	if _, err := parse(set, args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.shieldClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.WAF().ListCustomRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := []shield.CustomRule{}
+	t := &table{header: []string{"ID", "ZONE", "NAME", "ACTION", "ACTIVE", "PATTERN"}}
+	for _, r := range resp.Items {
+		if *zone != "" && r.ShieldZoneID != *zone {
+			continue
+		}
+		rules = append(rules, r)
+		t.add(r.ID, r.ShieldZoneID, r.Name, r.Action, r.IsActive, r.Pattern)
+	}
+	return a.print(rules, t)
+}
+
+func shieldRulesDelete(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny shield rules delete", "<rule-id>"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.shieldClient()
+	if err != nil {
+		return err
+	}
+	if err := client.WAF().DeleteCustomRule(ctx, pos[0]); err != nil {
+		return err
+	}
+	a.status("deleted rule %s", pos[0])
+	return nil
+}
+
+func shieldAccessList(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny shield access-list list", "-zone id")
+	zone := set.String("zone", "", "shield zone ID (required)")
+	if _, err := parse(set, args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.accessListZone(*zone)
+	if err != nil {
+		return err
+	}
+	list, err := client.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"LIST", "TYPE", "VALUE", "ACTION", "COMMENT", "ADDED"}}
+	for _, group := range []struct {
+		name    string
+		entries []shield.AccessListEntry
+	}{{"allowed", list.Allowed}, {"blocked", list.Blocked}, {"challenged", list.Challenged}} {
+		for _, e := range group.entries {
+			t.add(group.name, e.Type, e.Value, e.Action, e.Comment, e.DateAdded)
+		}
+	}
+	return a.print(list, t)
+}
+
+func shieldAccessAdd(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny shield access-list add", "-zone id -type ip -action block <value>")
+	zone := set.String("zone", "", "shield zone ID (required)")
+	typ := set.String("type", "ip", "entry type")
+	action := set.String("action", "block", "action to take")
+	comment := set.String("comment", "", "optional comment")
+	pos, err := parse(set, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.accessListZone(*zone)
+	if err != nil {
+		return err
+	}
+	entry, err := client.Add(ctx, &shield.AddAccessListEntryRequest{Type: *typ, Value: pos[0], Action: *action, Comment: *comment})
+	if err != nil {
+		return err
+	}
+	a.status("added %s %s (%s)", *typ, pos[0], *action)
+	if a.format == "table" {
+		return nil
+	}
+	return a.print(entry, nil)
+}
+
+func shieldAccessRemove(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny shield access-list rm", "-zone id -type ip <value>")
+	zone := set.String("zone", "", "shield zone ID (required)")
+	typ := set.String("type", "ip", "entry type")
+	pos, err := parse(set, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	client, err := a.accessListZone(*zone)
+	if err != nil {
+		return err
+	}
+	err = client.Delete(ctx, &shield.DeleteAccessListEntriesRequest{
+		Entries: []shield.AccessListEntryIdentifier{{Type: *typ, Value: pos[0]}},
+	})
+	if err != nil {
+		return err
+	}
+	a.status("removed %s %s", *typ, pos[0])
+	return nil
+}
+
+func (a *app) accessListZone(zoneID string) (shield.AccessListService, error) {
+	if zoneID == "" {
+		return nil, errors.New("-zone is required")
+	}
+	client, err := a.shieldClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.AccessLists(zoneID), nil
+}
+
+func shieldMetrics(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny shield metrics", "[-from date] [-to date]")
+	from := set.String("from", "", "start date (YYYY-MM-DD)")
+	to := set.String("to", "", "end date (YYYY-MM-DD)")
+	if _, err := parse(set, args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.shieldClient()
+	if err != nil {
+		return err
+	}
+	m, err := client.Metrics().GetOverview(ctx, &shield.DateRangeOptions{From: *from, To: *to})
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"METRIC", "VALUE"}}
+	t.add("total requests", m.TotalRequests)
+	t.add("allowed", m.AllowedRequests)
+	t.add("blocked", m.BlockedRequests)
+	t.add("bot detection blocks", m.BotDetectionBlocks)
+	t.add("rate limit blocks", m.RateLimitBlocks)
+	t.add("access list blocks", m.AccessListBlocks)
+	return a.print(m, t)
+}
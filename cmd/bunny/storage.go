@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+// remotePrefix marks a storage zone path in cp arguments, e.g. bunny:images/logo.png.
+const remotePrefix = "bunny:"
+
+func storageCommand() *command {
+	return &command{
+		name:    "storage",
+		summary: "Storage zones and files",
+		commands: []*command{
+			{name: "zones", summary: "List storage zones", run: storageZones},
+			{name: "ls", args: "[path]", summary: "List files in the profile's storage zone", run: storageLs},
+			{name: "cp", args: "<src> <dst>", summary: "Copy a file to or from the zone (prefix zone paths with bunny:)", run: storageCp},
+			{name: "rm", args: "[-r] <path>", summary: "Delete a file or directory", run: storageRm},
+			{name: "sync", args: "[-delete] [-dry-run] <local-dir> [remote-dir]", summary: "Upload new and changed files", run: storageSync},
+		},
+	}
+}
+
+func (a *app) storageClient() (*storage.Client, error) {
+	if err := a.profile.requireAPIKey(); err != nil {
+		return nil, err
+	}
+	var opts []storage.Option
+	if a.http != nil {
+		opts = append(opts, storage.WithHTTPClient(a.http))
+	}
+	return storage.NewClient(a.profile.APIKey, opts...), nil
+}
+
+func (a *app) fileService() (storage.FileService, error) {
+	if err := a.profile.requireStorageZone(); err != nil {
+		return nil, err
+	}
+	region := a.profile.StorageRegion
+	if region == "" {
+		region = storage.RegionFalkenstein
+	}
+	var opts []storage.FileServiceOption
+	if a.http != nil {
+		opts = append(opts, storage.WithFileHTTPClient(a.http))
+	}
+	return storage.NewFileService(a.profile.StorageZone, a.profile.StoragePassword, region, opts...), nil
+}
+
+func storageZones(ctx context.Context, a *app, args []string) error {
+	if _, err := parse(a.flags("bunny storage zones", ""), args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.storageClient()
+	if err != nil {
+		return err
+	}
+	var zones []storage.Zone
+	for page := 1; ; page++ {
+		resp, err := client.Zones().List(ctx, &storage.ZoneListOptions{Page: page, PerPage: 1000})
+		if err != nil {
+			return err
+		}
+		zones = append(zones, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMore() || len(zones) >= resp.TotalItems {
+			break
+		}
+	}
+
+	t := &table{header: []string{"ID", "NAME", "REGION", "REPLICATION", "FILES", "USED"}}
+	for _, z := range zones {
+		t.add(z.ID, z.Name, z.Region, z.ReplicationRegions, z.FilesStored, humanBytes(z.StorageUsed))
+	}
+	return a.print(redactZones(zones), t)
+}
+
+// redactZones drops zone passwords from machine-readable output.
+func redactZones(zones []storage.Zone) []storage.Zone {
+	out := make([]storage.Zone, len(zones))
+	for i, z := range zones {
+		z.Password, z.ReadOnlyPassword = "", ""
+		out[i] = z
+	}
+	return out
+}
+
+func storageLs(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny storage ls", "[path]"), args, 0, 1)
+	if err != nil {
+		return err
+	}
+	files, err := a.fileService()
+	if err != nil {
+		return err
+	}
+	dir := ""
+	if len(pos) == 1 {
+		dir = strings.TrimPrefix(pos[0], remotePrefix)
+	}
+	list, err := files.List(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"NAME", "SIZE", "LAST CHANGED"}}
+	for _, f := range list {
+		if f.IsDirectory {
+			t.add(f.ObjectName+"/", "-", f.LastChanged.Time)
+			continue
+		}
+		t.add(f.ObjectName, humanBytes(f.Length), f.LastChanged.Time)
+	}
+	return a.print(list, t)
+}
+
+func storageCp(ctx context.Context, a *app, args []string) error {
+	pos, err := parse(a.flags("bunny storage cp", "<src> <dst>"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	files, err := a.fileService()
+	if err != nil {
+		return err
+	}
+	src, dst := pos[0], pos[1]
+	srcRemote, dstRemote := strings.HasPrefix(src, remotePrefix), strings.HasPrefix(dst, remotePrefix)
+
+	switch {
+	case !srcRemote && dstRemote:
+		remote := strings.TrimPrefix(dst, remotePrefix)
+		if remote == "" || strings.HasSuffix(remote, "/") {
+			remote += filepath.Base(src)
+		}
+		if err := uploadFile(ctx, files, src, remote); err != nil {
+			return err
+		}
+		a.status("uploaded %s -> %s%s", src, remotePrefix, remote)
+		return nil
+
+	case srcRemote && !dstRemote:
+		remote := strings.TrimPrefix(src, remotePrefix)
+		body, err := files.Download(ctx, remote)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		if dst == "-" {
+			_, err := io.Copy(a.stdout, body)
+			return err
+		}
+		if info, err := os.Stat(dst); err == nil && info.IsDir() {
+			dst = filepath.Join(dst, path.Base(remote))
+		}
+		f, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, body); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		a.status("downloaded %s -> %s", src, dst)
+		return nil
+	}
+	return fmt.Errorf("exactly one of src and dst must be a zone path starting with %q", remotePrefix)
+}
+
+func uploadFile(ctx context.Context, files storage.FileService, local, remote string) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return files.Upload(ctx, remote, f, nil)
+}
+
+func storageRm(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny storage rm", "[-r] <path>")
+	recursive := set.Bool("r", false, "delete a directory and its contents")
+	pos, err := parse(set, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	files, err := a.fileService()
+	if err != nil {
+		return err
+	}
+	target := strings.TrimPrefix(pos[0], remotePrefix)
+	if *recursive {
+		err = files.DeleteDirectory(ctx, target)
+	} else {
+		err = files.Delete(ctx, target)
+	}
+	if err != nil {
+		return err
+	}
+	a.status("deleted %s%s", remotePrefix, target)
+	return nil
+}
+
+// syncAction is one line of sync output.
+type syncAction struct {
+	Action string `json:"action"` // upload or delete
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+}
+
+func storageSync(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny storage sync", "[-delete] [-dry-run] <local-dir> [remote-dir]")
+	del := set.Bool("delete", false, "delete remote files that do not exist locally")
+	dryRun := set.Bool("dry-run", false, "print what would change without changing anything")
+	pos, err := parse(set, args, 1, 2)
+	if err != nil {
+		return err
+	}
+	files, err := a.fileService()
+	if err != nil {
+		return err
+	}
+	localDir := pos[0]
+	remoteDir := ""
+	if len(pos) == 2 {
+		remoteDir = strings.Trim(strings.TrimPrefix(pos[1], remotePrefix), "/")
+	}
+
+	local := make(map[string]int64)
+	err = filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		local[filepath.ToSlash(rel)] = info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	remote, err := listRemote(ctx, files, remoteDir)
+	if err != nil {
+		return err
+	}
+
+	var actions []syncAction
+	for rel, size := range local {
+		if have, ok := remote[rel]; !ok || have != size {
+			actions = append(actions, syncAction{Action: "upload", Path: rel, Size: size})
+		}
+	}
+	if *del {
+		for rel, size := range remote {
+			if _, ok := local[rel]; !ok {
+				actions = append(actions, syncAction{Action: "delete", Path: rel, Size: size})
+			}
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Path < actions[j].Path })
+
+	if !*dryRun {
+		for _, act := range actions {
+			target := path.Join(remoteDir, act.Path)
+			switch act.Action {
+			case "upload":
+				err = uploadFile(ctx, files, filepath.Join(localDir, filepath.FromSlash(act.Path)), target)
+			case "delete":
+				err = files.Delete(ctx, target)
+			}
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", act.Action, target, err)
+			}
+		}
+	}
+
+	t := &table{header: []string{"ACTION", "PATH", "SIZE"}}
+	for _, act := range actions {
+		t.add(act.Action, act.Path, humanBytes(act.Size))
+	}
+	if len(actions) == 0 {
+		a.status("already in sync")
+		if a.format == "table" {
+			return nil
+		}
+	}
+	return a.print(actions, t)
+}
+
+// listRemote returns file sizes below dir keyed by path relative to dir.
+func listRemote(ctx context.Context, files storage.FileService, dir string) (map[string]int64, error) {
+	out := make(map[string]int64)
+	var walk func(rel string) error
+	walk = func(rel string) error {
+		list, err := files.List(ctx, path.Join(dir, rel))
+		if err != nil {
+			return err
+		}
+		for _, f := range list {
+			child := path.Join(rel, f.ObjectName)
+			if f.IsDirectory {
+				if err := walk(child); err != nil {
+					return err
+				}
+				continue
+			}
+			out[child] = f.Length
+		}
+		return nil
+	}
+	if err := walk(""); err != nil {
+		var apiErr *storage.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return out, nil
+		}
+		return nil, err
+	}
+	return out, nil
+}
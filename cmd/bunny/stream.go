@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+func streamCommand() *command {
+	return &command{
+		name:    "stream",
+		summary: "Stream libraries and videos",
+		commands: []*command{
+			{name: "libraries", summary: "List video libraries", run: streamLibraries},
+			{
+				name:    "videos",
+				summary: "Videos in the profile's library",
+				commands: []*command{
+					{name: "list", args: "[-search text] [-collection id]", summary: "List videos", run: streamVideosList},
+					{name: "upload", args: "[-title text] [-collection id] <file>", summary: "Create a video and upload a file", run: streamVideosUpload},
+					{name: "watch", args: "[-interval 5s] <video-id>", summary: "Wait until a video finishes encoding", run: streamVideosWatch},
+				},
+			},
+		},
+	}
+}
+
+// streamClient returns a client authenticated with the account API key, for library management.
+func (a *app) streamClient() (*stream.Client, error) {
+	if err := a.profile.requireAPIKey(); err != nil {
+		return nil, err
+	}
+	return stream.NewClient(a.profile.APIKey, a.streamOptions()...), nil
+}
+
+// videos returns the VideoService of the profile's library, authenticated with the library API key.
+func (a *app) videos() (stream.VideoService, error) {
+	if err := a.profile.requireStreamLibrary(); err != nil {
+		return nil, err
+	}
+	return stream.NewClient(a.profile.StreamAPIKey, a.streamOptions()...).Videos(a.profile.StreamLibraryID), nil
+}
+
+func (a *app) streamOptions() []stream.Option {
+	if a.http == nil {
+		return nil
+	}
+	return []stream.Option{stream.WithHTTPClient(a.http)}
+}
+
+func streamLibraries(ctx context.Context, a *app, args []string) error {
+	if _, err := parse(a.flags("bunny stream libraries", ""), args, 0, 0); err != nil {
+		return err
+	}
+	client, err := a.streamClient()
+	if err != nil {
+		return err
+	}
+	var libraries []stream.Library
+	for page := 1; ; page++ {
+		resp, err := client.Libraries().List(ctx, &stream.LibraryListOptions{Page: page, ItemsPerPage: 1000})
+		if err != nil {
+			return err
+		}
+		libraries = append(libraries, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			break
+		}
+	}
+
+	t := &table{header: []string{"ID", "NAME", "REGION", "VIDEOS", "USED"}}
+	for _, l := range libraries {
+		t.add(l.LibraryID, l.Name, l.Region, l.VideoCount, humanBytes(l.StorageUsed))
+	}
+	return a.print(libraries, t)
+}
+
+func streamVideosList(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny stream videos list", "[-search text] [-collection id]")
+	search := set.String("search", "", "filter by title")
+	collection := set.String("collection", "", "only videos in this collection")
+	if _, err := parse(set, args, 0, 0); err != nil {
+		return err
+	}
+	videos, err := a.videos()
+	if err != nil {
+		return err
+	}
+	var list []stream.Video
+	for page := 1; ; page++ {
+		resp, err := videos.List(ctx, &stream.VideoListOptions{
+			Page:         page,
+			ItemsPerPage: 1000,
+			Search:       *search,
+			Collection:   *collection,
+		})
+		if err != nil {
+			return err
+		}
+		list = append(list, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			break
+		}
+	}
+
+	t := &table{header: []string{"ID", "TITLE", "STATE", "DURATION", "VIEWS", "UPLOADED"}}
+	for _, v := range list {
+		t.add(v.VideoID, v.Title, v.State, time.Duration(v.Duration)*time.Second, v.Views, v.UploadDate.Time)
+	}
+	return a.print(list, t)
+}
+
+func streamVideosUpload(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny stream videos upload", "[-title text] [-collection id] <file>")
+	title := set.String("title", "", "video title (default: file name)")
+	collection := set.String("collection", "", "collection to add the video to")
+	pos, err := parse(set, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	videos, err := a.videos()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *title == "" {
+		*title = strings.TrimSuffix(filepath.Base(pos[0]), filepath.Ext(pos[0]))
+	}
+	video, err := videos.Create(ctx, &stream.CreateVideoRequest{Title: *title, CollectionID: *collection})
+	if err != nil {
+		return err
+	}
+	if err := videos.Upload(ctx, video.VideoID, f); err != nil {
+		return fmt.Errorf("upload %s: %w", video.VideoID, err)
+	}
+	a.status("uploaded %s as video %s", pos[0], video.VideoID)
+	if a.format == "table" {
+		return nil
+	}
+	return a.print(video, nil)
+}
+
+func streamVideosWatch(ctx context.Context, a *app, args []string) error {
+	set := a.flags("bunny stream videos watch", "[-interval 5s] <video-id>")
+	interval := set.Duration("interval", 5*time.Second, "polling interval")
+	pos, err := parse(set, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	videos, err := a.videos()
+	if err != nil {
+		return err
+	}
+
+	var last stream.VideoState
+	for {
+		video, err := videos.Get(ctx, pos[0])
+		if err != nil {
+			return err
+		}
+		if video.State != last {
+			a.status("%s  %s", time.Now().UTC().Format(time.RFC3339), video.State)
+			last = video.State
+		}
+		switch video.State {
+		case stream.VideoStateFinished:
+			if a.format == "table" {
+				return nil
+			}
+			return a.print(video, nil)
+		case stream.VideoStateError:
+			return fmt.Errorf("video %s failed to encode: %s", video.VideoID, strings.Join(video.TranscodingMessages, "; "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(*interval):
+		}
+	}
+}
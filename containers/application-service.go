@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // ApplicationService provides methods for managing Magic Containers applications.
@@ -52,6 +54,7 @@ func newApplicationService(client httpClient) ApplicationService {
 
 // List returns all applications with optional pagination.
 func (s *applicationService) List(ctx context.Context, opts *ListOptions) (*ApplicationListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.List")
 	path := "/apps"
 	if opts != nil {
 		path = buildListPath(path, opts)
@@ -66,6 +69,7 @@ func (s *applicationService) List(ctx context.Context, opts *ListOptions) (*Appl
 
 // Get returns a specific application by ID.
 func (s *applicationService) Get(ctx context.Context, appID string) (*Application, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Get")
 	path := fmt.Sprintf("/apps/%s", appID)
 
 	var app Application
@@ -77,6 +81,7 @@ func (s *applicationService) Get(ctx context.Context, appID string) (*Applicatio
 
 // Create creates a new application.
 func (s *applicationService) Create(ctx context.Context, req *CreateApplicationRequest) (*ApplicationIDResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Create")
 	var resp ApplicationIDResponse
 	if err := s.client.do(ctx, http.MethodPost, "/apps", req, &resp); err != nil {
 		return nil, err
@@ -86,6 +91,7 @@ func (s *applicationService) Create(ctx context.Context, req *CreateApplicationR
 
 // Update performs a full update of an application (PUT).
 func (s *applicationService) Update(ctx context.Context, appID string, req *UpdateApplicationRequest) (*ApplicationIDResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Update")
 	path := fmt.Sprintf("/apps/%s", appID)
 
 	var resp ApplicationIDResponse
@@ -97,6 +103,7 @@ func (s *applicationService) Update(ctx context.Context, appID string, req *Upda
 
 // Patch performs a partial update of an application (PATCH).
 func (s *applicationService) Patch(ctx context.Context, appID string, req *PatchApplicationRequest) (*ApplicationIDResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Patch")
 	path := fmt.Sprintf("/apps/%s", appID)
 
 	var resp ApplicationIDResponse
@@ -108,30 +115,35 @@ func (s *applicationService) Patch(ctx context.Context, appID string, req *Patch
 
 // Delete deletes an application.
 func (s *applicationService) Delete(ctx context.Context, appID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Delete")
 	path := fmt.Sprintf("/apps/%s", appID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // Deploy deploys an application.
 func (s *applicationService) Deploy(ctx context.Context, appID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Deploy")
 	path := fmt.Sprintf("/apps/%s/deploy", appID)
 	return s.client.do(ctx, http.MethodPost, path, nil, nil)
 }
 
 // Undeploy undeploys an application.
 func (s *applicationService) Undeploy(ctx context.Context, appID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Undeploy")
 	path := fmt.Sprintf("/apps/%s/undeploy", appID)
 	return s.client.do(ctx, http.MethodPost, path, nil, nil)
 }
 
 // Restart restarts an application.
 func (s *applicationService) Restart(ctx context.Context, appID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.Restart")
 	path := fmt.Sprintf("/apps/%s/restart", appID)
 	return s.client.do(ctx, http.MethodPost, path, nil, nil)
 }
 
 // GetOverview returns overview metrics for an application.
 func (s *applicationService) GetOverview(ctx context.Context, appID string) (*ApplicationOverview, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.GetOverview")
 	path := fmt.Sprintf("/apps/%s/overview", appID)
 
 	var overview ApplicationOverview
@@ -143,6 +155,7 @@ func (s *applicationService) GetOverview(ctx context.Context, appID string) (*Ap
 
 // GetStatistics returns statistics for an application.
 func (s *applicationService) GetStatistics(ctx context.Context, appID string, opts *StatisticsOptions) (*ApplicationStatistics, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ApplicationService.GetStatistics")
 	path := fmt.Sprintf("/apps/%s/statistics", appID)
 	if opts != nil {
 		path = buildStatisticsPath(path, opts)
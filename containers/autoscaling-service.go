@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // AutoscalingService provides methods for managing application autoscaling.
@@ -29,6 +31,7 @@ func newAutoscalingService(client httpClient, appID string) AutoscalingService {
 
 // Get returns the autoscaling settings for an application.
 func (s *autoscalingService) Get(ctx context.Context) (*AutoScaling, error) {
+	ctx = middleware.WithOperation(ctx, "containers.AutoscalingService.Get")
 	path := fmt.Sprintf("/apps/%s/autoscaling", s.appID)
 
 	var settings AutoScaling
@@ -40,6 +43,7 @@ func (s *autoscalingService) Get(ctx context.Context) (*AutoScaling, error) {
 
 // Update updates the autoscaling settings for an application.
 func (s *autoscalingService) Update(ctx context.Context, req *AutoScaling) error {
+	ctx = middleware.WithOperation(ctx, "containers.AutoscalingService.Update")
 	path := fmt.Sprintf("/apps/%s/autoscaling", s.appID)
 	return s.client.do(ctx, http.MethodPut, path, req, nil)
 }
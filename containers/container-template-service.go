@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // ContainerTemplateService provides methods for managing container templates.
@@ -38,6 +40,7 @@ func newContainerTemplateService(client httpClient, appID string) ContainerTempl
 
 // Get returns a specific container template.
 func (s *containerTemplateService) Get(ctx context.Context, containerID string) (*ContainerTemplate, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ContainerTemplateService.Get")
 	path := fmt.Sprintf("/apps/%s/containers/%s", s.appID, containerID)
 
 	var template ContainerTemplate
@@ -49,6 +52,7 @@ func (s *containerTemplateService) Get(ctx context.Context, containerID string)
 
 // Create adds a new container template to the application.
 func (s *containerTemplateService) Create(ctx context.Context, req *CreateContainerTemplateRequest) (*ContainerTemplate, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ContainerTemplateService.Create")
 	path := fmt.Sprintf("/apps/%s/containers", s.appID)
 
 	var template ContainerTemplate
@@ -60,6 +64,7 @@ func (s *containerTemplateService) Create(ctx context.Context, req *CreateContai
 
 // Patch performs a partial update of a container template.
 func (s *containerTemplateService) Patch(ctx context.Context, containerID string, req *PatchContainerTemplateRequest) (*ContainerTemplate, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ContainerTemplateService.Patch")
 	path := fmt.Sprintf("/apps/%s/containers/%s", s.appID, containerID)
 
 	var template ContainerTemplate
@@ -71,12 +76,14 @@ func (s *containerTemplateService) Patch(ctx context.Context, containerID string
 
 // Delete removes a container template from the application.
 func (s *containerTemplateService) Delete(ctx context.Context, containerID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.ContainerTemplateService.Delete")
 	path := fmt.Sprintf("/apps/%s/containers/%s", s.appID, containerID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // SetEnvironmentVariables sets environment variables for a container.
 func (s *containerTemplateService) SetEnvironmentVariables(ctx context.Context, containerID string, envVars SetEnvironmentVariablesRequest) (*ContainerTemplate, error) {
+	ctx = middleware.WithOperation(ctx, "containers.ContainerTemplateService.SetEnvironmentVariables")
 	path := fmt.Sprintf("/apps/%s/containers/%s/env", s.appID, containerID)
 
 	var template ContainerTemplate
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // EndpointService provides methods for managing application endpoints.
@@ -35,6 +37,7 @@ func newEndpointService(client httpClient, appID string) EndpointService {
 
 // List returns all endpoints for the application.
 func (s *endpointService) List(ctx context.Context) (*EndpointListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.EndpointService.List")
 	path := fmt.Sprintf("/apps/%s/endpoints", s.appID)
 
 	var resp EndpointListResponse
@@ -46,6 +49,7 @@ func (s *endpointService) List(ctx context.Context) (*EndpointListResponse, erro
 
 // Create adds a new endpoint to a container.
 func (s *endpointService) Create(ctx context.Context, containerID string, req *EndpointRequest) (*EndpointIDResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.EndpointService.Create")
 	path := fmt.Sprintf("/apps/%s/containers/%s/endpoints", s.appID, containerID)
 
 	var resp EndpointIDResponse
@@ -57,12 +61,14 @@ func (s *endpointService) Create(ctx context.Context, containerID string, req *E
 
 // Update updates an existing endpoint.
 func (s *endpointService) Update(ctx context.Context, endpointID string, req *EndpointRequest) error {
+	ctx = middleware.WithOperation(ctx, "containers.EndpointService.Update")
 	path := fmt.Sprintf("/apps/%s/endpoints/%s", s.appID, endpointID)
 	return s.client.do(ctx, http.MethodPut, path, req, nil)
 }
 
 // Delete removes an endpoint.
 func (s *endpointService) Delete(ctx context.Context, endpointID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.EndpointService.Delete")
 	path := fmt.Sprintf("/apps/%s/endpoints/%s", s.appID, endpointID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // LogForwardingService provides methods for managing log forwarding configurations.
@@ -34,6 +36,7 @@ func newLogForwardingService(client httpClient) LogForwardingService {
 
 // List returns all log forwarding configurations.
 func (s *logForwardingService) List(ctx context.Context) (*LogForwardingListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.LogForwardingService.List")
 	var resp LogForwardingListResponse
 	if err := s.client.do(ctx, http.MethodGet, "/log/forwarding", nil, &resp); err != nil {
 		return nil, err
@@ -43,6 +46,7 @@ func (s *logForwardingService) List(ctx context.Context) (*LogForwardingListResp
 
 // Get returns the log forwarding configuration for a specific application.
 func (s *logForwardingService) Get(ctx context.Context, appID string) (*LogForwardingConfig, error) {
+	ctx = middleware.WithOperation(ctx, "containers.LogForwardingService.Get")
 	path := fmt.Sprintf("/log/forwarding/%s", appID)
 
 	var config LogForwardingConfig
@@ -54,6 +58,7 @@ func (s *logForwardingService) Get(ctx context.Context, appID string) (*LogForwa
 
 // Create creates a new log forwarding configuration.
 func (s *logForwardingService) Create(ctx context.Context, req *CreateLogForwardingRequest) (*LogForwardingConfig, error) {
+	ctx = middleware.WithOperation(ctx, "containers.LogForwardingService.Create")
 	var config LogForwardingConfig
 	if err := s.client.do(ctx, http.MethodPost, "/log/forwarding", req, &config); err != nil {
 		return nil, err
@@ -63,6 +68,7 @@ func (s *logForwardingService) Create(ctx context.Context, req *CreateLogForward
 
 // Update updates an existing log forwarding configuration.
 func (s *logForwardingService) Update(ctx context.Context, appID string, req *UpdateLogForwardingRequest) (*LogForwardingConfig, error) {
+	ctx = middleware.WithOperation(ctx, "containers.LogForwardingService.Update")
 	path := fmt.Sprintf("/log/forwarding/%s", appID)
 
 	var config LogForwardingConfig
@@ -74,6 +80,7 @@ func (s *logForwardingService) Update(ctx context.Context, appID string, req *Up
 
 // Delete removes a log forwarding configuration.
 func (s *logForwardingService) Delete(ctx context.Context, appID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.LogForwardingService.Delete")
 	path := fmt.Sprintf("/log/forwarding/%s", appID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
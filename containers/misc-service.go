@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // LimitsService provides methods for retrieving user limits.
@@ -22,6 +24,7 @@ func newLimitsService(client httpClient) LimitsService {
 
 // Get returns the current user's limits.
 func (s *limitsService) Get(ctx context.Context) (*UserLimits, error) {
+	ctx = middleware.WithOperation(ctx, "containers.LimitsService.Get")
 	var limits UserLimits
 	if err := s.client.do(ctx, http.MethodGet, "/limits", nil, &limits); err != nil {
 		return nil, err
@@ -45,6 +48,7 @@ func newNodeService(client httpClient) NodeService {
 
 // List returns all available nodes.
 func (s *nodeService) List(ctx context.Context, opts *ListOptions) (*NodeListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.NodeService.List")
 	path := "/nodes"
 	if opts != nil {
 		path = buildListPath(path, opts)
@@ -77,6 +81,7 @@ func newPodService(client httpClient, appID string) PodService {
 
 // Recreate triggers recreation of a specific pod.
 func (s *podService) Recreate(ctx context.Context, podID string) error {
+	ctx = middleware.WithOperation(ctx, "containers.PodService.Recreate")
 	path := fmt.Sprintf("/apps/%s/pods/%s/recreate", s.appID, podID)
 	return s.client.do(ctx, http.MethodPost, path, nil, nil)
 }
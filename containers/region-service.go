@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // RegionService provides methods for managing regions.
@@ -26,6 +28,7 @@ func newRegionService(client httpClient) RegionService {
 
 // List returns all available regions.
 func (s *regionService) List(ctx context.Context, opts *ListOptions) (*RegionListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegionService.List")
 	path := "/regions"
 	if opts != nil {
 		path = buildListPath(path, opts)
@@ -40,6 +43,7 @@ func (s *regionService) List(ctx context.Context, opts *ListOptions) (*RegionLis
 
 // GetOptimal returns the optimal region based on location.
 func (s *regionService) GetOptimal(ctx context.Context, cdnServerToken string) (*OptimalRegionResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegionService.GetOptimal")
 	path := "/regions/optimal"
 	if cdnServerToken != "" {
 		params := url.Values{}
@@ -77,6 +81,7 @@ func newRegionSettingsService(client httpClient, appID string) RegionSettingsSer
 
 // Get returns the region settings for an application.
 func (s *regionSettingsService) Get(ctx context.Context) (*RegionSettings, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegionSettingsService.Get")
 	path := fmt.Sprintf("/apps/%s/region-settings", s.appID)
 
 	var settings RegionSettings
@@ -88,6 +93,7 @@ func (s *regionSettingsService) Get(ctx context.Context) (*RegionSettings, error
 
 // Update updates the region settings for an application.
 func (s *regionSettingsService) Update(ctx context.Context, req *UpdateRegionSettingsRequest) error {
+	ctx = middleware.WithOperation(ctx, "containers.RegionSettingsService.Update")
 	path := fmt.Sprintf("/apps/%s/region-settings", s.appID)
 	return s.client.do(ctx, http.MethodPut, path, req, nil)
 }
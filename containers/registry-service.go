@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // RegistryService provides methods for managing container registries.
@@ -49,6 +51,7 @@ func newRegistryService(client httpClient) RegistryService {
 
 // List returns all container registries.
 func (s *registryService) List(ctx context.Context) (*ContainerRegistryListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.List")
 	var resp ContainerRegistryListResponse
 	if err := s.client.do(ctx, http.MethodGet, "/registries", nil, &resp); err != nil {
 		return nil, err
@@ -58,6 +61,7 @@ func (s *registryService) List(ctx context.Context) (*ContainerRegistryListRespo
 
 // Get returns a specific registry by ID.
 func (s *registryService) Get(ctx context.Context, registryID int64) (*ContainerRegistry, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.Get")
 	path := fmt.Sprintf("/registries/%d", registryID)
 
 	var registry ContainerRegistry
@@ -69,6 +73,7 @@ func (s *registryService) Get(ctx context.Context, registryID int64) (*Container
 
 // Create adds a new container registry.
 func (s *registryService) Create(ctx context.Context, req *CreateRegistryRequest) (*RegistryOperationResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.Create")
 	var resp RegistryOperationResponse
 	if err := s.client.do(ctx, http.MethodPost, "/registries", req, &resp); err != nil {
 		return nil, err
@@ -78,6 +83,7 @@ func (s *registryService) Create(ctx context.Context, req *CreateRegistryRequest
 
 // Update updates an existing registry.
 func (s *registryService) Update(ctx context.Context, registryID int64, req *UpdateRegistryRequest) (*RegistryOperationResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.Update")
 	path := fmt.Sprintf("/registries/%d", registryID)
 
 	var resp RegistryOperationResponse
@@ -89,6 +95,7 @@ func (s *registryService) Update(ctx context.Context, registryID int64, req *Upd
 
 // Delete removes a registry.
 func (s *registryService) Delete(ctx context.Context, registryID int64) (*RegistryDeleteResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.Delete")
 	path := fmt.Sprintf("/registries/%d", registryID)
 
 	var resp RegistryDeleteResponse
@@ -100,6 +107,7 @@ func (s *registryService) Delete(ctx context.Context, registryID int64) (*Regist
 
 // ListImages lists container images in a registry.
 func (s *registryService) ListImages(ctx context.Context, req *ListImagesRequest) ([]ContainerImage, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.ListImages")
 	var images []ContainerImage
 	if err := s.client.do(ctx, http.MethodPost, "/registries/images", req, &images); err != nil {
 		return nil, err
@@ -109,6 +117,7 @@ func (s *registryService) ListImages(ctx context.Context, req *ListImagesRequest
 
 // ListTags lists tags for a container image.
 func (s *registryService) ListTags(ctx context.Context, req *ListTagsRequest) ([]ImageTag, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.ListTags")
 	var tags []ImageTag
 	if err := s.client.do(ctx, http.MethodPost, "/registries/tags", req, &tags); err != nil {
 		return nil, err
@@ -118,6 +127,7 @@ func (s *registryService) ListTags(ctx context.Context, req *ListTagsRequest) ([
 
 // GetDigest gets the digest for a specific image tag.
 func (s *registryService) GetDigest(ctx context.Context, req *GetDigestRequest) (*ImageDigest, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.GetDigest")
 	var digest ImageDigest
 	if err := s.client.do(ctx, http.MethodPost, "/registries/digest", req, &digest); err != nil {
 		return nil, err
@@ -127,6 +137,7 @@ func (s *registryService) GetDigest(ctx context.Context, req *GetDigestRequest)
 
 // GetConfigSuggestions gets configuration suggestions for an image.
 func (s *registryService) GetConfigSuggestions(ctx context.Context, req *GetConfigSuggestionsRequest) (*ConfigSuggestions, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.GetConfigSuggestions")
 	var suggestions ConfigSuggestions
 	if err := s.client.do(ctx, http.MethodPost, "/registries/config-suggestions", req, &suggestions); err != nil {
 		return nil, err
@@ -136,6 +147,7 @@ func (s *registryService) GetConfigSuggestions(ctx context.Context, req *GetConf
 
 // SearchPublicImages searches for public container images.
 func (s *registryService) SearchPublicImages(ctx context.Context, req *SearchPublicImagesRequest) ([]ContainerImage, error) {
+	ctx = middleware.WithOperation(ctx, "containers.RegistryService.SearchPublicImages")
 	var images []ContainerImage
 	if err := s.client.do(ctx, http.MethodPost, "/registries/public-images/search", req, &images); err != nil {
 		return nil, err
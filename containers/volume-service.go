@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // VolumeService provides methods for managing application volumes.
@@ -39,6 +41,7 @@ func newVolumeService(client httpClient, appID string) VolumeService {
 
 // List returns all volumes for the application.
 func (s *volumeService) List(ctx context.Context) (*VolumeListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.VolumeService.List")
 	path := fmt.Sprintf("/apps/%s/volumes", s.appID)
 
 	var resp VolumeListResponse
@@ -50,6 +53,7 @@ func (s *volumeService) List(ctx context.Context) (*VolumeListResponse, error) {
 
 // Update updates a volume's name or size.
 func (s *volumeService) Update(ctx context.Context, volumeID string, req *UpdateVolumeRequest) (*VolumeUpdateResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.VolumeService.Update")
 	path := fmt.Sprintf("/apps/%s/volumes/%s", s.appID, volumeID)
 
 	var resp VolumeUpdateResponse
@@ -61,6 +65,7 @@ func (s *volumeService) Update(ctx context.Context, volumeID string, req *Update
 
 // Detach detaches a volume from all pods.
 func (s *volumeService) Detach(ctx context.Context, volumeID string) (*VolumeNameResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.VolumeService.Detach")
 	path := fmt.Sprintf("/apps/%s/volumes/%s/detach", s.appID, volumeID)
 
 	var resp VolumeNameResponse
@@ -72,6 +77,7 @@ func (s *volumeService) Detach(ctx context.Context, volumeID string) (*VolumeNam
 
 // DeleteInstance deletes a specific volume instance.
 func (s *volumeService) DeleteInstance(ctx context.Context, volumeID string, instanceID string) (*VolumeInstanceIDResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.VolumeService.DeleteInstance")
 	path := fmt.Sprintf("/apps/%s/volumes/%s/instances/%s", s.appID, volumeID, instanceID)
 
 	var resp VolumeInstanceIDResponse
@@ -84,6 +90,7 @@ func (s *volumeService) DeleteInstance(ctx context.Context, volumeID string, ins
 // DeleteAll deletes all instances of a volume.
 // Note: All instances must be detached before deletion.
 func (s *volumeService) DeleteAll(ctx context.Context, volumeID string) (*VolumeInstanceIDsResponse, error) {
+	ctx = middleware.WithOperation(ctx, "containers.VolumeService.DeleteAll")
 	path := fmt.Sprintf("/apps/%s/volumes/%s", s.appID, volumeID)
 
 	var resp VolumeInstanceIDsResponse
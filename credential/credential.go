@@ -0,0 +1,213 @@
+// Package credential holds keys that can be rotated while clients are using
+// them, such as storage zone passwords and edge script deployment keys.
+//
+// A Secret is shared by every client that sends the key. Rotators in the
+// storage and scripting packages mark it as rotating before asking the API
+// for a new key and swap the value in place once it is known, so requests
+// rejected in between are retried with the new key instead of failing:
+//
+//	password := credential.New(zone.Password)
+//	files := storage.NewFileService(zone.Name, "", storage.RegionFalkenstein,
+//		storage.WithFileCredential(password))
+//
+//	rotator := storage.NewRotator(client.Zones(), saveToVault)
+//	rotator.Watch(zone.ID, password)
+//	newPassword, err := rotator.RotatePassword(ctx, zone.ID)
+package credential
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
+)
+
+// Secret is a key that can be replaced while it is in use. It is safe for
+// concurrent use.
+type Secret struct {
+	mu       sync.Mutex
+	value    string
+	pending  int
+	rotating chan struct{} // closed when the last pending rotation ends
+
+	refresh   RefreshFunc
+	refreshMu sync.Mutex // serializes refreshes
+}
+
+// RefreshFunc looks up the value of a Secret. rejected is empty for the
+// initial lookup of a Secret without a value, and the rejected value when
+// the API refused it.
+type RefreshFunc func(ctx context.Context, rejected string) (string, error)
+
+// Option is a functional option for New.
+type Option func(*Secret)
+
+// WithRefresh sets how the secret is looked up when it has no value yet and
+// when the API rejects it outside a rotation, for keys that are resolved on
+// demand instead of configured.
+func WithRefresh(fn RefreshFunc) Option {
+	return func(s *Secret) {
+		s.refresh = fn
+	}
+}
+
+// New returns a Secret holding value.
+func New(value string, opts ...Option) *Secret {
+	s := &Secret{value: value}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Get returns the current value.
+func (s *Secret) Get() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
+
+// Set replaces the value. Requests already sent with the old value and
+// rejected for it are retried with the new one.
+func (s *Secret) Set(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.value = value
+}
+
+// Resolve returns the current value, looking it up first when the secret
+// has none and a refresh function was set.
+func (s *Secret) Resolve(ctx context.Context) (string, error) {
+	if value := s.Get(); value != "" || s.refresh == nil {
+		return value, nil
+	}
+	return s.refreshFrom(ctx, "")
+}
+
+// refreshFrom replaces rejected with a looked-up value, unless a concurrent
+// caller already did.
+func (s *Secret) refreshFrom(ctx context.Context, rejected string) (string, error) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if value := s.Get(); value != rejected {
+		return value, nil
+	}
+	value, err := s.refresh(ctx, rejected)
+	if err != nil {
+		return "", err
+	}
+	s.Set(value)
+	return value, nil
+}
+
+// BeginRotation marks the secret as being rotated until end is called.
+// Meanwhile, callers of Next whose key was rejected wait for the new value.
+// end sets the new value, or keeps the old one when value is empty, for
+// example because the rotation failed. Calling end more than once has no
+// further effect.
+func (s *Secret) BeginRotation() (end func(value string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rotating == nil {
+		s.rotating = make(chan struct{})
+	}
+	s.pending++
+	var once sync.Once
+	return func(value string) {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if value != "" {
+				s.value = value
+			}
+			if s.pending--; s.pending == 0 {
+				close(s.rotating)
+				s.rotating = nil
+			}
+		})
+	}
+}
+
+// Next returns a value to retry with after used was rejected. It returns
+// the current value if it differs from used, waiting for a rotation in
+// progress to end first if necessary, or else a refreshed value when a
+// refresh function was set. It returns false if there is no newer value or
+// ctx is done.
+func (s *Secret) Next(ctx context.Context, used string) (string, bool) {
+	for {
+		s.mu.Lock()
+		value, rotating := s.value, s.rotating
+		s.mu.Unlock()
+		if value != used {
+			return value, true
+		}
+		if rotating == nil {
+			if s.refresh == nil {
+				return "", false
+			}
+			value, err := s.refreshFrom(ctx, used)
+			return value, err == nil && value != "" && value != used
+		}
+		select {
+		case <-rotating:
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+}
+
+// Header sets the named header to the current value of s on every request.
+// A request rejected with 401 Unauthorized is retried once with the new
+// value when s was rotated or refreshed in the meantime, provided its body
+// can be replayed. Requests fail without being sent when the initial lookup
+// of s fails.
+func Header(name string, s *Secret) middleware.Middleware {
+	return func(next middleware.RoundTripFunc) middleware.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			used, err := s.Resolve(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set(name, used)
+			resp, err := next(req)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				return resp, nil
+			}
+			value, ok := s.Next(req.Context(), used)
+			if !ok {
+				return resp, nil
+			}
+			retry := req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return resp, nil
+				}
+				retry.Body = body
+			}
+			resp.Body.Close()
+			retry.Header.Set(name, value)
+			return next(retry)
+		}
+	}
+}
+
+// Rotation describes a key that was rotated.
+type Rotation struct {
+	// Kind identifies the key, such as storage.KindZonePassword.
+	Kind string
+	// ID is the ID of the storage zone or edge script the key belongs to.
+	ID int64
+	// Value is the new key.
+	Value string
+}
+
+// Hook is called after a key was rotated and every watched Secret holds the
+// new value, typically to write it to a secret store. The old key no longer
+// works by then, so a failing hook does not undo the rotation.
+type Hook func(ctx context.Context, r Rotation) error
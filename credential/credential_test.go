@@ -0,0 +1,152 @@
+package credential_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/credential"
+)
+
+func status(code int) *http.Response {
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(""))}
+}
+
+func TestHeaderRetriesAfterRotation(t *testing.T) {
+	secret := credential.New("old")
+	end := secret.BeginRotation()
+
+	var keys, bodies []string
+	next := func(req *http.Request) (*http.Response, error) {
+		key := req.Header.Get("AccessKey")
+		keys = append(keys, key)
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if key != "new" {
+			// The server has already rotated the key; the rotation ends
+			// while the rejected request waits for it.
+			go end("new")
+			return status(http.StatusUnauthorized), nil
+		}
+		return status(http.StatusOK), nil
+	}
+	mw := credential.Header("AccessKey", secret)
+
+	req, _ := http.NewRequest(http.MethodPut, "http://example.test/f", strings.NewReader("data"))
+	resp, err := mw(next)(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("resp = %v, %v", resp, err)
+	}
+	if len(keys) != 2 || keys[1] != "new" || bodies[1] != "data" {
+		t.Errorf("keys = %v, bodies = %q", keys, bodies)
+	}
+
+	// Without a rotation a rejected request is returned as is.
+	keys = nil
+	rejected := func(req *http.Request) (*http.Response, error) {
+		keys = append(keys, req.Header.Get("AccessKey"))
+		return status(http.StatusUnauthorized), nil
+	}
+	req, _ = http.NewRequest(http.MethodGet, "http://example.test/f", nil)
+	if resp, _ := mw(rejected)(req); resp.StatusCode != http.StatusUnauthorized || len(keys) != 1 {
+		t.Errorf("status = %d after %d attempts", resp.StatusCode, len(keys))
+	}
+}
+
+func TestNextHonoursContext(t *testing.T) {
+	secret := credential.New("old")
+	end := secret.BeginRotation()
+	defer end("")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if v, ok := secret.Next(ctx, "old"); ok {
+		t.Errorf("Next = %q, want no value", v)
+	}
+	if v, ok := secret.Next(context.Background(), "older"); !ok || v != "old" {
+		t.Errorf("Next = %q, %v", v, ok)
+	}
+}
+
+func TestRotator(t *testing.T) {
+	var r credential.Rotator
+	a, b := credential.New("k1"), credential.New("k1")
+	r.Watch("test.key", 1, a)
+	r.Watch("test.key", 1, b)
+	other := credential.New("x")
+	r.Watch("test.key", 2, other)
+
+	var stored []credential.Rotation
+	r.OnRotate(func(_ context.Context, rot credential.Rotation) error {
+		if a.Get() != rot.Value {
+			t.Errorf("hook ran before the secret was swapped")
+		}
+		stored = append(stored, rot)
+		return nil
+	})
+	r.OnRotate(func(context.Context, credential.Rotation) error {
+		return errors.New("vault unavailable")
+	})
+
+	value, err := r.Rotate(context.Background(), "test.key", 1, func(context.Context) (string, error) {
+		return "k2", nil
+	})
+	if value != "k2" || err == nil || !strings.Contains(err.Error(), "vault unavailable") {
+		t.Errorf("Rotate = %q, %v", value, err)
+	}
+	if a.Get() != "k2" || b.Get() != "k2" || other.Get() != "x" {
+		t.Errorf("secrets = %q, %q, %q", a.Get(), b.Get(), other.Get())
+	}
+	if len(stored) != 1 || stored[0] != (credential.Rotation{Kind: "test.key", ID: 1, Value: "k2"}) {
+		t.Errorf("hook saw %+v", stored)
+	}
+
+	_, err = r.Rotate(context.Background(), "test.key", 1, func(context.Context) (string, error) {
+		return "", errors.New("reset failed")
+	})
+	if err == nil || a.Get() != "k2" || len(stored) != 1 {
+		t.Errorf("failed rotation: err = %v, secret = %q, hooks = %d", err, a.Get(), len(stored))
+	}
+	if _, ok := a.Next(context.Background(), "k2"); ok {
+		t.Error("secret still rotating after a failed rotation")
+	}
+}
+
+func TestHeaderRefreshesRejectedKey(t *testing.T) {
+	var lookups []string
+	keys := []string{"first", "second"}
+	secret := credential.New("", credential.WithRefresh(func(ctx context.Context, rejected string) (string, error) {
+		lookups = append(lookups, rejected)
+		key := keys[0]
+		keys = keys[1:]
+		return key, nil
+	}))
+
+	var sent []string
+	next := func(req *http.Request) (*http.Response, error) {
+		key := req.Header.Get("AccessKey")
+		sent = append(sent, key)
+		if key != "second" {
+			return status(http.StatusUnauthorized), nil
+		}
+		return status(http.StatusOK), nil
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.test/f", nil)
+	resp, err := credential.Header("AccessKey", secret)(next)(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("resp = %v, %v", resp, err)
+	}
+	if strings.Join(lookups, ",") != ",first" || strings.Join(sent, ",") != "first,second" {
+		t.Errorf("lookups = %q, sent = %q", lookups, sent)
+	}
+
+	failing := credential.New("", credential.WithRefresh(func(context.Context, string) (string, error) {
+		return "", errors.New("lookup failed")
+	}))
+	if _, err := credential.Header("AccessKey", failing)(next)(req); err == nil {
+		t.Error("expected the lookup error")
+	}
+}
@@ -0,0 +1,81 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Rotator runs key rotations: it marks the watched secrets as rotating,
+// obtains the new key, swaps it into the secrets and calls the hooks. The
+// zero value is ready to use. storage.Rotator and scripting.KeyRotator wrap
+// it for their keys.
+type Rotator struct {
+	mu      sync.Mutex
+	secrets map[rotationKey][]*Secret
+	hooks   []Hook
+}
+
+type rotationKey struct {
+	kind string
+	id   int64
+}
+
+// Watch updates s whenever the key of the given kind and ID is rotated.
+func (r *Rotator) Watch(kind string, id int64, s *Secret) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.secrets == nil {
+		r.secrets = make(map[rotationKey][]*Secret)
+	}
+	k := rotationKey{kind, id}
+	r.secrets[k] = append(r.secrets[k], s)
+}
+
+// OnRotate adds a hook called after every rotation, in the order added.
+func (r *Rotator) OnRotate(h Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, h)
+}
+
+// Rotate rotates the key of the given kind and ID with rotate, which must
+// return the new key. If the hooks fail, the new key is returned together
+// with their errors.
+func (r *Rotator) Rotate(ctx context.Context, kind string, id int64, rotate func(context.Context) (string, error)) (string, error) {
+	r.mu.Lock()
+	secrets := append([]*Secret(nil), r.secrets[rotationKey{kind, id}]...)
+	hooks := append([]Hook(nil), r.hooks...)
+	r.mu.Unlock()
+
+	ends := make([]func(string), len(secrets))
+	for i, s := range secrets {
+		ends[i] = s.BeginRotation()
+	}
+	value, err := rotate(ctx)
+	if err == nil && value == "" {
+		err = errors.New("credential: rotation returned an empty key")
+	}
+	if err != nil {
+		value = ""
+	}
+	for _, end := range ends {
+		end(value)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var errs []error
+	rot := Rotation{Kind: kind, ID: id, Value: value}
+	for _, h := range hooks {
+		if err := h(ctx, rot); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return value, fmt.Errorf("%s %d rotated but not every hook succeeded: %w", kind, id, err)
+	}
+	return value, nil
+}
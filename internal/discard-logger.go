@@ -0,0 +1,20 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+)
+
+// DiscardLogger returns a logger that drops every record, for components
+// whose logger was not configured. It stands in for slog.DiscardHandler,
+// which needs Go 1.24.
+func DiscardLogger() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DecodeArray decodes a JSON array from r one element at a time and calls
+// yield with each, so only one element is held in memory. With an empty
+// field the array is the top-level value; otherwise it is the value of that
+// field of the top-level object, matched case-insensitively like
+// encoding/json, and a missing or null field yields nothing. Decoding stops
+// without error when yield returns false.
+func DecodeArray[T any](r io.Reader, field string, yield func(T) bool) error {
+	dec := json.NewDecoder(r)
+	if field == "" {
+		return decodeElements(dec, yield)
+	}
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if key, _ := tok.(string); strings.EqualFold(key, field) {
+			return decodeElements(dec, yield)
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeElements[T any](dec *json.Decoder, yield func(T) bool) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("failed to decode response: expected array, got %v", tok)
+	}
+	for dec.More() {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if !yield(v) {
+			return nil
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("failed to decode response: expected %v, got %v", want, tok)
+	}
+	return nil
+}
+
+// skipValue reads past the next value without keeping it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+)
+
+func TestDecodeArray(t *testing.T) {
+	type item struct {
+		N int `json:"n"`
+	}
+	tests := []struct {
+		name    string
+		body    string
+		field   string
+		limit   int
+		want    []int
+		wantErr bool
+	}{
+		{name: "top-level array", body: `[{"n":1},{"n":2},{"n":3}]`, want: []int{1, 2, 3}},
+		{name: "field after skipped values", body: `{"total":3,"meta":{"a":[1,[2]],"b":null},"ITEMS":[{"n":1},{"n":2}]}`, field: "items", want: []int{1, 2}},
+		{name: "missing field", body: `{"total":0}`, field: "items"},
+		{name: "null array", body: `{"items":null}`, field: "items"},
+		{name: "stops early", body: `[{"n":1},{"n":2},{"n":3}]`, limit: 2, want: []int{1, 2}},
+		{name: "truncated", body: `[{"n":1},{"n":`, want: []int{1}, wantErr: true},
+		{name: "not an array", body: `{"items":{}}`, field: "items", wantErr: true},
+		{name: "not an object", body: `[]`, field: "items", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			err := internal.DecodeArray(strings.NewReader(tt.body), tt.field, func(v item) bool {
+				got = append(got, v.N)
+				return tt.limit == 0 || len(got) < tt.limit
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Package pipeline assembles the middleware stack shared by every client.
+package pipeline
+
+import "github.com/geraldo/bunny-sdk-go/middleware"
+
+// Config holds the middlewares configured through client options.
+type Config struct {
+	Tracer      middleware.Middleware
+	Middlewares []middleware.Middleware
+	RateLimiter middleware.Middleware
+	Metrics     middleware.Middleware
+	Logger      middleware.Middleware
+}
+
+// Wrap applies the configured middlewares to hc. The tracer is outermost so
+// one span covers every attempt of an operation; the rate limiter retries
+// inside it, and metrics and logging are innermost so they see each attempt.
+func (c *Config) Wrap(hc middleware.Doer) middleware.Doer {
+	mws := make([]middleware.Middleware, 0, len(c.Middlewares)+4)
+	mws = append(mws, c.Tracer)
+	mws = append(mws, c.Middlewares...)
+	mws = append(mws, c.RateLimiter, c.Metrics, c.Logger)
+	return middleware.Chain(hc, mws...)
+}
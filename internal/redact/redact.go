@@ -0,0 +1,99 @@
+// Package redact holds the credential redaction shared by request logging,
+// cassettes and snapshots, so the three never disagree on what is secret.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Placeholder replaces secret values.
+const Placeholder = "<redacted>"
+
+// fields are JSON field names, lower-case, whose values are secrets: zone
+// passwords, library API keys, registry credentials, edge script secrets
+// and deployment keys.
+var fields = []string{
+	"password",
+	"readonlypassword",
+	"apikey",
+	"readonlyapikey",
+	"passwordcredentials",
+	"token",
+	"secret",
+	"deploymentkey",
+}
+
+// secretWords mark environment variable names whose values are secrets.
+var secretWords = []string{"SECRET", "PASSWORD", "PASSWD", "TOKEN", "KEY", "CREDENTIAL", "PRIVATE"}
+
+// Fields returns a new set of the sensitive JSON field names, lower-case,
+// which callers may extend.
+func Fields() map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+	return set
+}
+
+// SecretName reports whether an environment variable name looks like it
+// holds a secret.
+func SecretName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, w := range secretWords {
+		if strings.Contains(upper, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value replaces the values of fields, matched case-insensitively at any
+// depth of a decoded JSON value, with Placeholder. It reports whether it
+// changed anything; null and empty values are left alone.
+func Value(v any, fields map[string]bool) bool {
+	changed := false
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if fields[strings.ToLower(k)] {
+				if child != nil && child != "" && child != Placeholder {
+					t[k] = Placeholder
+					changed = true
+				}
+				continue
+			}
+			if Value(child, fields) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range t {
+			if Value(child, fields) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// JSON redacts a JSON document with Value. It reports whether b is JSON;
+// b is returned as is when it is not or has nothing to redact.
+func JSON(b []byte, fields map[string]bool) ([]byte, bool) {
+	var v any
+	if len(b) == 0 || json.Unmarshal(b, &v) != nil {
+		return b, false
+	}
+	if !Value(v, fields) {
+		return b, true
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return b, false
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), true
+}
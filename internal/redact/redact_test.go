@@ -0,0 +1,35 @@
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/internal/redact"
+)
+
+func TestJSON(t *testing.T) {
+	in := `{"Name":"zone","Password":"pw","Nested":[{"token":"t"},{"PasswordCredentials":{"user":"u"}}],"ApiKey":"","Secret":null}`
+	out, ok := redact.JSON([]byte(in), redact.Fields())
+	if !ok {
+		t.Fatal("expected JSON")
+	}
+	want := `{"ApiKey":"","Name":"zone","Nested":[{"token":"<redacted>"},{"PasswordCredentials":"<redacted>"}],"Password":"<redacted>","Secret":null}`
+	if string(out) != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+
+	clean := []byte(`{"Name":  "zone"}`)
+	if out, ok := redact.JSON(clean, redact.Fields()); !ok || string(out) != string(clean) {
+		t.Errorf("expected an unchanged document, got %s", out)
+	}
+	if _, ok := redact.JSON([]byte("plain text"), redact.Fields()); ok {
+		t.Error("expected plain text to be reported as not JSON")
+	}
+}
+
+func TestSecretName(t *testing.T) {
+	for name, want := range map[string]bool{"DB_PASSWORD": true, "api_key": true, "LOG_LEVEL": false} {
+		if redact.SecretName(name) != want {
+			t.Errorf("SecretName(%q) = %v", name, !want)
+		}
+	}
+}
@@ -0,0 +1,669 @@
+// Package yaml implements the subset of YAML used by SDK configuration files.
+//
+// Supported: block mappings and sequences, flow collections on a single line,
+// plain/single-quoted/double-quoted scalars, literal (|) and folded (>) block
+// scalars, comments and a leading document marker. Anchors, aliases, tags and
+// multi-document streams are not supported.
+//
+// Values are converted through encoding/json, so struct fields are matched
+// using their json tags. Unquoted scalars such as 8080 or true are kept as
+// written when the target is a string.
+package yaml
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal parses YAML data and stores the result in the value pointed to by v.
+func Unmarshal(data []byte, v any) error {
+	node, err := parse(data)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(coerce(node, reflect.TypeOf(v)))
+	if err != nil {
+		return fmt.Errorf("yaml: %w", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("yaml: %w", err)
+	}
+	return nil
+}
+
+// Parse parses YAML data into maps, slices and scalar values.
+func Parse(data []byte) (any, error) {
+	node, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return coerce(node, nil), nil
+}
+
+// parse is Parse with unquoted scalars left as plain values.
+func parse(data []byte) (any, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+	p := &parser{lines: strings.Split(text, "\n")}
+
+	p.skipBlank()
+	if p.pos < len(p.lines) && strings.TrimSpace(stripComment(p.lines[p.pos])) == "---" {
+		p.pos++
+	}
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+
+	indent, _ := p.current()
+	node, err := p.parseBlock(indent)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected content")
+	}
+	return node, nil
+}
+
+type parser struct {
+	lines []string
+	pos   int
+
+	// pending holds a virtual line produced by "- key: value" sequence items.
+	pending       bool
+	pendingIndent int
+	pendingText   string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("yaml: line %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// skipBlank advances past empty and comment-only lines.
+func (p *parser) skipBlank() {
+	if p.pending {
+		return
+	}
+	for p.pos < len(p.lines) {
+		if strings.TrimSpace(stripComment(p.lines[p.pos])) != "" {
+			return
+		}
+		p.pos++
+	}
+}
+
+// current returns the indentation and content of the current line.
+func (p *parser) current() (int, string) {
+	if p.pending {
+		return p.pendingIndent, p.pendingText
+	}
+	raw := p.lines[p.pos]
+	content := strings.TrimRight(stripComment(raw), " \t")
+	indent := len(content) - len(strings.TrimLeft(content, " "))
+	return indent, strings.TrimLeft(content, " ")
+}
+
+func (p *parser) advance() {
+	if p.pending {
+		p.pending = false
+		p.pos++
+		return
+	}
+	p.pos++
+}
+
+func (p *parser) eof() bool {
+	p.skipBlank()
+	return !p.pending && p.pos >= len(p.lines)
+}
+
+// parseBlock parses the block node whose first line is at the given indentation.
+func (p *parser) parseBlock(indent int) (any, error) {
+	_, text := p.current()
+	if isSequenceItem(text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitKey(text); ok {
+		return p.parseMapping(indent)
+	}
+	p.advance()
+	return parseFlowOrScalar(text)
+}
+
+func (p *parser) parseMapping(indent int) (map[string]any, error) {
+	m := make(map[string]any)
+	for !p.eof() {
+		lineIndent, text := p.current()
+		if lineIndent < indent {
+			break
+		}
+		if lineIndent > indent {
+			return nil, p.errorf("bad indentation")
+		}
+		if isSequenceItem(text) {
+			break
+		}
+		key, rest, ok := splitKey(text)
+		if !ok {
+			return nil, p.errorf("expected key: value, got %q", text)
+		}
+		if _, dup := m[key]; dup {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		p.advance()
+
+		value, err := p.parseValue(indent, rest, true)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+func (p *parser) parseSequence(indent int) ([]any, error) {
+	seq := []any{}
+	for !p.eof() {
+		lineIndent, text := p.current()
+		if lineIndent != indent || !isSequenceItem(text) {
+			if lineIndent > indent {
+				return nil, p.errorf("bad indentation")
+			}
+			break
+		}
+		rest := strings.TrimLeft(text[1:], " ")
+		if rest == "" {
+			p.advance()
+			value, err := p.parseValue(indent, "", false)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, value)
+			continue
+		}
+
+		_, _, isKey := splitKey(rest)
+		if isKey || isSequenceItem(rest) {
+			// Reparse the remainder of the line as the first line of a nested block.
+			p.pending = true
+			p.pendingIndent = lineIndent + len(text) - len(rest)
+			p.pendingText = rest
+			value, err := p.parseBlock(p.pendingIndent)
+			if err != nil {
+				return nil, err
+			}
+			seq = append(seq, value)
+			continue
+		}
+
+		p.advance()
+		value, err := p.parseValue(indent, rest, false)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, value)
+	}
+	return seq, nil
+}
+
+// parseValue parses the value following "key:" or "-". inline is the rest of the line.
+func (p *parser) parseValue(parentIndent int, inline string, inMapping bool) (any, error) {
+	if inline == "|" || inline == "|-" || inline == "|+" || inline == ">" || inline == ">-" || inline == ">+" {
+		return p.parseBlockScalar(parentIndent, inline), nil
+	}
+	if inline != "" {
+		return parseFlowOrScalar(inline)
+	}
+	if p.eof() {
+		return nil, nil
+	}
+	nextIndent, nextText := p.current()
+	if nextIndent > parentIndent {
+		return p.parseBlock(nextIndent)
+	}
+	// A sequence may sit at the same indentation as its parent key.
+	if inMapping && nextIndent == parentIndent && isSequenceItem(nextText) {
+		return p.parseSequence(nextIndent)
+	}
+	return nil, nil
+}
+
+// parseBlockScalar reads a literal or folded block scalar.
+func (p *parser) parseBlockScalar(parentIndent int, header string) string {
+	var lines []string
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		raw := p.lines[p.pos]
+		if strings.TrimSpace(raw) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		if blockIndent < 0 {
+			if indent <= parentIndent {
+				break
+			}
+			blockIndent = indent
+		}
+		if indent < blockIndent {
+			break
+		}
+		lines = append(lines, raw[blockIndent:])
+		p.pos++
+	}
+
+	// Trailing blank lines belong to the chomping indicator, not the content.
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var text string
+	if header[0] == '>' {
+		text = foldLines(lines)
+	} else {
+		text = strings.Join(lines, "\n")
+	}
+
+	switch {
+	case strings.HasSuffix(header, "-"):
+		return text
+	case strings.HasSuffix(header, "+"):
+		return text + "\n" + strings.Repeat("\n", trailing)
+	case text == "":
+		return ""
+	default:
+		return text + "\n"
+	}
+}
+
+func foldLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			switch {
+			case line == "":
+				b.WriteString("\n")
+			case prev == "":
+				// The blank line already produced the line break.
+			case strings.HasPrefix(line, " ") || strings.HasPrefix(prev, " "):
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" outside of quotes and flow collections.
+func splitKey(text string) (key, rest string, ok bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 || end+1 >= len(text) || text[end+1] != ':' {
+			return "", "", false
+		}
+		if end+2 < len(text) && text[end+2] != ' ' {
+			return "", "", false
+		}
+		k, err := parseQuoted(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return k, strings.TrimSpace(text[end+2:]), true
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripComment removes a trailing comment that starts outside of quotes. A
+// quote only opens a string at the start of a scalar, so the apostrophe in
+// "don't # note" does not hide the comment.
+func stripComment(line string) string {
+	inSingle, inDouble := false, false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && inDouble:
+			i++
+		case c == '\'' && inSingle && i+1 < len(line) && line[i+1] == '\'':
+			i++
+		case c == '\'' && inSingle:
+			inSingle = false
+		case c == '"' && inDouble:
+			inDouble = false
+		case inSingle || inDouble:
+		case c == '\'' && scalarStart(line, i):
+			inSingle = true
+		case c == '"' && scalarStart(line, i):
+			inDouble = true
+		case c == '#' && !inSingle && !inDouble && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// scalarStart reports whether a scalar can start at line[i]: at the start of
+// the line or after an indicator such as ": ", "- " or a flow delimiter.
+func scalarStart(line string, i int) bool {
+	prev := strings.TrimRight(line[:i], " \t")
+	if prev == "" {
+		return true
+	}
+	switch prev[len(prev)-1] {
+	case '[', '{', ',':
+		return true
+	case ':', '-', '?':
+		return len(prev) < i
+	}
+	return false
+}
+
+func closingQuote(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func parseQuoted(text string) (string, error) {
+	if text[0] == '\'' {
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+	s, err := strconv.Unquote(text)
+	if err != nil {
+		return "", fmt.Errorf("yaml: invalid double-quoted string %s", text)
+	}
+	return s, nil
+}
+
+func parseFlowOrScalar(text string) (any, error) {
+	if text[0] == '[' || text[0] == '{' {
+		fp := &flowParser{text: text}
+		v, err := fp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		fp.skipSpace()
+		if fp.pos != len(fp.text) {
+			return nil, fmt.Errorf("yaml: unexpected %q after flow collection", fp.text[fp.pos:])
+		}
+		return v, nil
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end != len(text)-1 {
+			return nil, fmt.Errorf("yaml: invalid quoted scalar %s", text)
+		}
+		return parseQuoted(text)
+	}
+	return newPlain(text), nil
+}
+
+// plain is an unquoted scalar: its text and what it resolves to.
+type plain struct {
+	text  string
+	value any
+}
+
+func newPlain(s string) any {
+	v := resolvePlain(s)
+	if v == nil {
+		return nil
+	}
+	return plain{text: s, value: v}
+}
+
+var jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+
+// coerce resolves the plain scalars in node for decoding into t: to their
+// text where t expects a string and to their value elsewhere. A nil t
+// resolves every scalar to its value.
+func coerce(node any, t reflect.Type) any {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && (t.Implements(jsonUnmarshaler) || reflect.PointerTo(t).Implements(jsonUnmarshaler)) {
+		t = nil
+	}
+	switch n := node.(type) {
+	case plain:
+		if t != nil && t.Kind() == reflect.String {
+			return n.text
+		}
+		return n.value
+	case map[string]any:
+		for k, v := range n {
+			var elem reflect.Type
+			switch {
+			case t == nil:
+			case t.Kind() == reflect.Map:
+				elem = t.Elem()
+			case t.Kind() == reflect.Struct:
+				elem = fieldType(t, k)
+			}
+			n[k] = coerce(v, elem)
+		}
+	case []any:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for i, v := range n {
+			n[i] = coerce(v, elem)
+		}
+	}
+	return node
+}
+
+// fieldType returns the type of the struct field encoding/json would decode
+// key into, or nil if there is none.
+func fieldType(t reflect.Type, key string) reflect.Type {
+	var fold reflect.Type
+	var walk func(t reflect.Type) reflect.Type
+	walk = func(t reflect.Type) reflect.Type {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if tag == "-" {
+				continue
+			}
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && tag == "" && ft.Kind() == reflect.Struct {
+				if found := walk(ft); found != nil {
+					return found
+				}
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			name := cmp.Or(tag, f.Name)
+			if name == key {
+				return f.Type
+			}
+			if fold == nil && strings.EqualFold(name, key) {
+				fold = f.Type
+			}
+		}
+		return nil
+	}
+	if exact := walk(t); exact != nil {
+		return exact
+	}
+	return fold
+}
+
+// resolvePlain converts an unquoted scalar into a bool, number, nil or string.
+func resolvePlain(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if looksNumeric(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+func looksNumeric(s string) bool {
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9', c == '.', c == 'e', c == 'E':
+		case (c == '-' || c == '+') && (i == 0 || s[i-1] == 'e' || s[i-1] == 'E'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// flowParser parses single-line flow collections such as [a, b] and {k: v}.
+type flowParser struct {
+	text string
+	pos  int
+}
+
+func (f *flowParser) skipSpace() {
+	for f.pos < len(f.text) && (f.text[f.pos] == ' ' || f.text[f.pos] == '\t') {
+		f.pos++
+	}
+}
+
+func (f *flowParser) parseValue() (any, error) {
+	f.skipSpace()
+	if f.pos >= len(f.text) {
+		return nil, fmt.Errorf("yaml: unexpected end of flow collection")
+	}
+	switch f.text[f.pos] {
+	case '[':
+		return f.parseSequence()
+	case '{':
+		return f.parseMapping()
+	case '"', '\'':
+		end := closingQuote(f.text[f.pos:])
+		if end < 0 {
+			return nil, fmt.Errorf("yaml: unterminated string in flow collection")
+		}
+		s, err := parseQuoted(f.text[f.pos : f.pos+end+1])
+		f.pos += end + 1
+		return s, err
+	}
+	start := f.pos
+	for f.pos < len(f.text) && !strings.ContainsRune(",]}", rune(f.text[f.pos])) {
+		if f.text[f.pos] == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ') {
+			break
+		}
+		f.pos++
+	}
+	return newPlain(strings.TrimSpace(f.text[start:f.pos])), nil
+}
+
+func (f *flowParser) parseSequence() ([]any, error) {
+	f.pos++ // [
+	seq := []any{}
+	for {
+		f.skipSpace()
+		if f.pos < len(f.text) && f.text[f.pos] == ']' {
+			f.pos++
+			return seq, nil
+		}
+		v, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, v)
+		f.skipSpace()
+		if f.pos >= len(f.text) {
+			return nil, fmt.Errorf("yaml: unterminated flow sequence")
+		}
+		switch f.text[f.pos] {
+		case ',':
+			f.pos++
+		case ']':
+		default:
+			return nil, fmt.Errorf("yaml: unexpected %q in flow sequence", f.text[f.pos])
+		}
+	}
+}
+
+func (f *flowParser) parseMapping() (map[string]any, error) {
+	f.pos++ // {
+	m := make(map[string]any)
+	for {
+		f.skipSpace()
+		if f.pos < len(f.text) && f.text[f.pos] == '}' {
+			f.pos++
+			return m, nil
+		}
+		k, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		f.skipSpace()
+		if f.pos >= len(f.text) || f.text[f.pos] != ':' {
+			return nil, fmt.Errorf("yaml: expected ':' in flow mapping")
+		}
+		f.pos++
+		v, err := f.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if p, ok := k.(plain); ok {
+			k = p.text
+		}
+		m[fmt.Sprint(k)] = v
+		f.skipSpace()
+		if f.pos >= len(f.text) {
+			return nil, fmt.Errorf("yaml: unterminated flow mapping")
+		}
+		switch f.text[f.pos] {
+		case ',':
+			f.pos++
+		case '}':
+		default:
+			return nil, fmt.Errorf("yaml: unexpected %q in flow mapping", f.text[f.pos])
+		}
+	}
+}
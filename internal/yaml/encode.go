@@ -0,0 +1,250 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Marshal returns the YAML encoding of v.
+// The value is first encoded with encoding/json, so json tags and field order apply.
+func Marshal(v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	root, err := readNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+
+	var buf bytes.Buffer
+	switch n := root.(type) {
+	case *orderedMap:
+		if len(n.keys) == 0 {
+			buf.WriteString("{}\n")
+		}
+		writeMapping(&buf, n, 0)
+	case []any:
+		if len(n) == 0 {
+			buf.WriteString("[]\n")
+		}
+		writeSequence(&buf, n, 0)
+	default:
+		buf.WriteString(formatScalar(n))
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// orderedMap keeps JSON object keys in their encoded order.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func readNode(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := &orderedMap{values: make(map[string]any)}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyTok.(string)
+				value, err := readNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				m.keys = append(m.keys, key)
+				m.values[key] = value
+			}
+			_, err := dec.Token() // }
+			return m, err
+		case '[':
+			seq := []any{}
+			for dec.More() {
+				value, err := readNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				seq = append(seq, value)
+			}
+			_, err := dec.Token() // ]
+			return seq, err
+		}
+	}
+	return tok, nil
+}
+
+func writeMapping(buf *bytes.Buffer, m *orderedMap, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, key := range m.keys {
+		if i > 0 || indent > 0 {
+			buf.WriteString(pad)
+		}
+		writeEntry(buf, formatKey(key), m.values[key], indent)
+	}
+}
+
+// writeEntry writes "key: value" where the key has already been indented.
+func writeEntry(buf *bytes.Buffer, key string, value any, indent int) {
+	buf.WriteString(key)
+	buf.WriteString(":")
+	switch v := value.(type) {
+	case *orderedMap:
+		if len(v.keys) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeMapping(buf, v, indent+2)
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeSequence(buf, v, indent+2)
+	case string:
+		if isLiteralCandidate(v) {
+			writeLiteral(buf, v, indent+2)
+			return
+		}
+		buf.WriteString(" ")
+		buf.WriteString(formatScalar(v))
+		buf.WriteString("\n")
+	default:
+		buf.WriteString(" ")
+		buf.WriteString(formatScalar(v))
+		buf.WriteString("\n")
+	}
+}
+
+func writeSequence(buf *bytes.Buffer, seq []any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, item := range seq {
+		buf.WriteString(pad)
+		buf.WriteString("-")
+		switch v := item.(type) {
+		case *orderedMap:
+			if len(v.keys) == 0 {
+				buf.WriteString(" {}\n")
+				continue
+			}
+			buf.WriteString(" ")
+			// The first key shares the "- " line; the rest align under it.
+			writeEntry(buf, formatKey(v.keys[0]), v.values[v.keys[0]], indent+2)
+			rest := &orderedMap{keys: v.keys[1:], values: v.values}
+			for _, key := range rest.keys {
+				buf.WriteString(strings.Repeat(" ", indent+2))
+				writeEntry(buf, formatKey(key), v.values[key], indent+2)
+			}
+		case []any:
+			if len(v) == 0 {
+				buf.WriteString(" []\n")
+				continue
+			}
+			buf.WriteString("\n")
+			writeSequence(buf, v, indent+2)
+		default:
+			buf.WriteString(" ")
+			buf.WriteString(formatScalar(v))
+			buf.WriteString("\n")
+		}
+	}
+}
+
+// isLiteralCandidate reports whether s round-trips through a literal block scalar.
+func isLiteralCandidate(s string) bool {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") || strings.HasSuffix(s, "\n\n") {
+		return false
+	}
+	if strings.HasPrefix(s, " ") || strings.ContainsAny(s, "\r\t") {
+		return false
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if line != strings.TrimRight(line, " ") {
+			return false
+		}
+	}
+	return true
+}
+
+// writeLiteral writes a multi-line string as a literal block scalar.
+func writeLiteral(buf *bytes.Buffer, s string, indent int) {
+	if strings.HasSuffix(s, "\n") {
+		buf.WriteString(" |\n")
+	} else {
+		buf.WriteString(" |-\n")
+	}
+	pad := strings.Repeat(" ", indent)
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		if line != "" {
+			buf.WriteString(pad)
+			buf.WriteString(line)
+		}
+		buf.WriteString("\n")
+	}
+}
+
+func formatKey(key string) string {
+	if needsQuoting(key) {
+		return quote(key)
+	}
+	return key
+}
+
+func formatScalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if t {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return t.String()
+	case string:
+		if needsQuoting(t) {
+			return quote(t)
+		}
+		return t
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// needsQuoting reports whether a plain scalar would be read back as something else.
+func needsQuoting(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	if _, ok := resolvePlain(s).(string); !ok {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	return strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") ||
+		strings.ContainsAny(s, "\n\r\t")
+}
+
+func quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,154 @@
+package yaml_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/internal/yaml"
+)
+
+func TestUnmarshal(t *testing.T) {
+	src := `# environment spec
+name: production
+replicas: 3
+enabled: true
+ratio: 0.5
+empty: null
+tags: [a, "b c", 3]
+labels: {team: web, tier: "1"}
+zones:
+  - name: assets
+    region: DE
+    replicationRegions:
+      - NY
+      - LA
+  - name: "backups: daily"
+    region: SG
+script: |
+  export default function handler(req) {
+    return new Response("ok");
+  }
+folded: >-
+  one
+  two
+`
+	var got map[string]any
+	if err := yaml.Unmarshal([]byte(src), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{
+		"name":     "production",
+		"replicas": float64(3),
+		"enabled":  true,
+		"ratio":    0.5,
+		"empty":    nil,
+		"tags":     []any{"a", "b c", float64(3)},
+		"labels":   map[string]any{"team": "web", "tier": "1"},
+		"zones": []any{
+			map[string]any{"name": "assets", "region": "DE", "replicationRegions": []any{"NY", "LA"}},
+			map[string]any{"name": "backups: daily", "region": "SG"},
+		},
+		"script": "export default function handler(req) {\n  return new Response(\"ok\");\n}\n",
+		"folded": "one two",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v\nwant %#v", got, want)
+	}
+}
+
+func TestUnmarshal_PlainScalarsIntoStrings(t *testing.T) {
+	type app struct {
+		Name string            `json:"name"`
+		Env  map[string]string `json:"env"`
+		Port int               `json:"port"`
+	}
+	type config struct {
+		StoragePassword string   `json:"storagePassword"`
+		Tags            []string `json:"tags"`
+		Apps            []app    `json:"apps"`
+		Note            string   `json:"note"`
+		Quoted          string   `json:"quoted"`
+	}
+	src := `storagePassword: 123456
+tags: [2024, true, 1.50]
+apps:
+  - name: 8080
+    port: 8080
+    env: {RETRIES: 3, DEBUG: false}
+note: don't # not part of the note
+quoted: 'it''s # kept' # comment
+`
+	var got config
+	if err := yaml.Unmarshal([]byte(src), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := config{
+		StoragePassword: "123456",
+		Tags:            []string{"2024", "true", "1.50"},
+		Apps:            []app{{Name: "8080", Port: 8080, Env: map[string]string{"RETRIES": "3", "DEBUG": "false"}}},
+		Note:            "don't",
+		Quoted:          "it's # kept",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v\nwant %#v", got, want)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	tests := []string{
+		"key: [unterminated",
+		"a: [x}",
+		"a: {k: v]",
+		"a: [{k: v], x]",
+		"a: 1\n  b: 2\n c: 3",
+	}
+	for _, src := range tests {
+		var v any
+		if err := yaml.Unmarshal([]byte(src), &v); err == nil {
+			t.Errorf("expected error for %q", src)
+		}
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	type zone struct {
+		Name    string   `json:"name"`
+		Regions []string `json:"regions,omitempty"`
+		Size    int      `json:"size"`
+	}
+	type doc struct {
+		Version int               `json:"version"`
+		Zones   []zone            `json:"zones"`
+		Script  string            `json:"script"`
+		Labels  map[string]string `json:"labels"`
+		Empty   []string          `json:"empty"`
+	}
+	in := doc{
+		Version: 1,
+		Zones: []zone{
+			{Name: "assets", Regions: []string{"NY", "LA"}, Size: 10},
+			{Name: "true", Size: 0},
+		},
+		Script: "line one\n  line two\n",
+		Labels: map[string]string{"a": "x: y", "b": "<tag>", "c": "-1"},
+		Empty:  []string{},
+	}
+
+	b, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(b), "script: |\n  line one\n    line two\n") {
+		t.Errorf("expected literal block scalar, got:\n%s", b)
+	}
+
+	var out doc
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, b)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip mismatch:\n%s\ngot %#v", b, out)
+	}
+}
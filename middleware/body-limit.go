@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrRequestBodyTooLarge is returned by MaxRequestBody when a request body
+// exceeds the limit.
+var ErrRequestBodyTooLarge = errors.New("request body too large")
+
+// MaxRequestBody rejects requests whose body is larger than n bytes. Bodies
+// of known length are rejected before anything is sent; streamed bodies fail
+// with ErrRequestBodyTooLarge once more than n bytes have been read.
+func MaxRequestBody(n int64) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Body == nil || req.Body == http.NoBody {
+				return next(req)
+			}
+			if req.ContentLength > n {
+				req.Body.Close()
+				return nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrRequestBodyTooLarge, req.ContentLength, n)
+			}
+			if req.ContentLength <= 0 {
+				req = req.Clone(req.Context())
+				req.Body = &limitedBody{rc: req.Body, remaining: n}
+			}
+			return next(req)
+		}
+	}
+}
+
+// limitedBody fails reads past the limit instead of silently truncating the
+// upload the way io.LimitReader would.
+type limitedBody struct {
+	rc        io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, ErrRequestBodyTooLarge
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.rc.Close()
+}
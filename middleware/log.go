@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/internal/redact"
+)
+
+// sensitiveFields are JSON field names whose values are never logged.
+var sensitiveFields = redact.Fields()
+
+type attemptKey struct{}
+
+// WithAttempt returns a context recording that a request is the nth attempt.
+// Middlewares that retry set it so Log can report the attempt count.
+func WithAttempt(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, n)
+}
+
+// AttemptFromContext returns the attempt set by WithAttempt, or 1.
+func AttemptFromContext(ctx context.Context) int {
+	if n, ok := ctx.Value(attemptKey{}).(int); ok && n > 0 {
+		return n
+	}
+	return 1
+}
+
+type logConfig struct {
+	success   slog.Level
+	failure   slog.Level
+	bodyLimit int
+}
+
+// LogOption configures Log.
+type LogOption func(*logConfig)
+
+// LogLevels sets the level for successful requests and for requests that
+// failed or returned an error status. Defaults to Info and Warn.
+func LogLevels(success, failure slog.Level) LogOption {
+	return func(c *logConfig) {
+		c.success = success
+		c.failure = failure
+	}
+}
+
+// LogBodyLimit sets how many bytes of JSON request and response bodies are
+// logged when the logger is enabled for Debug. Defaults to 1024; zero
+// disables body logging. At most n bytes of a response are read for the log,
+// so response bodies longer than n are omitted instead of truncated.
+func LogBodyLimit(n int) LogOption {
+	return func(c *logConfig) {
+		c.bodyLimit = n
+	}
+}
+
+// Log logs every request with its method, path, status, duration, attempt
+// and API error key. The AccessKey header and credential fields in bodies
+// are always redacted.
+func Log(logger *slog.Logger, opts ...LogOption) Middleware {
+	cfg := logConfig{success: slog.LevelInfo, failure: slog.LevelWarn, bodyLimit: 1024}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			debug := cfg.bodyLimit > 0 && logger.Enabled(ctx, slog.LevelDebug)
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			}
+			if debug {
+				attrs = append(attrs, slog.Any("headers", RedactHeaders(req.Header)))
+				if body := requestBody(req); body != nil {
+					attrs = append(attrs, slog.String("request_body", truncate(redactBody(body), cfg.bodyLimit)))
+				}
+			}
+
+			start := time.Now()
+			resp, err := next(req)
+			attrs = append(attrs,
+				slog.Duration("duration", time.Since(start)),
+				slog.Int("attempt", AttemptFromContext(ctx)),
+			)
+
+			level := cfg.success
+			switch {
+			case err != nil:
+				level = cfg.failure
+				attrs = append(attrs, slog.String("error", err.Error()))
+			default:
+				attrs = append(attrs, slog.Int("status", resp.StatusCode))
+				var body []byte
+				complete := true
+				if resp.StatusCode >= 400 || (debug && isJSON(resp.Header)) {
+					limit := cfg.bodyLimit
+					if resp.StatusCode >= 400 {
+						limit = max(limit, errorBodyLimit)
+					}
+					body, complete = peekBody(resp, limit)
+				}
+				if resp.StatusCode >= 400 {
+					level = cfg.failure
+					if e := internal.ParseErrorResponse(body); e != nil && e.ErrorKey != "" {
+						attrs = append(attrs, slog.String("error_key", e.ErrorKey))
+					}
+				}
+				if debug && len(body) > 0 {
+					attrs = append(attrs, slog.String("response_body", logBody(body, complete, cfg.bodyLimit)))
+				}
+			}
+			logger.LogAttrs(ctx, level, "bunny request", attrs...)
+			return resp, err
+		}
+	}
+}
+
+// requestBody returns a copy of the request body without consuming it, or
+// nil when the body cannot be replayed (streamed uploads).
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil || req.ContentLength <= 0 || !isJSON(req.Header) {
+		return nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	body, _ := io.ReadAll(rc)
+	return body
+}
+
+// errorBodyLimit is how much of an error response is read to find its
+// error key when body logging is off or limited to less.
+const errorBodyLimit = 4096
+
+// peekBody reads up to limit bytes of the response body and puts them back
+// in front of the unread rest, so large downloads are never buffered. It
+// reports whether the whole body fit within limit.
+func peekBody(resp *http.Response, limit int) ([]byte, bool) {
+	if resp.Body == nil || limit <= 0 {
+		return nil, false
+	}
+	head, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	complete := err == nil && len(head) <= limit
+	resp.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(head), resp.Body), body: resp.Body}
+	if err != nil {
+		return nil, false
+	}
+	if !complete {
+		head = head[:limit]
+	}
+	return head, complete
+}
+
+// peekedBody replays the peeked bytes and closes the original body.
+type peekedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *peekedBody) Close() error {
+	return b.body.Close()
+}
+
+// logBody redacts a JSON body for logging. Redaction needs the whole
+// document, so a body that did not fit within limit is omitted rather than
+// logged partially.
+func logBody(body []byte, complete bool, limit int) string {
+	if !complete {
+		return fmt.Sprintf("<body over %d bytes omitted>", limit)
+	}
+	return truncate(redactBody(body), limit)
+}
+
+func isJSON(h http.Header) bool {
+	return strings.Contains(h.Get("Content-Type"), "json")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "...(truncated)"
+}
+
+// redactBody replaces sensitive fields in JSON bodies. Bodies that are not
+// JSON are replaced entirely since their contents are unknown.
+func redactBody(body []byte) string {
+	out, ok := redact.JSON(body, sensitiveFields)
+	if !ok {
+		return "<non-JSON body omitted>"
+	}
+	return string(out)
+}
@@ -0,0 +1,201 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+func jsonLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level}))
+}
+
+func decodeLogs(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("bad log line %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestWithLoggerRecordsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return testutil.NewMockResponse(404, `{"Message":"zone not found","ErrorKey":"storagezone.not_found"}`), nil
+	}}
+	client := storage.NewClient("global-key", storage.WithHTTPClient(hc), storage.WithLogger(jsonLogger(&buf, slog.LevelInfo)))
+	if _, err := client.Zones().Get(context.Background(), 7); err == nil {
+		t.Fatal("expected error")
+	}
+
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 {
+		t.Fatalf("got %d log lines", len(logs))
+	}
+	l := logs[0]
+	if l["level"] != "WARN" || l["method"] != "GET" || l["path"] != "/storagezone/7" || l["status"] != 404.0 || l["attempt"] != 1.0 {
+		t.Errorf("log = %v", l)
+	}
+	if l["error_key"] != "storagezone.not_found" {
+		t.Errorf("error_key = %v", l["error_key"])
+	}
+	if _, ok := l["duration"]; !ok {
+		t.Error("missing duration")
+	}
+	if strings.Contains(buf.String(), "global-key") {
+		t.Error("log contains the API key")
+	}
+}
+
+func TestLogLevelsAndAttempt(t *testing.T) {
+	var buf bytes.Buffer
+	base := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection reset")
+	}}
+	client := middleware.Chain(base, middleware.Log(jsonLogger(&buf, slog.LevelInfo), middleware.LogLevels(slog.LevelDebug, slog.LevelError)))
+
+	req, _ := http.NewRequestWithContext(middleware.WithAttempt(context.Background(), 3), "GET", "https://api.bunny.net/x", nil)
+	client.Do(req)
+
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 || logs[0]["level"] != "ERROR" || logs[0]["attempt"] != 3.0 || logs[0]["error"] != "connection reset" {
+		t.Errorf("logs = %v", logs)
+	}
+
+	buf.Reset()
+	base.DoFunc = func(req *http.Request) (*http.Response, error) {
+		return testutil.NewMockResponse(200, "{}"), nil
+	}
+	client.Do(req)
+	if buf.Len() != 0 {
+		t.Errorf("success logged at Debug should be filtered: %s", buf.String())
+	}
+}
+
+func TestDebugBodiesAreRedacted(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    func(hc *testutil.MockHTTPClient, l *slog.Logger) error
+		resp    string
+		secrets []string
+	}{
+		{
+			name: "zone passwords",
+			call: func(hc *testutil.MockHTTPClient, l *slog.Logger) error {
+				_, err := storage.NewClient("k", storage.WithHTTPClient(hc), storage.WithLogger(l)).Zones().Get(context.Background(), 1)
+				return err
+			},
+			resp:    `{"Id":1,"Name":"z","Password":"zone-pw","ReadOnlyPassword":"ro-pw"}`,
+			secrets: []string{"zone-pw", "ro-pw"},
+		},
+		{
+			name: "edge script secret",
+			call: func(hc *testutil.MockHTTPClient, l *slog.Logger) error {
+				_, err := scripting.NewClient("k", scripting.WithHTTPClient(hc), scripting.WithLogger(l)).
+					Secrets(1).Add(context.Background(), &scripting.AddSecretRequest{Name: "TOKEN", Secret: "s3cr3t-value"})
+				return err
+			},
+			resp:    `{"Id":1,"Name":"TOKEN"}`,
+			secrets: []string{"s3cr3t-value"},
+		},
+		{
+			name: "registry credentials",
+			call: func(hc *testutil.MockHTTPClient, l *slog.Logger) error {
+				_, err := containers.NewClient("k", containers.WithHTTPClient(hc), containers.WithLogger(l)).
+					Registries().Create(context.Background(), &containers.CreateRegistryRequest{
+					DisplayName:         "ghcr",
+					PasswordCredentials: &containers.PasswordCredentials{UserName: "bot", Password: "registry-pw"},
+				})
+				return err
+			},
+			resp:    `{"id":1}`,
+			secrets: []string{"registry-pw"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+				return testutil.NewMockResponseWithHeaders(200, tt.resp, map[string]string{"Content-Type": "application/json"}), nil
+			}}
+			if err := tt.call(hc, jsonLogger(&buf, slog.LevelDebug)); err != nil {
+				t.Fatal(err)
+			}
+			out := buf.String()
+			if !strings.Contains(out, "_body") {
+				t.Errorf("no body logged: %s", out)
+			}
+			for _, s := range tt.secrets {
+				if strings.Contains(out, s) {
+					t.Errorf("log contains %q: %s", s, out)
+				}
+			}
+		})
+	}
+}
+
+func TestLogDoesNotConsumeBodies(t *testing.T) {
+	var uploaded []byte
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		uploaded, _ = io.ReadAll(req.Body)
+		return testutil.NewMockResponse(201, ""), nil
+	}}
+	var buf bytes.Buffer
+	files := storage.NewFileService("z", "pw", storage.RegionFalkenstein,
+		storage.WithFileHTTPClient(hc),
+		storage.WithFileLogger(jsonLogger(&buf, slog.LevelDebug)),
+	)
+	if err := files.Upload(context.Background(), "a.txt", strings.NewReader("file contents"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if string(uploaded) != "file contents" {
+		t.Errorf("uploaded = %q", uploaded)
+	}
+	if strings.Contains(buf.String(), "file contents") || strings.Contains(buf.String(), `"pw"`) {
+		t.Errorf("log leaked upload data: %s", buf.String())
+	}
+}
+
+func TestLogPeeksLimitedResponseBody(t *testing.T) {
+	large := `{"Items":["` + strings.Repeat("x", 4096) + `"]}`
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return testutil.NewMockResponseWithHeaders(200, large, map[string]string{"Content-Type": "application/json"}), nil
+	}}
+	var buf bytes.Buffer
+	client := middleware.Chain(hc, middleware.Log(jsonLogger(&buf, slog.LevelDebug), middleware.LogBodyLimit(64)))
+
+	req, _ := http.NewRequest("GET", "https://api.bunny.net/x", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != large {
+		t.Errorf("caller read %d of %d bytes", len(body), len(large))
+	}
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 || logs[0]["response_body"] != "<body over 64 bytes omitted>" {
+		t.Errorf("logs = %v", logs)
+	}
+}
@@ -0,0 +1,52 @@
+// Package middleware provides request/response interceptors shared by every
+// client in the SDK.
+//
+// A Middleware wraps the next RoundTripFunc in the chain. Register middlewares
+// with the WithMiddleware option of any client; the first one registered is
+// the outermost and sees the request first:
+//
+//	client := storage.NewClient(apiKey,
+//		storage.WithMiddleware(
+//			middleware.RequestID(),
+//			middleware.MaxRequestBody(10<<20),
+//		),
+//	)
+package middleware
+
+import "net/http"
+
+// RoundTripFunc performs a single HTTP request.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f, so a RoundTripFunc can be used wherever the SDK accepts an HTTPClient.
+func (f RoundTripFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware intercepts requests and responses around next.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Doer is the HTTPClient interface every service package accepts.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Chain wraps client with mws. The first middleware is the outermost and nil
+// entries are skipped. Chain returns client unchanged when there is nothing
+// to apply.
+func Chain(client Doer, mws ...Middleware) Doer {
+	var next RoundTripFunc
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] == nil {
+			continue
+		}
+		if next == nil {
+			next = client.Do
+		}
+		next = mws[i](next)
+	}
+	if next == nil {
+		return client
+	}
+	return next
+}
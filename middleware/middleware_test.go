@@ -0,0 +1,190 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+func tag(name string, trail *[]string) middleware.Middleware {
+	return func(next middleware.RoundTripFunc) middleware.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			*trail = append(*trail, name+">")
+			resp, err := next(req)
+			*trail = append(*trail, "<"+name)
+			return resp, err
+		}
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var trail []string
+	base := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		trail = append(trail, "client")
+		return testutil.NewMockResponse(200, "{}"), nil
+	}}
+	client := middleware.Chain(base, tag("a", &trail), tag("b", &trail))
+	req, _ := http.NewRequest("GET", "https://api.bunny.net/", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(trail, " "), "a> b> client <b <a"; got != want {
+		t.Errorf("trail = %q, want %q", got, want)
+	}
+	if middleware.Chain(base) != base {
+		t.Error("Chain without middlewares should return the client unchanged")
+	}
+}
+
+func TestWithMiddlewareInEveryClient(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		call func(hc *testutil.MockHTTPClient, mw middleware.Middleware) error
+	}{
+		{"storage", func(hc *testutil.MockHTTPClient, mw middleware.Middleware) error {
+			_, err := storage.NewClient("k", storage.WithMiddleware(mw), storage.WithHTTPClient(hc)).Zones().Get(ctx, 1)
+			return err
+		}},
+		{"storage files", func(hc *testutil.MockHTTPClient, mw middleware.Middleware) error {
+			_, err := storage.NewFileService("z", "pw", storage.RegionFalkenstein, storage.WithFileMiddleware(mw), storage.WithFileHTTPClient(hc)).List(ctx, "/")
+			return err
+		}},
+		{"stream", func(hc *testutil.MockHTTPClient, mw middleware.Middleware) error {
+			_, err := stream.NewClient("k", stream.WithMiddleware(mw), stream.WithHTTPClient(hc)).Libraries().Get(ctx, 1)
+			return err
+		}},
+		{"shield", func(hc *testutil.MockHTTPClient, mw middleware.Middleware) error {
+			_, err := shield.NewClient("k", shield.WithMiddleware(mw), shield.WithHTTPClient(hc)).Zones().Get(ctx, "1")
+			return err
+		}},
+		{"scripting", func(hc *testutil.MockHTTPClient, mw middleware.Middleware) error {
+			_, err := scripting.NewClient("k", scripting.WithMiddleware(mw), scripting.WithHTTPClient(hc)).Scripts().Get(ctx, 1)
+			return err
+		}},
+		{"containers", func(hc *testutil.MockHTTPClient, mw middleware.Middleware) error {
+			_, err := containers.NewClient("k", containers.WithMiddleware(mw), containers.WithHTTPClient(hc)).Applications().Get(ctx, "app")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+			hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+				sent = req.Header.Get(middleware.RequestIDHeader)
+				body := "{}"
+				if tt.name == "storage files" {
+					body = "[]"
+				}
+				return testutil.NewMockResponse(200, body), nil
+			}}
+			if err := tt.call(hc, middleware.RequestID()); err != nil {
+				t.Fatal(err)
+			}
+			if sent == "" {
+				t.Error("middleware did not run")
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var got []string
+	base := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.Header.Get(middleware.RequestIDHeader))
+		return testutil.NewMockResponse(200, "{}"), nil
+	}}
+	client := middleware.Chain(base, middleware.RequestID())
+
+	req, _ := http.NewRequest("GET", "https://api.bunny.net/", nil)
+	client.Do(req)
+	client.Do(req)
+	if len(got[0]) != 32 || got[0] == got[1] {
+		t.Errorf("generated IDs = %q", got)
+	}
+	if req.Header.Get(middleware.RequestIDHeader) != "" {
+		t.Error("caller's request was modified")
+	}
+
+	req, _ = http.NewRequestWithContext(middleware.WithRequestID(context.Background(), "abc"), "GET", "https://api.bunny.net/", nil)
+	client.Do(req)
+	req.Header.Set(middleware.RequestIDHeader, "explicit")
+	client.Do(req)
+	if got[2] != "abc" || got[3] != "explicit" {
+		t.Errorf("IDs = %q", got[2:])
+	}
+}
+
+func TestLogHeadersRedacts(t *testing.T) {
+	base := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return testutil.NewMockResponseWithHeaders(201, "{}", map[string]string{"Set-Cookie": "session=1", "X-Trace": "t"}), nil
+	}}
+	var ex middleware.Exchange
+	client := middleware.Chain(base, middleware.LogHeaders(func(e middleware.Exchange) { ex = e }, "X-Trace"))
+
+	req, _ := http.NewRequest("POST", "https://api.bunny.net/storagezone", nil)
+	req.Header.Set("AccessKey", "secret")
+	req.Header.Set("Accept", "application/json")
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if ex.Method != "POST" || ex.StatusCode != 201 {
+		t.Errorf("exchange = %+v", ex)
+	}
+	if v := ex.RequestHeaders.Get("AccessKey"); v != middleware.Redacted {
+		t.Errorf("AccessKey = %q", v)
+	}
+	if v := ex.RequestHeaders.Get("Accept"); v != "application/json" {
+		t.Errorf("Accept = %q", v)
+	}
+	if ex.ResponseHeaders.Get("Set-Cookie") != middleware.Redacted || ex.ResponseHeaders.Get("X-Trace") != middleware.Redacted {
+		t.Errorf("response headers = %v", ex.ResponseHeaders)
+	}
+	if req.Header.Get("AccessKey") != "secret" {
+		t.Error("request headers were modified")
+	}
+}
+
+func TestMaxRequestBody(t *testing.T) {
+	calls := 0
+	base := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		calls++
+		if req.Body != nil {
+			if _, err := io.ReadAll(req.Body); err != nil {
+				return nil, err
+			}
+		}
+		return testutil.NewMockResponse(201, ""), nil
+	}}
+	files := storage.NewFileService("z", "pw", storage.RegionFalkenstein,
+		storage.WithFileHTTPClient(base),
+		storage.WithFileMiddleware(middleware.MaxRequestBody(4)),
+	)
+	ctx := context.Background()
+
+	if err := files.Upload(ctx, "ok.txt", strings.NewReader("1234"), nil); err != nil {
+		t.Errorf("upload at limit: %v", err)
+	}
+	err := files.Upload(ctx, "big.txt", strings.NewReader("12345"), nil)
+	if !errors.Is(err, middleware.ErrRequestBodyTooLarge) {
+		t.Errorf("known length: err = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, oversized body with known length should not be sent", calls)
+	}
+	err = files.Upload(ctx, "stream.txt", io.MultiReader(strings.NewReader("123"), strings.NewReader("456")), nil)
+	if !errors.Is(err, middleware.ErrRequestBodyTooLarge) {
+		t.Errorf("streamed: err = %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGroup is the endpoint group for requests that are not made by an
+// SDK service and have no other group.
+const DefaultGroup = "default"
+
+// Limit is a token bucket: Rate requests per second on average with bursts
+// of up to Burst requests. A zero Rate disables client-side throttling but
+// still honours pauses requested by the API.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter throttles requests per endpoint group with token buckets and
+// pauses a group when the API answers 429 or reports an exhausted quota.
+// Callers block until a token is available or their context is done.
+//
+// Responses carrying X-RateLimit-Remaining and X-RateLimit-Reset lower a
+// group's refill rate to the remaining quota spread over the time left until
+// the reset, so a configured rate above the account limit is corrected by the
+// API instead of running into 429s. The configured rate applies again after
+// the reset. Groups with a zero Rate are not throttled this way.
+//
+// A RateLimiter is safe for concurrent use and may be shared by several
+// clients so they draw from the same quota.
+type RateLimiter struct {
+	limit      Limit
+	groups     map[string]Limit
+	groupOf    func(req *http.Request) string
+	maxRetries int
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// RateLimitOption configures a RateLimiter.
+type RateLimitOption func(*RateLimiter)
+
+// WithGroupLimit sets the limit for one endpoint group, overriding the
+// default limit. Groups are named after SDK services, such as
+// "shield.MetricsService" or "stream.VideoService", unless WithGroupFunc
+// says otherwise.
+func WithGroupLimit(group string, rate float64, burst int) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.groups[group] = Limit{Rate: rate, Burst: burst}
+	}
+}
+
+// WithGroupFunc sets how requests are assigned to endpoint groups.
+func WithGroupFunc(fn func(req *http.Request) string) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.groupOf = fn
+	}
+}
+
+// WithMaxRetries sets how many times a request that received 429 is retried
+// after waiting. Defaults to 3. The last 429 response is returned to the
+// caller when retries run out.
+func WithMaxRetries(n int) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.maxRetries = n
+	}
+}
+
+// NewRateLimiter returns a RateLimiter allowing rate requests per second with
+// bursts of up to burst requests in every endpoint group.
+func NewRateLimiter(rate float64, burst int, opts ...RateLimitOption) *RateLimiter {
+	l := &RateLimiter{
+		limit:      Limit{Rate: rate, Burst: burst},
+		groups:     make(map[string]Limit),
+		groupOf:    ServiceGroup,
+		maxRetries: 3,
+		buckets:    make(map[string]*bucket),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// ServiceGroup is the default group function. It groups requests by the SDK
+// service that made them, such as "stream.VideoService".
+func ServiceGroup(req *http.Request) string {
+	op := Operation(req)
+	if i := strings.LastIndexByte(op, '.'); i > 0 && !strings.Contains(op, " ") {
+		return op[:i]
+	}
+	return DefaultGroup
+}
+
+// Wait blocks until group has a token available or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, group string) error {
+	for {
+		l.mu.Lock()
+		d := l.bucket(group).take(time.Now())
+		l.mu.Unlock()
+		if d <= 0 {
+			return nil
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Pause stops group from sending requests until the given time.
+func (l *RateLimiter) Pause(group string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(group)
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// Middleware returns the middleware that applies the limiter. Requests wait
+// for a token, and 429 responses pause the group for the Retry-After period
+// before the request is retried when its body can be replayed.
+func (l *RateLimiter) Middleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			group := l.groupOf(req)
+			ctx := req.Context()
+			for attempt := 1; ; attempt++ {
+				if err := l.Wait(ctx, group); err != nil {
+					return nil, err
+				}
+				try := req
+				if attempt > 1 {
+					try = req.Clone(WithAttempt(ctx, attempt))
+					if req.GetBody != nil {
+						body, err := req.GetBody()
+						if err != nil {
+							return nil, err
+						}
+						try.Body = body
+					}
+				}
+				resp, err := next(try)
+				if err != nil {
+					return nil, err
+				}
+				now := time.Now()
+				if remaining, reset, ok := quota(resp, now); ok && remaining > 0 {
+					l.slowDown(group, float64(remaining)/reset.Sub(now).Seconds(), reset)
+				}
+				until, ok := pauseUntil(resp, now)
+				if !ok && resp.StatusCode == http.StatusTooManyRequests {
+					until, ok = now.Add(time.Second), true
+				}
+				if ok {
+					l.Pause(group, until)
+				}
+				if resp.StatusCode != http.StatusTooManyRequests || attempt > l.maxRetries || !replayable(req) {
+					return resp, nil
+				}
+				resp.Body.Close()
+			}
+		}
+	}
+}
+
+// slowDown caps the refill rate of group at rate until the given time.
+func (l *RateLimiter) slowDown(group string, rate float64, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(group)
+	b.slowRate, b.slowUntil = rate, until
+}
+
+func (l *RateLimiter) bucket(group string) *bucket {
+	b, ok := l.buckets[group]
+	if !ok {
+		limit, ok := l.groups[group]
+		if !ok {
+			limit = l.limit
+		}
+		b = &bucket{limit: limit, tokens: float64(limit.Burst)}
+		l.buckets[group] = b
+	}
+	return b
+}
+
+type bucket struct {
+	limit       Limit
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	slowRate    float64
+	slowUntil   time.Time
+}
+
+// take consumes a token and returns zero, or returns how long to wait
+// before trying again.
+func (b *bucket) take(now time.Time) time.Duration {
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+	if b.limit.Rate <= 0 {
+		return 0
+	}
+	rate := b.limit.Rate
+	if now.Before(b.slowUntil) && b.slowRate < rate {
+		rate = b.slowRate
+	}
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * rate
+	}
+	b.last = now
+	if burst := float64(max(b.limit.Burst, 1)); b.tokens > burst {
+		b.tokens = burst
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// pauseUntil reads Retry-After on 429 and 503 responses, and the
+// X-RateLimit-Remaining/X-RateLimit-Reset pair on any response.
+func pauseUntil(resp *http.Response, now time.Time) (time.Time, bool) {
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if v := resp.Header.Get("Retry-After"); v != "" {
+			if secs, err := strconv.Atoi(v); err == nil {
+				return now.Add(time.Duration(secs) * time.Second), true
+			}
+			if t, err := http.ParseTime(v); err == nil {
+				return t, true
+			}
+		}
+	}
+	if remaining, reset, ok := quota(resp, now); ok && remaining == 0 {
+		return reset, true
+	}
+	return time.Time{}, false
+}
+
+// quota reads the X-RateLimit-Remaining/X-RateLimit-Reset pair. Resets in
+// the past are ignored.
+func quota(resp *http.Response, now time.Time) (remaining int, reset time.Time, ok bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining < 0 {
+		return 0, time.Time{}, false
+	}
+	v, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	// Large values are Unix timestamps, small ones are seconds to wait.
+	if v > 1e9 {
+		reset = time.Unix(v, 0)
+	} else {
+		reset = now.Add(time.Duration(v) * time.Second)
+	}
+	if !reset.After(now) {
+		return 0, time.Time{}, false
+	}
+	return remaining, reset, true
+}
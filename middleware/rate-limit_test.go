@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+func okClient(calls *int32) *testutil.MockHTTPClient {
+	return &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(calls, 1)
+		return testutil.NewMockResponse(200, "{}"), nil
+	}}
+}
+
+func TestRateLimiterThrottles(t *testing.T) {
+	var calls int32
+	limiter := middleware.NewRateLimiter(50, 1)
+	zones := storage.NewClient("k", storage.WithHTTPClient(okClient(&calls)), storage.WithRateLimiter(limiter)).Zones()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := zones.Get(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("3 requests at 50/s with burst 1 took %v", elapsed)
+	}
+}
+
+func TestRateLimiterRetriesAfter429(t *testing.T) {
+	var attempts []int
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		attempts = append(attempts, middleware.AttemptFromContext(req.Context()))
+		if len(attempts) == 1 {
+			return testutil.NewMockResponseWithHeaders(429, `{"Message":"slow down"}`, map[string]string{"Retry-After": "0"}), nil
+		}
+		return testutil.NewMockResponse(200, `{"libraryId":5}`), nil
+	}}
+	limiter := middleware.NewRateLimiter(0, 0)
+	lib, err := stream.NewClient("k", stream.WithHTTPClient(hc), stream.WithRateLimiter(limiter)).Libraries().Get(context.Background(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lib.LibraryID != 5 || len(attempts) != 2 || attempts[1] != 2 {
+		t.Errorf("library = %+v, attempts = %v", lib, attempts)
+	}
+}
+
+func TestRateLimiterGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return testutil.NewMockResponseWithHeaders(429, `{"Message":"slow down"}`, map[string]string{"Retry-After": "0"}), nil
+	}}
+	limiter := middleware.NewRateLimiter(0, 0, middleware.WithMaxRetries(2))
+	_, err := storage.NewClient("k", storage.WithHTTPClient(hc), storage.WithRateLimiter(limiter)).Zones().Get(context.Background(), 1)
+	var apiErr *storage.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 429 {
+		t.Errorf("err = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRateLimiterRespectsContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+	}{
+		{"retry-after", 429, map[string]string{"Retry-After": "60"}},
+		{"quota headers", 200, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "60"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&calls, 1)
+				return testutil.NewMockResponseWithHeaders(tt.status, "{}", tt.headers), nil
+			}}
+			zones := storage.NewClient("k", storage.WithHTTPClient(hc), storage.WithRateLimiter(middleware.NewRateLimiter(0, 0))).Zones()
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			zones.Get(ctx, 1)
+			_, err := zones.Get(ctx, 1)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("err = %v, want deadline exceeded", err)
+			}
+			if calls != 1 {
+				t.Errorf("calls = %d, paused group should not send", calls)
+			}
+		})
+	}
+}
+
+func TestRateLimiterGroups(t *testing.T) {
+	var calls int32
+	limiter := middleware.NewRateLimiter(0, 0, middleware.WithGroupLimit("stream.VideoService", 0.001, 1))
+	client := stream.NewClient("k", stream.WithHTTPClient(okClient(&calls)), stream.WithRateLimiter(limiter))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Videos(1).List(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Videos(1).List(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second video list: err = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := client.Libraries().Get(context.Background(), 1); err != nil {
+			t.Errorf("library get %d: %v", i, err)
+		}
+	}
+}
+
+func TestRateLimiterAdaptsToRemainingQuota(t *testing.T) {
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return testutil.NewMockResponseWithHeaders(200, "{}", map[string]string{
+			"X-RateLimit-Remaining": "20",
+			"X-RateLimit-Reset":     "1",
+		}), nil
+	}}
+	limiter := middleware.NewRateLimiter(1000, 1)
+	zones := storage.NewClient("k", storage.WithHTTPClient(hc), storage.WithRateLimiter(limiter)).Zones()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := zones.Get(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 20 requests left for one second allow about one request every 50ms.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("3 requests with 20 remaining for 1s took %v", elapsed)
+	}
+}
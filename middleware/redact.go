@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal/redact"
+)
+
+// Redacted replaces the values of sensitive headers and body fields.
+const Redacted = redact.Placeholder
+
+// SensitiveHeaders are always redacted by RedactHeaders.
+var SensitiveHeaders = []string{"AccessKey", "Authorization", "Cookie", "Set-Cookie"}
+
+// RedactHeaders returns a copy of h with the values of SensitiveHeaders and
+// any extra header names replaced by Redacted. It is safe to log the result.
+func RedactHeaders(h http.Header, extra ...string) http.Header {
+	out := h.Clone()
+	if out == nil {
+		return http.Header{}
+	}
+	for _, names := range [][]string{SensitiveHeaders, extra} {
+		for _, name := range names {
+			key := http.CanonicalHeaderKey(name)
+			if _, ok := out[key]; ok {
+				out[key] = []string{Redacted}
+			}
+		}
+	}
+	return out
+}
+
+// Exchange describes a completed request for LogHeaders. Header values are
+// already redacted.
+type Exchange struct {
+	Method          string
+	URL             string
+	RequestHeaders  http.Header
+	StatusCode      int // zero when Err is set
+	ResponseHeaders http.Header
+	Duration        time.Duration
+	Err             error
+}
+
+// LogHeaders calls log after every request with its headers redacted by
+// RedactHeaders. Extra names the headers to redact in addition to
+// SensitiveHeaders.
+func LogHeaders(log func(Exchange), extra ...string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			ex := Exchange{
+				Method:         req.Method,
+				URL:            req.URL.String(),
+				RequestHeaders: RedactHeaders(req.Header, extra...),
+				Duration:       time.Since(start),
+				Err:            err,
+			}
+			if resp != nil {
+				ex.StatusCode = resp.StatusCode
+				ex.ResponseHeaders = RedactHeaders(resp.Header, extra...)
+			}
+			log(ex)
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is the header set by RequestID.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying id. RequestID sends it instead of
+// generating a new one, which lets callers correlate SDK calls with their own
+// request logs.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestID sets the X-Request-ID header on requests that do not already
+// have one. The ID comes from the request context when set with
+// WithRequestID and is randomly generated otherwise.
+func RequestID() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) == "" {
+				id, ok := RequestIDFromContext(req.Context())
+				if !ok {
+					id = newRequestID()
+				}
+				req = req.Clone(req.Context())
+				req.Header.Set(RequestIDHeader, id)
+			}
+			return next(req)
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
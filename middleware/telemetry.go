@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Tracer starts a span for each SDK operation. It is small enough to adapt
+// to OpenTelemetry or any other tracing library without the SDK depending on
+// one. End is called exactly once with the error the operation failed with,
+// or nil.
+type Tracer interface {
+	Start(ctx context.Context, operation string) (context.Context, func(err error))
+}
+
+// Metrics records one entry per HTTP attempt, so retries appear as entries
+// with Attempt greater than one.
+type Metrics interface {
+	RecordRequest(ctx context.Context, m RequestMetrics)
+}
+
+// RequestMetrics describes one completed HTTP attempt.
+type RequestMetrics struct {
+	Operation     string
+	Method        string
+	StatusCode    int // zero when Err is a transport error
+	Duration      time.Duration
+	BytesSent     int64
+	BytesReceived int64
+	Attempt       int
+	Err           error
+}
+
+type operationKey struct{}
+
+// WithOperation returns a context that names the operation for Tracer and
+// Metrics. Service methods name themselves, such as
+// "storage.FileService.Download"; a name already on ctx is kept, so callers
+// override it by naming the operation before the call.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	if op, ok := ctx.Value(operationKey{}).(string); ok && op != "" {
+		return ctx
+	}
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// Operation returns the name of the operation req belongs to, or the method
+// and path for requests made outside a named operation.
+func Operation(req *http.Request) string {
+	if op, ok := req.Context().Value(operationKey{}).(string); ok && op != "" {
+		return op
+	}
+	return req.Method + " " + req.URL.Path
+}
+
+// StatusError is passed to Tracer and Metrics for responses with an error
+// status code. Services still return their own typed errors to callers.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("bunny: HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Trace starts a span named after the operation for every request. The span
+// ends when the response body is closed or fully read, so downloads are
+// timed to completion.
+func Trace(t Tracer) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx, end := t.Start(req.Context(), Operation(req))
+			resp, err := next(req.WithContext(ctx))
+			if err != nil {
+				end(err)
+				return nil, err
+			}
+			var statusErr error
+			if resp.StatusCode >= 400 {
+				statusErr = &StatusError{StatusCode: resp.StatusCode}
+			}
+			resp.Body = onClose(resp.Body, func(int64) { end(statusErr) })
+			return resp, nil
+		}
+	}
+}
+
+// Measure records RequestMetrics for every attempt. Bytes received are
+// counted as the response body is read and the entry is recorded when the
+// body is closed or fully read.
+func Measure(m Metrics) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			rm := RequestMetrics{
+				Operation: Operation(req),
+				Method:    req.Method,
+				Attempt:   AttemptFromContext(ctx),
+			}
+			var sent *countingBody
+			if req.Body != nil && req.Body != http.NoBody {
+				sent = &countingBody{ReadCloser: req.Body}
+				req = req.Clone(ctx)
+				req.Body = sent
+			}
+
+			start := time.Now()
+			resp, err := next(req)
+			if sent != nil {
+				rm.BytesSent = sent.n
+			}
+			if err != nil {
+				rm.Duration = time.Since(start)
+				rm.Err = err
+				m.RecordRequest(ctx, rm)
+				return nil, err
+			}
+			rm.StatusCode = resp.StatusCode
+			if resp.StatusCode >= 400 {
+				rm.Err = &StatusError{StatusCode: resp.StatusCode}
+			}
+			resp.Body = onClose(resp.Body, func(n int64) {
+				rm.Duration = time.Since(start)
+				rm.BytesReceived = n
+				m.RecordRequest(ctx, rm)
+			})
+			return resp, nil
+		}
+	}
+}
+
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// doneBody calls done once, with the number of bytes read, when the body
+// reaches EOF or is closed.
+type doneBody struct {
+	countingBody
+	once sync.Once
+	done func(n int64)
+}
+
+func onClose(rc io.ReadCloser, done func(n int64)) io.ReadCloser {
+	if rc == nil {
+		rc = http.NoBody
+	}
+	return &doneBody{countingBody: countingBody{ReadCloser: rc}, done: done}
+}
+
+func (b *doneBody) Read(p []byte) (int, error) {
+	n, err := b.countingBody.Read(p)
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *doneBody) Close() error {
+	err := b.countingBody.Close()
+	b.finish()
+	return err
+}
+
+func (b *doneBody) finish() {
+	b.once.Do(func() { b.done(b.n) })
+}
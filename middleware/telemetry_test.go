@@ -0,0 +1,161 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+type span struct {
+	operation string
+	err       error
+	ended     int
+}
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*span
+}
+
+func (t *fakeTracer) Start(ctx context.Context, operation string) (context.Context, func(error)) {
+	s := &span{operation: operation}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return ctx, func(err error) {
+		s.err = err
+		s.ended++
+	}
+}
+
+type fakeMetrics struct {
+	records []middleware.RequestMetrics
+}
+
+func (m *fakeMetrics) RecordRequest(ctx context.Context, rm middleware.RequestMetrics) {
+	m.records = append(m.records, rm)
+}
+
+func TestOperationNames(t *testing.T) {
+	ctx := context.Background()
+	tracer := &fakeTracer{}
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil {
+			io.Copy(io.Discard, req.Body)
+		}
+		return testutil.NewMockResponse(200, "{}"), nil
+	}}
+
+	stream.NewClient("k", stream.WithHTTPClient(hc), stream.WithTracer(tracer)).
+		Videos(1).Upload(ctx, "guid", strings.NewReader("video"))
+	shield.NewClient("k", shield.WithHTTPClient(hc), shield.WithTracer(tracer)).
+		WAF().ListRules(ctx)
+	files := storage.NewFileService("z", "pw", storage.RegionFalkenstein, storage.WithFileHTTPClient(hc), storage.WithFileTracer(tracer))
+	if rc, err := files.Download(ctx, "a.txt"); err == nil {
+		rc.Close()
+	}
+	storage.NewClient("k", storage.WithHTTPClient(hc), storage.WithTracer(tracer)).
+		Zones().Get(middleware.WithOperation(ctx, "custom"), 1)
+
+	var got []string
+	for _, s := range tracer.spans {
+		got = append(got, s.operation)
+		if s.ended != 1 {
+			t.Errorf("span %s ended %d times", s.operation, s.ended)
+		}
+	}
+	want := "stream.VideoService.Upload shield.WAFService.ListRules storage.FileService.Download custom"
+	if strings.Join(got, " ") != want {
+		t.Errorf("operations = %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestTraceEndsWithError(t *testing.T) {
+	tracer := &fakeTracer{}
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodDelete {
+			return nil, errors.New("dial tcp: refused")
+		}
+		return testutil.NewMockResponse(404, `{"Message":"not found"}`), nil
+	}}
+	zones := storage.NewClient("k", storage.WithHTTPClient(hc), storage.WithTracer(tracer)).Zones()
+	zones.Get(context.Background(), 1)
+	zones.Delete(context.Background(), 1)
+
+	var statusErr *middleware.StatusError
+	if s := tracer.spans[0]; !errors.As(s.err, &statusErr) || statusErr.StatusCode != 404 || s.ended != 1 {
+		t.Errorf("span 0 = %+v", s)
+	}
+	if s := tracer.spans[1]; s.err == nil || !strings.Contains(s.err.Error(), "refused") || s.ended != 1 {
+		t.Errorf("span 1 = %+v", s)
+	}
+}
+
+func TestMetricsCountBytesAndAttempts(t *testing.T) {
+	metrics := &fakeMetrics{}
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil {
+			io.Copy(io.Discard, req.Body)
+		}
+		return testutil.NewMockResponse(200, "0123456789"), nil
+	}}
+	files := storage.NewFileService("z", "pw", storage.RegionFalkenstein, storage.WithFileHTTPClient(hc), storage.WithFileMetrics(metrics))
+	ctx := context.Background()
+
+	if err := files.Upload(middleware.WithAttempt(ctx, 2), "a.txt", strings.NewReader("hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := files.Download(ctx, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics.records) != 1 {
+		t.Fatalf("download recorded before the body was read: %+v", metrics.records)
+	}
+	io.Copy(io.Discard, rc)
+	rc.Close()
+
+	if len(metrics.records) != 2 {
+		t.Fatalf("records = %+v", metrics.records)
+	}
+	up, down := metrics.records[0], metrics.records[1]
+	if up.Operation != "storage.FileService.Upload" || up.BytesSent != 5 || up.Attempt != 2 || up.StatusCode != 200 {
+		t.Errorf("upload = %+v", up)
+	}
+	if down.Operation != "storage.FileService.Download" || down.BytesReceived != 10 || down.Attempt != 1 || down.Err != nil {
+		t.Errorf("download = %+v", down)
+	}
+}
+
+// printTracer shows the shape of an adapter: an OpenTelemetry adapter would
+// call otel.Tracer(...).Start and span.RecordError/End in the same places.
+type printTracer struct{}
+
+func (printTracer) Start(ctx context.Context, operation string) (context.Context, func(error)) {
+	fmt.Println("start", operation)
+	return ctx, func(err error) {
+		fmt.Println("end", operation, err)
+	}
+}
+
+func ExampleTracer() {
+	hc := &testutil.MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return testutil.NewMockResponse(200, `{"Id":1}`), nil
+	}}
+	client := storage.NewClient("api-key", storage.WithHTTPClient(hc), storage.WithTracer(printTracer{}))
+	client.Zones().Get(context.Background(), 1)
+	// Output:
+	// start storage.ZoneService.Get
+	// end storage.ZoneService.Get <nil>
+}
@@ -1,5 +1,11 @@
 package bunny
 
+import (
+	"log/slog"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
+)
+
 // Option is a functional option for configuring the Client.
 type Option func(*Client)
 
@@ -17,6 +23,48 @@ func WithUserAgent(ua string) Option {
 	}
 }
 
+// WithMiddleware adds middlewares around the HTTP client. The first one
+// added is the outermost.
+func WithMiddleware(mws ...middleware.Middleware) Option {
+	return func(c *Client) {
+		c.pipeline.Middlewares = append(c.pipeline.Middlewares, mws...)
+	}
+}
+
+// WithLogger logs every request to l. The logger runs after all other
+// middlewares, so each retry attempt is logged separately. Credentials are
+// always redacted.
+func WithLogger(l *slog.Logger, opts ...middleware.LogOption) Option {
+	return func(c *Client) {
+		c.pipeline.Logger = middleware.Log(l, opts...)
+	}
+}
+
+// WithTracer starts a span for every request. The core client has no
+// service methods, so spans are named after the method and path.
+func WithTracer(t middleware.Tracer) Option {
+	return func(c *Client) {
+		c.pipeline.Tracer = middleware.Trace(t)
+	}
+}
+
+// WithMetrics records request counts, latencies, bytes transferred and
+// retries for every request.
+func WithMetrics(m middleware.Metrics) Option {
+	return func(c *Client) {
+		c.pipeline.Metrics = middleware.Measure(m)
+	}
+}
+
+// WithRateLimiter throttles requests with l and retries 429 responses after
+// the Retry-After period. Share one limiter between clients that draw from
+// the same quota.
+func WithRateLimiter(l *middleware.RateLimiter) Option {
+	return func(c *Client) {
+		c.pipeline.RateLimiter = l.Middleware()
+	}
+}
+
 // WithStreamBaseURL sets a custom base URL for the Stream API.
 func WithStreamBaseURL(url string) Option {
 	return func(c *Client) {
@@ -0,0 +1,299 @@
+package reconcile
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/redact"
+)
+
+func (r *Reconciler) planContainerApps(ctx context.Context, desired []ContainerAppSpec) (upserts, deletes []Action, err error) {
+	live := make(map[string]string) // name -> ID
+	opts := &containers.ListOptions{}
+	for {
+		resp, err := r.containerApps.List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, app := range resp.Items {
+			live[app.Name] = app.ID
+		}
+		if resp.Cursor == "" || len(resp.Items) == 0 {
+			break
+		}
+		opts = &containers.ListOptions{NextCursor: resp.Cursor}
+	}
+
+	for _, spec := range desired {
+		id, ok := live[spec.Name]
+		delete(live, spec.Name)
+		if !ok {
+			upserts = append(upserts, r.createContainerApp(spec))
+			continue
+		}
+
+		app, err := r.containerApps.Get(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes := diffContainerApp(spec, app)
+		if len(changes) == 0 {
+			continue
+		}
+		req := patchApplicationRequest(spec, app)
+		upserts = append(upserts, Action{
+			Type:    ActionUpdate,
+			Kind:    KindContainerApp,
+			Name:    spec.Name,
+			ID:      id,
+			Changes: changes,
+			apply: func(ctx context.Context) error {
+				_, err := r.containerApps.Patch(ctx, id, req)
+				return err
+			},
+		})
+	}
+
+	if r.prune {
+		for name, id := range live {
+			deletes = append(deletes, Action{
+				Type: ActionDelete,
+				Kind: KindContainerApp,
+				Name: name,
+				ID:   id,
+				apply: func(ctx context.Context) error {
+					return r.containerApps.Delete(ctx, id)
+				},
+			})
+		}
+		sortByName(deletes)
+	}
+	return upserts, deletes, nil
+}
+
+func (r *Reconciler) createContainerApp(spec ContainerAppSpec) Action {
+	req := &containers.CreateApplicationRequest{
+		Name:               spec.Name,
+		RuntimeType:        spec.RuntimeType,
+		ContainerTemplates: spec.ContainerTemplates,
+	}
+	if req.RuntimeType == "" {
+		req.RuntimeType = containers.RuntimeTypeShared
+	}
+	if spec.AutoScaling != nil {
+		req.AutoScaling = *spec.AutoScaling
+	}
+	if spec.RegionSettings != nil {
+		req.RegionSettings = *spec.RegionSettings
+	}
+
+	changes := []Change{{Field: "runtimeType", New: string(req.RuntimeType)}}
+	if spec.AutoScaling != nil {
+		changes = append(changes, Change{Field: "autoScaling", New: formatAutoScaling(spec.AutoScaling)})
+	}
+	if spec.RegionSettings != nil {
+		changes = append(changes, Change{Field: "regionSettings.allowedRegionIds", New: spec.RegionSettings.AllowedRegionIds})
+	}
+	for _, t := range spec.ContainerTemplates {
+		changes = append(changes, Change{Field: "containerTemplates." + t.Name, New: imageRef(t.ImageNamespace, t.ImageName, t.ImageTag)})
+	}
+
+	return Action{
+		Type:    ActionCreate,
+		Kind:    KindContainerApp,
+		Name:    spec.Name,
+		Changes: nonEmptyChanges(changes...),
+		apply: func(ctx context.Context) error {
+			_, err := r.containerApps.Create(ctx, req)
+			return err
+		},
+	}
+}
+
+// diffContainerApp compares the fields the spec manages. Templates are
+// compared by image, pull policy, entry point, probes, environment
+// variables outside KeepEnvironment and volume mounts.
+func diffContainerApp(spec ContainerAppSpec, app *containers.Application) []Change {
+	var changes []Change
+	if spec.RuntimeType != "" && spec.RuntimeType != app.RuntimeType {
+		changes = append(changes, Change{Field: "runtimeType", Old: string(app.RuntimeType), New: string(spec.RuntimeType)})
+	}
+	if spec.AutoScaling != nil && (app.AutoScaling == nil || *spec.AutoScaling != *app.AutoScaling) {
+		changes = append(changes, Change{Field: "autoScaling", Old: formatAutoScaling(app.AutoScaling), New: formatAutoScaling(spec.AutoScaling)})
+	}
+	if spec.RegionSettings != nil {
+		var live containers.RegionSettings
+		if app.RegionSettings != nil {
+			live = *app.RegionSettings
+		}
+		if !sameSet(spec.RegionSettings.AllowedRegionIds, live.AllowedRegionIds) {
+			changes = append(changes, Change{Field: "regionSettings.allowedRegionIds", Old: live.AllowedRegionIds, New: spec.RegionSettings.AllowedRegionIds})
+		}
+		if !sameSet(spec.RegionSettings.RequiredRegionIds, live.RequiredRegionIds) {
+			changes = append(changes, Change{Field: "regionSettings.requiredRegionIds", Old: live.RequiredRegionIds, New: spec.RegionSettings.RequiredRegionIds})
+		}
+		if spec.RegionSettings.MaxAllowedRegions != live.MaxAllowedRegions {
+			changes = append(changes, Change{Field: "regionSettings.maxAllowedRegions", Old: live.MaxAllowedRegions, New: spec.RegionSettings.MaxAllowedRegions})
+		}
+	}
+
+	if spec.ContainerTemplates != nil {
+		current := make(map[string]containers.ContainerTemplate, len(app.ContainerTemplates))
+		for _, t := range app.ContainerTemplates {
+			current[t.Name] = t
+		}
+		for _, t := range spec.ContainerTemplates {
+			live, ok := current[t.Name]
+			delete(current, t.Name)
+			if !ok {
+				changes = append(changes, Change{Field: "containerTemplates." + t.Name, Old: nil, New: imageRef(t.ImageNamespace, t.ImageName, t.ImageTag)})
+				continue
+			}
+			changes = append(changes, diffContainerTemplate(t, live, spec.KeepEnvironment)...)
+		}
+		names := make([]string, 0, len(current))
+		for name := range current {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			t := current[name]
+			changes = append(changes, Change{Field: "containerTemplates." + name, Old: imageRef(t.ImageNamespace, t.ImageName, t.ImageTag), New: nil})
+		}
+	}
+	return changes
+}
+
+// diffContainerTemplate compares a template of the spec with the live one
+// of the same name. Values of secret-looking variables are redacted.
+func diffContainerTemplate(t containers.CreateContainerTemplateRequest, live containers.ContainerTemplate, keep []string) []Change {
+	prefix := "containerTemplates." + t.Name + "."
+	var changes []Change
+	if want, have := imageRef(t.ImageNamespace, t.ImageName, t.ImageTag), imageRef(live.ImageNamespace, live.ImageName, live.ImageTag); want != have {
+		changes = append(changes, Change{Field: prefix + "image", Old: have, New: want})
+	}
+	if t.ImagePullPolicy != "" && t.ImagePullPolicy != live.ImagePullPolicy {
+		changes = append(changes, Change{Field: prefix + "imagePullPolicy", Old: string(live.ImagePullPolicy), New: string(t.ImagePullPolicy)})
+	}
+	if t.EntryPoint != nil && !reflect.DeepEqual(t.EntryPoint, live.EntryPoint) {
+		changes = append(changes, Change{Field: prefix + "entryPoint", Old: live.EntryPoint, New: t.EntryPoint})
+	}
+	if t.Probes != nil && !reflect.DeepEqual(t.Probes, live.Probes) {
+		changes = append(changes, Change{Field: prefix + "probes", Old: live.Probes, New: t.Probes})
+	}
+
+	env := func(vars []containers.EnvironmentVariable) map[string]string {
+		m := make(map[string]string, len(vars))
+		for _, v := range vars {
+			if !slices.Contains(keep, v.Name) {
+				m[v.Name] = v.Value
+			}
+		}
+		return m
+	}
+	want, have := env(t.EnvironmentVariables), env(live.EnvironmentVariables)
+	names := make([]string, 0, len(want)+len(have))
+	for name := range want {
+		names = append(names, name)
+	}
+	for name := range have {
+		if _, ok := want[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		w, inSpec := want[name]
+		h, inLive := have[name]
+		if inSpec && inLive && w == h {
+			continue
+		}
+		change := Change{Field: prefix + "environmentVariables." + name}
+		if inLive {
+			change.Old = envValue(name, h)
+		}
+		if inSpec {
+			change.New = envValue(name, w)
+		}
+		changes = append(changes, change)
+	}
+
+	mounts := make([]string, len(t.VolumeMounts))
+	for i, m := range t.VolumeMounts {
+		mounts[i] = volumeMountRef(m.VolumeName, m.MountPath, m.ReadOnly)
+	}
+	liveMounts := make([]string, len(live.VolumeMounts))
+	for i, m := range live.VolumeMounts {
+		liveMounts[i] = volumeMountRef(m.VolumeName, m.MountPath, m.ReadOnly)
+	}
+	if !sameSet(mounts, liveMounts) {
+		changes = append(changes, Change{Field: prefix + "volumeMounts", Old: liveMounts, New: mounts})
+	}
+	return changes
+}
+
+func envValue(name, value string) string {
+	if redact.SecretName(name) {
+		return redact.Placeholder
+	}
+	return value
+}
+
+func volumeMountRef(volume, path string, readOnly bool) string {
+	ref := volume + ":" + path
+	if readOnly {
+		ref += ":ro"
+	}
+	return ref
+}
+
+// patchApplicationRequest sends only the fields the spec manages, so unmanaged settings are kept.
+// Templates replace the live ones wholesale, so variables in KeepEnvironment are copied from app.
+func patchApplicationRequest(spec ContainerAppSpec, app *containers.Application) *containers.PatchApplicationRequest {
+	templates := spec.ContainerTemplates
+	if len(spec.KeepEnvironment) > 0 && templates != nil {
+		templates = make([]containers.CreateContainerTemplateRequest, len(spec.ContainerTemplates))
+		for i, t := range spec.ContainerTemplates {
+			t.EnvironmentVariables = slices.DeleteFunc(slices.Clone(t.EnvironmentVariables), func(v containers.EnvironmentVariable) bool {
+				return slices.Contains(spec.KeepEnvironment, v.Name)
+			})
+			for _, live := range app.ContainerTemplates {
+				if live.Name != t.Name {
+					continue
+				}
+				for _, v := range live.EnvironmentVariables {
+					if slices.Contains(spec.KeepEnvironment, v.Name) {
+						t.EnvironmentVariables = append(t.EnvironmentVariables, v)
+					}
+				}
+			}
+			templates[i] = t
+		}
+	}
+	return &containers.PatchApplicationRequest{
+		RuntimeType:        spec.RuntimeType,
+		AutoScaling:        spec.AutoScaling,
+		RegionSettings:     spec.RegionSettings,
+		ContainerTemplates: templates,
+	}
+}
+
+func formatAutoScaling(a *containers.AutoScaling) any {
+	if a == nil {
+		return nil
+	}
+	return []int{a.Min, a.Max}
+}
+
+func imageRef(namespace, name, tag string) string {
+	ref := name
+	if namespace != "" {
+		ref = namespace + "/" + ref
+	}
+	if tag != "" {
+		ref += ":" + tag
+	}
+	return ref
+}
@@ -0,0 +1,130 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/scripting"
+)
+
+func (r *Reconciler) planEdgeScripts(ctx context.Context, desired []EdgeScriptSpec) (upserts, deletes []Action, err error) {
+	scripts := r.scripting.Scripts()
+	live := make(map[string]scripting.EdgeScript)
+	for page := 1; ; page++ {
+		resp, err := scripts.List(ctx, &scripting.ScriptListOptions{Page: page, PerPage: defaultPageSize})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, s := range resp.Items {
+			if s.Name != nil && !s.Deleted {
+				live[*s.Name] = s
+			}
+		}
+		if len(resp.Items) == 0 || !resp.HasMoreItems {
+			break
+		}
+	}
+
+	for _, spec := range desired {
+		script, ok := live[spec.Name]
+		delete(live, spec.Name)
+		if !ok {
+			upserts = append(upserts, r.createEdgeScript(spec))
+			continue
+		}
+
+		id := script.ID
+		var changes []Change
+		if spec.Type != "" && spec.Type != script.ScriptType {
+			changes = append(changes, Change{Field: "type", Old: script.ScriptType, New: spec.Type})
+		}
+		codeChanged := false
+		if spec.Code != "" {
+			code, err := r.scripting.Code(id).Get(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			var current string
+			if code.Code != nil {
+				current = *code.Code
+			}
+			if current != spec.Code {
+				codeChanged = true
+				changes = append(changes, Change{Field: "code", Old: codeSummary(current), New: codeSummary(spec.Code)})
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		typeChanged := spec.Type != "" && spec.Type != script.ScriptType
+		upserts = append(upserts, Action{
+			Type:    ActionUpdate,
+			Kind:    KindEdgeScript,
+			Name:    spec.Name,
+			ID:      strconv.FormatInt(id, 10),
+			Changes: changes,
+			apply: func(ctx context.Context) error {
+				if typeChanged {
+					if _, err := scripts.Update(ctx, id, &scripting.UpdateScriptRequest{ScriptType: spec.Type}); err != nil {
+						return err
+					}
+				}
+				if codeChanged {
+					return r.scripting.Code(id).Set(ctx, &scripting.UpdateCodeRequest{Code: spec.Code})
+				}
+				return nil
+			},
+		})
+	}
+
+	if r.prune {
+		for name, script := range live {
+			id := script.ID
+			deletes = append(deletes, Action{
+				Type: ActionDelete,
+				Kind: KindEdgeScript,
+				Name: name,
+				ID:   strconv.FormatInt(id, 10),
+				apply: func(ctx context.Context) error {
+					return scripts.Delete(ctx, id, false)
+				},
+			})
+		}
+		sortByName(deletes)
+	}
+	return upserts, deletes, nil
+}
+
+func (r *Reconciler) createEdgeScript(spec EdgeScriptSpec) Action {
+	var changes []Change
+	if spec.Type != "" {
+		changes = append(changes, Change{Field: "type", New: spec.Type})
+	}
+	if spec.Code != "" {
+		changes = append(changes, Change{Field: "code", New: codeSummary(spec.Code)})
+	}
+	return Action{
+		Type:    ActionCreate,
+		Kind:    KindEdgeScript,
+		Name:    spec.Name,
+		Changes: changes,
+		apply: func(ctx context.Context) error {
+			_, err := r.scripting.Scripts().Create(ctx, &scripting.CreateScriptRequest{
+				Name:       spec.Name,
+				Code:       spec.Code,
+				ScriptType: spec.Type,
+			})
+			return err
+		},
+	}
+}
+
+// codeSummary keeps script bodies out of rendered plans.
+func codeSummary(code string) string {
+	if code == "" {
+		return ""
+	}
+	return fmt.Sprintf("(%d bytes)", len(code))
+}
@@ -0,0 +1,147 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Resource kinds used in plan addresses.
+const (
+	KindStorageZone   = "storage_zone"
+	KindStreamLibrary = "stream_library"
+	KindShieldZone    = "shield_zone"
+	KindCustomRule    = "shield_custom_rule"
+	KindRateLimit     = "shield_rate_limit"
+	KindAccessEntry   = "shield_access_entry"
+	KindEdgeScript    = "edge_script"
+	KindContainerApp  = "container_app"
+)
+
+// ActionType is the kind of change an action makes.
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// Change describes a single field that differs between live and desired state.
+type Change struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// Action is a single create, update or delete in a plan.
+type Action struct {
+	Type    ActionType
+	Kind    string
+	Name    string // resource name; for nested resources, "parent/name"
+	ID      string // live resource ID; empty for creates
+	Changes []Change
+
+	apply func(ctx context.Context) error
+}
+
+// Address returns the Terraform-style address of the resource, e.g. storage_zone.assets.
+func (a Action) Address() string {
+	return a.Kind + "." + a.Name
+}
+
+// Plan is the ordered list of actions needed to reach the desired state.
+// Creates and updates come first in dependency order, followed by deletes in reverse dependency order.
+type Plan struct {
+	Actions []Action
+}
+
+// Empty reports whether the live state already matches the spec.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// Counts returns the number of create, update and delete actions.
+func (p *Plan) Counts() (create, update, del int) {
+	for _, a := range p.Actions {
+		switch a.Type {
+		case ActionCreate:
+			create++
+		case ActionUpdate:
+			update++
+		case ActionDelete:
+			del++
+		}
+	}
+	return create, update, del
+}
+
+// WriteTo writes a human-readable rendering of the plan to w.
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if p.Empty() {
+		buf.WriteString("No changes. Live state matches the spec.\n")
+		n, err := w.Write(buf.Bytes())
+		return int64(n), err
+	}
+
+	for _, a := range p.Actions {
+		switch a.Type {
+		case ActionCreate:
+			fmt.Fprintf(&buf, "  + %s\n", a.Address())
+		case ActionUpdate:
+			fmt.Fprintf(&buf, "  ~ %s (id: %s)\n", a.Address(), a.ID)
+		case ActionDelete:
+			fmt.Fprintf(&buf, "  - %s (id: %s)\n", a.Address(), a.ID)
+		}
+		for _, c := range a.Changes {
+			switch a.Type {
+			case ActionCreate:
+				fmt.Fprintf(&buf, "      %s = %s\n", c.Field, formatValue(c.New))
+			default:
+				fmt.Fprintf(&buf, "      %s: %s -> %s\n", c.Field, formatValue(c.Old), formatValue(c.New))
+			}
+		}
+	}
+
+	create, update, del := p.Counts()
+	fmt.Fprintf(&buf, "\nPlan: %d to create, %d to update, %d to delete.\n", create, update, del)
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// String returns the plan rendering produced by WriteTo.
+func (p *Plan) String() string {
+	var sb strings.Builder
+	_, _ = p.WriteTo(&sb)
+	return sb.String()
+}
+
+func formatValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		return fmt.Sprintf("%q", t)
+	case *bool:
+		if t == nil {
+			return "(none)"
+		}
+		return fmt.Sprint(*t)
+	case *int:
+		if t == nil {
+			return "(none)"
+		}
+		return fmt.Sprint(*t)
+	case []string:
+		quoted := make([]string, len(t))
+		for i, s := range t {
+			quoted[i] = fmt.Sprintf("%q", s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	default:
+		return fmt.Sprint(t)
+	}
+}
@@ -0,0 +1,256 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+const defaultPageSize = 1000
+
+// ShieldClient is the subset of *shield.Client used by the reconciler.
+type ShieldClient interface {
+	Zones() shield.ZoneService
+	WAF() shield.WAFService
+	RateLimits() shield.RateLimitService
+	AccessLists(zoneID string) shield.AccessListService
+}
+
+// ScriptingClient is the subset of *scripting.Client used by the reconciler.
+type ScriptingClient interface {
+	Scripts() scripting.ScriptService
+	Code(scriptID int64) scripting.CodeService
+}
+
+// Reconciler diffs a Spec against live state and applies the resulting plan.
+type Reconciler struct {
+	storageZones    storage.ZoneService
+	streamLibraries stream.LibraryService
+	shield          ShieldClient
+	scripting       ScriptingClient
+	containerApps   containers.ApplicationService
+	prune           bool
+}
+
+// Option is a functional option for configuring the Reconciler.
+type Option func(*Reconciler)
+
+// WithStorage enables reconciliation of storage zones.
+func WithStorage(zones storage.ZoneService) Option {
+	return func(r *Reconciler) {
+		r.storageZones = zones
+	}
+}
+
+// WithStream enables reconciliation of stream libraries.
+func WithStream(libraries stream.LibraryService) Option {
+	return func(r *Reconciler) {
+		r.streamLibraries = libraries
+	}
+}
+
+// WithShield enables reconciliation of shield zones, custom rules, rate limits and access lists.
+func WithShield(client ShieldClient) Option {
+	return func(r *Reconciler) {
+		r.shield = client
+	}
+}
+
+// WithScripting enables reconciliation of edge scripts.
+func WithScripting(client ScriptingClient) Option {
+	return func(r *Reconciler) {
+		r.scripting = client
+	}
+}
+
+// WithContainers enables reconciliation of container apps.
+func WithContainers(apps containers.ApplicationService) Option {
+	return func(r *Reconciler) {
+		r.containerApps = apps
+	}
+}
+
+// WithPrune plans deletion of live resources that are missing from a managed section of the spec.
+// Without it, the reconciler only creates and updates.
+func WithPrune(prune bool) Option {
+	return func(r *Reconciler) {
+		r.prune = prune
+	}
+}
+
+// NewReconciler creates a new Reconciler.
+// Only the services passed as options can be reconciled.
+func NewReconciler(opts ...Option) *Reconciler {
+	r := &Reconciler{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Plan reads live state and returns the actions needed to reach spec.
+// It does not modify anything.
+func (r *Reconciler) Plan(ctx context.Context, spec *Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.checkServices(spec); err != nil {
+		return nil, err
+	}
+
+	var upserts, deletes [][]Action
+	add := func(u, d []Action, err error) error {
+		upserts = append(upserts, u)
+		deletes = append(deletes, d)
+		return err
+	}
+
+	if spec.StorageZones != nil {
+		if err := add(r.planStorageZones(ctx, spec.StorageZones)); err != nil {
+			return nil, fmt.Errorf("reconcile: %s: %w", KindStorageZone, err)
+		}
+	}
+	if spec.StreamLibraries != nil {
+		if err := add(r.planStreamLibraries(ctx, spec.StreamLibraries)); err != nil {
+			return nil, fmt.Errorf("reconcile: %s: %w", KindStreamLibrary, err)
+		}
+	}
+	if spec.ShieldZones != nil {
+		if err := add(r.planShieldZones(ctx, spec.ShieldZones)); err != nil {
+			return nil, fmt.Errorf("reconcile: %s: %w", KindShieldZone, err)
+		}
+	}
+	if spec.EdgeScripts != nil {
+		if err := add(r.planEdgeScripts(ctx, spec.EdgeScripts)); err != nil {
+			return nil, fmt.Errorf("reconcile: %s: %w", KindEdgeScript, err)
+		}
+	}
+	if spec.ContainerApps != nil {
+		if err := add(r.planContainerApps(ctx, spec.ContainerApps)); err != nil {
+			return nil, fmt.Errorf("reconcile: %s: %w", KindContainerApp, err)
+		}
+	}
+
+	plan := &Plan{}
+	for _, actions := range upserts {
+		plan.Actions = append(plan.Actions, actions...)
+	}
+	// Delete dependents before the resources they belong to.
+	slices.Reverse(deletes)
+	for _, actions := range deletes {
+		plan.Actions = append(plan.Actions, actions...)
+	}
+	return plan, nil
+}
+
+// Apply executes the actions of a plan in order.
+// It stops at the first failure; actions before it have already been applied.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	for _, a := range plan.Actions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if a.apply == nil {
+			return fmt.Errorf("reconcile: %s %s: action was not produced by Plan", a.Type, a.Address())
+		}
+		if err := a.apply(ctx); err != nil {
+			return fmt.Errorf("reconcile: %s %s: %w", a.Type, a.Address(), err)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) checkServices(spec *Spec) error {
+	var errs []error
+	missing := func(section, option string) {
+		errs = append(errs, fmt.Errorf("spec manages %s but %s was not configured", section, option))
+	}
+	if spec.StorageZones != nil && r.storageZones == nil {
+		missing("storageZones", "WithStorage")
+	}
+	if spec.StreamLibraries != nil && r.streamLibraries == nil {
+		missing("streamLibraries", "WithStream")
+	}
+	if spec.ShieldZones != nil && r.shield == nil {
+		missing("shieldZones", "WithShield")
+	}
+	if spec.EdgeScripts != nil && r.scripting == nil {
+		missing("edgeScripts", "WithScripting")
+	}
+	if spec.ContainerApps != nil && r.containerApps == nil {
+		missing("containerApps", "WithContainers")
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("reconcile: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// sameSet reports whether a and b contain the same strings, ignoring order.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+func boolOr(p *bool, def bool) bool {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+func accessKey(typ, value string) string {
+	return typ + ":" + value
+}
+
+func sortByName(actions []Action) {
+	slices.SortFunc(actions, func(a, b Action) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// nonEmptyChanges drops changes whose new value is unset, for rendering creates.
+func nonEmptyChanges(changes ...Change) []Change {
+	out := changes[:0]
+	for _, c := range changes {
+		switch v := c.New.(type) {
+		case nil:
+			continue
+		case string:
+			if v == "" {
+				continue
+			}
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		case *bool:
+			if v == nil {
+				continue
+			}
+		case *int:
+			if v == nil {
+				continue
+			}
+		case int:
+			if v == 0 {
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,347 @@
+package reconcile_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/reconcile"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+// router answers requests by "METHOD /path" and records every call with its body.
+type router struct {
+	routes map[string]string
+	calls  []string
+	bodies map[string]string
+}
+
+func newRouter(routes map[string]string) *router {
+	return &router{routes: routes, bodies: make(map[string]string)}
+}
+
+func (r *router) client() *testutil.MockHTTPClient {
+	return &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			key := req.Method + " " + req.URL.Path
+			r.calls = append(r.calls, key)
+			if req.Body != nil {
+				b, _ := io.ReadAll(req.Body)
+				r.bodies[key] = string(b)
+			}
+			body, ok := r.routes[key]
+			if !ok {
+				return testutil.NewMockResponse(404, `{"Message":"not found"}`), nil
+			}
+			return testutil.NewMockResponse(200, body), nil
+		},
+	}
+}
+
+func (r *router) mutations() []string {
+	var out []string
+	for _, c := range r.calls {
+		if !strings.HasPrefix(c, http.MethodGet) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+const specYAML = `
+storageZones:
+  - name: assets
+    region: DE
+    replicationRegions: [NY, LA]
+  - name: backups
+    region: SG
+streamLibraries:
+  - name: marketing
+    videoCacheExpirationDays: 7
+shieldZones:
+  - name: web
+    hostNames: [example.com]
+    customRules:
+      - name: block-admin
+        pattern: "path startswith /admin"
+        action: block
+    rateLimits:
+      - name: login
+        path: /login
+        requestsPerMinute: 30
+        action: block
+    accessList:
+      - type: ip
+        value: 203.0.113.7
+        action: block
+        comment: scanner
+`
+
+func TestParseSpec(t *testing.T) {
+	spec, err := reconcile.ParseSpec([]byte(specYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spec.StorageZones) != 2 || spec.StorageZones[0].ReplicationRegions[1] != "LA" {
+		t.Errorf("unexpected storage zones: %+v", spec.StorageZones)
+	}
+	if spec.EdgeScripts != nil {
+		t.Error("expected omitted section to stay nil (unmanaged)")
+	}
+	if got := spec.ShieldZones[0].RateLimits[0].RequestsPerMinute; got != 30 {
+		t.Errorf("expected 30 requests per minute, got %d", got)
+	}
+
+	spec, err = reconcile.ParseSpec([]byte(`{"edgeScripts":[{"name":"router","code":"const u = ` + "`${host}`" + `;"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.EdgeScripts[0].Code != "const u = `${host}`;" {
+		t.Errorf("expected code to be kept verbatim, got %q", spec.EdgeScripts[0].Code)
+	}
+
+	_, err = reconcile.ParseSpec([]byte("storageZones:\n  - name: a\n  - name: a\n"))
+	if err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("expected duplicate name error, got %v", err)
+	}
+}
+
+func TestReconciler_PlanAndApply(t *testing.T) {
+	r := newRouter(map[string]string{
+		"GET /storagezone": `{"Items":[
+			{"Id":1,"Name":"assets","Region":"DE","ReplicationRegions":["NY"]},
+			{"Id":2,"Name":"legacy","Region":"DE"}
+		],"TotalItems":2,"CurrentPage":1,"PageSize":1000}`,
+		"POST /storagezone":     `{"Id":3,"Name":"backups"}`,
+		"POST /storagezone/1":   `{"Id":1}`,
+		"DELETE /storagezone/2": ``,
+		"GET /videolibrary": `{"items":[{"libraryId":10,"name":"marketing","videoCacheExpirationDays":7}],
+			"totalItems":1,"currentPage":1,"itemsPerPage":1000}`,
+		"GET /shield/zones":                   `{"Items":[],"TotalCount":0}`,
+		"POST /shield/zone":                   `{"Id":"sz-1","Name":"web"}`,
+		"GET /shield/waf/custom-rules":        `{"Items":[],"TotalCount":0}`,
+		"POST /shield/waf/custom-rule":        `{"Id":"cr-1"}`,
+		"GET /shield/rate-limits":             `{"Items":[],"TotalCount":0}`,
+		"POST /shield/rate-limit":             `{"Id":"rl-1"}`,
+		"POST /shield/zone/sz-1/access-lists": `{"Type":"ip","Value":"203.0.113.7"}`,
+	})
+	hc := r.client()
+	rec := reconcile.NewReconciler(
+		reconcile.WithStorage(storage.NewClient("key", storage.WithHTTPClient(hc)).Zones()),
+		reconcile.WithStream(stream.NewClient("key", stream.WithHTTPClient(hc)).Libraries()),
+		reconcile.WithShield(shield.NewClient("key", shield.WithHTTPClient(hc))),
+		reconcile.WithPrune(true),
+	)
+
+	spec, err := reconcile.ParseSpec([]byte(specYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan, err := rec.Plan(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.mutations()) != 0 {
+		t.Fatalf("Plan must not modify anything, got %v", r.mutations())
+	}
+
+	create, update, del := plan.Counts()
+	if create != 5 || update != 1 || del != 1 {
+		t.Fatalf("expected 5 creates, 1 update, 1 delete, got %d/%d/%d\n%s", create, update, del, plan)
+	}
+	out := plan.String()
+	for _, want := range []string{
+		"+ storage_zone.backups",
+		`~ storage_zone.assets (id: 1)`,
+		`replicationRegions: ["NY"] -> ["NY", "LA"]`,
+		"- storage_zone.legacy (id: 2)",
+		"+ shield_custom_rule.web/block-admin",
+		"Plan: 5 to create, 1 to update, 1 to delete.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected plan to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "- storage_zone.legacy") < strings.Index(out, "+ shield_access_entry") {
+		t.Error("expected deletes after creates and updates")
+	}
+
+	if err := rec.Apply(context.Background(), plan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"POST /storagezone/1",
+		"POST /storagezone",
+		"POST /shield/zone",
+		"POST /shield/waf/custom-rule",
+		"POST /shield/rate-limit",
+		"POST /shield/zone/sz-1/access-lists",
+		"DELETE /storagezone/2",
+	}
+	if got := r.mutations(); !slices.Equal(got, want) {
+		t.Errorf("unexpected calls:\n got %v\nwant %v", got, want)
+	}
+	if !strings.Contains(r.bodies["POST /shield/waf/custom-rule"], `"ShieldZoneId":"sz-1"`) {
+		t.Errorf("expected rule to reference the created zone, got %s", r.bodies["POST /shield/waf/custom-rule"])
+	}
+}
+
+func TestReconciler_NoChanges(t *testing.T) {
+	r := newRouter(map[string]string{
+		"GET /videolibrary": `{"items":[{"libraryId":10,"name":"marketing","videoCacheExpirationDays":7},
+			{"libraryId":11,"name":"unmanaged"}],"totalItems":2,"currentPage":1,"itemsPerPage":1000}`,
+	})
+	rec := reconcile.NewReconciler(reconcile.WithStream(stream.NewClient("key", stream.WithHTTPClient(r.client())).Libraries()))
+
+	days := 7
+	plan, err := rec.Plan(context.Background(), &reconcile.Spec{
+		StreamLibraries: []reconcile.StreamLibrarySpec{{Name: "marketing", VideoCacheExpirationDays: &days}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("expected empty plan without prune, got:\n%s", plan)
+	}
+	if !strings.Contains(plan.String(), "No changes") {
+		t.Errorf("unexpected rendering: %s", plan)
+	}
+}
+
+func TestReconciler_MissingService(t *testing.T) {
+	rec := reconcile.NewReconciler()
+	_, err := rec.Plan(context.Background(), &reconcile.Spec{EdgeScripts: []reconcile.EdgeScriptSpec{}})
+	if err == nil || !strings.Contains(err.Error(), "WithScripting") {
+		t.Errorf("expected missing service error, got %v", err)
+	}
+}
+
+func TestReconciler_ClearRateLimitField(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := shield.NewClient("key", shield.WithBaseURL(srv.URL))
+	zone, err := client.Zones().Create(ctx, &shield.CreateZoneRequest{Name: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RateLimits().Create(ctx, &shield.CreateRateLimitRequest{Name: "login", Path: "/login", RequestsPerMinute: 30, Action: "Block", ShieldZoneID: zone.ID, IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	failCreate := true
+	flaky := shield.NewClient("key", shield.WithBaseURL(srv.URL), shield.WithMiddleware(
+		func(next middleware.RoundTripFunc) middleware.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				if failCreate && req.Method == http.MethodPost && req.URL.Path == "/shield/rate-limit" {
+					return testutil.NewMockResponse(500, `{"Message":"unavailable"}`), nil
+				}
+				return next(req)
+			}
+		}))
+	rec := reconcile.NewReconciler(reconcile.WithShield(flaky))
+	spec := &reconcile.Spec{ShieldZones: []reconcile.ShieldZoneSpec{{
+		Name:       "web",
+		RateLimits: []reconcile.RateLimitSpec{{Name: "login", RequestsPerMinute: 60, Action: "Block"}},
+	}}}
+	plan, err := rec.Plan(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A failed replacement keeps the old rule.
+	if err := rec.Apply(ctx, plan); err == nil {
+		t.Fatal("expected the failed create to be reported")
+	}
+	if limits, err := client.RateLimits().List(ctx); err != nil || len(limits.Items) != 1 || limits.Items[0].Path != "/login" {
+		t.Fatalf("expected the old rate limit kept, got %+v, %v", limits, err)
+	}
+	failCreate = false
+	if err := rec.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	// PATCH cannot clear the path, so the limit is recreated and the next
+	// plan converges.
+	plan, err = rec.Plan(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("expected no changes after clearing the path, got:\n%s", plan)
+	}
+	limits, err := client.RateLimits().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limits.Items) != 1 || limits.Items[0].Path != "" || limits.Items[0].RequestsPerMinute != 60 {
+		t.Errorf("unexpected rate limits: %+v", limits.Items)
+	}
+}
+
+func TestReconciler_ContainerTemplateDrift(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	apps := containers.NewClient("key", containers.WithBaseURL(srv.ContainersURL())).Applications()
+	template := containers.CreateContainerTemplateRequest{
+		Name: "web", ImageNamespace: "acme", ImageName: "web", ImageTag: "1.0",
+		EnvironmentVariables: []containers.EnvironmentVariable{{Name: "LOG", Value: "info"}, {Name: "API_TOKEN", Value: "live"}},
+	}
+	if _, err := apps.Create(ctx, &containers.CreateApplicationRequest{
+		Name: "api", ContainerTemplates: []containers.CreateContainerTemplateRequest{template},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	desired := template
+	desired.EnvironmentVariables = []containers.EnvironmentVariable{{Name: "LOG", Value: "debug"}, {Name: "API_TOKEN", Value: "rotated"}}
+	desired.EntryPoint = &containers.EntryPoint{Command: []string{"/app/server"}}
+	spec := &reconcile.Spec{ContainerApps: []reconcile.ContainerAppSpec{{
+		Name: "api", ContainerTemplates: []containers.CreateContainerTemplateRequest{desired},
+	}}}
+	rec := reconcile.NewReconciler(reconcile.WithContainers(apps))
+	plan, err := rec.Plan(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []string
+	for _, a := range plan.Actions {
+		for _, c := range a.Changes {
+			fields = append(fields, c.Field)
+			if strings.Contains(c.Field, "API_TOKEN") && (c.Old != "<redacted>" || c.New != "<redacted>") {
+				t.Errorf("expected the token value redacted, got %+v", c)
+			}
+		}
+	}
+	want := []string{
+		"containerTemplates.web.entryPoint",
+		"containerTemplates.web.environmentVariables.API_TOKEN",
+		"containerTemplates.web.environmentVariables.LOG",
+	}
+	if !slices.Equal(fields, want) {
+		t.Errorf("changes = %v, want %v", fields, want)
+	}
+	if err := rec.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	if plan, err = rec.Plan(ctx, spec); err != nil || !plan.Empty() {
+		t.Errorf("expected no changes after apply, got %v:\n%s", err, plan)
+	}
+
+	desired.Endpoints = []containers.EndpointRequest{{DisplayName: "public"}}
+	spec.ContainerApps[0].ContainerTemplates[0] = desired
+	if err := spec.Validate(); err == nil || !strings.Contains(err.Error(), "endpoints") {
+		t.Errorf("expected template endpoints rejected, got %v", err)
+	}
+}
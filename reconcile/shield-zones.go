@@ -0,0 +1,470 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+// zoneRef carries a shield zone ID to nested actions.
+// For zones created by the same plan the ID is only known once the create has been applied.
+type zoneRef struct {
+	id string
+}
+
+type liveShieldZone struct {
+	zone        shield.ShieldZone
+	customRules map[string]shield.CustomRule
+	rateLimits  map[string]shield.RateLimit
+}
+
+// planShieldZones plans shield zones and their nested rules.
+// The shield API cannot delete zones, so pruning only applies to nested rules and access list entries.
+func (r *Reconciler) planShieldZones(ctx context.Context, desired []ShieldZoneSpec) (upserts, deletes []Action, err error) {
+	zones, err := r.shield.Zones().List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	live := make(map[string]*liveShieldZone, len(zones.Items))
+	byID := make(map[string]*liveShieldZone, len(zones.Items))
+	for _, z := range zones.Items {
+		lz := &liveShieldZone{
+			zone:        z,
+			customRules: make(map[string]shield.CustomRule),
+			rateLimits:  make(map[string]shield.RateLimit),
+		}
+		live[z.Name] = lz
+		byID[z.ID] = lz
+	}
+
+	if needsNested(desired, func(z ShieldZoneSpec) bool { return z.CustomRules != nil }) {
+		rules, err := r.shield.WAF().ListCustomRules(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rule := range rules.Items {
+			if lz, ok := byID[rule.ShieldZoneID]; ok {
+				lz.customRules[rule.Name] = rule
+			}
+		}
+	}
+	if needsNested(desired, func(z ShieldZoneSpec) bool { return z.RateLimits != nil }) {
+		limits, err := r.shield.RateLimits().List(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, limit := range limits.Items {
+			if lz, ok := byID[limit.ShieldZoneID]; ok {
+				lz.rateLimits[limit.Name] = limit
+			}
+		}
+	}
+
+	for _, spec := range desired {
+		ref := &zoneRef{}
+		lz, ok := live[spec.Name]
+		if !ok {
+			lz = &liveShieldZone{}
+			upserts = append(upserts, r.createShieldZone(spec, ref))
+		} else {
+			ref.id = lz.zone.ID
+			if spec.HostNames != nil && !sameSet(spec.HostNames, lz.zone.HostNames) {
+				req := &shield.UpdateZoneRequest{HostNames: spec.HostNames}
+				upserts = append(upserts, Action{
+					Type:    ActionUpdate,
+					Kind:    KindShieldZone,
+					Name:    spec.Name,
+					ID:      ref.id,
+					Changes: []Change{{Field: "hostNames", Old: lz.zone.HostNames, New: spec.HostNames}},
+					apply: func(ctx context.Context) error {
+						_, err := r.shield.Zones().Update(ctx, ref.id, req)
+						return err
+					},
+				})
+			}
+		}
+
+		if spec.CustomRules != nil {
+			u, d := r.planCustomRules(spec, lz.customRules, ref)
+			upserts, deletes = append(upserts, u...), append(deletes, d...)
+		}
+		if spec.RateLimits != nil {
+			u, d := r.planRateLimits(spec, lz.rateLimits, ref)
+			upserts, deletes = append(upserts, u...), append(deletes, d...)
+		}
+		if spec.AccessList != nil {
+			var current []shield.AccessListEntry
+			if ok {
+				list, err := r.shield.AccessLists(ref.id).Get(ctx)
+				if err != nil {
+					return nil, nil, err
+				}
+				current = append(append(append(current, list.Allowed...), list.Blocked...), list.Challenged...)
+			}
+			u, d := r.planAccessList(spec, current, ref)
+			upserts, deletes = append(upserts, u...), append(deletes, d...)
+		}
+	}
+	return upserts, deletes, nil
+}
+
+func needsNested(zones []ShieldZoneSpec, managed func(ShieldZoneSpec) bool) bool {
+	for _, z := range zones {
+		if managed(z) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reconciler) createShieldZone(spec ShieldZoneSpec, ref *zoneRef) Action {
+	req := &shield.CreateZoneRequest{Name: spec.Name, HostNames: spec.HostNames}
+	return Action{
+		Type:    ActionCreate,
+		Kind:    KindShieldZone,
+		Name:    spec.Name,
+		Changes: nonEmptyChanges(Change{Field: "hostNames", New: spec.HostNames}),
+		apply: func(ctx context.Context) error {
+			zone, err := r.shield.Zones().Create(ctx, req)
+			if err != nil {
+				return err
+			}
+			ref.id = zone.ID
+			return nil
+		},
+	}
+}
+
+func (r *Reconciler) planCustomRules(zone ShieldZoneSpec, live map[string]shield.CustomRule, ref *zoneRef) (upserts, deletes []Action) {
+	for _, spec := range zone.CustomRules {
+		name := zone.Name + "/" + spec.Name
+		active := boolOr(spec.Active, true)
+		rule, ok := live[spec.Name]
+		delete(live, spec.Name)
+		if !ok {
+			upserts = append(upserts, Action{
+				Type: ActionCreate,
+				Kind: KindCustomRule,
+				Name: name,
+				Changes: nonEmptyChanges(
+					Change{Field: "description", New: spec.Description},
+					Change{Field: "pattern", New: spec.Pattern},
+					Change{Field: "action", New: spec.Action},
+					Change{Field: "active", New: &active},
+				),
+				apply: func(ctx context.Context) error {
+					_, err := r.shield.WAF().CreateCustomRule(ctx, &shield.CreateCustomRuleRequest{
+						Name:         spec.Name,
+						Description:  spec.Description,
+						Pattern:      spec.Pattern,
+						Action:       spec.Action,
+						ShieldZoneID: ref.id,
+						IsActive:     active,
+					})
+					return err
+				},
+			})
+			continue
+		}
+
+		var changes []Change
+		if spec.Description != rule.Description {
+			changes = append(changes, Change{Field: "description", Old: rule.Description, New: spec.Description})
+		}
+		if spec.Pattern != rule.Pattern {
+			changes = append(changes, Change{Field: "pattern", Old: rule.Pattern, New: spec.Pattern})
+		}
+		if spec.Action != rule.Action {
+			changes = append(changes, Change{Field: "action", Old: rule.Action, New: spec.Action})
+		}
+		if active != rule.IsActive {
+			changes = append(changes, Change{Field: "active", Old: rule.IsActive, New: active})
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		id := rule.ID
+		patch, canPatch := patchCustomRuleRequest(spec, rule, active)
+		upserts = append(upserts, Action{
+			Type:    ActionUpdate,
+			Kind:    KindCustomRule,
+			Name:    name,
+			ID:      id,
+			Changes: changes,
+			apply: func(ctx context.Context) error {
+				if canPatch {
+					_, err := r.shield.WAF().UpdateCustomRule(ctx, id, patch)
+					return err
+				}
+				_, err := r.shield.WAF().ReplaceCustomRule(ctx, id, &shield.ReplaceCustomRuleRequest{
+					Name:         spec.Name,
+					Description:  spec.Description,
+					Pattern:      spec.Pattern,
+					Action:       spec.Action,
+					ShieldZoneID: ref.id,
+					IsActive:     active,
+				})
+				return err
+			},
+		})
+	}
+
+	if r.prune {
+		for _, rule := range live {
+			id := rule.ID
+			deletes = append(deletes, Action{
+				Type: ActionDelete,
+				Kind: KindCustomRule,
+				Name: zone.Name + "/" + rule.Name,
+				ID:   id,
+				apply: func(ctx context.Context) error {
+					return r.shield.WAF().DeleteCustomRule(ctx, id)
+				},
+			})
+		}
+		sortByName(deletes)
+	}
+	return upserts, deletes
+}
+
+// patchCustomRuleRequest returns a PATCH request with only the fields that
+// change. PATCH cannot clear a field, so it reports false when one of the
+// changes is to an empty value and the rule has to be replaced instead.
+func patchCustomRuleRequest(spec CustomRuleSpec, rule shield.CustomRule, active bool) (*shield.UpdateCustomRuleRequest, bool) {
+	req := &shield.UpdateCustomRuleRequest{}
+	for _, f := range []struct {
+		want, have string
+		dst        *string
+	}{
+		{spec.Description, rule.Description, &req.Description},
+		{spec.Pattern, rule.Pattern, &req.Pattern},
+		{spec.Action, rule.Action, &req.Action},
+	} {
+		if f.want == f.have {
+			continue
+		}
+		if f.want == "" {
+			return nil, false
+		}
+		*f.dst = f.want
+	}
+	if active != rule.IsActive {
+		req.IsActive = &active
+	}
+	return req, true
+}
+
+// patchRateLimitRequest is patchCustomRuleRequest for rate limits. The
+// request counts are pointers and can be cleared by PATCH; path and action
+// cannot.
+func patchRateLimitRequest(spec RateLimitSpec, limit shield.RateLimit, active bool) (*shield.UpdateRateLimitRequest, bool) {
+	if spec.Path != limit.Path && spec.Path == "" || spec.Action != limit.Action && spec.Action == "" {
+		return nil, false
+	}
+	req := &shield.UpdateRateLimitRequest{}
+	if spec.Path != limit.Path {
+		req.Path = spec.Path
+	}
+	if spec.Action != limit.Action {
+		req.Action = spec.Action
+	}
+	if spec.RequestsPerSecond != limit.RequestsPerSecond {
+		req.RequestsPerSecond = &spec.RequestsPerSecond
+	}
+	if spec.RequestsPerMinute != limit.RequestsPerMinute {
+		req.RequestsPerMinute = &spec.RequestsPerMinute
+	}
+	if active != limit.IsActive {
+		req.IsActive = &active
+	}
+	return req, true
+}
+
+func (r *Reconciler) planRateLimits(zone ShieldZoneSpec, live map[string]shield.RateLimit, ref *zoneRef) (upserts, deletes []Action) {
+	for _, spec := range zone.RateLimits {
+		name := zone.Name + "/" + spec.Name
+		active := boolOr(spec.Active, true)
+		limit, ok := live[spec.Name]
+		delete(live, spec.Name)
+		if !ok {
+			upserts = append(upserts, Action{
+				Type: ActionCreate,
+				Kind: KindRateLimit,
+				Name: name,
+				Changes: nonEmptyChanges(
+					Change{Field: "path", New: spec.Path},
+					Change{Field: "requestsPerSecond", New: spec.RequestsPerSecond},
+					Change{Field: "requestsPerMinute", New: spec.RequestsPerMinute},
+					Change{Field: "action", New: spec.Action},
+					Change{Field: "active", New: &active},
+				),
+				apply: func(ctx context.Context) error {
+					_, err := r.shield.RateLimits().Create(ctx, &shield.CreateRateLimitRequest{
+						Name:              spec.Name,
+						Path:              spec.Path,
+						RequestsPerSecond: spec.RequestsPerSecond,
+						RequestsPerMinute: spec.RequestsPerMinute,
+						Action:            spec.Action,
+						ShieldZoneID:      ref.id,
+						IsActive:          active,
+					})
+					return err
+				},
+			})
+			continue
+		}
+
+		var changes []Change
+		if spec.Path != limit.Path {
+			changes = append(changes, Change{Field: "path", Old: limit.Path, New: spec.Path})
+		}
+		if spec.RequestsPerSecond != limit.RequestsPerSecond {
+			changes = append(changes, Change{Field: "requestsPerSecond", Old: limit.RequestsPerSecond, New: spec.RequestsPerSecond})
+		}
+		if spec.RequestsPerMinute != limit.RequestsPerMinute {
+			changes = append(changes, Change{Field: "requestsPerMinute", Old: limit.RequestsPerMinute, New: spec.RequestsPerMinute})
+		}
+		if spec.Action != limit.Action {
+			changes = append(changes, Change{Field: "action", Old: limit.Action, New: spec.Action})
+		}
+		if active != limit.IsActive {
+			changes = append(changes, Change{Field: "active", Old: limit.IsActive, New: active})
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		id := limit.ID
+		patch, canPatch := patchRateLimitRequest(spec, limit, active)
+		upserts = append(upserts, Action{
+			Type:    ActionUpdate,
+			Kind:    KindRateLimit,
+			Name:    name,
+			ID:      id,
+			Changes: changes,
+			apply: func(ctx context.Context) error {
+				if canPatch {
+					_, err := r.shield.RateLimits().Update(ctx, id, patch)
+					return err
+				}
+				// Rate limits have no PUT; recreate the rule to clear a field.
+				// The replacement is created first, so a failure leaves the
+				// old rule in place.
+				if _, err := r.shield.RateLimits().Create(ctx, &shield.CreateRateLimitRequest{
+					Name:              spec.Name,
+					Path:              spec.Path,
+					RequestsPerSecond: spec.RequestsPerSecond,
+					RequestsPerMinute: spec.RequestsPerMinute,
+					Action:            spec.Action,
+					ShieldZoneID:      ref.id,
+					IsActive:          active,
+				}); err != nil {
+					return err
+				}
+				if err := r.shield.RateLimits().Delete(ctx, id); err != nil {
+					return fmt.Errorf("replacement created, deleting the old rate limit %s: %w", id, err)
+				}
+				return nil
+			},
+		})
+	}
+
+	if r.prune {
+		for _, limit := range live {
+			id := limit.ID
+			deletes = append(deletes, Action{
+				Type: ActionDelete,
+				Kind: KindRateLimit,
+				Name: zone.Name + "/" + limit.Name,
+				ID:   id,
+				apply: func(ctx context.Context) error {
+					return r.shield.RateLimits().Delete(ctx, id)
+				},
+			})
+		}
+		sortByName(deletes)
+	}
+	return upserts, deletes
+}
+
+func (r *Reconciler) planAccessList(zone ShieldZoneSpec, current []shield.AccessListEntry, ref *zoneRef) (upserts, deletes []Action) {
+	live := make(map[string]shield.AccessListEntry, len(current))
+	for _, e := range current {
+		live[accessKey(e.Type, e.Value)] = e
+	}
+
+	for _, spec := range zone.AccessList {
+		key := accessKey(spec.Type, spec.Value)
+		name := zone.Name + "/" + key
+		entry, ok := live[key]
+		delete(live, key)
+		if !ok {
+			upserts = append(upserts, Action{
+				Type: ActionCreate,
+				Kind: KindAccessEntry,
+				Name: name,
+				Changes: nonEmptyChanges(
+					Change{Field: "action", New: spec.Action},
+					Change{Field: "comment", New: spec.Comment},
+				),
+				apply: func(ctx context.Context) error {
+					_, err := r.shield.AccessLists(ref.id).Add(ctx, &shield.AddAccessListEntryRequest{
+						Type:    spec.Type,
+						Value:   spec.Value,
+						Action:  spec.Action,
+						Comment: spec.Comment,
+					})
+					return err
+				},
+			})
+			continue
+		}
+
+		var changes []Change
+		if spec.Action != entry.Action {
+			changes = append(changes, Change{Field: "action", Old: entry.Action, New: spec.Action})
+		}
+		if spec.Comment != entry.Comment {
+			changes = append(changes, Change{Field: "comment", Old: entry.Comment, New: spec.Comment})
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		upserts = append(upserts, Action{
+			Type:    ActionUpdate,
+			Kind:    KindAccessEntry,
+			Name:    name,
+			ID:      key,
+			Changes: changes,
+			apply: func(ctx context.Context) error {
+				return r.shield.AccessLists(ref.id).Update(ctx, &shield.UpdateAccessListEntriesRequest{
+					Updates: []shield.AccessListEntryUpdate{{
+						Type:    spec.Type,
+						Value:   spec.Value,
+						Action:  spec.Action,
+						Comment: spec.Comment,
+					}},
+				})
+			},
+		})
+	}
+
+	if r.prune {
+		for key, entry := range live {
+			id := shield.AccessListEntryIdentifier{Type: entry.Type, Value: entry.Value}
+			deletes = append(deletes, Action{
+				Type: ActionDelete,
+				Kind: KindAccessEntry,
+				Name: zone.Name + "/" + key,
+				ID:   key,
+				apply: func(ctx context.Context) error {
+					return r.shield.AccessLists(ref.id).Delete(ctx, &shield.DeleteAccessListEntriesRequest{
+						Entries: []shield.AccessListEntryIdentifier{id},
+					})
+				},
+			})
+		}
+		sortByName(deletes)
+	}
+	return upserts, deletes
+}
@@ -0,0 +1,197 @@
+// Package reconcile applies a declarative description of Bunny.net resources
+// to an account, in the style of a plan/apply infrastructure workflow.
+//
+// A Spec lists the desired storage zones, stream libraries, shield zones (with
+// their custom WAF rules, rate limits and access lists), edge scripts and
+// container apps. Resources are matched to live resources by name. A section
+// that is omitted from the spec is left unmanaged; a section that is present
+// but empty is managed and, when pruning is enabled, everything in it is deleted.
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/yaml"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+)
+
+// Spec is the desired state of an account.
+type Spec struct {
+	StorageZones    []StorageZoneSpec   `json:"storageZones,omitempty"`
+	StreamLibraries []StreamLibrarySpec `json:"streamLibraries,omitempty"`
+	ShieldZones     []ShieldZoneSpec    `json:"shieldZones,omitempty"`
+	EdgeScripts     []EdgeScriptSpec    `json:"edgeScripts,omitempty"`
+	ContainerApps   []ContainerAppSpec  `json:"containerApps,omitempty"`
+}
+
+// StorageZoneSpec is the desired state of a storage zone.
+// Region is only used on creation; the API does not allow moving a zone.
+type StorageZoneSpec struct {
+	Name               string   `json:"name"`
+	Region             string   `json:"region,omitempty"`
+	ReplicationRegions []string `json:"replicationRegions,omitempty"`
+	OriginURL          string   `json:"originUrl,omitempty"`
+	Custom404FilePath  string   `json:"custom404FilePath,omitempty"`
+	Rewrite404To200    *bool    `json:"rewrite404To200,omitempty"`
+}
+
+// StreamLibrarySpec is the desired state of a stream video library.
+type StreamLibrarySpec struct {
+	Name                     string `json:"name"`
+	VideoCacheExpirationDays *int   `json:"videoCacheExpirationDays,omitempty"`
+}
+
+// ShieldZoneSpec is the desired state of a shield zone and its security rules.
+// Nil rule lists are unmanaged.
+type ShieldZoneSpec struct {
+	Name        string            `json:"name"`
+	HostNames   []string          `json:"hostNames,omitempty"`
+	CustomRules []CustomRuleSpec  `json:"customRules,omitempty"`
+	RateLimits  []RateLimitSpec   `json:"rateLimits,omitempty"`
+	AccessList  []AccessEntrySpec `json:"accessList,omitempty"`
+}
+
+// CustomRuleSpec is the desired state of a custom WAF rule.
+type CustomRuleSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+	Action      string `json:"action,omitempty"`
+	Active      *bool  `json:"active,omitempty"` // defaults to true
+}
+
+// RateLimitSpec is the desired state of a rate limit rule.
+type RateLimitSpec struct {
+	Name              string `json:"name"`
+	Path              string `json:"path,omitempty"`
+	RequestsPerSecond int    `json:"requestsPerSecond,omitempty"`
+	RequestsPerMinute int    `json:"requestsPerMinute,omitempty"`
+	Action            string `json:"action,omitempty"`
+	Active            *bool  `json:"active,omitempty"` // defaults to true
+}
+
+// AccessEntrySpec is the desired state of an access list entry.
+// Entries are identified by Type and Value.
+type AccessEntrySpec struct {
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	Action  string `json:"action"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// EdgeScriptSpec is the desired state of an edge script.
+// Code is compared byte for byte; leave it empty to manage only the script itself.
+type EdgeScriptSpec struct {
+	Name string               `json:"name"`
+	Type scripting.ScriptType `json:"type,omitempty"`
+	Code string               `json:"code,omitempty"`
+}
+
+// ContainerAppSpec is the desired state of a Magic Containers application.
+// KeepEnvironment names environment variables whose values are taken from
+// the live templates when they are patched, for secrets the spec cannot
+// hold; an application created from the spec starts without them.
+type ContainerAppSpec struct {
+	Name               string                                      `json:"name"`
+	RuntimeType        containers.RuntimeType                      `json:"runtimeType,omitempty"`
+	AutoScaling        *containers.AutoScaling                     `json:"autoScaling,omitempty"`
+	RegionSettings     *containers.CreateRegionSettingsRequest     `json:"regionSettings,omitempty"`
+	ContainerTemplates []containers.CreateContainerTemplateRequest `json:"containerTemplates,omitempty"`
+	KeepEnvironment    []string                                    `json:"keepEnvironment,omitempty"`
+}
+
+// ParseSpec parses a spec from JSON or YAML.
+// JSON is detected by a leading '{'; anything else is parsed as YAML.
+// Values are used verbatim, so edge script code may contain "${...}".
+func ParseSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("reconcile: parse spec: %w", err)
+		}
+	} else if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("reconcile: parse spec: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// LoadSpec reads and parses a spec file.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+	return ParseSpec(data)
+}
+
+// Validate checks that every resource has a name, that names are unique per
+// kind, and that container templates set no endpoints, which the reconciler
+// cannot diff.
+func (s *Spec) Validate() error {
+	var errs []error
+	check := func(kind string, names []string) {
+		seen := make(map[string]bool, len(names))
+		for i, name := range names {
+			switch {
+			case name == "":
+				errs = append(errs, fmt.Errorf("%s[%d]: name is required", kind, i))
+			case seen[name]:
+				errs = append(errs, fmt.Errorf("%s %q: duplicate name", kind, name))
+			}
+			seen[name] = true
+		}
+	}
+
+	check(KindStorageZone, collect(s.StorageZones, func(z StorageZoneSpec) string { return z.Name }))
+	check(KindStreamLibrary, collect(s.StreamLibraries, func(l StreamLibrarySpec) string { return l.Name }))
+	check(KindShieldZone, collect(s.ShieldZones, func(z ShieldZoneSpec) string { return z.Name }))
+	check(KindEdgeScript, collect(s.EdgeScripts, func(e EdgeScriptSpec) string { return e.Name }))
+	check(KindContainerApp, collect(s.ContainerApps, func(a ContainerAppSpec) string { return a.Name }))
+
+	for _, a := range s.ContainerApps {
+		for _, t := range a.ContainerTemplates {
+			if len(t.Endpoints) > 0 {
+				errs = append(errs, fmt.Errorf("%s %q: template %q: endpoints cannot be reconciled; manage them with the endpoint API", KindContainerApp, a.Name, t.Name))
+			}
+		}
+	}
+
+	for _, z := range s.ShieldZones {
+		check(KindCustomRule, collect(z.CustomRules, func(r CustomRuleSpec) string { return r.Name }))
+		check(KindRateLimit, collect(z.RateLimits, func(r RateLimitSpec) string { return r.Name }))
+		seen := make(map[string]bool, len(z.AccessList))
+		for _, e := range z.AccessList {
+			key := accessKey(e.Type, e.Value)
+			if e.Type == "" || e.Value == "" {
+				errs = append(errs, fmt.Errorf("%s in %q: type and value are required", KindAccessEntry, z.Name))
+			} else if seen[key] {
+				errs = append(errs, fmt.Errorf("%s %q in %q: duplicate entry", KindAccessEntry, key, z.Name))
+			}
+			seen[key] = true
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("reconcile: invalid spec: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func collect[T any](items []T, name func(T) string) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = name(item)
+	}
+	return names
+}
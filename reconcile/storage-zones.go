@@ -0,0 +1,132 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+func (r *Reconciler) planStorageZones(ctx context.Context, desired []StorageZoneSpec) (upserts, deletes []Action, err error) {
+	live := make(map[string]storage.Zone)
+	var count int
+	for page := 1; ; page++ {
+		resp, err := r.storageZones.List(ctx, &storage.ZoneListOptions{Page: page, PerPage: defaultPageSize})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, z := range resp.Items {
+			if !z.Deleted {
+				live[z.Name] = z
+			}
+		}
+		count += len(resp.Items)
+		if len(resp.Items) == 0 || !resp.HasMore() || count >= resp.TotalItems {
+			break
+		}
+	}
+
+	for _, spec := range desired {
+		zone, ok := live[spec.Name]
+		delete(live, spec.Name)
+		if !ok {
+			upserts = append(upserts, r.createStorageZone(spec))
+			continue
+		}
+		if spec.Region != "" && !strings.EqualFold(spec.Region, zone.Region) {
+			return nil, nil, fmt.Errorf("%q: region cannot change from %s to %s", spec.Name, zone.Region, spec.Region)
+		}
+		if a, ok := r.updateStorageZone(spec, zone); ok {
+			upserts = append(upserts, a)
+		}
+	}
+
+	if r.prune {
+		for _, zone := range live {
+			id := zone.ID
+			deletes = append(deletes, Action{
+				Type: ActionDelete,
+				Kind: KindStorageZone,
+				Name: zone.Name,
+				ID:   strconv.FormatInt(id, 10),
+				apply: func(ctx context.Context) error {
+					return r.storageZones.Delete(ctx, id)
+				},
+			})
+		}
+		sortByName(deletes)
+	}
+	return upserts, deletes, nil
+}
+
+func (r *Reconciler) createStorageZone(spec StorageZoneSpec) Action {
+	a := Action{Type: ActionCreate, Kind: KindStorageZone, Name: spec.Name}
+	a.Changes = nonEmptyChanges(
+		Change{Field: "region", New: spec.Region},
+		Change{Field: "replicationRegions", New: spec.ReplicationRegions},
+		Change{Field: "originUrl", New: spec.OriginURL},
+		Change{Field: "custom404FilePath", New: spec.Custom404FilePath},
+		Change{Field: "rewrite404To200", New: spec.Rewrite404To200},
+	)
+	a.apply = func(ctx context.Context) error {
+		zone, err := r.storageZones.Create(ctx, &storage.CreateZoneRequest{
+			Name:               spec.Name,
+			Region:             spec.Region,
+			ReplicationRegions: spec.ReplicationRegions,
+			OriginURL:          spec.OriginURL,
+		})
+		if err != nil {
+			return err
+		}
+		// Custom 404 settings are not accepted on creation.
+		if spec.Custom404FilePath == "" && spec.Rewrite404To200 == nil {
+			return nil
+		}
+		_, err = r.storageZones.Update(ctx, zone.ID, &storage.UpdateZoneRequest{
+			Custom404FilePath: spec.Custom404FilePath,
+			Rewrite404To200:   spec.Rewrite404To200,
+		})
+		return err
+	}
+	return a
+}
+
+func (r *Reconciler) updateStorageZone(spec StorageZoneSpec, zone storage.Zone) (Action, bool) {
+	var changes []Change
+	if spec.ReplicationRegions != nil && !sameSet(spec.ReplicationRegions, zone.ReplicationRegions) {
+		changes = append(changes, Change{Field: "replicationRegions", Old: zone.ReplicationRegions, New: spec.ReplicationRegions})
+	}
+	if spec.OriginURL != "" && spec.OriginURL != zone.OriginURL {
+		changes = append(changes, Change{Field: "originUrl", Old: zone.OriginURL, New: spec.OriginURL})
+	}
+	if spec.Custom404FilePath != "" && spec.Custom404FilePath != zone.Custom404FilePath {
+		changes = append(changes, Change{Field: "custom404FilePath", Old: zone.Custom404FilePath, New: spec.Custom404FilePath})
+	}
+	if spec.Rewrite404To200 != nil && *spec.Rewrite404To200 != zone.Rewrite404To200 {
+		changes = append(changes, Change{Field: "rewrite404To200", Old: zone.Rewrite404To200, New: *spec.Rewrite404To200})
+	}
+	if len(changes) == 0 {
+		return Action{}, false
+	}
+
+	id := zone.ID
+	req := &storage.UpdateZoneRequest{
+		ReplicationRegions: spec.ReplicationRegions,
+		OriginURL:          spec.OriginURL,
+		Custom404FilePath:  spec.Custom404FilePath,
+		Rewrite404To200:    spec.Rewrite404To200,
+	}
+	return Action{
+		Type:    ActionUpdate,
+		Kind:    KindStorageZone,
+		Name:    spec.Name,
+		ID:      strconv.FormatInt(id, 10),
+		Changes: changes,
+		apply: func(ctx context.Context) error {
+			_, err := r.storageZones.Update(ctx, id, req)
+			return err
+		},
+	}, true
+}
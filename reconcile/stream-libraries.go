@@ -0,0 +1,88 @@
+package reconcile
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+func (r *Reconciler) planStreamLibraries(ctx context.Context, desired []StreamLibrarySpec) (upserts, deletes []Action, err error) {
+	live := make(map[string]stream.Library)
+	for page := 1; ; page++ {
+		resp, err := r.streamLibraries.List(ctx, &stream.LibraryListOptions{Page: page, ItemsPerPage: defaultPageSize})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, l := range resp.Items {
+			live[l.Name] = l
+		}
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			break
+		}
+	}
+
+	for _, spec := range desired {
+		library, ok := live[spec.Name]
+		delete(live, spec.Name)
+		if !ok {
+			upserts = append(upserts, r.createStreamLibrary(spec))
+			continue
+		}
+		if spec.VideoCacheExpirationDays == nil || *spec.VideoCacheExpirationDays == library.VideoCacheExpirationDays {
+			continue
+		}
+
+		id := library.LibraryID
+		req := &stream.UpdateLibraryRequest{VideoCacheExpirationDays: spec.VideoCacheExpirationDays}
+		upserts = append(upserts, Action{
+			Type: ActionUpdate,
+			Kind: KindStreamLibrary,
+			Name: spec.Name,
+			ID:   strconv.FormatInt(id, 10),
+			Changes: []Change{{
+				Field: "videoCacheExpirationDays",
+				Old:   library.VideoCacheExpirationDays,
+				New:   *spec.VideoCacheExpirationDays,
+			}},
+			apply: func(ctx context.Context) error {
+				_, err := r.streamLibraries.Update(ctx, id, req)
+				return err
+			},
+		})
+	}
+
+	if r.prune {
+		for _, library := range live {
+			id := library.LibraryID
+			deletes = append(deletes, Action{
+				Type: ActionDelete,
+				Kind: KindStreamLibrary,
+				Name: library.Name,
+				ID:   strconv.FormatInt(id, 10),
+				apply: func(ctx context.Context) error {
+					return r.streamLibraries.Delete(ctx, id)
+				},
+			})
+		}
+		sortByName(deletes)
+	}
+	return upserts, deletes, nil
+}
+
+func (r *Reconciler) createStreamLibrary(spec StreamLibrarySpec) Action {
+	req := &stream.CreateLibraryRequest{Name: spec.Name}
+	if spec.VideoCacheExpirationDays != nil {
+		req.VideoCacheExpirationDays = *spec.VideoCacheExpirationDays
+	}
+	return Action{
+		Type:    ActionCreate,
+		Kind:    KindStreamLibrary,
+		Name:    spec.Name,
+		Changes: nonEmptyChanges(Change{Field: "videoCacheExpirationDays", New: spec.VideoCacheExpirationDays}),
+		apply: func(ctx context.Context) error {
+			_, err := r.streamLibraries.Create(ctx, req)
+			return err
+		},
+	}
+}
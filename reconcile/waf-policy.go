@@ -0,0 +1,146 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/geraldo/bunny-sdk-go/internal/yaml"
+)
+
+// WAFPolicy is the WAF policy of one or more shield zones, kept in a
+// versioned file. Rules use the same fields as in a Spec, and are active
+// unless they set active to false:
+//
+//	zones:
+//	  - name: web
+//	    customRules:
+//	      - name: block-admin
+//	        pattern: /admin/*
+//	        action: Block
+//	    rateLimits:
+//	      - name: api
+//	        path: /api/*
+//	        requestsPerMinute: 600
+//	        action: Block
+//	        active: false
+//
+// Every zone in the policy has both its custom rules and rate limits
+// managed, even when a list is empty. Zones that are not listed are left
+// alone; the listed ones must exist.
+type WAFPolicy struct {
+	Zones []WAFPolicyZone `json:"zones"`
+}
+
+// WAFPolicyZone is the policy of one shield zone, identified by name.
+type WAFPolicyZone struct {
+	Name        string           `json:"name"`
+	CustomRules []CustomRuleSpec `json:"customRules,omitempty"`
+	RateLimits  []RateLimitSpec  `json:"rateLimits,omitempty"`
+}
+
+// ParseWAFPolicy parses a policy from JSON or YAML, like ParseSpec.
+func ParseWAFPolicy(data []byte) (*WAFPolicy, error) {
+	var policy WAFPolicy
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&policy); err != nil {
+			return nil, fmt.Errorf("reconcile: parse WAF policy: %w", err)
+		}
+	} else if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("reconcile: parse WAF policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// LoadWAFPolicy reads and parses a policy file.
+func LoadWAFPolicy(path string) (*WAFPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+	return ParseWAFPolicy(data)
+}
+
+// Validate checks the policy as Spec.Validate does.
+func (p *WAFPolicy) Validate() error {
+	return p.Spec().Validate()
+}
+
+// Spec converts the policy into a spec that manages the custom rules and
+// rate limits of its zones.
+func (p *WAFPolicy) Spec() *Spec {
+	spec := &Spec{ShieldZones: make([]ShieldZoneSpec, 0, len(p.Zones))}
+	for _, z := range p.Zones {
+		// Empty lists stay non-nil so that they are managed.
+		spec.ShieldZones = append(spec.ShieldZones, ShieldZoneSpec{
+			Name:        z.Name,
+			CustomRules: append(make([]CustomRuleSpec, 0, len(z.CustomRules)), z.CustomRules...),
+			RateLimits:  append(make([]RateLimitSpec, 0, len(z.RateLimits)), z.RateLimits...),
+		})
+	}
+	return spec
+}
+
+// SyncOption configures a SyncWAFPolicy call.
+type SyncOption func(*syncOptions)
+
+type syncOptions struct {
+	prune *bool
+}
+
+// WithSyncPrune deletes the custom rules and rate limits of policy zones
+// that are missing from the policy, or keeps them when prune is false,
+// regardless of the Reconciler's WithPrune setting.
+func WithSyncPrune(prune bool) SyncOption {
+	return func(o *syncOptions) {
+		o.prune = &prune
+	}
+}
+
+// SyncWAFPolicy plans the changes that bring the zones in policy to it and,
+// unless dryRun is set, applies them. The plan is returned either way, so a
+// dry run can be printed for review. Rules missing from the policy are
+// deleted as set by WithSyncPrune, or else the Reconciler's WithPrune. The
+// zones must already exist; SyncWAFPolicy fails without changing anything
+// when one does not.
+func (r *Reconciler) SyncWAFPolicy(ctx context.Context, policy *WAFPolicy, dryRun bool, opts ...SyncOption) (*Plan, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	var o syncOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	rec := r
+	if o.prune != nil {
+		copied := *r
+		copied.prune = *o.prune
+		rec = &copied
+	}
+	plan, err := rec.Plan(ctx, policy.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, a := range plan.Actions {
+		if a.Type == ActionCreate && a.Kind == KindShieldZone {
+			missing = append(missing, a.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("reconcile: WAF policy: shield zones not found: %s", strings.Join(missing, ", "))
+	}
+	if dryRun {
+		return plan, nil
+	}
+	return plan, rec.Apply(ctx, plan)
+}
@@ -0,0 +1,167 @@
+package reconcile_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/middleware"
+	"github.com/geraldo/bunny-sdk-go/reconcile"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+const wafPolicy = `
+zones:
+  - name: web
+    customRules:
+      - name: block-admin
+        pattern: REQUEST_URI contains "/admin"
+        action: Block
+        active: true
+      - name: tor
+        pattern: COUNTRY eq "T1"
+        action: Challenge
+      - name: scanners
+        pattern: REQUEST_HEADERS:User-Agent contains "sqlmap"
+        action: Block
+    rateLimits:
+      - name: api
+        path: /api/*
+        requestsPerMinute: 600
+        action: Block
+`
+
+func TestSyncWAFPolicy(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	var calls []string
+	record := func(next middleware.RoundTripFunc) middleware.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				calls = append(calls, req.Method+" "+req.URL.Path)
+			}
+			return next(req)
+		}
+	}
+	client := shield.NewClient("key", shield.WithBaseURL(srv.URL), shield.WithMiddleware(record))
+	zone, err := client.Zones().Create(ctx, &shield.CreateZoneRequest{Name: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range []*shield.CreateCustomRuleRequest{
+		{Name: "block-admin", Pattern: `REQUEST_URI eq "/admin"`, Action: "Block", IsActive: true},
+		{Name: "scanners", Description: "added by hand", Pattern: `REQUEST_HEADERS:User-Agent contains "sqlmap"`, Action: "Block", IsActive: true},
+		{Name: "legacy", Pattern: `REQUEST_URI eq "/old"`, Action: "Log"},
+	} {
+		req.ShieldZoneID = zone.ID
+		if _, err := client.WAF().CreateCustomRule(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.RateLimits().Create(ctx, &shield.CreateRateLimitRequest{Name: "api", Path: "/api/*", RequestsPerMinute: 100, Action: "Block", ShieldZoneID: zone.ID, IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := reconcile.ParseWAFPolicy([]byte(wafPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := reconcile.NewReconciler(reconcile.WithShield(client))
+
+	calls = nil
+	plan, err := rec.SyncWAFPolicy(ctx, policy, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("dry run made changes: %v", calls)
+	}
+	if c, u, d := plan.Counts(); c != 1 || u != 3 || d != 0 {
+		t.Errorf("plan counts = %d/%d/%d, want 1/3/0:\n%s", c, u, d, plan)
+	}
+
+	if _, err := rec.SyncWAFPolicy(ctx, policy, false); err != nil {
+		t.Fatal(err)
+	}
+	// The pattern change is patched; clearing the description needs a replace.
+	for _, want := range []string{"PATCH /shield/waf/custom-rule/", "PUT /shield/waf/custom-rule/", "POST /shield/waf/custom-rule", "PATCH /shield/rate-limit/"} {
+		if !slices.ContainsFunc(calls, func(c string) bool { return strings.HasPrefix(c, want) }) {
+			t.Errorf("no %s call in %v", want, calls)
+		}
+	}
+
+	pruning := reconcile.NewReconciler(reconcile.WithShield(client), reconcile.WithPrune(true))
+	plan, err = pruning.SyncWAFPolicy(ctx, policy, true, reconcile.WithSyncPrune(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("WithSyncPrune(false) should override WithPrune:\n%s", plan)
+	}
+	plan, err = rec.SyncWAFPolicy(ctx, policy, false, reconcile.WithSyncPrune(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, u, d := plan.Counts(); c != 0 || u != 0 || d != 1 || plan.Actions[0].Name != "web/legacy" {
+		t.Errorf("prune plan:\n%s", plan)
+	}
+	rules, err := client.WAF().ListCustomRules(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, r := range rules.Items {
+		names = append(names, r.Name)
+		if !r.IsActive {
+			t.Errorf("rule %s is inactive; rules default to active", r.Name)
+		}
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"block-admin", "scanners", "tor"}) {
+		t.Errorf("rules = %v", names)
+	}
+}
+
+func TestSyncWAFPolicyRequiresZones(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	var calls []string
+	record := func(next middleware.RoundTripFunc) middleware.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				calls = append(calls, req.Method+" "+req.URL.Path)
+			}
+			return next(req)
+		}
+	}
+	client := shield.NewClient("key", shield.WithBaseURL(srv.URL), shield.WithMiddleware(record))
+	policy, err := reconcile.ParseWAFPolicy([]byte(wafPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := reconcile.NewReconciler(reconcile.WithShield(client))
+	if _, err := rec.SyncWAFPolicy(ctx, policy, false); err == nil || !strings.Contains(err.Error(), "not found: web") {
+		t.Errorf("expected the missing zone to be reported, got %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("sync with a missing zone made changes: %v", calls)
+	}
+}
+
+func TestParseWAFPolicyRejectsInvalidRules(t *testing.T) {
+	_, err := reconcile.ParseWAFPolicy([]byte(`{"zones":[{"name":"web","customRules":[{"name":"a","shieldZoneId":"1"}]}]}`))
+	if err == nil {
+		t.Error("expected error for a rule naming its zone")
+	}
+	_, err = reconcile.ParseWAFPolicy([]byte(`{"zones":[{"name":"web","rateLimits":[{"name":"a"},{"name":"a"}]}]}`))
+	if err == nil {
+		t.Error("expected error for duplicate names")
+	}
+}
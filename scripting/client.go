@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 
 	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/internal/pipeline"
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 const (
@@ -27,6 +30,7 @@ type Client struct {
 	httpClient HTTPClient
 	userAgent  string
 	baseURL    string
+	pipeline   pipeline.Config
 }
 
 // Option is a functional option for configuring the Client.
@@ -46,6 +50,48 @@ func WithUserAgent(ua string) Option {
 	}
 }
 
+// WithMiddleware adds middlewares around the HTTP client. The first one
+// added is the outermost.
+func WithMiddleware(mws ...middleware.Middleware) Option {
+	return func(c *Client) {
+		c.pipeline.Middlewares = append(c.pipeline.Middlewares, mws...)
+	}
+}
+
+// WithLogger logs every request to l. The logger runs after all other
+// middlewares, so each retry attempt is logged separately. Credentials are
+// always redacted.
+func WithLogger(l *slog.Logger, opts ...middleware.LogOption) Option {
+	return func(c *Client) {
+		c.pipeline.Logger = middleware.Log(l, opts...)
+	}
+}
+
+// WithTracer starts a span for every edge script request, such as
+// "scripting.ReleaseService.Publish".
+func WithTracer(t middleware.Tracer) Option {
+	return func(c *Client) {
+		c.pipeline.Tracer = middleware.Trace(t)
+	}
+}
+
+// WithMetrics records request counts, latencies, bytes transferred and
+// retries for every request.
+func WithMetrics(m middleware.Metrics) Option {
+	return func(c *Client) {
+		c.pipeline.Metrics = middleware.Measure(m)
+	}
+}
+
+// WithRateLimiter throttles requests with l and retries 429 responses after
+// the Retry-After period. Share one limiter between clients that draw from
+// the same quota.
+func WithRateLimiter(l *middleware.RateLimiter) Option {
+	return func(c *Client) {
+		c.pipeline.RateLimiter = l.Middleware()
+	}
+}
+
 // WithBaseURL sets a custom base URL for the API.
 func WithBaseURL(url string) Option {
 	return func(c *Client) {
@@ -65,6 +111,7 @@ func NewClient(apiKey string, opts ...Option) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.httpClient = c.pipeline.Wrap(c.httpClient)
 	return c
 }
 
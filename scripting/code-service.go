@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // CodeService provides methods for managing edge script code.
@@ -23,6 +25,7 @@ func newCodeService(client httpClient, scriptID int64) CodeService {
 
 // Get returns the code for the edge script.
 func (s *codeService) Get(ctx context.Context) (*EdgeScriptCode, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.CodeService.Get")
 	path := fmt.Sprintf("/compute/script/%d/code", s.scriptID)
 	var code EdgeScriptCode
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &code); err != nil {
@@ -33,6 +36,7 @@ func (s *codeService) Get(ctx context.Context) (*EdgeScriptCode, error) {
 
 // Set sets the code for the edge script.
 func (s *codeService) Set(ctx context.Context, req *UpdateCodeRequest) error {
+	ctx = middleware.WithOperation(ctx, "scripting.CodeService.Set")
 	path := fmt.Sprintf("/compute/script/%d/code", s.scriptID)
 	return s.client.do(ctx, http.MethodPost, path, req, nil)
 }
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // ReleaseService provides methods for managing edge script releases.
@@ -27,6 +29,7 @@ func newReleaseService(client httpClient, scriptID int64) ReleaseService {
 
 // List returns all releases for the edge script.
 func (s *releaseService) List(ctx context.Context, opts *ReleaseListOptions) (*ReleaseListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.ReleaseService.List")
 	path := fmt.Sprintf("/compute/script/%d/releases", s.scriptID)
 	if opts != nil {
 		q := url.Values{}
@@ -50,6 +53,7 @@ func (s *releaseService) List(ctx context.Context, opts *ReleaseListOptions) (*R
 
 // GetActive returns the active release for the edge script.
 func (s *releaseService) GetActive(ctx context.Context) (*EdgeScriptRelease, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.ReleaseService.GetActive")
 	path := fmt.Sprintf("/compute/script/%d/releases/active", s.scriptID)
 	var release EdgeScriptRelease
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &release); err != nil {
@@ -60,12 +64,14 @@ func (s *releaseService) GetActive(ctx context.Context) (*EdgeScriptRelease, err
 
 // Publish publishes a new release for the edge script.
 func (s *releaseService) Publish(ctx context.Context, req *PublishReleaseRequest) error {
+	ctx = middleware.WithOperation(ctx, "scripting.ReleaseService.Publish")
 	path := fmt.Sprintf("/compute/script/%d/publish", s.scriptID)
 	return s.client.do(ctx, http.MethodPost, path, req, nil)
 }
 
 // PublishByUUID publishes a specific release by UUID.
 func (s *releaseService) PublishByUUID(ctx context.Context, uuid string, req *PublishReleaseRequest) error {
+	ctx = middleware.WithOperation(ctx, "scripting.ReleaseService.PublishByUUID")
 	path := fmt.Sprintf("/compute/script/%d/publish/%s", s.scriptID, uuid)
 	return s.client.do(ctx, http.MethodPost, path, req, nil)
 }
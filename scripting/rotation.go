@@ -0,0 +1,57 @@
+package scripting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/geraldo/bunny-sdk-go/credential"
+)
+
+// KindDeploymentKey identifies edge script deployment keys in rotation hooks.
+const KindDeploymentKey = "scripting.script.deploymentKey"
+
+// KeyRotator rotates edge script deployment keys and hands the new key to
+// watched secrets and hooks. Deployment tooling that sends the key with
+// credential.Header keeps working through a rotation.
+type KeyRotator struct {
+	scripts ScriptService
+	rotator credential.Rotator
+}
+
+// NewKeyRotator returns a KeyRotator that rotates keys through scripts and
+// calls hooks after every rotation, for example to update a secret store.
+func NewKeyRotator(scripts ScriptService, hooks ...credential.Hook) *KeyRotator {
+	r := &KeyRotator{scripts: scripts}
+	for _, h := range hooks {
+		r.rotator.OnRotate(h)
+	}
+	return r
+}
+
+// OnRotate adds a hook called after every rotation.
+func (r *KeyRotator) OnRotate(h credential.Hook) {
+	r.rotator.OnRotate(h)
+}
+
+// Watch keeps s in sync with the deployment key of an edge script.
+func (r *KeyRotator) Watch(scriptID int64, s *credential.Secret) {
+	r.rotator.Watch(KindDeploymentKey, scriptID, s)
+}
+
+// RotateDeploymentKey rotates the deployment key of an edge script and
+// returns the new one.
+func (r *KeyRotator) RotateDeploymentKey(ctx context.Context, scriptID int64) (string, error) {
+	return r.rotator.Rotate(ctx, KindDeploymentKey, scriptID, func(ctx context.Context) (string, error) {
+		if err := r.scripts.RotateDeploymentKey(ctx, scriptID); err != nil {
+			return "", err
+		}
+		script, err := r.scripts.Get(ctx, scriptID)
+		if err != nil {
+			return "", fmt.Errorf("fetch new deployment key: %w", err)
+		}
+		if script.DeploymentKey == nil {
+			return "", fmt.Errorf("edge script %d has no deployment key", scriptID)
+		}
+		return *script.DeploymentKey, nil
+	})
+}
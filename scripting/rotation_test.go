@@ -0,0 +1,46 @@
+package scripting_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/credential"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+)
+
+func TestKeyRotator(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	scripts := scripting.NewClient("key", scripting.WithBaseURL(srv.URL)).Scripts()
+	script, err := scripts.Create(ctx, &scripting.CreateScriptRequest{Name: "edge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := credential.New(*script.DeploymentKey)
+
+	var rotations []credential.Rotation
+	rotator := scripting.NewKeyRotator(scripts)
+	rotator.OnRotate(func(_ context.Context, r credential.Rotation) error {
+		rotations = append(rotations, r)
+		return nil
+	})
+	rotator.Watch(script.ID, key)
+
+	newKey, err := rotator.RotateDeploymentKey(ctx, script.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newKey == *script.DeploymentKey || key.Get() != newKey {
+		t.Errorf("key = %q, secret = %q, old = %q", newKey, key.Get(), *script.DeploymentKey)
+	}
+	if len(rotations) != 1 || rotations[0].Kind != scripting.KindDeploymentKey || rotations[0].ID != script.ID {
+		t.Errorf("rotations = %+v", rotations)
+	}
+	got, err := scripts.Get(ctx, script.ID)
+	if err != nil || *got.DeploymentKey != newKey {
+		t.Errorf("Get = %v, %v", got, err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // ScriptService provides methods for managing edge scripts.
@@ -29,6 +31,7 @@ func newScriptService(client httpClient) ScriptService {
 
 // List returns all edge scripts.
 func (s *scriptService) List(ctx context.Context, opts *ScriptListOptions) (*ScriptListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.ScriptService.List")
 	path := "/compute/script"
 	if opts != nil {
 		q := url.Values{}
@@ -61,6 +64,7 @@ func (s *scriptService) List(ctx context.Context, opts *ScriptListOptions) (*Scr
 
 // Create creates a new edge script.
 func (s *scriptService) Create(ctx context.Context, req *CreateScriptRequest) (*EdgeScript, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.ScriptService.Create")
 	var script EdgeScript
 	if err := s.client.do(ctx, http.MethodPost, "/compute/script", req, &script); err != nil {
 		return nil, err
@@ -70,6 +74,7 @@ func (s *scriptService) Create(ctx context.Context, req *CreateScriptRequest) (*
 
 // Get returns a specific edge script by ID.
 func (s *scriptService) Get(ctx context.Context, id int64) (*EdgeScript, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.ScriptService.Get")
 	path := fmt.Sprintf("/compute/script/%d", id)
 	var script EdgeScript
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &script); err != nil {
@@ -80,6 +85,7 @@ func (s *scriptService) Get(ctx context.Context, id int64) (*EdgeScript, error)
 
 // Update updates an edge script.
 func (s *scriptService) Update(ctx context.Context, id int64, req *UpdateScriptRequest) (*EdgeScript, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.ScriptService.Update")
 	path := fmt.Sprintf("/compute/script/%d", id)
 	var script EdgeScript
 	if err := s.client.do(ctx, http.MethodPost, path, req, &script); err != nil {
@@ -90,6 +96,7 @@ func (s *scriptService) Update(ctx context.Context, id int64, req *UpdateScriptR
 
 // Delete deletes an edge script.
 func (s *scriptService) Delete(ctx context.Context, id int64, deleteLinkedPullZones bool) error {
+	ctx = middleware.WithOperation(ctx, "scripting.ScriptService.Delete")
 	path := fmt.Sprintf("/compute/script/%d", id)
 	if deleteLinkedPullZones {
 		path += "?deleteLinkedPullZones=true"
@@ -99,6 +106,7 @@ func (s *scriptService) Delete(ctx context.Context, id int64, deleteLinkedPullZo
 
 // GetStatistics returns statistics for an edge script.
 func (s *scriptService) GetStatistics(ctx context.Context, id int64, opts *StatisticsOptions) (*ScriptStatistics, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.ScriptService.GetStatistics")
 	path := fmt.Sprintf("/compute/script/%d/statistics", id)
 	if opts != nil {
 		q := url.Values{}
@@ -128,6 +136,7 @@ func (s *scriptService) GetStatistics(ctx context.Context, id int64, opts *Stati
 
 // RotateDeploymentKey rotates the deployment key for an edge script.
 func (s *scriptService) RotateDeploymentKey(ctx context.Context, id int64) error {
+	ctx = middleware.WithOperation(ctx, "scripting.ScriptService.RotateDeploymentKey")
 	path := fmt.Sprintf("/compute/script/%d/deploymentKey/rotate", id)
 	return s.client.do(ctx, http.MethodPost, path, nil, nil)
 }
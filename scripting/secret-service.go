@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // SecretService provides methods for managing edge script secrets.
@@ -26,6 +28,7 @@ func newSecretService(client httpClient, scriptID int64) SecretService {
 
 // List returns all secrets for the edge script.
 func (s *secretService) List(ctx context.Context) (*SecretListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.SecretService.List")
 	path := fmt.Sprintf("/compute/script/%d/secrets", s.scriptID)
 	var resp SecretListResponse
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -36,6 +39,7 @@ func (s *secretService) List(ctx context.Context) (*SecretListResponse, error) {
 
 // Add creates a new secret for the edge script.
 func (s *secretService) Add(ctx context.Context, req *AddSecretRequest) (*EdgeScriptSecret, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.SecretService.Add")
 	path := fmt.Sprintf("/compute/script/%d/secrets", s.scriptID)
 	var secret EdgeScriptSecret
 	if err := s.client.do(ctx, http.MethodPost, path, req, &secret); err != nil {
@@ -46,6 +50,7 @@ func (s *secretService) Add(ctx context.Context, req *AddSecretRequest) (*EdgeSc
 
 // Update updates an existing secret.
 func (s *secretService) Update(ctx context.Context, secretID int64, req *UpdateSecretRequest) (*EdgeScriptSecret, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.SecretService.Update")
 	path := fmt.Sprintf("/compute/script/%d/secrets/%d", s.scriptID, secretID)
 	var secret EdgeScriptSecret
 	if err := s.client.do(ctx, http.MethodPost, path, req, &secret); err != nil {
@@ -56,6 +61,7 @@ func (s *secretService) Update(ctx context.Context, secretID int64, req *UpdateS
 
 // Upsert creates or updates a secret by name.
 func (s *secretService) Upsert(ctx context.Context, req *UpsertSecretRequest) (*EdgeScriptSecret, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.SecretService.Upsert")
 	path := fmt.Sprintf("/compute/script/%d/secrets", s.scriptID)
 	var secret EdgeScriptSecret
 	// Upsert returns 200 for new, 204 for update - try to decode response
@@ -67,6 +73,7 @@ func (s *secretService) Upsert(ctx context.Context, req *UpsertSecretRequest) (*
 
 // Delete deletes a secret.
 func (s *secretService) Delete(ctx context.Context, secretID int64) error {
+	ctx = middleware.WithOperation(ctx, "scripting.SecretService.Delete")
 	path := fmt.Sprintf("/compute/script/%d/secrets/%d", s.scriptID, secretID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // VariableService provides methods for managing edge script variables.
@@ -26,6 +28,7 @@ func newVariableService(client httpClient, scriptID int64) VariableService {
 
 // Add creates a new variable for the edge script.
 func (s *variableService) Add(ctx context.Context, req *AddVariableRequest) (*EdgeScriptVariable, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.VariableService.Add")
 	path := fmt.Sprintf("/compute/script/%d/variables/add", s.scriptID)
 	var variable EdgeScriptVariable
 	if err := s.client.do(ctx, http.MethodPost, path, req, &variable); err != nil {
@@ -36,6 +39,7 @@ func (s *variableService) Add(ctx context.Context, req *AddVariableRequest) (*Ed
 
 // Get returns a specific variable by ID.
 func (s *variableService) Get(ctx context.Context, variableID int64) (*EdgeScriptVariable, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.VariableService.Get")
 	path := fmt.Sprintf("/compute/script/%d/variables/%d", s.scriptID, variableID)
 	var variable EdgeScriptVariable
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &variable); err != nil {
@@ -46,6 +50,7 @@ func (s *variableService) Get(ctx context.Context, variableID int64) (*EdgeScrip
 
 // Update updates an existing variable.
 func (s *variableService) Update(ctx context.Context, variableID int64, req *UpdateVariableRequest) (*EdgeScriptVariable, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.VariableService.Update")
 	path := fmt.Sprintf("/compute/script/%d/variables/%d", s.scriptID, variableID)
 	var variable EdgeScriptVariable
 	if err := s.client.do(ctx, http.MethodPost, path, req, &variable); err != nil {
@@ -56,6 +61,7 @@ func (s *variableService) Update(ctx context.Context, variableID int64, req *Upd
 
 // Upsert creates or updates a variable by name.
 func (s *variableService) Upsert(ctx context.Context, req *UpsertVariableRequest) (*EdgeScriptVariable, error) {
+	ctx = middleware.WithOperation(ctx, "scripting.VariableService.Upsert")
 	path := fmt.Sprintf("/compute/script/%d/variables", s.scriptID)
 	var variable EdgeScriptVariable
 	// Upsert returns 200 for new, 204 for update - try to decode response
@@ -67,6 +73,7 @@ func (s *variableService) Upsert(ctx context.Context, req *UpsertVariableRequest
 
 // Delete deletes a variable.
 func (s *variableService) Delete(ctx context.Context, variableID int64) error {
+	ctx = middleware.WithOperation(ctx, "scripting.VariableService.Delete")
 	path := fmt.Sprintf("/compute/script/%d/variables/%d", s.scriptID, variableID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
@@ -0,0 +1,201 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// expiryTag marks the expiry time inside an access list entry's comment.
+const expiryTag = "[expires "
+
+// SetExpiry returns comment with its expiry tag set to t, replacing any
+// existing tag:
+//
+//	SetExpiry("scanner", t) == "scanner [expires 2026-10-19T12:00:00Z]"
+func SetExpiry(comment string, t time.Time) string {
+	comment = strings.TrimSpace(stripExpiry(comment))
+	tag := expiryTag + t.UTC().Truncate(time.Second).Format(time.RFC3339) + "]"
+	if comment == "" {
+		return tag
+	}
+	return comment + " " + tag
+}
+
+// EntryExpiry returns the expiry time recorded in an entry's comment by
+// SetExpiry. It reports false for entries that never expire.
+func EntryExpiry(comment string) (time.Time, bool) {
+	i := strings.LastIndex(comment, expiryTag)
+	if i < 0 {
+		return time.Time{}, false
+	}
+	rest := comment[i+len(expiryTag):]
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, rest[:end])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// stripExpiry removes the expiry tag from comment.
+func stripExpiry(comment string) string {
+	i := strings.LastIndex(comment, expiryTag)
+	if i < 0 {
+		return comment
+	}
+	end := strings.IndexByte(comment[i:], ']')
+	if end < 0 {
+		return comment
+	}
+	return comment[:i] + comment[i+end+1:]
+}
+
+// ExpiringAccessList adds access list entries that are removed again once
+// their time is up, such as a 24 hour block during an incident. The expiry
+// is stored in the entry's comment, so it survives restarts and is visible
+// in the dashboard; Reap removes the entries that have expired.
+//
+//	x := shield.NewExpiringAccessList(client.AccessLists(zoneID))
+//	_, err := x.Add(ctx, &shield.AddAccessListEntryRequest{
+//		Type: shield.AccessListTypeIP, Value: "203.0.113.7", Action: shield.AccessListActionBlock,
+//	}, 24*time.Hour)
+//
+//	// in a cron job or ticker, on any number of workers:
+//	removed, err := x.Reap(ctx)
+type ExpiringAccessList struct {
+	lists AccessListService
+	now   func() time.Time
+}
+
+// ExpiryOption configures an ExpiringAccessList.
+type ExpiryOption func(*ExpiringAccessList)
+
+// WithExpiryClock sets the function used as the current time.
+func WithExpiryClock(now func() time.Time) ExpiryOption {
+	return func(x *ExpiringAccessList) {
+		x.now = now
+	}
+}
+
+// NewExpiringAccessList wraps a zone's access list.
+func NewExpiringAccessList(lists AccessListService, opts ...ExpiryOption) *ExpiringAccessList {
+	x := &ExpiringAccessList{lists: lists, now: time.Now}
+	for _, opt := range opts {
+		opt(x)
+	}
+	return x
+}
+
+// Add adds an entry that expires after ttl.
+func (x *ExpiringAccessList) Add(ctx context.Context, req *AddAccessListEntryRequest, ttl time.Duration) (*AccessListEntry, error) {
+	return x.AddUntil(ctx, req, x.now().Add(ttl))
+}
+
+// AddUntil adds an entry that expires at t.
+func (x *ExpiringAccessList) AddUntil(ctx context.Context, req *AddAccessListEntryRequest, t time.Time) (*AccessListEntry, error) {
+	r := *req
+	r.Comment = SetExpiry(r.Comment, t)
+	return x.lists.Add(ctx, &r)
+}
+
+// Extend moves the expiry of an existing entry to t, keeping the rest of its
+// comment. Extending an entry that never expired makes it expire.
+func (x *ExpiringAccessList) Extend(ctx context.Context, typ, value string, t time.Time) error {
+	entry, err := x.find(ctx, typ, value)
+	if err != nil {
+		return err
+	}
+	return x.lists.Update(ctx, &UpdateAccessListEntriesRequest{
+		Updates: []AccessListEntryUpdate{{Type: entry.Type, Value: entry.Value, Comment: SetExpiry(entry.Comment, t)}},
+	})
+}
+
+// Expired returns the entries whose expiry has passed.
+func (x *ExpiringAccessList) Expired(ctx context.Context) ([]AccessListEntry, error) {
+	list, err := x.lists.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := x.now()
+	var expired []AccessListEntry
+	for _, e := range allAccessEntries(list) {
+		if t, ok := EntryExpiry(e.Comment); ok && !t.After(now) {
+			expired = append(expired, e)
+		}
+	}
+	return expired, nil
+}
+
+// Reap removes expired entries and returns them.
+//
+// Several workers may reap at once: an entry another worker already
+// removed counts as removed rather than failing the run. The API has no
+// conditional delete, so an entry extended after Reap read the list but
+// before it deleted the entry is still removed; extend entries before they
+// expire rather than racing the reaper for expired ones.
+func (x *ExpiringAccessList) Reap(ctx context.Context) ([]AccessListEntry, error) {
+	reap, err := x.Expired(ctx)
+	if err != nil || len(reap) == 0 {
+		return nil, err
+	}
+
+	req := &DeleteAccessListEntriesRequest{Entries: make([]AccessListEntryIdentifier, len(reap))}
+	for i, e := range reap {
+		req.Entries[i] = AccessListEntryIdentifier{Type: e.Type, Value: e.Value}
+	}
+	err = x.lists.Delete(ctx, req)
+	if err == nil {
+		return reap, nil
+	}
+	if !isNotFound(err) {
+		return nil, fmt.Errorf("shield: reaping access list entries: %w", err)
+	}
+
+	// Another worker removed some of the batch; delete the rest one by one.
+	var removed []AccessListEntry
+	var errs []error
+	for i, id := range req.Entries {
+		err := x.lists.Delete(ctx, &DeleteAccessListEntriesRequest{Entries: []AccessListEntryIdentifier{id}})
+		if err != nil && !isNotFound(err) {
+			errs = append(errs, fmt.Errorf("shield: reaping access list entry %s: %w", id.Value, err))
+			continue
+		}
+		removed = append(removed, reap[i])
+	}
+	return removed, errors.Join(errs...)
+}
+
+// find returns the live entry with the given type and value.
+func (x *ExpiringAccessList) find(ctx context.Context, typ, value string) (*AccessListEntry, error) {
+	list, err := x.lists.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := accessListKey(AccessListEntry{Type: typ, Value: value})
+	for _, e := range allAccessEntries(list) {
+		if accessListKey(e) == key {
+			return &e, nil
+		}
+	}
+	return nil, newAPIError(http.StatusNotFound, fmt.Sprintf("access list entry %s not found", value), "", "")
+}
+
+// allAccessEntries flattens the allowed, blocked and challenged lists.
+func allAccessEntries(list *AccessList) []AccessListEntry {
+	all := make([]AccessListEntry, 0, len(list.Allowed)+len(list.Blocked)+len(list.Challenged))
+	all = append(all, list.Allowed...)
+	all = append(all, list.Blocked...)
+	return append(all, list.Challenged...)
+}
+
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,145 @@
+package shield_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func TestEntryExpiry(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	comment := shield.SetExpiry("scanner", at)
+	if comment != "scanner [expires 2026-10-19T12:00:00Z]" {
+		t.Errorf("unexpected comment %q", comment)
+	}
+	later := at.Add(time.Hour)
+	if got, ok := shield.EntryExpiry(shield.SetExpiry(comment, later)); !ok || !got.Equal(later) {
+		t.Errorf("expected replaced expiry %v, got %v %v", later, got, ok)
+	}
+	if _, ok := shield.EntryExpiry("scanner"); ok {
+		t.Error("expected no expiry without a tag")
+	}
+}
+
+func TestExpiringAccessListReap(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	client := shield.NewClient("key", shield.WithBaseURL(srv.URL))
+	zone, err := client.Zones().Create(ctx, &shield.CreateZoneRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lists := client.AccessLists(zone.ID)
+
+	var mu sync.Mutex
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	x := shield.NewExpiringAccessList(lists, shield.WithExpiryClock(clock))
+
+	block := func(value string) *shield.AddAccessListEntryRequest {
+		return &shield.AddAccessListEntryRequest{Type: shield.AccessListTypeIP, Value: value, Action: shield.AccessListActionBlock, Comment: "incident 42"}
+	}
+	for _, value := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		if _, err := x.Add(ctx, block(value), 24*time.Hour); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := x.Add(ctx, block("203.0.113.4"), 48*time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := lists.Add(ctx, block("203.0.113.5")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if removed, err := x.Reap(ctx); err != nil || len(removed) != 0 {
+		t.Fatalf("expected nothing to reap yet, got %v, %v", removed, err)
+	}
+
+	mu.Lock()
+	now = now.Add(25 * time.Hour)
+	mu.Unlock()
+	if err := x.Extend(ctx, shield.AccessListTypeIP, "203.0.113.3", now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := x.Reap(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("unexpected reap error: %v", err)
+	}
+
+	list, err := lists.Get(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var left []string
+	for _, e := range list.Blocked {
+		left = append(left, e.Value)
+	}
+	want := []string{"203.0.113.3", "203.0.113.4", "203.0.113.5"}
+	if len(left) != len(want) {
+		t.Fatalf("expected %v to remain, got %v", want, left)
+	}
+	for i := range want {
+		if left[i] != want[i] {
+			t.Errorf("expected %v to remain, got %v", want, left)
+		}
+	}
+	if list.Blocked[0].Comment != shield.SetExpiry("incident 42", now.Add(time.Hour)) {
+		t.Errorf("expected extended expiry, got %q", list.Blocked[0].Comment)
+	}
+}
+
+func TestExpiringAccessListReapAlreadyRemoved(t *testing.T) {
+	expired := shield.SetExpiry("", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	var gets, deletes int
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodDelete {
+				deletes++
+				return testutil.NewMockResponse(404, `{"Message":"entry not found"}`), nil
+			}
+			gets++
+			return testutil.NewMockResponse(200, `{"Blocked":[
+				{"Type":"IP","Value":"203.0.113.1","Comment":"`+expired+`"},
+				{"Type":"IP","Value":"203.0.113.2","Comment":"`+expired+`"}]}`), nil
+		},
+	}
+	x := shield.NewExpiringAccessList(shield.NewClient("key", shield.WithHTTPClient(mock)).AccessLists("zone-1"))
+
+	removed, err := x.Reap(context.Background())
+	if err != nil {
+		t.Fatalf("expected entries removed elsewhere to count as reaped, got %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("expected 2 removed entries, got %+v", removed)
+	}
+	if gets != 1 {
+		t.Errorf("expected the list to be read once, got %d reads", gets)
+	}
+	if deletes != 3 {
+		t.Errorf("expected a batch delete and two single deletes, got %d", deletes)
+	}
+}
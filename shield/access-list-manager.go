@@ -0,0 +1,254 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrAccessListFull is returned when applying changes would take a zone past
+// the MaxEntries limit reported by AccessListService.GetEnums.
+var ErrAccessListFull = errors.New("shield: access list entry limit exceeded")
+
+const defaultAccessListBatchSize = 100
+
+// AccessListManager syncs a zone's access list with a large set of entries,
+// such as blocklists built from threat-intel feeds:
+//
+//	f, err := os.Open("drop.txt")
+//	entries, err := shield.ParseAccessList(f, shield.AccessListActionBlock)
+//	m := shield.NewAccessListManager(client.AccessLists(zoneID), shield.WithAccessListPrune(true))
+//	changes, err := m.Sync(ctx, entries)
+//	fmt.Print(changes)
+type AccessListManager struct {
+	lists     AccessListService
+	batchSize int
+	prune     bool
+}
+
+// AccessListManagerOption configures an AccessListManager.
+type AccessListManagerOption func(*AccessListManager)
+
+// WithAccessListBatchSize sets how many entries each Update and Delete call
+// carries. The default is 100; a smaller MaxEntriesPerRequest from GetEnums
+// takes precedence.
+func WithAccessListBatchSize(n int) AccessListManagerOption {
+	return func(m *AccessListManager) {
+		m.batchSize = n
+	}
+}
+
+// WithAccessListPrune makes the manager delete live entries missing from the
+// desired set. Only entries whose action appears in the desired set are
+// pruned, so syncing a blocklist never touches the allowlist.
+func WithAccessListPrune(prune bool) AccessListManagerOption {
+	return func(m *AccessListManager) {
+		m.prune = prune
+	}
+}
+
+// NewAccessListManager creates a manager for one zone's access list.
+func NewAccessListManager(lists AccessListService, opts ...AccessListManagerOption) *AccessListManager {
+	m := &AccessListManager{lists: lists, batchSize: defaultAccessListBatchSize}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// AccessListChanges lists the entries to add, update and delete. Returned by
+// Plan it describes pending work; returned by Apply or Sync it reports the
+// changes that were made.
+type AccessListChanges struct {
+	Add    []AccessListEntry
+	Update []AccessListEntry
+	Delete []AccessListEntry
+	// Unchanged counts desired entries that were already live as requested.
+	Unchanged int
+
+	batchSize int
+}
+
+// Empty reports whether there is nothing to change.
+func (c *AccessListChanges) Empty() bool {
+	return len(c.Add) == 0 && len(c.Update) == 0 && len(c.Delete) == 0
+}
+
+// String renders one line per change followed by a summary.
+func (c *AccessListChanges) String() string {
+	var b strings.Builder
+	for _, e := range c.Add {
+		fmt.Fprintf(&b, "+ %s %s (%s)\n", e.Type, e.Value, e.Action)
+	}
+	for _, e := range c.Update {
+		fmt.Fprintf(&b, "~ %s %s (%s)\n", e.Type, e.Value, e.Action)
+	}
+	for _, e := range c.Delete {
+		fmt.Fprintf(&b, "- %s %s (%s)\n", e.Type, e.Value, e.Action)
+	}
+	fmt.Fprintf(&b, "Access list: %d to add, %d to update, %d to delete, %d unchanged.\n",
+		len(c.Add), len(c.Update), len(c.Delete), c.Unchanged)
+	return b.String()
+}
+
+// Plan normalizes entries with NormalizeAccessList and diffs them against the
+// live access list without changing anything. It fails when an entry's type
+// or action is not among those reported by GetEnums, or when the result
+// would exceed the zone's entry limit.
+func (m *AccessListManager) Plan(ctx context.Context, entries []AccessListEntry) (*AccessListChanges, error) {
+	desired, err := NormalizeAccessList(entries)
+	if err != nil {
+		return nil, err
+	}
+	enums, err := m.lists.GetEnums(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("shield: getting access list enums: %w", err)
+	}
+	if err := validateAccessEntries(desired, enums); err != nil {
+		return nil, err
+	}
+	current, err := m.lists.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("shield: getting access list: %w", err)
+	}
+
+	live := make(map[string]AccessListEntry)
+	var order []string
+	for _, list := range []struct {
+		action  string
+		entries []AccessListEntry
+	}{
+		{AccessListActionAllow, current.Allowed},
+		{AccessListActionBlock, current.Blocked},
+		{AccessListActionChallenge, current.Challenged},
+	} {
+		for _, e := range list.entries {
+			if e.Action == "" {
+				e.Action = list.action
+			}
+			key := accessListKey(e)
+			if _, dup := live[key]; !dup {
+				order = append(order, key)
+			}
+			live[key] = e
+		}
+	}
+	total := len(live)
+
+	changes := &AccessListChanges{batchSize: m.batchSize}
+	if n := enums.MaxEntriesPerRequest; n > 0 && (changes.batchSize <= 0 || n < changes.batchSize) {
+		changes.batchSize = n
+	}
+	actions := make(map[string]bool)
+	for _, e := range desired {
+		actions[strings.ToLower(e.Action)] = true
+		key := accessListKey(e)
+		cur, ok := live[key]
+		delete(live, key)
+		switch {
+		case !ok:
+			changes.Add = append(changes.Add, e)
+		case !strings.EqualFold(cur.Action, e.Action) || e.Comment != "" && e.Comment != cur.Comment:
+			e.Type, e.Value = cur.Type, cur.Value
+			changes.Update = append(changes.Update, e)
+		default:
+			changes.Unchanged++
+		}
+	}
+	if m.prune {
+		for _, key := range order {
+			if e, ok := live[key]; ok && actions[strings.ToLower(e.Action)] {
+				changes.Delete = append(changes.Delete, e)
+			}
+		}
+	}
+
+	if limit := enums.MaxEntries; limit > 0 {
+		if after := total + len(changes.Add) - len(changes.Delete); after > limit {
+			return nil, fmt.Errorf("%w: %d entries after sync, limit is %d", ErrAccessListFull, after, limit)
+		}
+	}
+	return changes, nil
+}
+
+// Apply makes the planned changes: deletes first to free room, then updates,
+// both in batches, then one Add call per new entry. It returns the changes
+// that were applied, which on error is the work done before the failure.
+func (m *AccessListManager) Apply(ctx context.Context, changes *AccessListChanges) (*AccessListChanges, error) {
+	done := &AccessListChanges{Unchanged: changes.Unchanged, batchSize: changes.batchSize}
+	size := changes.batchSize
+	if size <= 0 {
+		size = max(len(changes.Delete), len(changes.Update), 1)
+	}
+
+	for batch := range slices.Chunk(changes.Delete, size) {
+		req := &DeleteAccessListEntriesRequest{Entries: make([]AccessListEntryIdentifier, len(batch))}
+		for i, e := range batch {
+			req.Entries[i] = AccessListEntryIdentifier{Type: e.Type, Value: e.Value}
+		}
+		if err := m.lists.Delete(ctx, req); err != nil {
+			return done, fmt.Errorf("shield: deleting access list entries: %w", err)
+		}
+		done.Delete = append(done.Delete, batch...)
+	}
+
+	for batch := range slices.Chunk(changes.Update, size) {
+		req := &UpdateAccessListEntriesRequest{Updates: make([]AccessListEntryUpdate, len(batch))}
+		for i, e := range batch {
+			req.Updates[i] = AccessListEntryUpdate{Type: e.Type, Value: e.Value, Action: e.Action, Comment: e.Comment}
+		}
+		if err := m.lists.Update(ctx, req); err != nil {
+			return done, fmt.Errorf("shield: updating access list entries: %w", err)
+		}
+		done.Update = append(done.Update, batch...)
+	}
+
+	for _, e := range changes.Add {
+		if err := ctx.Err(); err != nil {
+			return done, err
+		}
+		_, err := m.lists.Add(ctx, &AddAccessListEntryRequest{Type: e.Type, Value: e.Value, Action: e.Action, Comment: e.Comment})
+		if err != nil {
+			return done, fmt.Errorf("shield: adding access list entry %s: %w", e.Value, err)
+		}
+		done.Add = append(done.Add, e)
+	}
+	return done, nil
+}
+
+// Sync plans and applies in one step, returning the changes made.
+func (m *AccessListManager) Sync(ctx context.Context, entries []AccessListEntry) (*AccessListChanges, error) {
+	changes, err := m.Plan(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+	return m.Apply(ctx, changes)
+}
+
+// validateAccessEntries checks types and actions against the API's enums.
+func validateAccessEntries(entries []AccessListEntry, enums *AccessListEnums) error {
+	var errs []error
+	reported := make(map[string]bool)
+	for _, e := range entries {
+		if e.Action == "" {
+			errs = append(errs, fmt.Errorf("shield: access list entry %s has no action", e.Value))
+			continue
+		}
+		for _, check := range []struct {
+			kind, value string
+			allowed     []string
+		}{
+			{"type", e.Type, enums.Types},
+			{"action", e.Action, enums.Actions},
+		} {
+			if len(check.allowed) == 0 || containsFold(check.allowed, check.value) || reported[check.kind+check.value] {
+				continue
+			}
+			reported[check.kind+check.value] = true
+			errs = append(errs, fmt.Errorf("shield: access list %s %q is not supported by the API", check.kind, check.value))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,172 @@
+package shield_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+const threatFeed = `# example feed
+; generated daily
+10.0.0.0/25 ; SBL1
+10.0.0.128/25 ; SBL2
+10.0.0.5
+192.0.2.1
+192.0.2.1/32
+2001:db8::/33
+2001:db8:8000::/33
+::ffff:198.51.100.9
+as13335
+de
+`
+
+func TestParseAndNormalizeAccessList(t *testing.T) {
+	entries, err := shield.ParseAccessList(strings.NewReader(threatFeed), shield.AccessListActionBlock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 10 {
+		t.Fatalf("expected 10 parsed entries, got %d: %+v", len(entries), entries)
+	}
+
+	got, err := shield.NormalizeAccessList(entries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var values []string
+	for _, e := range got {
+		values = append(values, e.Type+" "+e.Value)
+		if e.Action != shield.AccessListActionBlock {
+			t.Errorf("expected Block action, got %+v", e)
+		}
+	}
+	want := []string{
+		"CIDR 10.0.0.0/24",
+		"IP 192.0.2.1",
+		"IP 198.51.100.9",
+		"CIDR 2001:db8::/32",
+		"ASN AS13335",
+		"Country DE",
+	}
+	if !slices.Equal(values, want) {
+		t.Errorf("unexpected entries:\n got %v\nwant %v", values, want)
+	}
+	if got[0].Comment != "" {
+		t.Errorf("expected inline feed comments to be dropped, got %q", got[0].Comment)
+	}
+
+	csvFeed := "value,comment\n203.0.113.7,\"scanner, port 22\"\nAS64500,bulletproof host\n"
+	entries, err = shield.ParseAccessList(strings.NewReader(csvFeed), shield.AccessListActionChallenge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Comment != "scanner, port 22" || entries[1].Value != "AS64500" {
+		t.Errorf("unexpected CSV entries: %+v", entries)
+	}
+
+	entries, err = shield.ParseAccessList(strings.NewReader("ip,comment\n192.0.2.1,office\nfr,\n"), shield.AccessListActionBlock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Value != "192.0.2.1" || entries[1].Type != shield.AccessListTypeCountry {
+		t.Errorf("expected the ip,comment header to be skipped: %+v", entries)
+	}
+
+	_, err = shield.ParseAccessList(strings.NewReader("10.0.0.1\nnot-an-ip\n"), shield.AccessListActionBlock)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error naming line 2, got %v", err)
+	}
+	_, err = shield.ParseAccessList(strings.NewReader("DE\nOK\n"), shield.AccessListActionBlock)
+	if err == nil || !strings.Contains(err.Error(), `"OK"`) {
+		t.Errorf("expected OK to be rejected as a country, got %v", err)
+	}
+}
+
+func TestAccessListManagerSync(t *testing.T) {
+	srv := bunnytest.NewServer(bunnytest.WithAccessListLimits(6, 2))
+	defer srv.Close()
+	ctx := context.Background()
+	client := shield.NewClient("key", shield.WithBaseURL(srv.URL))
+
+	zone, err := client.Zones().Create(ctx, &shield.CreateZoneRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lists := client.AccessLists(zone.ID)
+	for _, req := range []shield.AddAccessListEntryRequest{
+		{Type: "IP", Value: "192.0.2.10", Action: "Allow", Comment: "office"},
+		{Type: "IP", Value: "198.51.100.1", Action: "Block", Comment: "stale"},
+		{Type: "IP", Value: "198.51.100.2", Action: "Block", Comment: "stale"},
+		{Type: "IP", Value: "198.51.100.3", Action: "Block", Comment: "stale"},
+		{Type: "IP", Value: "203.0.113.7", Action: "Challenge"},
+	} {
+		if _, err := lists.Add(ctx, &req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	desired := []shield.AccessListEntry{
+		{Value: "203.0.113.7", Action: "Block", Comment: "scanner"},
+		{Value: "10.1.0.0/24", Action: "Block"},
+		{Value: "10.1.1.0/24", Action: "Block"},
+		{Value: "AS64500", Action: "Block"},
+	}
+	m := shield.NewAccessListManager(lists, shield.WithAccessListPrune(true))
+	plan, err := m.Plan(ctx, desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Add) != 2 || len(plan.Update) != 1 || len(plan.Delete) != 3 {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	if !strings.Contains(plan.String(), "+ CIDR 10.1.0.0/23 (Block)") {
+		t.Errorf("expected adjacent ranges to be merged, got:\n%s", plan)
+	}
+
+	// The server allows two entries per batch, so three deletes take two calls.
+	done, err := m.Apply(ctx, plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(done.Add) != 2 || len(done.Update) != 1 || len(done.Delete) != 3 {
+		t.Errorf("unexpected report:\n%s", done)
+	}
+
+	live, err := lists.Get(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(live.Allowed) != 1 || live.Allowed[0].Value != "192.0.2.10" {
+		t.Errorf("expected allowlist to be left alone, got %+v", live.Allowed)
+	}
+	if len(live.Blocked) != 3 || len(live.Challenged) != 0 {
+		t.Errorf("unexpected access list: %+v", live)
+	}
+
+	plan, err = m.Plan(ctx, desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Empty() || plan.Unchanged != 3 {
+		t.Errorf("expected no changes after sync, got:\n%s", plan)
+	}
+
+	desired = append(desired,
+		shield.AccessListEntry{Value: "DE", Action: "Block"},
+		shield.AccessListEntry{Value: "FR", Action: "Block"},
+		shield.AccessListEntry{Value: "IT", Action: "Block"},
+	)
+	if _, err := m.Sync(ctx, desired); !errors.Is(err, shield.ErrAccessListFull) {
+		t.Errorf("expected ErrAccessListFull, got %v", err)
+	}
+
+	_, err = m.Plan(ctx, []shield.AccessListEntry{{Value: "DE", Action: "Quarantine"}})
+	if err == nil || !strings.Contains(err.Error(), "Quarantine") {
+		t.Errorf("expected unsupported action error, got %v", err)
+	}
+}
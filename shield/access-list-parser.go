@@ -0,0 +1,261 @@
+package shield
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Access list entry types.
+const (
+	AccessListTypeIP      = "IP"
+	AccessListTypeCIDR    = "CIDR"
+	AccessListTypeASN     = "ASN"
+	AccessListTypeCountry = "Country"
+)
+
+// Access list actions, matching the Allowed, Blocked and Challenged lists.
+const (
+	AccessListActionAllow     = "Allow"
+	AccessListActionBlock     = "Block"
+	AccessListActionChallenge = "Challenge"
+)
+
+// ParseAccessList reads IP addresses, CIDR ranges, ASNs ("AS13335") and
+// ISO 3166-1 alpha-2 country codes, one per line, and gives each entry
+// action. Other two-letter tokens, such as "OK" or "UK", are not countries.
+//
+// Blank lines and anything after "#" or ";" are ignored, and only the first
+// word of a line is used, so plaintext threat feeds such as Spamhaus DROP or
+// ipsum parse unchanged. Lines containing a comma are read as CSV: the first
+// column is the value and the second, when present, the comment. A first CSV
+// row whose value is not recognised is skipped as a header.
+//
+// The entries are returned as read; pass them through NormalizeAccessList to
+// dedupe and merge them.
+func ParseAccessList(r io.Reader, action string) ([]AccessListEntry, error) {
+	var entries []AccessListEntry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line, first := 0, true
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '#' || text[0] == ';' {
+			continue
+		}
+
+		var value, comment string
+		isCSV := strings.Contains(text, ",")
+		if isCSV {
+			cr := csv.NewReader(strings.NewReader(text))
+			cr.LazyQuotes = true
+			cr.TrimLeadingSpace = true
+			record, err := cr.Read()
+			if err != nil {
+				return nil, fmt.Errorf("shield: access list line %d: %w", line, err)
+			}
+			value = strings.TrimSpace(record[0])
+			if len(record) > 1 {
+				comment = strings.TrimSpace(record[1])
+			}
+		} else {
+			if i := strings.IndexAny(text, "#;"); i >= 0 {
+				text = text[:i]
+			}
+			if fields := strings.Fields(text); len(fields) > 0 {
+				value = fields[0]
+			}
+		}
+
+		typ, norm, ok := classifyAccessValue(value)
+		if !ok {
+			if isCSV && first {
+				first = false
+				continue
+			}
+			return nil, fmt.Errorf("shield: access list line %d: unrecognized value %q", line, value)
+		}
+		first = false
+		entries = append(entries, AccessListEntry{Type: typ, Value: norm, Action: action, Comment: comment})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("shield: reading access list: %w", err)
+	}
+	return entries, nil
+}
+
+// NormalizeAccessList canonicalizes entries and removes redundancy: values
+// are rewritten in canonical form (masked prefixes, single-address prefixes
+// as IPs, upper-case ASNs and country codes), duplicates are dropped, and
+// addresses and ranges sharing an action are merged into the fewest CIDRs
+// covering exactly the same addresses. A merged range keeps the comment of
+// its lowest member.
+//
+// When the same value appears with different actions the first one wins.
+// Address entries come out sorted; ASNs and countries keep their input order.
+func NormalizeAccessList(entries []AccessListEntry) ([]AccessListEntry, error) {
+	type group struct {
+		action   string
+		prefixes []commentedPrefix
+		others   []AccessListEntry
+	}
+	var groups []*group
+	byAction := make(map[string]*group)
+	seen := make(map[string]bool)
+
+	for _, e := range entries {
+		typ, value, ok := classifyAccessValue(e.Value)
+		if !ok {
+			return nil, fmt.Errorf("shield: unrecognized access list value %q", e.Value)
+		}
+		if seen[typ+"|"+value] {
+			continue
+		}
+		seen[typ+"|"+value] = true
+
+		key := strings.ToLower(e.Action)
+		g := byAction[key]
+		if g == nil {
+			g = &group{action: e.Action}
+			byAction[key] = g
+			groups = append(groups, g)
+		}
+		switch typ {
+		case AccessListTypeIP, AccessListTypeCIDR:
+			g.prefixes = append(g.prefixes, commentedPrefix{parsePrefix(value), e.Comment})
+		default:
+			g.others = append(g.others, AccessListEntry{Type: typ, Value: value, Action: e.Action, Comment: e.Comment})
+		}
+	}
+
+	var out []AccessListEntry
+	for _, g := range groups {
+		for _, p := range mergePrefixes(g.prefixes) {
+			typ, value := AccessListTypeCIDR, p.prefix.String()
+			if p.prefix.IsSingleIP() {
+				typ, value = AccessListTypeIP, p.prefix.Addr().String()
+			}
+			out = append(out, AccessListEntry{Type: typ, Value: value, Action: g.action, Comment: p.comment})
+		}
+		out = append(out, g.others...)
+	}
+	return out, nil
+}
+
+// classifyAccessValue detects the entry type of v and returns it in
+// canonical form.
+func classifyAccessValue(v string) (typ, value string, ok bool) {
+	v = strings.TrimSpace(v)
+	if addr, err := netip.ParseAddr(v); err == nil && addr.Zone() == "" {
+		return AccessListTypeIP, addr.Unmap().String(), true
+	}
+	if p, err := netip.ParsePrefix(v); err == nil {
+		p = unmapPrefix(p.Masked())
+		if p.IsSingleIP() {
+			return AccessListTypeIP, p.Addr().String(), true
+		}
+		return AccessListTypeCIDR, p.String(), true
+	}
+	upper := strings.ToUpper(v)
+	if digits, found := strings.CutPrefix(upper, "AS"); found && digits != "" {
+		if n, err := strconv.ParseUint(digits, 10, 32); err == nil {
+			return AccessListTypeASN, "AS" + strconv.FormatUint(n, 10), true
+		}
+	}
+	if countryCodes[upper] {
+		return AccessListTypeCountry, upper, true
+	}
+	return "", "", false
+}
+
+// countryCodes holds the officially assigned ISO 3166-1 alpha-2 codes.
+var countryCodes = func() map[string]bool {
+	const codes = `
+AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ
+BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ
+CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ
+DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR
+GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY
+HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP
+KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY
+MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ
+NA NC NE NF NG NI NL NO NP NR NU NZ OM
+PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ
+TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ
+VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`
+	m := make(map[string]bool)
+	for _, c := range strings.Fields(codes) {
+		m[c] = true
+	}
+	return m
+}()
+
+// accessListKey identifies an entry regardless of how its value is spelled.
+func accessListKey(e AccessListEntry) string {
+	if typ, value, ok := classifyAccessValue(e.Value); ok {
+		return typ + "|" + value
+	}
+	return strings.ToUpper(e.Type) + "|" + e.Value
+}
+
+type commentedPrefix struct {
+	prefix  netip.Prefix
+	comment string
+}
+
+// parsePrefix parses a canonical IP or CIDR value as a prefix.
+func parsePrefix(v string) netip.Prefix {
+	if addr, err := netip.ParseAddr(v); err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return netip.MustParsePrefix(v)
+}
+
+// unmapPrefix turns an IPv4-mapped IPv6 prefix into its IPv4 form.
+func unmapPrefix(p netip.Prefix) netip.Prefix {
+	if p.Addr().Is4In6() && p.Bits() >= 96 {
+		return netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+	}
+	return p
+}
+
+// mergePrefixes drops prefixes contained in others and joins sibling
+// prefixes into their parent until no more merges are possible.
+func mergePrefixes(in []commentedPrefix) []commentedPrefix {
+	slices.SortFunc(in, func(a, b commentedPrefix) int {
+		if c := a.prefix.Addr().Compare(b.prefix.Addr()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.prefix.Bits(), b.prefix.Bits())
+	})
+	var out []commentedPrefix
+	for _, p := range in {
+		if n := len(out); n > 0 {
+			top := out[n-1].prefix
+			if top.Bits() <= p.prefix.Bits() && top.Contains(p.prefix.Addr()) {
+				continue
+			}
+		}
+		out = append(out, p)
+		for len(out) >= 2 {
+			a, b := out[len(out)-2].prefix, out[len(out)-1].prefix
+			if a.Bits() != b.Bits() || a.Bits() == 0 {
+				break
+			}
+			parent := netip.PrefixFrom(a.Addr(), a.Bits()-1).Masked()
+			if parent != netip.PrefixFrom(b.Addr(), b.Bits()-1).Masked() {
+				break
+			}
+			out = append(out[:len(out)-2], commentedPrefix{parent, out[len(out)-2].comment})
+		}
+	}
+	return out
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // AccessListService provides methods for managing zone-scoped access lists.
@@ -27,6 +29,7 @@ func newAccessListService(client httpClient, zoneID string) AccessListService {
 
 // Get returns access lists for the zone.
 func (s *accessListService) Get(ctx context.Context) (*AccessList, error) {
+	ctx = middleware.WithOperation(ctx, "shield.AccessListService.Get")
 	path := fmt.Sprintf("/shield/zone/%s/access-lists", s.zoneID)
 	var resp AccessList
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -37,6 +40,7 @@ func (s *accessListService) Get(ctx context.Context) (*AccessList, error) {
 
 // Add adds an entry to the access list.
 func (s *accessListService) Add(ctx context.Context, req *AddAccessListEntryRequest) (*AccessListEntry, error) {
+	ctx = middleware.WithOperation(ctx, "shield.AccessListService.Add")
 	path := fmt.Sprintf("/shield/zone/%s/access-lists", s.zoneID)
 	var entry AccessListEntry
 	if err := s.client.do(ctx, http.MethodPost, path, req, &entry); err != nil {
@@ -47,18 +51,21 @@ func (s *accessListService) Add(ctx context.Context, req *AddAccessListEntryRequ
 
 // Update updates access list entries (batch PATCH).
 func (s *accessListService) Update(ctx context.Context, req *UpdateAccessListEntriesRequest) error {
+	ctx = middleware.WithOperation(ctx, "shield.AccessListService.Update")
 	path := fmt.Sprintf("/shield/zone/%s/access-lists", s.zoneID)
 	return s.client.do(ctx, http.MethodPatch, path, req, nil)
 }
 
 // Delete removes entries from the access list (DELETE with body).
 func (s *accessListService) Delete(ctx context.Context, req *DeleteAccessListEntriesRequest) error {
+	ctx = middleware.WithOperation(ctx, "shield.AccessListService.Delete")
 	path := fmt.Sprintf("/shield/zone/%s/access-lists", s.zoneID)
 	return s.client.do(ctx, http.MethodDelete, path, req, nil)
 }
 
 // GetEnums returns available access list types and actions.
 func (s *accessListService) GetEnums(ctx context.Context) (*AccessListEnums, error) {
+	ctx = middleware.WithOperation(ctx, "shield.AccessListService.GetEnums")
 	path := fmt.Sprintf("/shield/zone/%s/access-lists/enums", s.zoneID)
 	var resp AccessListEnums
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -69,6 +76,7 @@ func (s *accessListService) GetEnums(ctx context.Context) (*AccessListEnums, err
 
 // UpdateConfig updates access list configuration.
 func (s *accessListService) UpdateConfig(ctx context.Context, req *UpdateAccessListConfigRequest) (*AccessListConfig, error) {
+	ctx = middleware.WithOperation(ctx, "shield.AccessListService.UpdateConfig")
 	path := fmt.Sprintf("/shield/zone/%s/access-lists/configurations", s.zoneID)
 	var resp AccessListConfig
 	if err := s.client.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // BotDetectionService provides methods for managing bot detection settings.
@@ -23,6 +25,7 @@ func newBotDetectionService(client httpClient, zoneID string) BotDetectionServic
 
 // Get returns bot detection settings for the zone.
 func (s *botDetectionService) Get(ctx context.Context) (*BotDetectionSettings, error) {
+	ctx = middleware.WithOperation(ctx, "shield.BotDetectionService.Get")
 	path := fmt.Sprintf("/shield/zone/%s/bot-detection", s.zoneID)
 	var resp BotDetectionSettings
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -33,6 +36,7 @@ func (s *botDetectionService) Get(ctx context.Context) (*BotDetectionSettings, e
 
 // Update updates bot detection settings for the zone.
 func (s *botDetectionService) Update(ctx context.Context, req *UpdateBotDetectionRequest) (*BotDetectionSettings, error) {
+	ctx = middleware.WithOperation(ctx, "shield.BotDetectionService.Update")
 	path := fmt.Sprintf("/shield/zone/%s/bot-detection", s.zoneID)
 	var resp BotDetectionSettings
 	if err := s.client.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
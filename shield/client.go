@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 
 	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/internal/pipeline"
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 const (
@@ -27,6 +30,7 @@ type Client struct {
 	httpClient HTTPClient
 	userAgent  string
 	baseURL    string
+	pipeline   pipeline.Config
 }
 
 // Option is a functional option for configuring the Client.
@@ -46,6 +50,48 @@ func WithUserAgent(ua string) Option {
 	}
 }
 
+// WithMiddleware adds middlewares around the HTTP client. The first one
+// added is the outermost.
+func WithMiddleware(mws ...middleware.Middleware) Option {
+	return func(c *Client) {
+		c.pipeline.Middlewares = append(c.pipeline.Middlewares, mws...)
+	}
+}
+
+// WithLogger logs every request to l. The logger runs after all other
+// middlewares, so each retry attempt is logged separately. Credentials are
+// always redacted.
+func WithLogger(l *slog.Logger, opts ...middleware.LogOption) Option {
+	return func(c *Client) {
+		c.pipeline.Logger = middleware.Log(l, opts...)
+	}
+}
+
+// WithTracer starts a span for every shield request, named after the
+// service method, such as "shield.WAFService.ListCustomRules".
+func WithTracer(t middleware.Tracer) Option {
+	return func(c *Client) {
+		c.pipeline.Tracer = middleware.Trace(t)
+	}
+}
+
+// WithMetrics records request counts, latencies, bytes transferred and
+// retries for every request.
+func WithMetrics(m middleware.Metrics) Option {
+	return func(c *Client) {
+		c.pipeline.Metrics = middleware.Measure(m)
+	}
+}
+
+// WithRateLimiter throttles requests with l and retries 429 responses after
+// the Retry-After period. Share one limiter between clients that draw from
+// the same quota.
+func WithRateLimiter(l *middleware.RateLimiter) Option {
+	return func(c *Client) {
+		c.pipeline.RateLimiter = l.Middleware()
+	}
+}
+
 // WithBaseURL sets a custom base URL for the API.
 func WithBaseURL(url string) Option {
 	return func(c *Client) {
@@ -65,12 +111,14 @@ func NewClient(apiKey string, opts ...Option) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.httpClient = c.pipeline.Wrap(c.httpClient)
 	return c
 }
 
 // httpClient is the internal interface for making API requests.
 type httpClient interface {
 	do(ctx context.Context, method, path string, body any, result any) error
+	open(ctx context.Context, method, path string) (io.ReadCloser, error)
 }
 
 // clientAdapter adapts Client to the httpClient interface.
@@ -82,21 +130,49 @@ func (a *clientAdapter) do(ctx context.Context, method, path string, body any, r
 	return a.client.doRequest(ctx, method, path, body, result)
 }
 
+func (a *clientAdapter) open(ctx context.Context, method, path string) (io.ReadCloser, error) {
+	resp, err := a.client.send(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 func (c *Client) doRequest(ctx context.Context, method, path string, body any, result any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		defer resp.Body.Close()
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	} else {
+		resp.Body.Close()
+	}
+
+	return nil
+}
+
+// send makes a request and returns the response of a successful one, whose
+// body the caller must close.
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
 	fullURL := c.baseURL + path
 
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
 	req, err := internal.NewRequest(ctx, method, fullURL, bodyReader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("AccessKey", c.apiKey)
@@ -108,23 +184,13 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body any, r
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
-		return handleErrorResponse(resp)
+		return nil, handleErrorResponse(resp)
 	}
-
-	if result != nil && resp.StatusCode != http.StatusNoContent {
-		defer resp.Body.Close()
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
-	} else {
-		resp.Body.Close()
-	}
-
-	return nil
+	return resp, nil
 }
 
 func handleErrorResponse(resp *http.Response) error {
@@ -551,6 +551,34 @@ func TestEventLogsService_List(t *testing.T) {
 	}
 }
 
+func TestEventLogsService_ListIter(t *testing.T) {
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if !strings.Contains(req.URL.RawQuery, "limit=10000") {
+				t.Errorf("unexpected query: %s", req.URL.RawQuery)
+			}
+			body := `{"TotalCount":2,"Meta":{"Nested":[1,{"a":[]}]},"Items":[{"Id":"log-1","Action":"Block"},{"Id":"log-2","Action":"Log"}]}`
+			return testutil.NewMockResponse(200, body), nil
+		},
+	}
+
+	client := shield.NewClient("test-key", shield.WithHTTPClient(mock))
+	// The embedding hides ListIter, so the second service falls back to List.
+	listOnly := struct{ shield.EventLogsService }{client.EventLogs()}
+	for _, logs := range []shield.EventLogsService{client.EventLogs(), listOnly} {
+		var ids []string
+		for log, err := range shield.ListEventLogs(context.Background(), logs, &shield.EventLogListOptions{Limit: 10000}) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids = append(ids, log.ID)
+		}
+		if strings.Join(ids, ",") != "log-1,log-2" {
+			t.Errorf("ids = %v", ids)
+		}
+	}
+}
+
 func TestEventLogsService_List_WithOptions(t *testing.T) {
 	mock := &testutil.MockHTTPClient{
 		DoFunc: func(req *http.Request) (*http.Response, error) {
@@ -3,6 +3,8 @@ package shield
 import (
 	"context"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // DDoSService provides methods for DDoS protection information.
@@ -20,6 +22,7 @@ func newDDoSService(client httpClient) DDoSService {
 
 // GetEnums returns DDoS protection enumeration values.
 func (s *ddosService) GetEnums(ctx context.Context) (*DDoSEnums, error) {
+	ctx = middleware.WithOperation(ctx, "shield.DDoSService.GetEnums")
 	var resp DDoSEnums
 	if err := s.client.do(ctx, http.MethodGet, "/shield/ddos/enums", nil, &resp); err != nil {
 		return nil, err
@@ -0,0 +1,385 @@
+package shield
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+)
+
+const (
+	defaultTailInterval = 10 * time.Second
+	defaultTailPageSize = 100
+	defaultTailLookback = 5 * time.Minute
+)
+
+// TailCursor is the position of an EventTailer in a zone's event log.
+//
+// Polled is the end of the last window that was fully delivered. Each poll
+// reads from Polled minus the lookback, so events that are indexed late are
+// still picked up; Seen holds the IDs already delivered from that overlap,
+// with their timestamps, so they are not delivered twice.
+type TailCursor struct {
+	Polled time.Time            `json:"polled"`
+	Seen   map[string]time.Time `json:"seen,omitempty"`
+}
+
+// TailCursorStore persists tail cursors so a restarted tailer resumes where
+// it stopped.
+type TailCursorStore interface {
+	// LoadCursor returns the saved cursor for the zone, or nil if there is none.
+	LoadCursor(ctx context.Context, zoneID string) (*TailCursor, error)
+	SaveCursor(ctx context.Context, zoneID string, c *TailCursor) error
+}
+
+// FileCursorStore keeps one JSON file per zone in a directory. Files are
+// replaced atomically, so a crash never leaves a torn cursor behind.
+type FileCursorStore struct {
+	Dir string
+}
+
+// LoadCursor reads the zone's cursor file.
+func (s FileCursorStore) LoadCursor(_ context.Context, zoneID string) (*TailCursor, error) {
+	b, err := os.ReadFile(s.path(zoneID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c TailCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("shield: reading tail cursor %s: %w", s.path(zoneID), err)
+	}
+	return &c, nil
+}
+
+// SaveCursor writes the zone's cursor file.
+func (s FileCursorStore) SaveCursor(_ context.Context, zoneID string, c *TailCursor) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.Dir, ".cursor-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path(zoneID))
+}
+
+func (s FileCursorStore) path(zoneID string) string {
+	return filepath.Join(s.Dir, "shield-events-"+filepath.Base(zoneID)+".json")
+}
+
+// EventTailer follows a zone's security event log by polling
+// EventLogsService.List, delivering every event once on a channel.
+//
+//	t := shield.NewEventTailer(client.EventLogs(),
+//		shield.WithTailCursorStore(shield.FileCursorStore{Dir: "/var/lib/soc"}))
+//	events, err := t.Tail(ctx, zoneID)
+//	for e := range events {
+//		dashboard.Push(e)
+//	}
+type EventTailer struct {
+	logs     EventLogsService
+	interval time.Duration
+	pageSize int
+	lookback time.Duration
+	from     time.Time
+	store    TailCursorStore
+	logger   *slog.Logger
+	onError  func(zoneID string, err error)
+	maxFails int
+	now      func() time.Time
+}
+
+// TailOption configures an EventTailer.
+type TailOption func(*EventTailer)
+
+// WithTailInterval sets how long the tailer waits between polls. The default is 10 seconds.
+func WithTailInterval(d time.Duration) TailOption {
+	return func(t *EventTailer) {
+		t.interval = d
+	}
+}
+
+// WithTailPageSize sets the Limit of each List call. The default is 100.
+func WithTailPageSize(n int) TailOption {
+	return func(t *EventTailer) {
+		t.pageSize = n
+	}
+}
+
+// WithTailLookback sets how far each poll reaches back before the previous
+// one, to catch events that show up in the log late. The default is 5 minutes.
+func WithTailLookback(d time.Duration) TailOption {
+	return func(t *EventTailer) {
+		t.lookback = d
+	}
+}
+
+// WithTailFrom makes a tailer without a saved cursor start at from instead
+// of the current time, replaying the events since then. Like every poll,
+// the first one also reaches back by the lookback.
+func WithTailFrom(from time.Time) TailOption {
+	return func(t *EventTailer) {
+		t.from = from
+	}
+}
+
+// WithTailCursorStore persists the cursor after every poll and on shutdown.
+func WithTailCursorStore(s TailCursorStore) TailOption {
+	return func(t *EventTailer) {
+		t.store = s
+	}
+}
+
+// WithTailLogger sets the logger that records failed polls and cursor
+// saves. Failed polls are retried on the next interval without moving the
+// cursor.
+func WithTailLogger(logger *slog.Logger) TailOption {
+	return func(t *EventTailer) {
+		t.logger = logger
+	}
+}
+
+// WithTailErrorHandler sets a function called from the tail goroutine with
+// every failed poll or cursor save, and with the error that stopped Tail.
+func WithTailErrorHandler(fn func(zoneID string, err error)) TailOption {
+	return func(t *EventTailer) {
+		t.onError = fn
+	}
+}
+
+// WithTailMaxFailures stops Tail after n consecutive failed polls. The
+// default, zero, retries until ctx is done unless the API rejects the key
+// or the zone.
+func WithTailMaxFailures(n int) TailOption {
+	return func(t *EventTailer) {
+		t.maxFails = n
+	}
+}
+
+// WithTailClock sets the function used as the current time.
+func WithTailClock(now func() time.Time) TailOption {
+	return func(t *EventTailer) {
+		t.now = now
+	}
+}
+
+// NewEventTailer creates a tailer reading from logs.
+func NewEventTailer(logs EventLogsService, opts ...TailOption) *EventTailer {
+	t := &EventTailer{
+		logs:     logs,
+		interval: defaultTailInterval,
+		pageSize: defaultTailPageSize,
+		lookback: defaultTailLookback,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.pageSize < 1 {
+		t.pageSize = defaultTailPageSize
+	}
+	if t.logger == nil {
+		t.logger = internal.DiscardLogger()
+	}
+	return t
+}
+
+// Tail polls the zone's event log until ctx is done, sending new events on
+// the returned channel oldest first, and closes the channel when it stops.
+//
+// Each poll reads a fixed time window page by page, so events arriving
+// while it pages cannot shift results between pages, and reads everything
+// since the last successful poll, so downtime or failed polls leave no gap.
+// Events are deduplicated by ID. The cursor is saved after every poll and
+// when ctx is done, so a restart does not repeat events; only a crash in the
+// middle of a poll can deliver that poll's events again.
+//
+// Failed polls are retried on the next interval and passed to the error
+// handler. Tail stops and closes the channel when the API answers 401, 403
+// or 404, since retrying cannot help, or after WithTailMaxFailures
+// consecutive failures; the handler then receives the final error.
+//
+// Tail returns an error only when the saved cursor cannot be loaded.
+func (t *EventTailer) Tail(ctx context.Context, zoneID string) (<-chan EventLog, error) {
+	cursor, err := t.loadCursor(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan EventLog)
+	go func() {
+		defer close(events)
+		failures := 0
+		for {
+			err := t.poll(ctx, zoneID, cursor, events)
+			t.saveCursor(zoneID, cursor)
+			switch {
+			case err == nil:
+				failures = 0
+			case ctx.Err() == nil:
+				failures++
+				if permanentTailError(err) || t.maxFails > 0 && failures >= t.maxFails {
+					t.report(zoneID, "shield: tailing event log stopped", fmt.Errorf("shield: tailing event log stopped after %d failed polls: %w", failures, err))
+					return
+				}
+				t.report(zoneID, "shield: polling event log", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(t.interval):
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (t *EventTailer) loadCursor(ctx context.Context, zoneID string) (*TailCursor, error) {
+	if t.store != nil {
+		c, err := t.store.LoadCursor(ctx, zoneID)
+		if err != nil {
+			return nil, fmt.Errorf("shield: loading tail cursor: %w", err)
+		}
+		if c != nil {
+			if c.Seen == nil {
+				c.Seen = make(map[string]time.Time)
+			}
+			return c, nil
+		}
+	}
+	from := t.from
+	if from.IsZero() {
+		from = t.now()
+	}
+	return &TailCursor{Polled: from, Seen: make(map[string]time.Time)}, nil
+}
+
+// saveCursor persists the cursor. It runs detached from the tail context
+// so the final save on shutdown still happens.
+func (t *EventTailer) saveCursor(zoneID string, c *TailCursor) {
+	if t.store == nil {
+		return
+	}
+	if err := t.store.SaveCursor(context.Background(), zoneID, c); err != nil {
+		t.report(zoneID, "shield: saving tail cursor", fmt.Errorf("shield: saving tail cursor: %w", err))
+	}
+}
+
+// report logs err and passes it to the error handler.
+func (t *EventTailer) report(zoneID, msg string, err error) {
+	t.logger.Error(msg, "zone", zoneID, "error", err)
+	if t.onError != nil {
+		t.onError(zoneID, err)
+	}
+}
+
+// permanentTailError reports whether err is an API answer that polling
+// again will not change: a rejected key or an unknown zone.
+func permanentTailError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// poll delivers the unseen events between the cursor and now, then moves
+// the cursor to now. On error or cancellation the cursor keeps the events
+// delivered so far in Seen but does not move.
+func (t *EventTailer) poll(ctx context.Context, zoneID string, c *TailCursor, out chan<- EventLog) error {
+	from, to := c.Polled.Add(-t.lookback), t.now()
+	opts := &EventLogListOptions{
+		ZoneID: zoneID,
+		From:   from.UTC().Format(time.RFC3339),
+		To:     to.UTC().Format(time.RFC3339),
+		Limit:  t.pageSize,
+	}
+
+	type timedEvent struct {
+		EventLog
+		at time.Time
+	}
+	var fresh []timedEvent
+	pending := make(map[string]bool)
+	for {
+		resp, err := t.logs.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for _, e := range resp.Items {
+			if _, seen := c.Seen[e.ID]; seen || pending[e.ID] {
+				continue
+			}
+			pending[e.ID] = true
+			fresh = append(fresh, timedEvent{e, parseEventTime(e.Timestamp, to)})
+		}
+		opts.Offset += len(resp.Items)
+		if len(resp.Items) < opts.Limit || resp.TotalCount > 0 && opts.Offset >= resp.TotalCount {
+			break
+		}
+	}
+
+	slices.SortStableFunc(fresh, func(a, b timedEvent) int {
+		if c := a.at.Compare(b.at); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for _, e := range fresh {
+		select {
+		case out <- e.EventLog:
+			c.Seen[e.ID] = e.at
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// The next poll asks for events from the second the lookback starts in,
+	// so only IDs older than that second can be forgotten.
+	c.Polled = to
+	horizon := to.Add(-t.lookback).Truncate(time.Second)
+	for id, at := range c.Seen {
+		if at.Before(horizon) {
+			delete(c.Seen, id)
+		}
+	}
+	return nil
+}
+
+// parseEventTime parses an event timestamp in any format the API uses,
+// falling back to def so unparsable events still sort and expire.
+func parseEventTime(s string, def time.Time) time.Time {
+	var bt internal.BunnyTime
+	if err := bt.UnmarshalJSON([]byte(s)); err != nil || bt.IsZero() {
+		return def
+	}
+	return bt.Time
+}
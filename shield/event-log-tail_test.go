@@ -0,0 +1,218 @@
+package shield_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func TestEventTailer(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	logs := shield.NewClient("key", shield.WithBaseURL(srv.URL)).EventLogs()
+
+	base := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	event := func(id string, at time.Time) shield.EventLog {
+		return shield.EventLog{ID: id, ZoneID: "z1", Timestamp: at.Format(time.RFC3339), Action: "Block"}
+	}
+	srv.AddEventLogs(
+		event("e1", base.Add(-10*time.Minute)),
+		event("e2", base.Add(-8*time.Minute)),
+		event("e3", base.Add(-6*time.Minute)),
+		event("e4", base.Add(-4*time.Minute)),
+		event("e5", base.Add(-2*time.Minute)),
+		shield.EventLog{ID: "other", ZoneID: "z2", Timestamp: base.Add(-time.Minute).Format(time.RFC3339)},
+	)
+
+	var mu sync.Mutex
+	now := base
+	setNow := func(t time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = t
+	}
+	store := shield.FileCursorStore{Dir: t.TempDir()}
+	newTailer := func() *shield.EventTailer {
+		return shield.NewEventTailer(logs,
+			shield.WithTailFrom(base.Add(-time.Hour)),
+			shield.WithTailPageSize(2),
+			shield.WithTailInterval(5*time.Millisecond),
+			shield.WithTailCursorStore(store),
+			shield.WithTailClock(func() time.Time {
+				mu.Lock()
+				defer mu.Unlock()
+				return now
+			}),
+		)
+	}
+	receive := func(events <-chan shield.EventLog, n int) []string {
+		t.Helper()
+		var ids []string
+		for len(ids) < n {
+			select {
+			case e := <-events:
+				ids = append(ids, e.ID)
+			case <-time.After(2 * time.Second):
+				t.Fatalf("timed out after %v", ids)
+			}
+		}
+		return ids
+	}
+	drain := func(events <-chan shield.EventLog) []string {
+		var ids []string
+		for e := range events {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := newTailer().Tail(ctx, "z1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := receive(events, 5); !slices.Equal(got, []string{"e1", "e2", "e3", "e4", "e5"}) {
+		t.Fatalf("expected backlog oldest first across pages, got %v", got)
+	}
+
+	// A late event from before the last poll and a new one arrive.
+	setNow(base.Add(time.Minute))
+	srv.AddEventLogs(event("late", base.Add(-30*time.Second)), event("e6", base.Add(30*time.Second)))
+	if got := receive(events, 2); !slices.Equal(got, []string{"late", "e6"}) {
+		t.Fatalf("expected late and new events, got %v", got)
+	}
+	cancel()
+	if extra := drain(events); len(extra) != 0 {
+		t.Errorf("expected no duplicates, got %v", extra)
+	}
+
+	// A restarted tailer resumes from the saved cursor.
+	setNow(base.Add(3 * time.Minute))
+	srv.AddEventLogs(event("e7", base.Add(2*time.Minute)))
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	events, err = newTailer().Tail(ctx, "z1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := receive(events, 1); !slices.Equal(got, []string{"e7"}) {
+		t.Fatalf("expected only the new event after restart, got %v", got)
+	}
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if extra := drain(events); len(extra) != 0 {
+		t.Errorf("expected no duplicates after restart, got %v", extra)
+	}
+}
+
+// failingLogs rejects every List call, with err when it is set.
+type failingLogs struct {
+	err error
+}
+
+func (f failingLogs) List(context.Context, *shield.EventLogListOptions) (*shield.EventLogListResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return nil, errors.New("unavailable")
+}
+
+// memoryCursors records the cursors saved for each zone.
+type memoryCursors struct {
+	mu    sync.Mutex
+	saved []shield.TailCursor
+}
+
+func (m *memoryCursors) LoadCursor(context.Context, string) (*shield.TailCursor, error) {
+	return nil, nil
+}
+
+func (m *memoryCursors) SaveCursor(_ context.Context, _ string, c *shield.TailCursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved = append(m.saved, *c)
+	return nil
+}
+
+func TestEventTailerFailedFirstPollKeepsStart(t *testing.T) {
+	from := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := &memoryCursors{}
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := shield.NewEventTailer(failingLogs{},
+		shield.WithTailFrom(from),
+		shield.WithTailInterval(time.Hour),
+		shield.WithTailCursorStore(store),
+	).Tail(ctx, "z1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	for range events {
+	}
+	if len(store.saved) == 0 || !store.saved[0].Polled.Equal(from) {
+		t.Errorf("expected the cursor saved at the start time %v, got %+v", from, store.saved)
+	}
+}
+
+func TestEventTailerStopsOnFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		logs  shield.EventLogsService
+		opts  []shield.TailOption
+		fails int
+	}{
+		{
+			name:  "rejected key",
+			logs:  shield.NewClient("key", shield.WithHTTPClient(&testutil.MockHTTPClient{DoFunc: unauthorized})).EventLogs(),
+			fails: 1,
+		},
+		{
+			name:  "max failures",
+			logs:  failingLogs{},
+			opts:  []shield.TailOption{shield.WithTailMaxFailures(3)},
+			fails: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			opts := append(tt.opts,
+				shield.WithTailInterval(time.Millisecond),
+				shield.WithTailErrorHandler(func(zoneID string, err error) {
+					if zoneID != "z1" {
+						t.Errorf("zoneID = %q", zoneID)
+					}
+					errs = append(errs, err)
+				}),
+			)
+			events, err := shield.NewEventTailer(tt.logs, opts...).Tail(context.Background(), "z1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			select {
+			case _, ok := <-events:
+				if ok {
+					t.Fatal("unexpected event")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Tail kept polling")
+			}
+			if len(errs) != tt.fails || !strings.Contains(errs[len(errs)-1].Error(), "stopped") {
+				t.Errorf("handler got %v, want %d errors ending with the stop", errs, tt.fails)
+			}
+		})
+	}
+}
+
+func unauthorized(*http.Request) (*http.Response, error) {
+	return testutil.NewMockResponse(401, `{"Message":"invalid key"}`), nil
+}
@@ -2,9 +2,13 @@ package shield
 
 import (
 	"context"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // EventLogsService provides methods for accessing security event logs.
@@ -12,6 +16,34 @@ type EventLogsService interface {
 	List(ctx context.Context, opts *EventLogListOptions) (*EventLogListResponse, error)
 }
 
+// EventLogIterator is implemented by event log services that can stream a
+// listing; the one returned by Client.EventLogs does. Use ListEventLogs
+// rather than asserting it directly.
+type EventLogIterator interface {
+	ListIter(ctx context.Context, opts *EventLogListOptions) iter.Seq2[EventLog, error]
+}
+
+// ListEventLogs returns the security event logs matching opts one entry at
+// a time. It streams the response when logs implements EventLogIterator and
+// otherwise yields the items of logs.List.
+func ListEventLogs(ctx context.Context, logs EventLogsService, opts *EventLogListOptions) iter.Seq2[EventLog, error] {
+	if it, ok := logs.(EventLogIterator); ok {
+		return it.ListIter(ctx, opts)
+	}
+	return func(yield func(EventLog, error) bool) {
+		resp, err := logs.List(ctx, opts)
+		if err != nil {
+			yield(EventLog{}, err)
+			return
+		}
+		for _, e := range resp.Items {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
 type eventLogsService struct {
 	client httpClient
 }
@@ -22,6 +54,7 @@ func newEventLogsService(client httpClient) EventLogsService {
 
 // List returns security event logs with optional filtering.
 func (s *eventLogsService) List(ctx context.Context, opts *EventLogListOptions) (*EventLogListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.EventLogsService.List")
 	path := "/shield/event-logs" + buildEventLogQuery(opts)
 	var resp EventLogListResponse
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -30,6 +63,32 @@ func (s *eventLogsService) List(ctx context.Context, opts *EventLogListOptions)
 	return &resp, nil
 }
 
+// ListIter returns security event logs like List, but decodes the response
+// one entry at a time as the loop consumes it, so memory use stays flat with
+// large limits. The request is sent when the loop starts; an error is
+// yielded once and ends the loop.
+func (s *eventLogsService) ListIter(ctx context.Context, opts *EventLogListOptions) iter.Seq2[EventLog, error] {
+	ctx = middleware.WithOperation(ctx, "shield.EventLogsService.ListIter")
+	path := "/shield/event-logs" + buildEventLogQuery(opts)
+	return func(yield func(EventLog, error) bool) {
+		body, err := s.client.open(ctx, http.MethodGet, path)
+		if err != nil {
+			yield(EventLog{}, err)
+			return
+		}
+		defer body.Close()
+
+		stopped := false
+		err = internal.DecodeArray(body, "Items", func(e EventLog) bool {
+			stopped = !yield(e, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(EventLog{}, err)
+		}
+	}
+}
+
 func buildEventLogQuery(opts *EventLogListOptions) string {
 	if opts == nil {
 		return ""
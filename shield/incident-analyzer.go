@@ -0,0 +1,466 @@
+package shield
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAnalyzerTopN        = 10
+	defaultAnalyzerSpikeFactor = 3.0
+	defaultAnalyzerMinCount    = 50
+	defaultAnalyzerSuggestAt   = 100
+	defaultAnalyzerMaxEvents   = 100_000
+	analyzerASNMinIPs          = 5
+	analyzerPageSize           = 1000
+)
+
+// ASNLookup maps an IP address to its autonomous system number. The event
+// log does not carry ASNs, so the analyzer only ranks ASNs when one is set
+// with WithASNLookup.
+type ASNLookup func(addr netip.Addr) (asn uint32, ok bool)
+
+// IncidentAnalyzer summarizes attack activity on a Shield zone from its
+// metrics and event log, comparing a window against the window of the same
+// length just before it:
+//
+//	a := shield.NewIncidentAnalyzer(client.Metrics(), client.EventLogs())
+//	report, err := a.Analyze(ctx, zoneID, time.Now().Add(-time.Hour), time.Now())
+//	bot.Post(report.String())
+type IncidentAnalyzer struct {
+	metrics     MetricsService
+	logs        EventLogsService
+	topN        int
+	spikeFactor float64
+	minCount    int64
+	suggestAt   int64
+	maxEvents   int
+	asn         ASNLookup
+}
+
+// AnalyzerOption configures an IncidentAnalyzer.
+type AnalyzerOption func(*IncidentAnalyzer)
+
+// WithAnalyzerTopN sets how many IPs, ASNs and paths are listed. The default is 10.
+func WithAnalyzerTopN(n int) AnalyzerOption {
+	return func(a *IncidentAnalyzer) {
+		a.topN = n
+	}
+}
+
+// WithSpikeThreshold sets when a count is reported as a spike or a rule as
+// firing above baseline: it must be at least factor times its baseline and
+// at least minCount. The defaults are 3 and 50.
+func WithSpikeThreshold(factor float64, minCount int64) AnalyzerOption {
+	return func(a *IncidentAnalyzer) {
+		a.spikeFactor = factor
+		a.minCount = minCount
+	}
+}
+
+// WithSuggestionThreshold sets how many events an IP or ASN must cause in
+// the window before an access list entry is suggested for it. The default
+// is 100.
+func WithSuggestionThreshold(n int64) AnalyzerOption {
+	return func(a *IncidentAnalyzer) {
+		a.suggestAt = n
+	}
+}
+
+// WithAnalyzerMaxEvents caps the events read per window. The default is
+// 100,000; reports on truncated windows carry a warning.
+func WithAnalyzerMaxEvents(n int) AnalyzerOption {
+	return func(a *IncidentAnalyzer) {
+		a.maxEvents = n
+	}
+}
+
+// WithASNLookup enables ASN ranking and ASN suggestions.
+func WithASNLookup(lookup ASNLookup) AnalyzerOption {
+	return func(a *IncidentAnalyzer) {
+		a.asn = lookup
+	}
+}
+
+// NewIncidentAnalyzer creates an analyzer reading from metrics and logs.
+func NewIncidentAnalyzer(metrics MetricsService, logs EventLogsService, opts ...AnalyzerOption) *IncidentAnalyzer {
+	a := &IncidentAnalyzer{
+		metrics:     metrics,
+		logs:        logs,
+		topN:        defaultAnalyzerTopN,
+		spikeFactor: defaultAnalyzerSpikeFactor,
+		minCount:    defaultAnalyzerMinCount,
+		suggestAt:   defaultAnalyzerSuggestAt,
+		maxEvents:   defaultAnalyzerMaxEvents,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// TimeWindow is a half-open time range.
+type TimeWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Offender is an IP, ASN or path ranked by the events it caused.
+type Offender struct {
+	Value    string `json:"value"`
+	Count    int64  `json:"count"`
+	Baseline int64  `json:"baseline"`
+}
+
+// RuleActivity is a WAF rule or rate limit firing above its baseline.
+type RuleActivity struct {
+	Kind     string  `json:"kind"` // "waf" or "rate-limit"
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Count    int64   `json:"count"`
+	Baseline int64   `json:"baseline"`
+	Ratio    float64 `json:"ratio"`
+}
+
+// Spike is a zone metric that rose sharply against the baseline window.
+type Spike struct {
+	Metric   string  `json:"metric"`
+	Count    int64   `json:"count"`
+	Baseline int64   `json:"baseline"`
+	Ratio    float64 `json:"ratio"`
+}
+
+// IncidentReport is the result of IncidentAnalyzer.Analyze.
+type IncidentReport struct {
+	ZoneID         string         `json:"zoneId"`
+	Window         TimeWindow     `json:"window"`
+	Baseline       TimeWindow     `json:"baseline"`
+	Events         int            `json:"events"`
+	BaselineEvents int            `json:"baselineEvents"`
+	TopIPs         []Offender     `json:"topIps"`
+	TopASNs        []Offender     `json:"topAsns,omitempty"`
+	TopPaths       []Offender     `json:"topPaths"`
+	Rules          []RuleActivity `json:"rules"`
+	Spikes         []Spike        `json:"spikes"`
+	// Suggestions are access list entries for the worst offenders, ready
+	// for AccessListManager or ExpiringAccessList.
+	Suggestions []AccessListEntry `json:"suggestions"`
+	// Warnings lists metrics that could not be read and truncated windows.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Analyze reads the zone's events and metrics for [from, to) and for the
+// window of the same length before it, and summarizes the difference.
+// Events must be readable; metrics that fail, for example because bot
+// detection is not enabled, are skipped with a warning.
+func (a *IncidentAnalyzer) Analyze(ctx context.Context, zoneID string, from, to time.Time) (*IncidentReport, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("shield: analysis window end %s is not after its start %s", to, from)
+	}
+	report := &IncidentReport{
+		ZoneID:   zoneID,
+		Window:   TimeWindow{From: from, To: to},
+		Baseline: TimeWindow{From: from.Add(-to.Sub(from)), To: from},
+	}
+
+	current, err := a.tally(ctx, zoneID, report.Window, report)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := a.tally(ctx, zoneID, report.Baseline, report)
+	if err != nil {
+		return nil, err
+	}
+	report.Events, report.BaselineEvents = current.events, baseline.events
+	report.TopIPs = a.top(current.ips, baseline.ips)
+	report.TopASNs = a.top(current.asns, baseline.asns)
+	report.TopPaths = a.top(current.paths, baseline.paths)
+	report.Spikes = append(report.Spikes, a.spike("events", int64(current.events), int64(baseline.events))...)
+
+	a.compareMetrics(ctx, zoneID, current, report)
+	report.Suggestions = a.suggest(current)
+	return report, nil
+}
+
+// eventTally counts a window's events by attribute.
+type eventTally struct {
+	events int
+	ips    map[string]int64
+	asns   map[string]int64
+	asnIPs map[string]map[string]bool
+	paths  map[string]int64
+	rules  map[string]int64
+	names  map[string]string
+}
+
+// tally reads the events in w page by page, up to the event cap.
+func (a *IncidentAnalyzer) tally(ctx context.Context, zoneID string, w TimeWindow, report *IncidentReport) (*eventTally, error) {
+	t := &eventTally{
+		ips:    make(map[string]int64),
+		asns:   make(map[string]int64),
+		asnIPs: make(map[string]map[string]bool),
+		paths:  make(map[string]int64),
+		rules:  make(map[string]int64),
+		names:  make(map[string]string),
+	}
+	opts := &EventLogListOptions{
+		ZoneID: zoneID,
+		From:   w.From.UTC().Format(time.RFC3339),
+		To:     w.To.UTC().Format(time.RFC3339),
+	}
+	for t.events < a.maxEvents {
+		opts.Limit = min(analyzerPageSize, a.maxEvents-t.events)
+		n := 0
+		for e, err := range ListEventLogs(ctx, a.logs, opts) {
+			if err != nil {
+				return nil, fmt.Errorf("shield: reading event log: %w", err)
+			}
+			n++
+			t.add(e, a.asn)
+		}
+		if n < opts.Limit {
+			break
+		}
+		opts.Offset += n
+	}
+	if t.events >= a.maxEvents {
+		report.Warnings = append(report.Warnings,
+			fmt.Sprintf("window %s - %s truncated at %d events", w.From.Format(time.RFC3339), w.To.Format(time.RFC3339), a.maxEvents))
+	}
+	return t, nil
+}
+
+func (t *eventTally) add(e EventLog, lookup ASNLookup) {
+	t.events++
+	if e.SourceIP != "" {
+		t.ips[e.SourceIP]++
+		if addr, err := netip.ParseAddr(e.SourceIP); err == nil && lookup != nil {
+			if n, ok := lookup(addr.Unmap()); ok {
+				asn := fmt.Sprintf("AS%d", n)
+				t.asns[asn]++
+				if t.asnIPs[asn] == nil {
+					t.asnIPs[asn] = make(map[string]bool)
+				}
+				t.asnIPs[asn][e.SourceIP] = true
+			}
+		}
+	}
+	if e.Path != "" {
+		t.paths[e.Path]++
+	}
+	if e.RuleID != "" {
+		t.rules[e.RuleID]++
+		t.names[e.RuleID] = e.RuleName
+	}
+}
+
+// top ranks the current counts, attaching the baseline count of each value.
+func (a *IncidentAnalyzer) top(current, baseline map[string]int64) []Offender {
+	out := make([]Offender, 0, len(current))
+	for v, n := range current {
+		out = append(out, Offender{Value: v, Count: n, Baseline: baseline[v]})
+	}
+	slices.SortFunc(out, func(x, y Offender) int {
+		if c := cmp.Compare(y.Count, x.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Value, y.Value)
+	})
+	if a.topN > 0 && len(out) > a.topN {
+		out = out[:a.topN]
+	}
+	return out
+}
+
+// spike returns the metric as a spike when it crosses the threshold.
+func (a *IncidentAnalyzer) spike(metric string, count, baseline int64) []Spike {
+	ratio, above := a.aboveBaseline(count, baseline)
+	if !above {
+		return nil
+	}
+	return []Spike{{Metric: metric, Count: count, Baseline: baseline, Ratio: ratio}}
+}
+
+func (a *IncidentAnalyzer) aboveBaseline(count, baseline int64) (float64, bool) {
+	ratio := float64(count) / float64(max(baseline, 1))
+	return ratio, count >= a.minCount && ratio >= a.spikeFactor
+}
+
+// compareMetrics adds spikes from the zone metrics and the rules firing
+// above baseline.
+func (a *IncidentAnalyzer) compareMetrics(ctx context.Context, zoneID string, events *eventTally, report *IncidentReport) {
+	cur, base := a.dateRange(report.Window), a.dateRange(report.Baseline)
+	warn := func(what string, err error) {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	zoneMetrics := func(r *DateRangeOptions) (*ZoneMetrics, error) {
+		m, err := a.metrics.GetOverviewDetailed(ctx, &MetricsDetailedOptions{From: r.From, To: r.To, ZoneID: zoneID})
+		if err != nil {
+			return nil, err
+		}
+		for i := range m.Zones {
+			if m.Zones[i].ZoneID == zoneID {
+				return &m.Zones[i], nil
+			}
+		}
+		return &ZoneMetrics{ZoneID: zoneID}, nil
+	}
+	if now, err := zoneMetrics(cur); err != nil {
+		warn("zone metrics", err)
+	} else if then, err := zoneMetrics(base); err != nil {
+		warn("zone metrics", err)
+	} else {
+		report.Spikes = append(report.Spikes, a.spike("total requests", now.TotalRequests, then.TotalRequests)...)
+		report.Spikes = append(report.Spikes, a.spike("blocked requests", now.BlockedRequests, then.BlockedRequests)...)
+		report.Spikes = append(report.Spikes, a.spike("bot detection blocks", now.Breakdown.BotDetection, then.Breakdown.BotDetection)...)
+		report.Spikes = append(report.Spikes, a.spike("rate limit blocks", now.Breakdown.RateLimit, then.Breakdown.RateLimit)...)
+		report.Spikes = append(report.Spikes, a.spike("access list blocks", now.Breakdown.AccessList, then.Breakdown.AccessList)...)
+	}
+
+	if now, err := a.metrics.GetBotDetectionMetrics(ctx, zoneID, cur); err != nil {
+		warn("bot detection metrics", err)
+	} else if then, err := a.metrics.GetBotDetectionMetrics(ctx, zoneID, base); err != nil {
+		warn("bot detection metrics", err)
+	} else {
+		report.Spikes = append(report.Spikes, a.spike("bot requests", now.TotalBotRequests, then.TotalBotRequests)...)
+	}
+
+	if now, err := a.metrics.GetUploadScanningMetrics(ctx, zoneID, cur); err != nil {
+		warn("upload scanning metrics", err)
+	} else if then, err := a.metrics.GetUploadScanningMetrics(ctx, zoneID, base); err != nil {
+		warn("upload scanning metrics", err)
+	} else {
+		report.Spikes = append(report.Spikes, a.spike("malicious uploads", now.MaliciousFiles, then.MaliciousFiles)...)
+	}
+
+	// WAF rules are those seen in the zone's events; the metrics endpoint
+	// gives their exact trigger counts.
+	for _, id := range sortedKeys(events.rules) {
+		now, err := a.metrics.GetWAFRuleMetrics(ctx, id, cur)
+		if isNotFound(err) {
+			continue // not a WAF rule, e.g. a rate limit seen in the log
+		}
+		if err != nil {
+			warn("WAF rule "+id+" metrics", err)
+			continue
+		}
+		then, err := a.metrics.GetWAFRuleMetrics(ctx, id, base)
+		if err != nil {
+			warn("WAF rule "+id+" metrics", err)
+			continue
+		}
+		if ratio, above := a.aboveBaseline(now.TriggerCount, then.TriggerCount); above {
+			name := cmp.Or(now.RuleName, events.names[id])
+			report.Rules = append(report.Rules, RuleActivity{Kind: "waf", ID: id, Name: name, Count: now.TriggerCount, Baseline: then.TriggerCount, Ratio: ratio})
+		}
+	}
+
+	if now, err := a.metrics.GetAllRateLimitMetrics(ctx, cur); err != nil {
+		warn("rate limit metrics", err)
+	} else if then, err := a.metrics.GetAllRateLimitMetrics(ctx, base); err != nil {
+		warn("rate limit metrics", err)
+	} else {
+		before := make(map[string]int64, len(then.Items))
+		for _, r := range then.Items {
+			before[r.RuleID] = r.BlockedRequests
+		}
+		for _, r := range now.Items {
+			if ratio, above := a.aboveBaseline(r.BlockedRequests, before[r.RuleID]); above {
+				report.Rules = append(report.Rules, RuleActivity{Kind: "rate-limit", ID: r.RuleID, Name: r.RuleName, Count: r.BlockedRequests, Baseline: before[r.RuleID], Ratio: ratio})
+			}
+		}
+	}
+	slices.SortStableFunc(report.Rules, func(x, y RuleActivity) int { return cmp.Compare(y.Ratio, x.Ratio) })
+}
+
+func (a *IncidentAnalyzer) dateRange(w TimeWindow) *DateRangeOptions {
+	return &DateRangeOptions{From: w.From.UTC().Format(time.RFC3339), To: w.To.UTC().Format(time.RFC3339)}
+}
+
+// suggest proposes blocking IPs over the threshold and challenging ASNs
+// over it that span several addresses, merged with NormalizeAccessList.
+func (a *IncidentAnalyzer) suggest(t *eventTally) []AccessListEntry {
+	var entries []AccessListEntry
+	for _, ip := range sortedKeys(t.ips) {
+		if n := t.ips[ip]; n >= a.suggestAt {
+			entries = append(entries, AccessListEntry{Value: ip, Action: AccessListActionBlock, Comment: fmt.Sprintf("incident: %d events", n)})
+		}
+	}
+	for _, asn := range sortedKeys(t.asns) {
+		if n := t.asns[asn]; n >= a.suggestAt && len(t.asnIPs[asn]) >= analyzerASNMinIPs {
+			entries = append(entries, AccessListEntry{Value: asn, Action: AccessListActionChallenge,
+				Comment: fmt.Sprintf("incident: %d events from %d IPs", n, len(t.asnIPs[asn]))})
+		}
+	}
+	normalized, err := NormalizeAccessList(entries)
+	if err != nil {
+		return entries
+	}
+	return normalized
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// String renders the report as plain text for chat and terminals.
+func (r *IncidentReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Incident report for zone %s, %s to %s\n", r.ZoneID,
+		r.Window.From.UTC().Format(time.RFC3339), r.Window.To.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Events: %d (previous window: %d)\n", r.Events, r.BaselineEvents)
+
+	section := func(title string, n int) bool {
+		if n == 0 {
+			return false
+		}
+		fmt.Fprintf(&b, "\n%s:\n", title)
+		return true
+	}
+	if section("Spikes", len(r.Spikes)) {
+		for _, s := range r.Spikes {
+			fmt.Fprintf(&b, "  %s: %d (was %d, x%.1f)\n", s.Metric, s.Count, s.Baseline, s.Ratio)
+		}
+	}
+	if section("Rules above baseline", len(r.Rules)) {
+		for _, rule := range r.Rules {
+			fmt.Fprintf(&b, "  [%s] %s (%s): %d (was %d, x%.1f)\n", rule.Kind, rule.Name, rule.ID, rule.Count, rule.Baseline, rule.Ratio)
+		}
+	}
+	for _, list := range []struct {
+		title     string
+		offenders []Offender
+	}{
+		{"Top IPs", r.TopIPs},
+		{"Top ASNs", r.TopASNs},
+		{"Top paths", r.TopPaths},
+	} {
+		if section(list.title, len(list.offenders)) {
+			for _, o := range list.offenders {
+				fmt.Fprintf(&b, "  %s: %d (was %d)\n", o.Value, o.Count, o.Baseline)
+			}
+		}
+	}
+	if section("Suggested access list entries", len(r.Suggestions)) {
+		for _, e := range r.Suggestions {
+			fmt.Fprintf(&b, "  %s %s %s  # %s\n", e.Action, e.Type, e.Value, e.Comment)
+		}
+	}
+	if section("Warnings", len(r.Warnings)) {
+		for _, w := range r.Warnings {
+			fmt.Fprintf(&b, "  %s\n", w)
+		}
+	}
+	return b.String()
+}
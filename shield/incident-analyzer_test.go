@@ -0,0 +1,146 @@
+package shield_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func TestIncidentAnalyzer(t *testing.T) {
+	from := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	windowFrom := from.Format(time.RFC3339)
+
+	var current, baseline []shield.EventLog
+	for i := range 120 {
+		current = append(current, shield.EventLog{ID: fmt.Sprint("c", i), SourceIP: "203.0.113.7", Path: "/login", RuleID: "r1", RuleName: "Credential stuffing"})
+	}
+	for i := range 150 {
+		ip := fmt.Sprintf("198.51.100.%d", i%5+1)
+		current = append(current, shield.EventLog{ID: fmt.Sprint("a", i), SourceIP: ip, Path: "/wp-login.php", RuleID: "rl-1"})
+	}
+	for i := range 10 {
+		baseline = append(baseline, shield.EventLog{ID: fmt.Sprint("b", i), SourceIP: "192.0.2.1", Path: "/login", RuleID: "r1"})
+	}
+
+	respond := func(v any) *http.Response {
+		b, _ := json.Marshal(v)
+		return testutil.NewMockResponse(200, string(b))
+	}
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			q := req.URL.Query()
+			inWindow := q.Get("from") == windowFrom
+			switch req.URL.Path {
+			case "/shield/event-logs":
+				events := baseline
+				if inWindow {
+					events = current
+				}
+				offset, _ := strconv.Atoi(q.Get("offset"))
+				limit, _ := strconv.Atoi(q.Get("limit"))
+				events = events[min(offset, len(events)):min(offset+limit, len(events))]
+				return respond(shield.EventLogListResponse{Items: events, TotalCount: len(events)}), nil
+			case "/shield/metrics/overview-detailed":
+				m := shield.ZoneMetrics{ZoneID: "z1", TotalRequests: 10000, BlockedRequests: 40}
+				if inWindow {
+					m = shield.ZoneMetrics{ZoneID: "z1", TotalRequests: 12000, BlockedRequests: 900,
+						Breakdown: shield.MetricsBreakdown{RateLimit: 150}}
+				}
+				return respond(shield.MetricsOverviewDetailed{Zones: []shield.ZoneMetrics{m}}), nil
+			case "/shield/metrics/waf-rule/r1":
+				n := int64(10)
+				if inWindow {
+					n = 120
+				}
+				return respond(shield.WAFRuleMetrics{RuleID: "r1", RuleName: "Credential stuffing", TriggerCount: n}), nil
+			case "/shield/metrics/rate-limits":
+				n := int64(0)
+				if inWindow {
+					n = 150
+				}
+				return respond(shield.RateLimitMetricsList{Items: []shield.RateLimitMetricsSummary{
+					{RuleID: "rl-1", RuleName: "wp-login", BlockedRequests: n},
+					{RuleID: "rl-2", RuleName: "api", BlockedRequests: 20},
+				}}), nil
+			case "/shield/metrics/upload-scanning/z1":
+				return respond(shield.UploadScanningMetrics{}), nil
+			}
+			return testutil.NewMockResponse(404, `{"Message":"not found"}`), nil
+		},
+	}
+	client := shield.NewClient("key", shield.WithHTTPClient(mock))
+	analyzer := shield.NewIncidentAnalyzer(client.Metrics(), client.EventLogs(),
+		shield.WithAnalyzerTopN(3),
+		shield.WithASNLookup(func(addr netip.Addr) (uint32, bool) {
+			if netip.MustParsePrefix("198.51.100.0/24").Contains(addr) {
+				return 64500, true
+			}
+			return 0, false
+		}),
+	)
+
+	report, err := analyzer.Analyze(context.Background(), "z1", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Events != 270 || report.BaselineEvents != 10 || !report.Baseline.To.Equal(from) {
+		t.Errorf("unexpected windows: %+v", report)
+	}
+	if top := report.TopIPs[0]; top.Value != "203.0.113.7" || top.Count != 120 || len(report.TopIPs) != 3 {
+		t.Errorf("unexpected top IPs: %+v", report.TopIPs)
+	}
+	if top := report.TopASNs; len(top) != 1 || top[0].Value != "AS64500" || top[0].Count != 150 {
+		t.Errorf("unexpected top ASNs: %+v", top)
+	}
+	if top := report.TopPaths[0]; top.Value != "/wp-login.php" || top.Count != 150 {
+		t.Errorf("unexpected top paths: %+v", report.TopPaths)
+	}
+
+	if len(report.Rules) != 2 || report.Rules[0].ID != "rl-1" || report.Rules[1].ID != "r1" || report.Rules[1].Ratio != 12 {
+		t.Errorf("expected rate limit and WAF rule above baseline, got %+v", report.Rules)
+	}
+	spikes := map[string]bool{}
+	for _, s := range report.Spikes {
+		spikes[s.Metric] = true
+	}
+	if !spikes["events"] || !spikes["blocked requests"] || !spikes["rate limit blocks"] || spikes["total requests"] {
+		t.Errorf("unexpected spikes: %+v", report.Spikes)
+	}
+
+	var suggested []string
+	for _, e := range report.Suggestions {
+		suggested = append(suggested, e.Action+" "+e.Value)
+	}
+	if strings.Join(suggested, ",") != "Block 203.0.113.7,Challenge AS64500" {
+		t.Errorf("unexpected suggestions: %v", suggested)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "bot detection") {
+		t.Errorf("expected a bot detection warning, got %v", report.Warnings)
+	}
+
+	out := report.String()
+	for _, want := range []string{
+		"Incident report for zone z1, 2026-10-18T12:00:00Z",
+		"[waf] Credential stuffing (r1): 120 (was 10, x12.0)",
+		"203.0.113.7: 120 (was 0)",
+		"Block IP 203.0.113.7  # incident: 120 events",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if _, err := analyzer.Analyze(context.Background(), "z1", to, from); err == nil {
+		t.Error("expected error for an empty window")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // MetricsService provides methods for accessing Shield metrics.
@@ -28,6 +30,7 @@ func newMetricsService(client httpClient) MetricsService {
 
 // GetOverview returns overview metrics for all Shield zones.
 func (s *metricsService) GetOverview(ctx context.Context, opts *DateRangeOptions) (*MetricsOverview, error) {
+	ctx = middleware.WithOperation(ctx, "shield.MetricsService.GetOverview")
 	path := "/shield/metrics/overview" + buildDateRangeQuery(opts)
 	var resp MetricsOverview
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -38,6 +41,7 @@ func (s *metricsService) GetOverview(ctx context.Context, opts *DateRangeOptions
 
 // GetOverviewDetailed returns detailed overview metrics with breakdown.
 func (s *metricsService) GetOverviewDetailed(ctx context.Context, opts *MetricsDetailedOptions) (*MetricsOverviewDetailed, error) {
+	ctx = middleware.WithOperation(ctx, "shield.MetricsService.GetOverviewDetailed")
 	path := "/shield/metrics/overview-detailed" + buildMetricsDetailedQuery(opts)
 	var resp MetricsOverviewDetailed
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -48,6 +52,7 @@ func (s *metricsService) GetOverviewDetailed(ctx context.Context, opts *MetricsD
 
 // GetWAFRuleMetrics returns metrics for a specific WAF rule.
 func (s *metricsService) GetWAFRuleMetrics(ctx context.Context, ruleID string, opts *DateRangeOptions) (*WAFRuleMetrics, error) {
+	ctx = middleware.WithOperation(ctx, "shield.MetricsService.GetWAFRuleMetrics")
 	path := fmt.Sprintf("/shield/metrics/waf-rule/%s", ruleID) + buildDateRangeQuery(opts)
 	var resp WAFRuleMetrics
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -58,6 +63,7 @@ func (s *metricsService) GetWAFRuleMetrics(ctx context.Context, ruleID string, o
 
 // GetRateLimitMetrics returns metrics for a specific rate limit rule.
 func (s *metricsService) GetRateLimitMetrics(ctx context.Context, rateLimitID string, opts *DateRangeOptions) (*RateLimitMetrics, error) {
+	ctx = middleware.WithOperation(ctx, "shield.MetricsService.GetRateLimitMetrics")
 	path := fmt.Sprintf("/shield/metrics/rate-limit/%s", rateLimitID) + buildDateRangeQuery(opts)
 	var resp RateLimitMetrics
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -68,6 +74,7 @@ func (s *metricsService) GetRateLimitMetrics(ctx context.Context, rateLimitID st
 
 // GetAllRateLimitMetrics returns metrics for all rate limit rules.
 func (s *metricsService) GetAllRateLimitMetrics(ctx context.Context, opts *DateRangeOptions) (*RateLimitMetricsList, error) {
+	ctx = middleware.WithOperation(ctx, "shield.MetricsService.GetAllRateLimitMetrics")
 	path := "/shield/metrics/rate-limits" + buildDateRangeQuery(opts)
 	var resp RateLimitMetricsList
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -78,6 +85,7 @@ func (s *metricsService) GetAllRateLimitMetrics(ctx context.Context, opts *DateR
 
 // GetBotDetectionMetrics returns bot detection metrics for a zone.
 func (s *metricsService) GetBotDetectionMetrics(ctx context.Context, zoneID string, opts *DateRangeOptions) (*BotDetectionMetrics, error) {
+	ctx = middleware.WithOperation(ctx, "shield.MetricsService.GetBotDetectionMetrics")
 	path := fmt.Sprintf("/shield/metrics/bot-detection/%s", zoneID) + buildDateRangeQuery(opts)
 	var resp BotDetectionMetrics
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -88,6 +96,7 @@ func (s *metricsService) GetBotDetectionMetrics(ctx context.Context, zoneID stri
 
 // GetUploadScanningMetrics returns upload scanning metrics for a zone.
 func (s *metricsService) GetUploadScanningMetrics(ctx context.Context, zoneID string, opts *DateRangeOptions) (*UploadScanningMetrics, error) {
+	ctx = middleware.WithOperation(ctx, "shield.MetricsService.GetUploadScanningMetrics")
 	path := fmt.Sprintf("/shield/metrics/upload-scanning/%s", zoneID) + buildDateRangeQuery(opts)
 	var resp UploadScanningMetrics
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -3,6 +3,8 @@ package shield
 import (
 	"context"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // PromoService provides methods for promotional information.
@@ -20,6 +22,7 @@ func newPromoService(client httpClient) PromoService {
 
 // Get returns promotional information.
 func (s *promoService) Get(ctx context.Context) (*PromoInfo, error) {
+	ctx = middleware.WithOperation(ctx, "shield.PromoService.Get")
 	var resp PromoInfo
 	if err := s.client.do(ctx, http.MethodGet, "/shield/promo", nil, &resp); err != nil {
 		return nil, err
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // RateLimitService provides methods for managing rate limit rules.
@@ -25,6 +27,7 @@ func newRateLimitService(client httpClient) RateLimitService {
 
 // List returns all rate limit rules.
 func (s *rateLimitService) List(ctx context.Context) (*RateLimitListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.RateLimitService.List")
 	var resp RateLimitListResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/rate-limits", nil, &resp); err != nil {
 		return nil, err
@@ -34,6 +37,7 @@ func (s *rateLimitService) List(ctx context.Context) (*RateLimitListResponse, er
 
 // Create creates a new rate limit rule.
 func (s *rateLimitService) Create(ctx context.Context, req *CreateRateLimitRequest) (*RateLimit, error) {
+	ctx = middleware.WithOperation(ctx, "shield.RateLimitService.Create")
 	var rateLimit RateLimit
 	if err := s.client.do(ctx, http.MethodPost, "/shield/rate-limit", req, &rateLimit); err != nil {
 		return nil, err
@@ -43,6 +47,7 @@ func (s *rateLimitService) Create(ctx context.Context, req *CreateRateLimitReque
 
 // Get returns a specific rate limit rule by ID.
 func (s *rateLimitService) Get(ctx context.Context, rateLimitID string) (*RateLimit, error) {
+	ctx = middleware.WithOperation(ctx, "shield.RateLimitService.Get")
 	path := fmt.Sprintf("/shield/rate-limit/%s", rateLimitID)
 	var rateLimit RateLimit
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &rateLimit); err != nil {
@@ -53,6 +58,7 @@ func (s *rateLimitService) Get(ctx context.Context, rateLimitID string) (*RateLi
 
 // Update updates a rate limit rule (PATCH).
 func (s *rateLimitService) Update(ctx context.Context, rateLimitID string, req *UpdateRateLimitRequest) (*RateLimit, error) {
+	ctx = middleware.WithOperation(ctx, "shield.RateLimitService.Update")
 	path := fmt.Sprintf("/shield/rate-limit/%s", rateLimitID)
 	var rateLimit RateLimit
 	if err := s.client.do(ctx, http.MethodPatch, path, req, &rateLimit); err != nil {
@@ -63,6 +69,7 @@ func (s *rateLimitService) Update(ctx context.Context, rateLimitID string, req *
 
 // Delete deletes a rate limit rule.
 func (s *rateLimitService) Delete(ctx context.Context, rateLimitID string) error {
+	ctx = middleware.WithOperation(ctx, "shield.RateLimitService.Delete")
 	path := fmt.Sprintf("/shield/rate-limit/%s", rateLimitID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
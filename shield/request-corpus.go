@@ -0,0 +1,163 @@
+package shield
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SampleRequest is a recorded HTTP request to simulate against rules. In
+// JSON lines corpora each line is one request:
+//
+//	{"time":"2026-10-18T12:00:00Z","method":"GET","uri":"/admin","remoteAddr":"203.0.113.7","country":"DE","headers":{"User-Agent":"curl/8.0"},"expect":"Block"}
+//
+// Expect is optional; when set, the simulation reports requests whose
+// predicted action differs.
+type SampleRequest struct {
+	Time       time.Time         `json:"time"`
+	Method     string            `json:"method"`
+	URI        string            `json:"uri"` // path and query; a full URL is accepted
+	RemoteAddr string            `json:"remoteAddr,omitempty"`
+	Country    string            `json:"country,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Expect     string            `json:"expect,omitempty"`
+}
+
+// Header returns the value of the named header, ignoring case.
+func (r *SampleRequest) Header(name string) string {
+	if v, ok := r.Headers[http.CanonicalHeaderKey(name)]; ok {
+		return v
+	}
+	for k, v := range r.Headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalize reduces a full URL to its request URI and canonicalizes header
+// names.
+func (r *SampleRequest) normalize() {
+	if u, err := url.Parse(r.URI); err == nil && u.IsAbs() {
+		r.URI = u.RequestURI()
+	}
+	r.Method = strings.ToUpper(r.Method)
+	if len(r.Headers) > 0 {
+		headers := make(map[string]string, len(r.Headers))
+		for k, v := range r.Headers {
+			headers[http.CanonicalHeaderKey(k)] = v
+		}
+		r.Headers = headers
+	}
+}
+
+// ReadRequestLines reads a JSON lines corpus of SampleRequests. Blank lines
+// are skipped.
+func ReadRequestLines(r io.Reader) ([]SampleRequest, error) {
+	var requests []SampleRequest
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var req SampleRequest
+		if err := json.Unmarshal([]byte(text), &req); err != nil {
+			return nil, fmt.Errorf("shield: request corpus line %d: %w", line, err)
+		}
+		req.normalize()
+		requests = append(requests, req)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("shield: reading request corpus: %w", err)
+	}
+	return requests, nil
+}
+
+// harFile is the part of a HAR 1.2 archive the simulator reads.
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			StartedDateTime string `json:"startedDateTime"`
+			ClientIP        string `json:"_clientIP"`
+			Country         string `json:"_country"`
+			Request         struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					Text string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// ReadHAR reads the requests of a HAR archive, as exported by browsers and
+// proxies. HAR does not record the client, so the address is taken from
+// the custom _clientIP entry field or else the first X-Forwarded-For hop,
+// and the country from the custom _country field.
+func ReadHAR(r io.Reader) ([]SampleRequest, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("shield: reading HAR: %w", err)
+	}
+	requests := make([]SampleRequest, 0, len(har.Log.Entries))
+	for i, e := range har.Log.Entries {
+		req := SampleRequest{
+			Method:     e.Request.Method,
+			URI:        e.Request.URL,
+			RemoteAddr: e.ClientIP,
+			Country:    e.Country,
+		}
+		if e.StartedDateTime != "" {
+			t, err := time.Parse(time.RFC3339Nano, e.StartedDateTime)
+			if err != nil {
+				return nil, fmt.Errorf("shield: HAR entry %d: %w", i, err)
+			}
+			req.Time = t
+		}
+		for _, h := range e.Request.Headers {
+			if req.Headers == nil {
+				req.Headers = make(map[string]string)
+			}
+			req.Headers[h.Name] = h.Value
+		}
+		if e.Request.PostData != nil {
+			req.Body = e.Request.PostData.Text
+		}
+		req.normalize()
+		if req.RemoteAddr == "" {
+			req.RemoteAddr = strings.TrimSpace(strings.Split(req.Header("X-Forwarded-For"), ",")[0])
+		}
+		requests = append(requests, req)
+	}
+	return requests, nil
+}
+
+// LoadRequestCorpus reads a corpus file: ReadHAR for .har files and
+// ReadRequestLines for anything else.
+func LoadRequestCorpus(path string) ([]SampleRequest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(path), ".har") {
+		return ReadHAR(f)
+	}
+	return ReadRequestLines(f)
+}
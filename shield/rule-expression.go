@@ -0,0 +1,315 @@
+package shield
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Variable is a request attribute that a custom rule condition matches.
+type Variable string
+
+// Variables of the pattern syntax.
+const (
+	VarRequestURI     Variable = "REQUEST_URI"
+	VarRequestHeaders Variable = "REQUEST_HEADERS" // needs a header name selector
+	VarRemoteAddr     Variable = "REMOTE_ADDR"     // values may be IPs or CIDR prefixes
+	VarCountry        Variable = "COUNTRY"         // ISO 3166-1 alpha-2 code
+	VarRequestMethod  Variable = "REQUEST_METHOD"
+	VarRequestBody    Variable = "REQUEST_BODY"
+)
+
+// Operator compares a variable with the values of a condition.
+type Operator string
+
+// Operators of the pattern syntax.
+const (
+	OpEquals   Operator = "eq"
+	OpContains Operator = "contains"
+	OpRegex    Operator = "rx"
+	OpIn       Operator = "in"
+)
+
+// RuleAction is the action a custom rule takes when its pattern matches.
+type RuleAction string
+
+// Custom rule actions.
+const (
+	RuleActionBlock     RuleAction = "Block"
+	RuleActionAllow     RuleAction = "Allow"
+	RuleActionLog       RuleAction = "Log"
+	RuleActionChallenge RuleAction = "Challenge"
+)
+
+var (
+	knownVariables = []Variable{VarRequestURI, VarRequestHeaders, VarRemoteAddr, VarCountry, VarRequestMethod, VarRequestBody}
+	knownOperators = []Operator{OpEquals, OpContains, OpRegex, OpIn}
+	knownActions   = []RuleAction{RuleActionBlock, RuleActionAllow, RuleActionLog, RuleActionChallenge}
+)
+
+// Expr is a custom rule pattern: a Condition, or conditions combined with
+// And, Or and Not. Its String method renders the pattern in a syntax defined
+// by this package, which ParseExpr reads back, for example:
+//
+//	REQUEST_URI contains "/admin" and (REMOTE_ADDR in ["10.0.0.0/8"] or COUNTRY eq "CN")
+//
+// The syntax is local to the SDK. The API documents no pattern grammar, and
+// rules created in the dashboard hold patterns such as "/admin/*" that
+// ParseExpr rejects. Check how the API evaluates a rendered pattern before
+// relying on it to block traffic.
+type Expr interface {
+	String() string
+	validate(errs *[]error)
+}
+
+// Field is a variable, optionally narrowed by a selector such as a header
+// name, on which conditions are built.
+type Field struct {
+	Variable Variable
+	Selector string
+}
+
+// URI matches the request path and query string.
+func URI() Field { return Field{Variable: VarRequestURI} }
+
+// Header matches the value of the named request header.
+func Header(name string) Field { return Field{Variable: VarRequestHeaders, Selector: name} }
+
+// UserAgent matches the User-Agent request header.
+func UserAgent() Field { return Header("User-Agent") }
+
+// IP matches the client IP address against IPs or CIDR prefixes.
+func IP() Field { return Field{Variable: VarRemoteAddr} }
+
+// Country matches the two-letter country code of the client.
+func Country() Field { return Field{Variable: VarCountry} }
+
+// Method matches the request method.
+func Method() Field { return Field{Variable: VarRequestMethod} }
+
+// Body matches the request body.
+func Body() Field { return Field{Variable: VarRequestBody} }
+
+// Equals matches when the field equals value.
+func (f Field) Equals(value string) Condition { return f.cond(OpEquals, value) }
+
+// Contains matches when the field contains value.
+func (f Field) Contains(value string) Condition { return f.cond(OpContains, value) }
+
+// Matches matches when the field matches the regular expression pattern.
+func (f Field) Matches(pattern string) Condition { return f.cond(OpRegex, pattern) }
+
+// In matches when the field equals any of values, or for IP lies in any of
+// them.
+func (f Field) In(values ...string) Condition { return f.cond(OpIn, values...) }
+
+func (f Field) cond(op Operator, values ...string) Condition {
+	return Condition{Field: f, Operator: op, Values: values}
+}
+
+func (f Field) String() string {
+	if f.Selector == "" {
+		return string(f.Variable)
+	}
+	return string(f.Variable) + ":" + f.Selector
+}
+
+// Condition compares a field with one value, or a list of values for OpIn.
+type Condition struct {
+	Field
+	Operator Operator
+	Values   []string
+}
+
+func (c Condition) String() string {
+	if c.Operator == OpIn {
+		quoted := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			quoted[i] = strconv.Quote(v)
+		}
+		return fmt.Sprintf("%s in [%s]", c.Field, strings.Join(quoted, ", "))
+	}
+	value := ""
+	if len(c.Values) > 0 {
+		value = c.Values[0]
+	}
+	return fmt.Sprintf("%s %s %s", c.Field, c.Operator, strconv.Quote(value))
+}
+
+func (c Condition) validate(errs *[]error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, fmt.Errorf("%s: %s", c, fmt.Sprintf(format, args...)))
+	}
+	if !slices.Contains(knownVariables, c.Variable) {
+		fail("unknown variable %q", c.Variable)
+	}
+	if (c.Variable == VarRequestHeaders) != (c.Selector != "") {
+		if c.Selector == "" {
+			fail("%s needs a header name", c.Variable)
+		} else {
+			fail("%s does not take a selector", c.Variable)
+		}
+	}
+	if c.Selector != "" && !isToken(c.Selector) {
+		fail("invalid header name %q", c.Selector)
+	}
+	switch c.Operator {
+	case OpIn:
+		if len(c.Values) == 0 {
+			fail("in needs at least one value")
+		}
+	case OpEquals, OpContains, OpRegex:
+		if len(c.Values) != 1 {
+			fail("%s needs exactly one value", c.Operator)
+		}
+	default:
+		fail("unknown operator %q", c.Operator)
+	}
+	for _, v := range c.Values {
+		switch {
+		case c.Operator == OpRegex:
+			if _, err := regexp.Compile(v); err != nil {
+				fail("invalid regex: %v", err)
+			}
+		case c.Variable == VarRemoteAddr && c.Operator != OpContains:
+			if _, err := netip.ParseAddr(v); err != nil {
+				if _, err := netip.ParsePrefix(v); err != nil {
+					fail("%q is not an IP address or CIDR prefix", v)
+				}
+			}
+		case c.Variable == VarCountry && c.Operator != OpContains:
+			if len(v) != 2 || strings.ToUpper(v) != v || !isToken(v) {
+				fail("%q is not a two-letter uppercase country code", v)
+			}
+		case c.Variable == VarRequestMethod && c.Operator != OpContains:
+			if v == "" || strings.ToUpper(v) != v || !isToken(v) {
+				fail("%q is not an uppercase request method", v)
+			}
+		}
+	}
+}
+
+// Group combines expressions with "and" or "or".
+type Group struct {
+	Or    bool
+	Exprs []Expr
+}
+
+// And matches when all exprs match.
+func And(exprs ...Expr) Group { return Group{Exprs: exprs} }
+
+// Or matches when any of exprs matches.
+func Or(exprs ...Expr) Group { return Group{Or: true, Exprs: exprs} }
+
+func (g Group) String() string {
+	op := " and "
+	if g.Or {
+		op = " or "
+	}
+	parts := make([]string, len(g.Exprs))
+	for i, e := range g.Exprs {
+		parts[i] = e.String()
+		if inner, ok := e.(Group); ok && inner.Or != g.Or && len(inner.Exprs) > 1 {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, op)
+}
+
+func (g Group) validate(errs *[]error) {
+	if len(g.Exprs) == 0 {
+		*errs = append(*errs, errors.New("empty group"))
+	}
+	for _, e := range g.Exprs {
+		if e == nil {
+			*errs = append(*errs, errors.New("nil expression in group"))
+			continue
+		}
+		e.validate(errs)
+	}
+}
+
+// Negation matches when Expr does not.
+type Negation struct {
+	Expr Expr
+}
+
+// Not matches when expr does not.
+func Not(expr Expr) Negation { return Negation{Expr: expr} }
+
+func (n Negation) String() string {
+	if _, ok := n.Expr.(Condition); ok {
+		return "not " + n.Expr.String()
+	}
+	return "not (" + n.Expr.String() + ")"
+}
+
+func (n Negation) validate(errs *[]error) {
+	if n.Expr == nil {
+		*errs = append(*errs, errors.New("not without an expression"))
+		return
+	}
+	n.Expr.validate(errs)
+}
+
+// ValidateExpr checks expr locally: variables, operators, selectors and the
+// number of values, that regexes compile, and that IPs, CIDR prefixes,
+// country codes and methods are well formed. When enums is not nil and lists
+// operators or variables, those of expr must be among them. All problems are
+// reported together.
+func ValidateExpr(expr Expr, enums *WAFEnums) error {
+	if expr == nil {
+		return errors.New("shield: empty rule pattern")
+	}
+	var errs []error
+	expr.validate(&errs)
+	if enums != nil {
+		walkConditions(expr, func(c Condition) {
+			if len(enums.Variables) > 0 && !containsFold(enums.Variables, string(c.Variable)) {
+				errs = append(errs, fmt.Errorf("%s: variable %s is not among the WAF enums", c, c.Variable))
+			}
+			if len(enums.Operators) > 0 && !containsFold(enums.Operators, string(c.Operator)) {
+				errs = append(errs, fmt.Errorf("%s: operator %s is not among the WAF enums", c, c.Operator))
+			}
+		})
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("shield: invalid rule pattern: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// walkConditions calls fn for every condition in expr.
+func walkConditions(expr Expr, fn func(Condition)) {
+	switch e := expr.(type) {
+	case Condition:
+		fn(e)
+	case Group:
+		for _, inner := range e.Exprs {
+			walkConditions(inner, fn)
+		}
+	case Negation:
+		walkConditions(e.Expr, fn)
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// isToken reports whether s is an HTTP token, as used for header names.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return false
+		}
+	}
+	return true
+}
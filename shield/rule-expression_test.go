@@ -0,0 +1,139 @@
+package shield_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func TestExprStringAndParse(t *testing.T) {
+	expr := shield.And(
+		shield.URI().Contains("/admin"),
+		shield.Or(
+			shield.IP().In("10.0.0.0/8", "2001:db8::1"),
+			shield.Country().Equals("CN"),
+		),
+		shield.Not(shield.UserAgent().Matches(`(?i)curl/\d+`)),
+		shield.Method().In("POST", "PUT"),
+		shield.Not(shield.Or(shield.Body().Contains(`"quoted"`), shield.Header("X-Debug").Equals("1"))),
+	)
+	want := `REQUEST_URI contains "/admin" and (REMOTE_ADDR in ["10.0.0.0/8", "2001:db8::1"] or COUNTRY eq "CN")` +
+		` and not REQUEST_HEADERS:User-Agent rx "(?i)curl/\\d+" and REQUEST_METHOD in ["POST", "PUT"]` +
+		` and not (REQUEST_BODY contains "\"quoted\"" or REQUEST_HEADERS:X-Debug eq "1")`
+	if got := expr.String(); got != want {
+		t.Fatalf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if err := shield.ValidateExpr(expr, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := shield.ParseExpr(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, shield.Expr(expr)) {
+		t.Errorf("ParseExpr = %#v", parsed)
+	}
+
+	// "and" binds tighter than "or", and keywords are case-insensitive.
+	parsed, err = shield.ParseExpr(`request_uri eq "/a" OR REQUEST_URI eq "/b" AND COUNTRY eq "DE"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.String(); got != `REQUEST_URI eq "/a" or (REQUEST_URI eq "/b" and COUNTRY eq "DE")` {
+		t.Errorf("precedence: %s", got)
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	for _, pattern := range []string{
+		``,
+		`REQUEST_URI`,
+		`REQUEST_URI eq`,
+		`REQUEST_URI eq /admin`,
+		`REQUEST_URI in ["a" "b"]`,
+		`(REQUEST_URI eq "a"`,
+		`REQUEST_URI eq "a" COUNTRY eq "DE"`,
+	} {
+		_, err := shield.ParseExpr(pattern)
+		var syntaxErr *shield.PatternSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseExpr(%q) error = %v, want PatternSyntaxError", pattern, err)
+		}
+	}
+}
+
+func TestValidateExpr(t *testing.T) {
+	bad := shield.And(
+		shield.IP().Equals("10.0.0.300"),
+		shield.Country().In("germany"),
+		shield.URI().Matches("(unclosed"),
+		shield.Field{Variable: shield.VarRequestHeaders}.Equals("x"),
+		shield.Method().In(),
+		shield.Condition{Field: shield.URI(), Operator: "startswith", Values: []string{"/"}},
+	)
+	err := shield.ValidateExpr(bad, nil)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"10.0.0.300", "germany", "invalid regex", "needs a header name", "at least one value", "unknown operator"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+
+	enums := &shield.WAFEnums{Operators: []string{"eq"}, Variables: []string{"REQUEST_URI"}}
+	err = shield.ValidateExpr(shield.Or(shield.URI().Equals("/"), shield.Country().In("DE")), enums)
+	if err == nil || !strings.Contains(err.Error(), "variable COUNTRY is not among") || !strings.Contains(err.Error(), "operator in is not among") {
+		t.Errorf("enum validation: %v", err)
+	}
+}
+
+func TestRuleSpecCreatesRule(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+	client := shield.NewClient("key", shield.WithBaseURL(srv.URL))
+	waf := client.WAF()
+	zone, err := client.Zones().Create(ctx, &shield.CreateZoneRequest{Name: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enums, err := waf.GetEnums(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &shield.RuleSpec{
+		Name:     "admin",
+		Pattern:  shield.And(shield.URI().Contains("/admin"), shield.Not(shield.IP().In("203.0.113.0/24"))),
+		Action:   shield.RuleActionBlock,
+		IsActive: true,
+	}
+	if err := spec.Validate(enums); err != nil {
+		t.Fatal(err)
+	}
+	rule, err := waf.CreateCustomRule(ctx, spec.CreateRequest(zone.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Pattern != spec.Pattern.String() || rule.Action != "Block" {
+		t.Errorf("rule = %+v", rule)
+	}
+	got, err := shield.ParseCustomRule(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, spec) {
+		t.Errorf("ParseCustomRule = %+v, want %+v", got, spec)
+	}
+
+	if err := (&shield.RuleSpec{Name: "x", Pattern: shield.URI().Equals("/"), Action: "Drop"}).Validate(enums); err == nil {
+		t.Error("expected unsupported action to fail")
+	}
+}
@@ -0,0 +1,195 @@
+package shield
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PatternSyntaxError reports where a custom rule pattern could not be
+// parsed.
+type PatternSyntaxError struct {
+	Pattern string
+	Offset  int // byte offset into Pattern
+	Msg     string
+}
+
+func (e *PatternSyntaxError) Error() string {
+	return fmt.Sprintf("shield: rule pattern at offset %d: %s", e.Offset, e.Msg)
+}
+
+// ParseExpr parses a custom rule pattern in the SDK syntax produced by
+// Expr.String. "and" binds tighter than "or"; keywords are case-insensitive.
+// Patterns in any other format, such as "/admin/*", fail with a
+// PatternSyntaxError. ParseExpr only checks the syntax; use ValidateExpr to
+// check the result.
+func ParseExpr(pattern string) (Expr, error) {
+	p := &exprParser{src: pattern}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return expr, nil
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return &PatternSyntaxError{Pattern: p.src, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// peekWord returns the identifier at the current position without
+// consuming it.
+func (p *exprParser) peekWord() string {
+	p.skipSpace()
+	end := p.pos
+	for end < len(p.src) && (isIdentByte(p.src[end])) {
+		end++
+	}
+	return p.src[p.pos:end]
+}
+
+func (p *exprParser) keyword(kw string) bool {
+	if w := p.peekWord(); strings.EqualFold(w, kw) {
+		p.pos += len(w)
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) punct(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (Expr, error) {
+	return p.parseGroup(true, "or", p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (Expr, error) {
+	return p.parseGroup(false, "and", p.parseUnary)
+}
+
+func (p *exprParser) parseGroup(or bool, kw string, next func() (Expr, error)) (Expr, error) {
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expr{first}
+	for p.keyword(kw) {
+		e, err := next()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return Group{Or: or, Exprs: exprs}, nil
+}
+
+func (p *exprParser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Negation{Expr: e}, nil
+	}
+	if p.punct('(') {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.punct(')') {
+			return nil, p.errorf("expected )")
+		}
+		return e, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *exprParser) parseCondition() (Expr, error) {
+	name := p.peekWord()
+	if name == "" {
+		return nil, p.errorf("expected a variable")
+	}
+	p.pos += len(name)
+	field := Field{Variable: Variable(strings.ToUpper(name))}
+	if p.pos < len(p.src) && p.src[p.pos] == ':' {
+		p.pos++
+		start := p.pos
+		for p.pos < len(p.src) && !unicode.IsSpace(rune(p.src[p.pos])) {
+			p.pos++
+		}
+		if field.Selector = p.src[start:p.pos]; field.Selector == "" {
+			return nil, p.errorf("expected a selector after %s:", name)
+		}
+	}
+
+	op := p.peekWord()
+	if op == "" {
+		return nil, p.errorf("expected an operator after %s", field)
+	}
+	p.pos += len(op)
+	cond := Condition{Field: field, Operator: Operator(strings.ToLower(op))}
+	if cond.Operator != OpIn {
+		v, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = []string{v}
+		return cond, nil
+	}
+
+	if !p.punct('[') {
+		return nil, p.errorf("expected [ after in")
+	}
+	for !p.punct(']') {
+		if len(cond.Values) > 0 && !p.punct(',') {
+			return nil, p.errorf("expected , or ]")
+		}
+		v, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = append(cond.Values, v)
+	}
+	return cond, nil
+}
+
+func (p *exprParser) parseString() (string, error) {
+	p.skipSpace()
+	quoted, err := strconv.QuotedPrefix(p.src[p.pos:])
+	if err != nil || quoted[0] != '"' {
+		return "", p.errorf("expected a double-quoted string")
+	}
+	v, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", p.errorf("invalid string: %v", err)
+	}
+	p.pos += len(quoted)
+	return v, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
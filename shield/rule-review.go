@@ -0,0 +1,332 @@
+package shield
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultReviewConcurrency = 4
+
+// ReviewOutcome is what a review policy decided for a triggered rule.
+type ReviewOutcome string
+
+const (
+	ReviewAccepted ReviewOutcome = "accepted" // submit the AI recommendation
+	ReviewRejected ReviewOutcome = "rejected" // submit a different action
+	ReviewSkipped  ReviewOutcome = "skipped"  // leave for a human reviewer
+)
+
+// ReviewDecision is a policy's verdict on one triggered rule. Action is the
+// review action submitted; for accepted decisions it defaults to the AI
+// recommendation. Reason is sent as the review comment and written to the
+// audit log.
+type ReviewDecision struct {
+	Outcome ReviewOutcome `json:"outcome"`
+	Action  string        `json:"action,omitempty"`
+	Reason  string        `json:"reason,omitempty"`
+}
+
+// ReviewCandidate is a triggered rule with the AI recommendation for it, or
+// a nil Recommendation when the API had none.
+type ReviewCandidate struct {
+	Rule           TriggeredRule
+	Recommendation *AIRecommendation
+}
+
+// ReviewPolicy decides a triggered rule. Policies must be safe for
+// concurrent use.
+type ReviewPolicy func(c ReviewCandidate) ReviewDecision
+
+// AutoAcceptBelow accepts the AI recommendation for rules triggered fewer
+// than maxTriggers times when the recommendation's confidence is at least
+// minConfidence, and skips everything else.
+func AutoAcceptBelow(maxTriggers int64, minConfidence float64) ReviewPolicy {
+	return func(c ReviewCandidate) ReviewDecision {
+		switch {
+		case c.Recommendation == nil || c.Recommendation.Recommendation == "":
+			return ReviewDecision{Outcome: ReviewSkipped, Reason: "no AI recommendation"}
+		case c.Rule.TriggerCount >= maxTriggers:
+			return ReviewDecision{Outcome: ReviewSkipped,
+				Reason: fmt.Sprintf("triggered %d times, auto-accept limit is %d", c.Rule.TriggerCount, maxTriggers)}
+		case c.Recommendation.Confidence < minConfidence:
+			return ReviewDecision{Outcome: ReviewSkipped,
+				Reason: fmt.Sprintf("confidence %.2f below %.2f", c.Recommendation.Confidence, minConfidence)}
+		}
+		return ReviewDecision{Outcome: ReviewAccepted,
+			Reason: fmt.Sprintf("auto-accepted: %d triggers, confidence %.2f", c.Rule.TriggerCount, c.Recommendation.Confidence)}
+	}
+}
+
+// RejectRecommendation submits action instead of the AI recommendation
+// when it recommends one of the given actions, and skips everything else.
+// Use it to veto recommendations the team never wants applied blindly.
+func RejectRecommendation(action string, recommendations ...string) ReviewPolicy {
+	return func(c ReviewCandidate) ReviewDecision {
+		if c.Recommendation != nil && containsFold(recommendations, c.Recommendation.Recommendation) {
+			return ReviewDecision{Outcome: ReviewRejected, Action: action,
+				Reason: "recommendation " + c.Recommendation.Recommendation + " is not applied automatically"}
+		}
+		return ReviewDecision{Outcome: ReviewSkipped}
+	}
+}
+
+// FirstDecision combines policies: the first one that does not skip
+// decides. When all skip, the reasons are joined.
+func FirstDecision(policies ...ReviewPolicy) ReviewPolicy {
+	return func(c ReviewCandidate) ReviewDecision {
+		var reasons []string
+		for _, p := range policies {
+			d := p(c)
+			if d.Outcome != ReviewSkipped {
+				return d
+			}
+			if d.Reason != "" {
+				reasons = append(reasons, d.Reason)
+			}
+		}
+		return ReviewDecision{Outcome: ReviewSkipped, Reason: strings.Join(reasons, "; ")}
+	}
+}
+
+// ReviewResult records the decision on one triggered rule. It is also the
+// audit log record. A decision that is submitted is audited twice: first
+// with Pending set, before the review is sent, and then with its outcome.
+//
+// Error is why the rule was not reviewed; AuditError is why its audit
+// record could not be written, and is not itself logged.
+type ReviewResult struct {
+	Time           time.Time         `json:"time"`
+	Rule           TriggeredRule     `json:"rule"`
+	Recommendation *AIRecommendation `json:"recommendation,omitempty"`
+	Decision       ReviewDecision    `json:"decision"`
+	Pending        bool              `json:"pending,omitempty"`
+	ReviewID       string            `json:"reviewId,omitempty"`
+	DryRun         bool              `json:"dryRun,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	AuditError     string            `json:"-"`
+}
+
+// ReviewSummary is the result of a review run.
+type ReviewSummary struct {
+	Results     []ReviewResult `json:"results"`
+	Accepted    int            `json:"accepted"`
+	Rejected    int            `json:"rejected"`
+	Skipped     int            `json:"skipped"`
+	Failed      int            `json:"failed"`
+	AuditFailed int            `json:"auditFailed"` // results not audited, whatever their outcome
+}
+
+// RuleReviewer works through the WAF rules awaiting review: it lists the
+// triggered rules, fetches the AI recommendation for each, lets a policy
+// decide, submits the accepted and rejected reviews, and writes every
+// decision to an audit log.
+//
+//	audit, err := shield.OpenReviewAuditLog("/var/log/waf-review.jsonl")
+//	defer audit.Close()
+//	r := shield.NewRuleReviewer(client.WAF(), shield.AutoAcceptBelow(50, 0.9),
+//		shield.WithReviewAuditLog(audit))
+//	summary, err := r.Run(ctx)
+type RuleReviewer struct {
+	waf         WAFService
+	policy      ReviewPolicy
+	audit       io.Writer
+	auditMu     sync.Mutex
+	concurrency int
+	dryRun      bool
+	now         func() time.Time
+}
+
+// ReviewOption configures a RuleReviewer.
+type ReviewOption func(*RuleReviewer)
+
+// WithReviewAuditLog writes one JSON ReviewResult per line to w for every
+// decision, including skipped rules and failed submissions. A review is
+// only submitted once its decision has been written.
+func WithReviewAuditLog(w io.Writer) ReviewOption {
+	return func(r *RuleReviewer) {
+		r.audit = w
+	}
+}
+
+// WithReviewConcurrency limits how many recommendations are fetched and
+// reviews submitted at once. The default is 4.
+func WithReviewConcurrency(n int) ReviewOption {
+	return func(r *RuleReviewer) {
+		r.concurrency = n
+	}
+}
+
+// WithReviewDryRun decides and audits every rule without submitting reviews.
+func WithReviewDryRun(dryRun bool) ReviewOption {
+	return func(r *RuleReviewer) {
+		r.dryRun = dryRun
+	}
+}
+
+// WithReviewClock sets the function used to timestamp audit records.
+func WithReviewClock(now func() time.Time) ReviewOption {
+	return func(r *RuleReviewer) {
+		r.now = now
+	}
+}
+
+// NewRuleReviewer creates a reviewer deciding with policy.
+func NewRuleReviewer(waf WAFService, policy ReviewPolicy, opts ...ReviewOption) *RuleReviewer {
+	r := &RuleReviewer{
+		waf:         waf,
+		policy:      policy,
+		concurrency: defaultReviewConcurrency,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.concurrency < 1 {
+		r.concurrency = 1
+	}
+	return r
+}
+
+// OpenReviewAuditLog opens path for appending audit records, creating it
+// readable only by the owner.
+func OpenReviewAuditLog(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+}
+
+// Run reviews every triggered rule. Failures to fetch a recommendation,
+// submit a review or write the audit log are recorded per rule and returned
+// joined as the error; the other rules are still reviewed.
+func (r *RuleReviewer) Run(ctx context.Context) (*ReviewSummary, error) {
+	triggered, err := r.waf.GetTriggeredRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("shield: listing triggered rules: %w", err)
+	}
+
+	results := make([]ReviewResult, len(triggered.Items))
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	for i, rule := range triggered.Items {
+		results[i] = ReviewResult{Rule: rule, DryRun: r.dryRun}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Error = ctx.Err().Error()
+			r.record(&results[i])
+			continue
+		}
+		wg.Add(1)
+		go func(res *ReviewResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.review(ctx, res)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	summary := &ReviewSummary{Results: results}
+	var errs []error
+	for _, res := range results {
+		if res.AuditError != "" {
+			summary.AuditFailed++
+			errs = append(errs, fmt.Errorf("shield: auditing rule %s: %s", res.Rule.RuleID, res.AuditError))
+		}
+		switch {
+		case res.Error != "":
+			summary.Failed++
+			errs = append(errs, fmt.Errorf("shield: reviewing rule %s: %s", res.Rule.RuleID, res.Error))
+		case res.Decision.Outcome == ReviewAccepted:
+			summary.Accepted++
+		case res.Decision.Outcome == ReviewRejected:
+			summary.Rejected++
+		default:
+			summary.Skipped++
+		}
+	}
+	return summary, errors.Join(errs...)
+}
+
+// review decides one rule and submits the decision, auditing it before and
+// after. A decision that cannot be audited is not submitted.
+func (r *RuleReviewer) review(ctx context.Context, res *ReviewResult) {
+	if !r.decide(ctx, res) {
+		r.record(res)
+		return
+	}
+	res.Pending = true
+	ok := r.record(res)
+	res.Pending = false
+	if !ok {
+		res.Error = "not submitted: the decision could not be audited"
+		return
+	}
+
+	review, err := r.waf.SubmitTriggeredRuleReview(ctx, &TriggeredRuleReviewRequest{
+		RuleID:  res.Rule.RuleID,
+		Action:  res.Decision.Action,
+		Comment: res.Decision.Reason,
+	})
+	if err != nil {
+		res.Error = "submitting review: " + err.Error()
+	} else {
+		res.ReviewID = review.ReviewID
+	}
+	r.record(res)
+}
+
+// decide fetches the recommendation and applies the policy. It reports
+// whether the decision should be submitted.
+func (r *RuleReviewer) decide(ctx context.Context, res *ReviewResult) bool {
+	recs, err := r.waf.GetAIRecommendation(ctx, res.Rule.RuleID)
+	if err != nil {
+		res.Error = "fetching AI recommendation: " + err.Error()
+		return false
+	}
+	for i, rec := range recs.Recommendations {
+		if rec.RuleID == res.Rule.RuleID || rec.RuleID == "" && res.Recommendation == nil {
+			res.Recommendation = &recs.Recommendations[i]
+		}
+	}
+
+	res.Decision = r.policy(ReviewCandidate{Rule: res.Rule, Recommendation: res.Recommendation})
+	if res.Decision.Outcome == "" {
+		res.Decision.Outcome = ReviewSkipped
+	}
+	if res.Decision.Outcome == ReviewAccepted && res.Decision.Action == "" && res.Recommendation != nil {
+		res.Decision.Action = res.Recommendation.Recommendation
+	}
+	if res.Decision.Outcome == ReviewSkipped {
+		return false
+	}
+	if res.Decision.Action == "" {
+		res.Error = "policy decided " + string(res.Decision.Outcome) + " without an action"
+		return false
+	}
+	return !r.dryRun
+}
+
+// record appends the result to the audit log and reports whether it was
+// written. A failed write is kept in AuditError.
+func (r *RuleReviewer) record(res *ReviewResult) bool {
+	res.Time = r.now().UTC()
+	if r.audit == nil {
+		return true
+	}
+	b, err := json.Marshal(res)
+	if err == nil {
+		r.auditMu.Lock()
+		_, err = r.audit.Write(append(b, '\n'))
+		r.auditMu.Unlock()
+	}
+	if err != nil {
+		res.AuditError = "writing audit log: " + err.Error()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package shield_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func TestRuleReviewer(t *testing.T) {
+	rules := []shield.TriggeredRule{
+		{RuleID: "r1", RuleName: "SQLi probe", TriggerCount: 3},
+		{RuleID: "r2", RuleName: "Scanner", TriggerCount: 500},
+		{RuleID: "r3", RuleName: "Admin path", TriggerCount: 8},
+		{RuleID: "r4", RuleName: "Broken", TriggerCount: 1},
+		{RuleID: "r5", RuleName: "Unsure", TriggerCount: 2},
+	}
+	recommendations := map[string]shield.AIRecommendation{
+		"r1": {RuleID: "r1", Recommendation: "Whitelist", Confidence: 0.95},
+		"r2": {RuleID: "r2", Recommendation: "Whitelist", Confidence: 0.99},
+		"r3": {RuleID: "r3", Recommendation: "Disable", Confidence: 0.97},
+		"r5": {RuleID: "r5", Recommendation: "Whitelist", Confidence: 0.4},
+	}
+
+	var mu sync.Mutex
+	var submitted []shield.TriggeredRuleReviewRequest
+	respond := func(v any) *http.Response {
+		b, _ := json.Marshal(v)
+		return testutil.NewMockResponse(200, string(b))
+	}
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			switch {
+			case req.URL.Path == "/shield/waf/rules/review-triggered" && req.Method == http.MethodGet:
+				return respond(shield.TriggeredRulesResponse{Items: rules, TotalCount: len(rules)}), nil
+			case req.URL.Path == "/shield/waf/rules/review-triggered" && req.Method == http.MethodPost:
+				var r shield.TriggeredRuleReviewRequest
+				_ = json.NewDecoder(req.Body).Decode(&r)
+				mu.Lock()
+				submitted = append(submitted, r)
+				mu.Unlock()
+				return respond(shield.TriggeredRuleReview{ReviewID: "rev-" + r.RuleID, RuleID: r.RuleID, Action: r.Action}), nil
+			case req.URL.Path == "/shield/waf/rules/review-triggered/ai-recommendation":
+				id := req.URL.Query().Get("ruleId")
+				if id == "r4" {
+					return testutil.NewMockResponse(500, `{"Message":"model unavailable"}`), nil
+				}
+				resp := shield.AIRecommendationResponse{}
+				if rec, ok := recommendations[id]; ok {
+					resp.Recommendations = append(resp.Recommendations, rec)
+				}
+				return respond(resp), nil
+			}
+			return testutil.NewMockResponse(404, `{"Message":"not found"}`), nil
+		},
+	}
+	client := shield.NewClient("key", shield.WithHTTPClient(mock))
+
+	policy := shield.FirstDecision(
+		shield.RejectRecommendation("Keep", "Disable"),
+		shield.AutoAcceptBelow(100, 0.9),
+	)
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := shield.OpenReviewAuditLog(auditPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	reviewer := shield.NewRuleReviewer(client.WAF(), policy,
+		shield.WithReviewAuditLog(audit),
+		shield.WithReviewClock(func() time.Time { return now }),
+	)
+
+	summary, err := reviewer.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "r4") {
+		t.Errorf("expected the recommendation failure for r4, got %v", err)
+	}
+	if err := audit.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Accepted != 1 || summary.Rejected != 1 || summary.Skipped != 2 || summary.Failed != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	outcomes := map[string]shield.ReviewResult{}
+	for _, res := range summary.Results {
+		outcomes[res.Rule.RuleID] = res
+	}
+	if r := outcomes["r1"]; r.Decision.Outcome != shield.ReviewAccepted || r.Decision.Action != "Whitelist" || r.ReviewID != "rev-r1" {
+		t.Errorf("expected r1 accepted, got %+v", r)
+	}
+	if r := outcomes["r2"]; r.Decision.Outcome != shield.ReviewSkipped || !strings.Contains(r.Decision.Reason, "500 times") {
+		t.Errorf("expected r2 skipped for its trigger count, got %+v", r)
+	}
+	if r := outcomes["r3"]; r.Decision.Outcome != shield.ReviewRejected || r.Decision.Action != "Keep" {
+		t.Errorf("expected r3 rejected, got %+v", r)
+	}
+	if r := outcomes["r5"]; r.Decision.Outcome != shield.ReviewSkipped || !strings.Contains(r.Decision.Reason, "confidence 0.40") {
+		t.Errorf("expected r5 skipped for low confidence, got %+v", r)
+	}
+
+	if len(submitted) != 2 {
+		t.Fatalf("expected 2 reviews submitted, got %+v", submitted)
+	}
+	for _, s := range submitted {
+		if s.Comment == "" || (s.RuleID == "r1") != (s.Action == "Whitelist") {
+			t.Errorf("unexpected submission %+v", s)
+		}
+	}
+
+	f, err := os.Open(auditPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	logged := map[string]shield.ReviewResult{}
+	pending := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var res shield.ReviewResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		if res.Pending {
+			if _, ok := logged[res.Rule.RuleID]; ok || res.ReviewID != "" {
+				t.Errorf("expected the pending record first and without a review, got %+v", res)
+			}
+			pending++
+		}
+		logged[res.Rule.RuleID] = res
+	}
+	if pending != 2 {
+		t.Errorf("expected both submissions audited before they were sent, got %d pending records", pending)
+	}
+	if len(logged) != len(rules) {
+		t.Fatalf("expected every decision audited, got %d records", len(logged))
+	}
+	if r := logged["r4"]; !strings.Contains(r.Error, "model unavailable") || !r.Time.Equal(now) {
+		t.Errorf("expected the failure audited, got %+v", r)
+	}
+	if info, err := os.Stat(auditPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected audit log mode 0600, got %v, %v", info.Mode(), err)
+	}
+
+	t.Run("audit failures", func(t *testing.T) {
+		// Failing the pending record stops the submission; failing the
+		// outcome record leaves the submitted review counted as such.
+		submitted = nil
+		summary, err := shield.NewRuleReviewer(client.WAF(), policy,
+			shield.WithReviewAuditLog(failingAudit{when: `"pending":true`})).Run(context.Background())
+		if len(submitted) != 0 || summary.Failed != 3 || summary.AuditFailed != 2 || err == nil {
+			t.Errorf("expected no submissions without an audit, got %d submitted, %+v, %v", len(submitted), summary, err)
+		}
+
+		submitted = nil
+		summary, err = shield.NewRuleReviewer(client.WAF(), policy,
+			shield.WithReviewAuditLog(failingAudit{when: `"reviewId"`})).Run(context.Background())
+		if len(submitted) != 2 || summary.Accepted != 1 || summary.Rejected != 1 || summary.Failed != 1 || summary.AuditFailed != 2 {
+			t.Errorf("expected submitted reviews kept despite the audit failure, got %d submitted, %+v", len(submitted), summary)
+		}
+		if err == nil || !strings.Contains(err.Error(), "auditing rule r1") {
+			t.Errorf("expected the audit failure reported, got %v", err)
+		}
+	})
+
+	t.Run("dry run submits nothing", func(t *testing.T) {
+		submitted = nil
+		summary, _ := shield.NewRuleReviewer(client.WAF(), policy, shield.WithReviewDryRun(true)).Run(context.Background())
+		if len(submitted) != 0 || summary.Accepted != 1 || summary.Rejected != 1 {
+			t.Errorf("expected decisions without submissions, got %d submitted, %+v", len(submitted), summary)
+		}
+	})
+}
+
+// failingAudit rejects the audit records containing when.
+type failingAudit struct{ when string }
+
+func (a failingAudit) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), a.when) {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
@@ -0,0 +1,371 @@
+package shield
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Verdict is the predicted outcome of one sample request. Action is empty
+// when no rule took a terminating action and the request passes.
+type Verdict struct {
+	Request     *SampleRequest
+	Action      RuleAction
+	RuleID      string
+	RuleName    string
+	RateLimited bool
+	Logged      []string // names of Log rules that matched
+}
+
+// Mismatch reports whether the request has an expectation the verdict
+// does not meet. An expectation of "Pass" or "Allow" is met by a request
+// no rule stops.
+func (v Verdict) Mismatch() bool {
+	if v.Request.Expect == "" {
+		return false
+	}
+	got := string(v.Action)
+	if got == "" || v.Action == RuleActionAllow {
+		got = "Pass"
+	}
+	want := v.Request.Expect
+	if strings.EqualFold(want, string(RuleActionAllow)) {
+		want = "Pass"
+	}
+	return !strings.EqualFold(got, want)
+}
+
+// SimulationReport is the result of simulating a corpus.
+type SimulationReport struct {
+	Verdicts    []Verdict      // in corpus order
+	Hits        map[string]int // matches per rule name, Log rules included
+	Unsupported []UnsupportedRule
+}
+
+// UnsupportedRule is a custom rule the simulator skipped because its
+// pattern is not in the SDK syntax of ParseExpr, or does not validate.
+// Verdicts are predicted as if the rule did not exist.
+type UnsupportedRule struct {
+	Rule CustomRule
+	Err  error
+}
+
+// Count returns how many requests ended with action; "" counts the
+// requests that passed without a terminating action.
+func (r *SimulationReport) Count(action RuleAction) int {
+	n := 0
+	for _, v := range r.Verdicts {
+		if v.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Mismatches returns the verdicts that differ from their request's
+// expectation.
+func (r *SimulationReport) Mismatches() []Verdict {
+	var out []Verdict
+	for _, v := range r.Verdicts {
+		if v.Mismatch() {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// String renders the report as plain text.
+func (r *SimulationReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d requests: %d blocked, %d challenged, %d allowed, %d passed\n", len(r.Verdicts),
+		r.Count(RuleActionBlock), r.Count(RuleActionChallenge), r.Count(RuleActionAllow), r.Count(""))
+	names := make([]string, 0, len(r.Hits))
+	for name := range r.Hits {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s: %d\n", name, r.Hits[name])
+	}
+	if len(r.Unsupported) > 0 {
+		fmt.Fprintf(&b, "%d rules not simulated:\n", len(r.Unsupported))
+		for _, u := range r.Unsupported {
+			fmt.Fprintf(&b, "  %s: %v\n", u.Rule.Name, u.Err)
+		}
+	}
+	if mismatches := r.Mismatches(); len(mismatches) > 0 {
+		fmt.Fprintf(&b, "%d mismatches:\n", len(mismatches))
+		for _, v := range mismatches {
+			got := string(v.Action)
+			if got == "" {
+				got = "Pass"
+			}
+			fmt.Fprintf(&b, "  %s %s from %s: expected %s, got %s", v.Request.Method, v.Request.URI, v.Request.RemoteAddr, v.Request.Expect, got)
+			if v.RuleName != "" {
+				fmt.Fprintf(&b, " (%s)", v.RuleName)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// RuleSimulator predicts locally which custom rule or rate limit acts on
+// each request of a recorded corpus, so rule sets can be regression tested
+// in CI before they are deployed:
+//
+//	requests, err := shield.LoadRequestCorpus("testdata/traffic.jsonl")
+//	sim, err := shield.NewRuleSimulator(rules.Items, limits.Items)
+//	report := sim.Run(requests)
+//	if m := report.Mismatches(); len(m) > 0 {
+//		t.Fatal(report)
+//	}
+//
+// Custom rules are evaluated in order. Log rules are recorded and
+// evaluation continues; the first Block, Challenge or Allow rule decides.
+// Requests no custom rule decided go through the rate limits, which count
+// requests per client address over the corpus timestamps. A rate limit
+// applies to the request paths starting with its Path; a trailing "*", as
+// in "/api/*", is a wildcard.
+//
+// Only patterns in the SDK syntax of ParseExpr can be evaluated. Rules with
+// other patterns, such as "/admin/*", are skipped and listed in Unsupported
+// and in every report, so the rest of a real rule set is still simulated.
+type RuleSimulator struct {
+	rules       []simRule
+	limits      []RateLimit
+	unsupported []UnsupportedRule
+	inactive    bool
+}
+
+type simRule struct {
+	rule   CustomRule
+	action RuleAction
+	match  matcher
+}
+
+type matcher func(*SampleRequest) bool
+
+// SimulatorOption configures a RuleSimulator.
+type SimulatorOption func(*RuleSimulator)
+
+// WithSimulateInactive also evaluates inactive rules and rate limits, for
+// trying out rules before enabling them.
+func WithSimulateInactive(inactive bool) SimulatorOption {
+	return func(s *RuleSimulator) {
+		s.inactive = inactive
+	}
+}
+
+// NewRuleSimulator compiles the custom rules' patterns, setting aside the
+// rules it cannot evaluate as unsupported. It fails when a rule's action is
+// not one of the RuleAction constants or a rate limit has no limit.
+func NewRuleSimulator(rules []CustomRule, limits []RateLimit, opts ...SimulatorOption) (*RuleSimulator, error) {
+	s := &RuleSimulator{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	var errs []error
+	for _, rule := range rules {
+		if !rule.IsActive && !s.inactive {
+			continue
+		}
+		i := slices.IndexFunc(knownActions, func(a RuleAction) bool { return strings.EqualFold(string(a), rule.Action) })
+		if i < 0 {
+			errs = append(errs, fmt.Errorf("custom rule %q: unknown action %q", rule.Name, rule.Action))
+			continue
+		}
+		spec, err := ParseCustomRule(&rule)
+		if err == nil {
+			err = ValidateExpr(spec.Pattern, nil)
+		}
+		if err != nil {
+			s.unsupported = append(s.unsupported, UnsupportedRule{Rule: rule, Err: err})
+			continue
+		}
+		s.rules = append(s.rules, simRule{rule: rule, action: knownActions[i], match: compileExpr(spec.Pattern)})
+	}
+	for _, limit := range limits {
+		if !limit.IsActive && !s.inactive {
+			continue
+		}
+		if limit.RequestsPerSecond <= 0 && limit.RequestsPerMinute <= 0 {
+			errs = append(errs, fmt.Errorf("rate limit %q has no limit", limit.Name))
+			continue
+		}
+		s.limits = append(s.limits, limit)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("shield: simulating rules: %w", errors.Join(errs...))
+	}
+	return s, nil
+}
+
+// Unsupported returns the custom rules that are not simulated.
+func (s *RuleSimulator) Unsupported() []UnsupportedRule {
+	return slices.Clone(s.unsupported)
+}
+
+// Run simulates requests and returns a verdict for each, in corpus order.
+// Rate limits are simulated in timestamp order; requests without a
+// timestamp are not rate limited.
+func (s *RuleSimulator) Run(requests []SampleRequest) *SimulationReport {
+	report := &SimulationReport{
+		Verdicts:    make([]Verdict, len(requests)),
+		Hits:        map[string]int{},
+		Unsupported: s.Unsupported(),
+	}
+	for i := range requests {
+		v := &report.Verdicts[i]
+		v.Request = &requests[i]
+		for _, r := range s.rules {
+			if !r.match(v.Request) {
+				continue
+			}
+			report.Hits[r.rule.Name]++
+			if r.action == RuleActionLog {
+				v.Logged = append(v.Logged, r.rule.Name)
+				continue
+			}
+			v.Action, v.RuleID, v.RuleName = r.action, r.rule.ID, r.rule.Name
+			break
+		}
+	}
+
+	order := make([]int, 0, len(requests))
+	for i, req := range requests {
+		if !req.Time.IsZero() && report.Verdicts[i].Action == "" {
+			order = append(order, i)
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return requests[a].Time.Compare(requests[b].Time) })
+	for _, limit := range s.limits {
+		windows := map[string][]time.Time{}
+		for _, i := range order {
+			req, v := &requests[i], &report.Verdicts[i]
+			if !strings.HasPrefix(requestPath(req.URI), strings.TrimSuffix(limit.Path, "*")) {
+				continue
+			}
+			seen := windows[req.RemoteAddr]
+			seen = slices.DeleteFunc(seen, func(t time.Time) bool { return req.Time.Sub(t) >= time.Minute })
+			seen = append(seen, req.Time)
+			windows[req.RemoteAddr] = seen
+			if v.Action != "" || !exceeds(limit, seen) {
+				continue
+			}
+			v.Action = cmp.Or(RuleAction(limit.Action), RuleActionBlock)
+			v.RuleID, v.RuleName, v.RateLimited = limit.ID, limit.Name, true
+			report.Hits[limit.Name]++
+		}
+	}
+	return report
+}
+
+// exceeds reports whether the requests in seen, the last minute ending
+// with the current one, are over the limit.
+func exceeds(limit RateLimit, seen []time.Time) bool {
+	if limit.RequestsPerMinute > 0 && len(seen) > limit.RequestsPerMinute {
+		return true
+	}
+	if limit.RequestsPerSecond > 0 {
+		now := seen[len(seen)-1]
+		n := 0
+		for _, t := range seen {
+			if now.Sub(t) < time.Second {
+				n++
+			}
+		}
+		return n > limit.RequestsPerSecond
+	}
+	return false
+}
+
+func requestPath(uri string) string {
+	path, _, _ := strings.Cut(uri, "?")
+	return path
+}
+
+// compileExpr turns a validated expression into a matcher.
+func compileExpr(expr Expr) matcher {
+	switch e := expr.(type) {
+	case Group:
+		matchers := make([]matcher, len(e.Exprs))
+		for i, inner := range e.Exprs {
+			matchers[i] = compileExpr(inner)
+		}
+		if e.Or {
+			return func(r *SampleRequest) bool {
+				return slices.ContainsFunc(matchers, func(m matcher) bool { return m(r) })
+			}
+		}
+		return func(r *SampleRequest) bool {
+			return !slices.ContainsFunc(matchers, func(m matcher) bool { return !m(r) })
+		}
+	case Negation:
+		inner := compileExpr(e.Expr)
+		return func(r *SampleRequest) bool { return !inner(r) }
+	case Condition:
+		return compileCondition(e)
+	}
+	return func(*SampleRequest) bool { return false }
+}
+
+func compileCondition(c Condition) matcher {
+	value := func(r *SampleRequest) string {
+		switch c.Variable {
+		case VarRequestURI:
+			return r.URI
+		case VarRequestHeaders:
+			return r.Header(c.Selector)
+		case VarRemoteAddr:
+			return r.RemoteAddr
+		case VarCountry:
+			return r.Country
+		case VarRequestMethod:
+			return r.Method
+		case VarRequestBody:
+			return r.Body
+		}
+		return ""
+	}
+	equal := func(got, want string) bool { return got == want }
+	switch c.Variable {
+	case VarCountry, VarRequestMethod:
+		equal = strings.EqualFold
+	case VarRemoteAddr:
+		equal = addrMatches
+	}
+
+	switch c.Operator {
+	case OpContains:
+		return func(r *SampleRequest) bool { return strings.Contains(value(r), c.Values[0]) }
+	case OpRegex:
+		re := regexp.MustCompile(c.Values[0])
+		return func(r *SampleRequest) bool { return re.MatchString(value(r)) }
+	default:
+		return func(r *SampleRequest) bool {
+			got := value(r)
+			return slices.ContainsFunc(c.Values, func(want string) bool { return equal(got, want) })
+		}
+	}
+}
+
+// addrMatches reports whether the address got is want or lies in the
+// prefix want.
+func addrMatches(got, want string) bool {
+	addr, err := netip.ParseAddr(got)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	if prefix, err := netip.ParsePrefix(want); err == nil {
+		return prefix.Contains(addr)
+	}
+	w, err := netip.ParseAddr(want)
+	return err == nil && w.Unmap() == addr
+}
@@ -0,0 +1,185 @@
+package shield_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func TestRuleSimulator(t *testing.T) {
+	rules := []shield.CustomRule{
+		{ID: "c1", Name: "log scanners", Pattern: `REQUEST_HEADERS:User-Agent rx "(?i)sqlmap|nikto"`, Action: "Log", IsActive: true},
+		{ID: "c2", Name: "office", Pattern: `REMOTE_ADDR in ["10.0.0.0/8"]`, Action: "Allow", IsActive: true},
+		{ID: "c3", Name: "admin", Pattern: `REQUEST_URI contains "/admin" and not COUNTRY eq "DE"`, Action: "Block", IsActive: true},
+		{ID: "c4", Name: "post from CN", Pattern: `REQUEST_METHOD eq "POST" and COUNTRY in ["CN"]`, Action: "Challenge", IsActive: true},
+		{ID: "c5", Name: "draft", Pattern: `REQUEST_URI contains "/"`, Action: "Block"},
+	}
+	limits := []shield.RateLimit{
+		{ID: "rl1", Name: "login", Path: "/login", RequestsPerSecond: 2, IsActive: true},
+	}
+	sim, err := shield.NewRuleSimulator(rules, limits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	corpus := `
+{"method":"get","uri":"https://example.com/admin?x=1","remoteAddr":"203.0.113.7","country":"US","headers":{"user-agent":"sqlmap/1.7"},"expect":"Block"}
+{"method":"GET","uri":"/admin","remoteAddr":"10.1.2.3","country":"US","expect":"Allow"}
+{"method":"GET","uri":"/admin","remoteAddr":"198.51.100.1","country":"DE"}
+{"method":"POST","uri":"/api","remoteAddr":"198.51.100.2","country":"CN","expect":"Pass"}
+`
+	requests, err := shield.ReadRequestLines(strings.NewReader(corpus))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		requests = append(requests, shield.SampleRequest{
+			Time: base.Add(time.Duration(i) * 300 * time.Millisecond), Method: "POST", URI: "/login?next=/", RemoteAddr: "192.0.2.1",
+		})
+	}
+	requests = append(requests, shield.SampleRequest{Time: base.Add(2 * time.Second), Method: "POST", URI: "/login", RemoteAddr: "192.0.2.1"})
+
+	report := sim.Run(requests)
+	got := make([]string, len(report.Verdicts))
+	for i, v := range report.Verdicts {
+		got[i] = fmt.Sprintf("%s/%s", v.Action, v.RuleID)
+	}
+	want := "Block/c3 Allow/c2 / Challenge/c4 / / Block/rl1 Block/rl1 /"
+	if strings.Join(got, " ") != want {
+		t.Errorf("unexpected verdicts:\n got %s\nwant %s", strings.Join(got, " "), want)
+	}
+	if v := report.Verdicts[0]; len(v.Logged) != 1 || v.Logged[0] != "log scanners" || v.Request.URI != "/admin?x=1" {
+		t.Errorf("expected the scanner logged on the normalized request, got %+v", v)
+	}
+	if !report.Verdicts[6].RateLimited || report.Hits["login"] != 2 || report.Hits["draft"] != 0 {
+		t.Errorf("unexpected hits: %v", report.Hits)
+	}
+
+	mismatches := report.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].Request.URI != "/api" {
+		t.Errorf("expected the CN post to mismatch, got %+v", mismatches)
+	}
+	out := report.String()
+	for _, want := range []string{
+		"9 requests: 3 blocked, 1 challenged, 1 allowed, 4 passed",
+		"  login: 2\n",
+		"POST /api from 198.51.100.2: expected Pass, got Challenge (post from CN)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	t.Run("inactive rules", func(t *testing.T) {
+		sim, err := shield.NewRuleSimulator(rules, nil, shield.WithSimulateInactive(true))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := sim.Run(requests[2:3]).Verdicts[0]; v.RuleID != "c5" {
+			t.Errorf("expected the draft rule to block, got %+v", v)
+		}
+	})
+
+	t.Run("wildcard path", func(t *testing.T) {
+		sim, err := shield.NewRuleSimulator(nil, []shield.RateLimit{
+			{ID: "rl2", Name: "api", Path: "/api/*", RequestsPerSecond: 1, IsActive: true},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var burst []shield.SampleRequest
+		for i, uri := range []string{"/api/users", "/api/orders?page=2", "/apiary", "/api/users"} {
+			burst = append(burst, shield.SampleRequest{Time: base.Add(time.Duration(i) * 100 * time.Millisecond), Method: "GET", URI: uri, RemoteAddr: "192.0.2.9"})
+		}
+		report := sim.Run(burst)
+		if report.Hits["api"] != 2 || report.Verdicts[2].RateLimited || !report.Verdicts[3].RateLimited {
+			t.Errorf("expected the api burst limited and /apiary left out, got %+v", report.Verdicts)
+		}
+	})
+
+	t.Run("rule actions", func(t *testing.T) {
+		sim, err := shield.NewRuleSimulator([]shield.CustomRule{
+			{ID: "c6", Name: "lower case", Pattern: `REQUEST_URI contains "/"`, Action: "block", IsActive: true},
+		}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := sim.Run(requests[:1]).Verdicts[0]; v.Action != shield.RuleActionBlock {
+			t.Errorf("expected the action normalized, got %+v", v)
+		}
+
+		_, err = shield.NewRuleSimulator([]shield.CustomRule{
+			{Name: "no action", Pattern: `REQUEST_URI contains "/"`, IsActive: true},
+			{Name: "typo", Pattern: `REQUEST_URI contains "/"`, Action: "Blok", IsActive: true},
+		}, nil)
+		if err == nil || !strings.Contains(err.Error(), `"no action": unknown action ""`) || !strings.Contains(err.Error(), `"Blok"`) {
+			t.Errorf("expected both actions rejected, got %v", err)
+		}
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := shield.NewRuleSimulator(nil, []shield.RateLimit{{Name: "empty", IsActive: true}})
+		if err == nil || !strings.Contains(err.Error(), "empty") {
+			t.Errorf("expected the empty rate limit reported, got %v", err)
+		}
+	})
+
+	t.Run("unsupported patterns", func(t *testing.T) {
+		sim, err := shield.NewRuleSimulator([]shield.CustomRule{
+			{ID: "c7", Name: "dashboard glob", Pattern: "/admin/*", Action: "Block", IsActive: true},
+			{ID: "c8", Name: "broken", Pattern: `REQUEST_URI rx "("`, Action: "Block", IsActive: true},
+			{ID: "c9", Name: "admin", Pattern: `REQUEST_URI contains "/admin"`, Action: "Challenge", IsActive: true},
+		}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		report := sim.Run(requests[1:2])
+		if v := report.Verdicts[0]; v.RuleID != "c9" {
+			t.Errorf("expected the supported rule to act, got %+v", v)
+		}
+		if len(report.Unsupported) != 2 || report.Unsupported[0].Rule.ID != "c7" || report.Unsupported[1].Rule.ID != "c8" {
+			t.Errorf("unexpected unsupported rules: %+v", report.Unsupported)
+		}
+		if out := report.String(); !strings.Contains(out, "2 rules not simulated:\n  dashboard glob: ") {
+			t.Errorf("expected unsupported rules in the report, got:\n%s", out)
+		}
+	})
+}
+
+func TestLoadRequestCorpus(t *testing.T) {
+	har := `{"log":{"version":"1.2","entries":[
+		{"startedDateTime":"2026-10-18T12:00:00.250Z","_country":"FR","request":{"method":"POST","url":"https://example.com/login?a=b",
+		 "headers":[{"name":"x-forwarded-for","value":"203.0.113.9, 10.0.0.1"},{"name":"User-Agent","value":"Mozilla/5.0"}],
+		 "postData":{"mimeType":"application/json","text":"{\"user\":\"a\"}"}}},
+		{"startedDateTime":"2026-10-18T12:00:01Z","_clientIP":"198.51.100.3","request":{"method":"GET","url":"https://example.com/","headers":[]}}
+	]}}`
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := os.WriteFile(path, []byte(har), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requests, err := shield.LoadRequestCorpus(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	r := requests[0]
+	if r.URI != "/login?a=b" || r.RemoteAddr != "203.0.113.9" || r.Country != "FR" || r.Body != `{"user":"a"}` ||
+		r.Header("user-agent") != "Mozilla/5.0" || r.Time.Nanosecond() != 250_000_000 {
+		t.Errorf("unexpected request: %+v", r)
+	}
+	if requests[1].RemoteAddr != "198.51.100.3" {
+		t.Errorf("expected the custom client IP, got %+v", requests[1])
+	}
+
+	if _, err := shield.ReadRequestLines(strings.NewReader("{}\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected a line error, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package shield
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// RuleSpec is a custom WAF rule with a typed pattern and action, checked
+// locally before it is sent. The pattern is sent as rendered by Expr.String,
+// in the SDK's own syntax:
+//
+//	spec := &shield.RuleSpec{
+//		Name:     "Block admin outside the office",
+//		Pattern:  shield.And(shield.URI().Contains("/admin"), shield.Not(shield.IP().In("203.0.113.0/24"))),
+//		Action:   shield.RuleActionBlock,
+//		IsActive: true,
+//	}
+//	enums, err := client.WAF().GetEnums(ctx)
+//	if err := spec.Validate(enums); err != nil {
+//		return err
+//	}
+//	rule, err := client.WAF().CreateCustomRule(ctx, spec.CreateRequest(zoneID))
+type RuleSpec struct {
+	Name        string
+	Description string
+	Pattern     Expr
+	Action      RuleAction
+	IsActive    bool
+}
+
+// Validate checks the spec locally with ValidateExpr. The action must be
+// one of the RuleAction constants, or among enums.RuleActions when enums is
+// not nil.
+func (r *RuleSpec) Validate(enums *WAFEnums) error {
+	var errs []error
+	if r.Name == "" {
+		errs = append(errs, errors.New("shield: rule name is required"))
+	}
+	switch {
+	case enums != nil && len(enums.RuleActions) > 0:
+		if !containsFold(enums.RuleActions, string(r.Action)) {
+			errs = append(errs, fmt.Errorf("shield: action %q is not supported by the API", r.Action))
+		}
+	case !slices.Contains(knownActions, r.Action):
+		errs = append(errs, fmt.Errorf("shield: unknown action %q", r.Action))
+	}
+	if err := ValidateExpr(r.Pattern, enums); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// CreateRequest returns the request creating the rule in a Shield zone.
+func (r *RuleSpec) CreateRequest(zoneID string) *CreateCustomRuleRequest {
+	return &CreateCustomRuleRequest{
+		Name:         r.Name,
+		Description:  r.Description,
+		Pattern:      r.pattern(),
+		Action:       string(r.Action),
+		ShieldZoneID: zoneID,
+		IsActive:     r.IsActive,
+	}
+}
+
+// ReplaceRequest returns the request replacing an existing rule with the
+// spec.
+func (r *RuleSpec) ReplaceRequest(zoneID string) *ReplaceCustomRuleRequest {
+	return &ReplaceCustomRuleRequest{
+		Name:         r.Name,
+		Description:  r.Description,
+		Pattern:      r.pattern(),
+		Action:       string(r.Action),
+		ShieldZoneID: zoneID,
+		IsActive:     r.IsActive,
+	}
+}
+
+func (r *RuleSpec) pattern() string {
+	if r.Pattern == nil {
+		return ""
+	}
+	return r.Pattern.String()
+}
+
+// ParseCustomRule returns the spec of an existing rule, parsing its pattern
+// with ParseExpr. It fails for rules whose pattern is not in the SDK syntax.
+func ParseCustomRule(rule *CustomRule) (*RuleSpec, error) {
+	pattern, err := ParseExpr(rule.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("custom rule %s: %w", rule.ID, err)
+	}
+	return &RuleSpec{
+		Name:        rule.Name,
+		Description: rule.Description,
+		Pattern:     pattern,
+		Action:      RuleAction(rule.Action),
+		IsActive:    rule.IsActive,
+	}, nil
+}
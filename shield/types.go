@@ -18,8 +18,10 @@ type ZoneListResponse struct {
 
 // CreateZoneRequest represents a request to create a Shield zone.
 type CreateZoneRequest struct {
-	Name      string   `json:"Name"`
-	HostNames []string `json:"HostNames,omitempty"`
+	Name         string   `json:"Name"`
+	HostNames    []string `json:"HostNames,omitempty"`
+	PullZoneID   int64    `json:"PullZoneId,omitempty"`
+	WAFProfileID string   `json:"WafProfileId,omitempty"`
 }
 
 // UpdateZoneRequest represents a request to update a Shield zone.
@@ -131,10 +133,16 @@ type WAFEngineConfig struct {
 }
 
 // WAFEnums represents available WAF enumeration values.
+//
+// Operators and Variables are decoded when the response carries them, but the
+// API is not known to return them; they name the SDK pattern syntax of Expr,
+// and ValidateExpr skips those checks when they are empty.
 type WAFEnums struct {
 	RuleActions []string `json:"RuleActions,omitempty"`
 	RuleTypes   []string `json:"RuleTypes,omitempty"`
 	Categories  []string `json:"Categories,omitempty"`
+	Operators   []string `json:"Operators,omitempty"`
+	Variables   []string `json:"Variables,omitempty"`
 }
 
 // TriggeredRule represents a WAF rule that was triggered and needs review.
@@ -242,9 +250,13 @@ type DeleteAccessListEntriesRequest struct {
 }
 
 // AccessListEnums represents available access list types and actions.
+// MaxEntries caps the entries a zone may hold and MaxEntriesPerRequest the
+// entries a single Update or Delete call may carry; zero means no limit.
 type AccessListEnums struct {
-	Types   []string `json:"Types,omitempty"`
-	Actions []string `json:"Actions,omitempty"`
+	Types                []string `json:"Types,omitempty"`
+	Actions              []string `json:"Actions,omitempty"`
+	MaxEntries           int      `json:"MaxEntries,omitempty"`
+	MaxEntriesPerRequest int      `json:"MaxEntriesPerRequest,omitempty"`
 }
 
 // AccessListConfig represents access list configuration.
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // UploadScanningService provides methods for managing upload scanning settings.
@@ -23,6 +25,7 @@ func newUploadScanningService(client httpClient, zoneID string) UploadScanningSe
 
 // Get returns upload scanning configuration for the zone.
 func (s *uploadScanningService) Get(ctx context.Context) (*UploadScanningConfig, error) {
+	ctx = middleware.WithOperation(ctx, "shield.UploadScanningService.Get")
 	path := fmt.Sprintf("/shield/zone/%s/upload-scanning", s.zoneID)
 	var resp UploadScanningConfig
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
@@ -33,6 +36,7 @@ func (s *uploadScanningService) Get(ctx context.Context) (*UploadScanningConfig,
 
 // Update updates upload scanning configuration for the zone.
 func (s *uploadScanningService) Update(ctx context.Context, req *UpdateUploadScanningRequest) (*UploadScanningConfig, error) {
+	ctx = middleware.WithOperation(ctx, "shield.UploadScanningService.Update")
 	path := fmt.Sprintf("/shield/zone/%s/upload-scanning", s.zoneID)
 	var resp UploadScanningConfig
 	if err := s.client.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // WAFService provides methods for managing WAF rules and configuration.
@@ -44,6 +46,7 @@ func newWAFService(client httpClient) WAFService {
 
 // ListRules returns all WAF rules (predefined and custom).
 func (s *wafService) ListRules(ctx context.Context) (*WAFRuleListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.ListRules")
 	var resp WAFRuleListResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/waf/rules", nil, &resp); err != nil {
 		return nil, err
@@ -53,6 +56,7 @@ func (s *wafService) ListRules(ctx context.Context) (*WAFRuleListResponse, error
 
 // ListCustomRules returns all custom WAF rules.
 func (s *wafService) ListCustomRules(ctx context.Context) (*CustomRuleListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.ListCustomRules")
 	var resp CustomRuleListResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/waf/custom-rules", nil, &resp); err != nil {
 		return nil, err
@@ -62,6 +66,7 @@ func (s *wafService) ListCustomRules(ctx context.Context) (*CustomRuleListRespon
 
 // CreateCustomRule creates a new custom WAF rule.
 func (s *wafService) CreateCustomRule(ctx context.Context, req *CreateCustomRuleRequest) (*CustomRule, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.CreateCustomRule")
 	var rule CustomRule
 	if err := s.client.do(ctx, http.MethodPost, "/shield/waf/custom-rule", req, &rule); err != nil {
 		return nil, err
@@ -71,6 +76,7 @@ func (s *wafService) CreateCustomRule(ctx context.Context, req *CreateCustomRule
 
 // GetCustomRule returns a specific custom WAF rule by ID.
 func (s *wafService) GetCustomRule(ctx context.Context, ruleID string) (*CustomRule, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.GetCustomRule")
 	path := fmt.Sprintf("/shield/waf/custom-rule/%s", ruleID)
 	var rule CustomRule
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &rule); err != nil {
@@ -81,6 +87,7 @@ func (s *wafService) GetCustomRule(ctx context.Context, ruleID string) (*CustomR
 
 // UpdateCustomRule updates a custom WAF rule (PATCH - partial update).
 func (s *wafService) UpdateCustomRule(ctx context.Context, ruleID string, req *UpdateCustomRuleRequest) (*CustomRule, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.UpdateCustomRule")
 	path := fmt.Sprintf("/shield/waf/custom-rule/%s", ruleID)
 	var rule CustomRule
 	if err := s.client.do(ctx, http.MethodPatch, path, req, &rule); err != nil {
@@ -91,6 +98,7 @@ func (s *wafService) UpdateCustomRule(ctx context.Context, ruleID string, req *U
 
 // ReplaceCustomRule replaces a custom WAF rule (PUT - full replacement).
 func (s *wafService) ReplaceCustomRule(ctx context.Context, ruleID string, req *ReplaceCustomRuleRequest) (*CustomRule, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.ReplaceCustomRule")
 	path := fmt.Sprintf("/shield/waf/custom-rule/%s", ruleID)
 	var rule CustomRule
 	if err := s.client.do(ctx, http.MethodPut, path, req, &rule); err != nil {
@@ -101,12 +109,14 @@ func (s *wafService) ReplaceCustomRule(ctx context.Context, ruleID string, req *
 
 // DeleteCustomRule deletes a custom WAF rule.
 func (s *wafService) DeleteCustomRule(ctx context.Context, ruleID string) error {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.DeleteCustomRule")
 	path := fmt.Sprintf("/shield/waf/custom-rule/%s", ruleID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // GetProfiles returns available WAF profiles.
 func (s *wafService) GetProfiles(ctx context.Context) (*WAFProfilesResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.GetProfiles")
 	var resp WAFProfilesResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/waf/profiles", nil, &resp); err != nil {
 		return nil, err
@@ -116,6 +126,7 @@ func (s *wafService) GetProfiles(ctx context.Context) (*WAFProfilesResponse, err
 
 // GetEngineConfig returns WAF engine configuration.
 func (s *wafService) GetEngineConfig(ctx context.Context) (*WAFEngineConfig, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.GetEngineConfig")
 	var resp WAFEngineConfig
 	if err := s.client.do(ctx, http.MethodGet, "/shield/waf/engine-config", nil, &resp); err != nil {
 		return nil, err
@@ -125,6 +136,7 @@ func (s *wafService) GetEngineConfig(ctx context.Context) (*WAFEngineConfig, err
 
 // GetEnums returns WAF enumeration values.
 func (s *wafService) GetEnums(ctx context.Context) (*WAFEnums, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.GetEnums")
 	var resp WAFEnums
 	if err := s.client.do(ctx, http.MethodGet, "/shield/waf/enums", nil, &resp); err != nil {
 		return nil, err
@@ -134,6 +146,7 @@ func (s *wafService) GetEnums(ctx context.Context) (*WAFEnums, error) {
 
 // GetTriggeredRules returns WAF rules that were triggered and need review.
 func (s *wafService) GetTriggeredRules(ctx context.Context) (*TriggeredRulesResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.GetTriggeredRules")
 	var resp TriggeredRulesResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/waf/rules/review-triggered", nil, &resp); err != nil {
 		return nil, err
@@ -143,6 +156,7 @@ func (s *wafService) GetTriggeredRules(ctx context.Context) (*TriggeredRulesResp
 
 // SubmitTriggeredRuleReview submits a review for a triggered WAF rule.
 func (s *wafService) SubmitTriggeredRuleReview(ctx context.Context, req *TriggeredRuleReviewRequest) (*TriggeredRuleReview, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.SubmitTriggeredRuleReview")
 	var resp TriggeredRuleReview
 	if err := s.client.do(ctx, http.MethodPost, "/shield/waf/rules/review-triggered", req, &resp); err != nil {
 		return nil, err
@@ -152,6 +166,7 @@ func (s *wafService) SubmitTriggeredRuleReview(ctx context.Context, req *Trigger
 
 // GetAIRecommendation returns AI-powered recommendations for triggered rules.
 func (s *wafService) GetAIRecommendation(ctx context.Context, ruleID string) (*AIRecommendationResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.GetAIRecommendation")
 	path := "/shield/waf/rules/review-triggered/ai-recommendation"
 	if ruleID != "" {
 		params := url.Values{}
@@ -167,6 +182,7 @@ func (s *wafService) GetAIRecommendation(ctx context.Context, ruleID string) (*A
 
 // GetPlanSegmentation returns WAF rule plan segmentation details.
 func (s *wafService) GetPlanSegmentation(ctx context.Context) (*PlanSegmentationResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.WAFService.GetPlanSegmentation")
 	var resp PlanSegmentationResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/waf/rules/plan-segmentation", nil, &resp); err != nil {
 		return nil, err
@@ -0,0 +1,297 @@
+package shield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PullZone is the part of a pull zone the onboarder needs.
+type PullZone struct {
+	ID        int64
+	Name      string
+	HostNames []string
+}
+
+// PullZoneSource lists every pull zone in the account. The shield API has
+// no pull zone listing, so callers supply one, typically backed by the
+// core pull zone API.
+type PullZoneSource func(ctx context.Context) ([]PullZone, error)
+
+// ZoneTemplate is the baseline protection applied to newly created shield
+// zones.
+type ZoneTemplate struct {
+	// WAFProfile is the ID or name of a profile from WAFService.GetProfiles.
+	// When empty, the profile marked as default is used.
+	WAFProfile string
+
+	// BotDetection and UploadScanning are applied to each new zone when set.
+	BotDetection   *UpdateBotDetectionRequest
+	UploadScanning *UpdateUploadScanningRequest
+
+	// RateLimits are created for each new zone; ShieldZoneID is filled in.
+	RateLimits []CreateRateLimitRequest
+}
+
+// Coverage counts how many pull zones are protected by a shield zone.
+type Coverage struct {
+	PullZones int
+	Protected int
+}
+
+// Percent returns the protected share of pull zones, or 100 when there
+// are none.
+func (c Coverage) Percent() float64 {
+	if c.PullZones == 0 {
+		return 100
+	}
+	return float64(c.Protected) * 100 / float64(c.PullZones)
+}
+
+// OnboardingResult records the onboarding of one pull zone. Zone is set
+// once the shield zone was created, even if a later template step failed.
+type OnboardingResult struct {
+	PullZone PullZone
+	Zone     *ShieldZone
+	Err      error
+}
+
+// OnboardingReport is the result of an onboarding run.
+type OnboardingReport struct {
+	Before     Coverage
+	After      Coverage
+	WAFProfile WAFProfile
+	DryRun     bool
+	Results    []OnboardingResult
+}
+
+// String renders the report as plain text.
+func (r *OnboardingReport) String() string {
+	var b strings.Builder
+	verb := "Onboarded"
+	if r.DryRun {
+		verb = "Would onboard"
+	}
+	fmt.Fprintf(&b, "Coverage before: %d/%d pull zones (%.1f%%)\n", r.Before.Protected, r.Before.PullZones, r.Before.Percent())
+	fmt.Fprintf(&b, "Coverage after:  %d/%d pull zones (%.1f%%)\n", r.After.Protected, r.After.PullZones, r.After.Percent())
+	if len(r.Results) > 0 {
+		fmt.Fprintf(&b, "%s with WAF profile %s (%s):\n", verb, r.WAFProfile.Name, r.WAFProfile.ID)
+	}
+	for _, res := range r.Results {
+		fmt.Fprintf(&b, "  %s (%d)", res.PullZone.Name, res.PullZone.ID)
+		if res.Zone != nil {
+			fmt.Fprintf(&b, " -> shield zone %s", res.Zone.ID)
+		}
+		if res.Err != nil {
+			fmt.Fprintf(&b, ": %v", res.Err)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// OnboardingServices are the services a ZoneOnboarder works with.
+// BotDetection and UploadScanning return the service of one shield zone and
+// are only needed when the template configures that feature; with a
+// *Client, pass its methods:
+//
+//	shield.OnboardingServices{
+//		Zones:          client.Zones(),
+//		WAF:            client.WAF(),
+//		RateLimits:     client.RateLimits(),
+//		BotDetection:   client.BotDetection,
+//		UploadScanning: client.UploadScanning,
+//	}
+type OnboardingServices struct {
+	Zones          ZoneService
+	WAF            WAFService
+	RateLimits     RateLimitService
+	BotDetection   func(zoneID string) BotDetectionService
+	UploadScanning func(zoneID string) UploadScanningService
+}
+
+// ZoneOnboarder creates shield zones for pull zones that have none and
+// applies a baseline template to them.
+//
+//	o := shield.NewZoneOnboarder(services, listPullZones, shield.ZoneTemplate{
+//		BotDetection: &shield.UpdateBotDetectionRequest{IsEnabled: &enabled, DetectionLevel: "Medium"},
+//		RateLimits:   []shield.CreateRateLimitRequest{{Name: "login", Path: "/login", RequestsPerMinute: 60, Action: "Block", IsActive: true}},
+//	})
+//	report, err := o.Run(ctx)
+type ZoneOnboarder struct {
+	services  OnboardingServices
+	pullZones PullZoneSource
+	template  ZoneTemplate
+	dryRun    bool
+	filter    func(PullZone) bool
+}
+
+// OnboardingOption configures a ZoneOnboarder.
+type OnboardingOption func(*ZoneOnboarder)
+
+// WithOnboardingDryRun reports which pull zones would be onboarded without
+// creating anything. The after coverage is then a projection.
+func WithOnboardingDryRun(dryRun bool) OnboardingOption {
+	return func(o *ZoneOnboarder) {
+		o.dryRun = dryRun
+	}
+}
+
+// WithOnboardingFilter onboards only the unprotected pull zones for which
+// keep returns true. Coverage still counts every pull zone.
+func WithOnboardingFilter(keep func(PullZone) bool) OnboardingOption {
+	return func(o *ZoneOnboarder) {
+		o.filter = keep
+	}
+}
+
+// NewZoneOnboarder creates an onboarder applying template to new zones.
+func NewZoneOnboarder(services OnboardingServices, pullZones PullZoneSource, template ZoneTemplate, opts ...OnboardingOption) *ZoneOnboarder {
+	o := &ZoneOnboarder{services: services, pullZones: pullZones, template: template}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Run onboards every unprotected pull zone. A failure for one pull zone is
+// recorded in its result and the rest are still onboarded; the failures
+// are returned joined.
+func (o *ZoneOnboarder) Run(ctx context.Context) (*OnboardingReport, error) {
+	pullZones, err := o.pullZones(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("shield: listing pull zones: %w", err)
+	}
+	protected, err := o.protected(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := o.profile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OnboardingReport{
+		Before:     coverage(pullZones, protected),
+		WAFProfile: profile,
+		DryRun:     o.dryRun,
+	}
+	for _, pz := range pullZones {
+		if protected[pz.ID] || o.filter != nil && !o.filter(pz) {
+			continue
+		}
+		res := OnboardingResult{PullZone: pz}
+		if !o.dryRun {
+			res.Zone, res.Err = o.onboard(ctx, pz, profile.ID)
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	var errs []error
+	for _, res := range report.Results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("shield: onboarding pull zone %d: %w", res.PullZone.ID, res.Err))
+		}
+	}
+
+	if o.dryRun {
+		for _, res := range report.Results {
+			protected[res.PullZone.ID] = true
+		}
+	} else if len(report.Results) > 0 {
+		if protected, err = o.protected(ctx); err != nil {
+			errs = append(errs, err)
+			for _, res := range report.Results {
+				if res.Zone != nil {
+					protected[res.PullZone.ID] = true
+				}
+			}
+		}
+	}
+	report.After = coverage(pullZones, protected)
+	return report, errors.Join(errs...)
+}
+
+// onboard creates the shield zone for pz and applies the template.
+func (o *ZoneOnboarder) onboard(ctx context.Context, pz PullZone, profileID string) (*ShieldZone, error) {
+	zone, err := o.services.Zones.Create(ctx, &CreateZoneRequest{
+		Name:         pz.Name,
+		HostNames:    pz.HostNames,
+		PullZoneID:   pz.ID,
+		WAFProfileID: profileID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating shield zone: %w", err)
+	}
+
+	var errs []error
+	if o.template.BotDetection != nil {
+		if o.services.BotDetection == nil {
+			errs = append(errs, errors.New("applying bot detection: no BotDetection service"))
+		} else if _, err := o.services.BotDetection(zone.ID).Update(ctx, o.template.BotDetection); err != nil {
+			errs = append(errs, fmt.Errorf("applying bot detection: %w", err))
+		}
+	}
+	if o.template.UploadScanning != nil {
+		if o.services.UploadScanning == nil {
+			errs = append(errs, errors.New("applying upload scanning: no UploadScanning service"))
+		} else if _, err := o.services.UploadScanning(zone.ID).Update(ctx, o.template.UploadScanning); err != nil {
+			errs = append(errs, fmt.Errorf("applying upload scanning: %w", err))
+		}
+	}
+	for _, rl := range o.template.RateLimits {
+		rl.ShieldZoneID = zone.ID
+		if _, err := o.services.RateLimits.Create(ctx, &rl); err != nil {
+			errs = append(errs, fmt.Errorf("creating rate limit %q: %w", rl.Name, err))
+		}
+	}
+	return zone, errors.Join(errs...)
+}
+
+// protected returns the IDs of pull zones mapped to a shield zone.
+func (o *ZoneOnboarder) protected(ctx context.Context) (map[int64]bool, error) {
+	mapping, err := o.services.Zones.GetPullZoneMapping(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("shield: getting pull zone mapping: %w", err)
+	}
+	protected := make(map[int64]bool, len(mapping.Items))
+	for _, m := range mapping.Items {
+		if m.ShieldZoneID != "" {
+			protected[m.PullZoneID] = true
+		}
+	}
+	return protected, nil
+}
+
+// profile resolves the template's WAF profile.
+func (o *ZoneOnboarder) profile(ctx context.Context) (WAFProfile, error) {
+	profiles, err := o.services.WAF.GetProfiles(ctx)
+	if err != nil {
+		return WAFProfile{}, fmt.Errorf("shield: listing WAF profiles: %w", err)
+	}
+	i := slices.IndexFunc(profiles.Items, func(p WAFProfile) bool {
+		if o.template.WAFProfile == "" {
+			return p.IsDefault
+		}
+		return p.ID == o.template.WAFProfile || strings.EqualFold(p.Name, o.template.WAFProfile)
+	})
+	if i < 0 {
+		if o.template.WAFProfile == "" {
+			return WAFProfile{}, errors.New("shield: no default WAF profile")
+		}
+		return WAFProfile{}, fmt.Errorf("shield: unknown WAF profile %q", o.template.WAFProfile)
+	}
+	return profiles.Items[i], nil
+}
+
+func coverage(pullZones []PullZone, protected map[int64]bool) Coverage {
+	c := Coverage{PullZones: len(pullZones)}
+	for _, pz := range pullZones {
+		if protected[pz.ID] {
+			c.Protected++
+		}
+	}
+	return c
+}
@@ -0,0 +1,141 @@
+package shield_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+func TestZoneOnboarder(t *testing.T) {
+	pullZones := []shield.PullZone{
+		{ID: 1, Name: "www", HostNames: []string{"www.example.com"}},
+		{ID: 2, Name: "cdn"},
+		{ID: 3, Name: "api"},
+		{ID: 4, Name: "broken"},
+	}
+	mapping := []shield.PullZoneMapping{{ShieldZoneID: "sz-1", PullZoneID: 1}}
+
+	var created []shield.CreateZoneRequest
+	var rateLimits []shield.CreateRateLimitRequest
+	botUpdates := map[string]bool{}
+	respond := func(v any) *http.Response {
+		b, _ := json.Marshal(v)
+		return testutil.NewMockResponse(200, string(b))
+	}
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			path := req.URL.Path
+			switch {
+			case path == "/shield/zones/pullzone-mapping":
+				return respond(shield.PullZoneMappingResponse{Items: mapping}), nil
+			case path == "/shield/waf/profiles":
+				return respond(shield.WAFProfilesResponse{Items: []shield.WAFProfile{
+					{ID: "p-basic", Name: "Basic", IsDefault: true},
+					{ID: "p-strict", Name: "Strict"},
+				}}), nil
+			case path == "/shield/zone" && req.Method == http.MethodPost:
+				var r shield.CreateZoneRequest
+				_ = json.NewDecoder(req.Body).Decode(&r)
+				if r.Name == "broken" {
+					return testutil.NewMockResponse(400, `{"Message":"hostname in use"}`), nil
+				}
+				created = append(created, r)
+				id := "sz-" + r.Name
+				mapping = append(mapping, shield.PullZoneMapping{ShieldZoneID: id, PullZoneID: r.PullZoneID})
+				return respond(shield.ShieldZone{ID: id, Name: r.Name}), nil
+			case strings.HasSuffix(path, "/bot-detection") && req.Method == http.MethodPatch:
+				botUpdates[strings.Split(path, "/")[3]] = true
+				return respond(shield.BotDetectionSettings{IsEnabled: true}), nil
+			case strings.HasSuffix(path, "/upload-scanning") && req.Method == http.MethodPatch:
+				if strings.Contains(path, "sz-api") {
+					return testutil.NewMockResponse(403, `{"Message":"upload scanning not in plan"}`), nil
+				}
+				return respond(shield.UploadScanningConfig{IsEnabled: true}), nil
+			case path == "/shield/rate-limit" && req.Method == http.MethodPost:
+				var r shield.CreateRateLimitRequest
+				_ = json.NewDecoder(req.Body).Decode(&r)
+				rateLimits = append(rateLimits, r)
+				return respond(shield.RateLimit{ID: "rl", Name: r.Name, ShieldZoneID: r.ShieldZoneID}), nil
+			}
+			return testutil.NewMockResponse(404, `{"Message":"not found"}`), nil
+		},
+	}
+	client := shield.NewClient("key", shield.WithHTTPClient(mock))
+	services := shield.OnboardingServices{
+		Zones:          client.Zones(),
+		WAF:            client.WAF(),
+		RateLimits:     client.RateLimits(),
+		BotDetection:   client.BotDetection,
+		UploadScanning: client.UploadScanning,
+	}
+	source := func(context.Context) ([]shield.PullZone, error) { return pullZones, nil }
+	enabled := true
+	template := shield.ZoneTemplate{
+		WAFProfile:     "strict",
+		BotDetection:   &shield.UpdateBotDetectionRequest{IsEnabled: &enabled, DetectionLevel: "Medium"},
+		UploadScanning: &shield.UpdateUploadScanningRequest{IsEnabled: &enabled},
+		RateLimits:     []shield.CreateRateLimitRequest{{Name: "login", Path: "/login", RequestsPerMinute: 60, IsActive: true}},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		report, err := shield.NewZoneOnboarder(services, source, template, shield.WithOnboardingDryRun(true)).Run(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(created) != 0 || len(report.Results) != 3 || report.Before.Percent() != 25 || report.After.Percent() != 100 {
+			t.Errorf("unexpected dry run: %d created, %+v", len(created), report)
+		}
+		if !strings.Contains(report.String(), "Would onboard with WAF profile Strict (p-strict)") {
+			t.Errorf("unexpected report:\n%s", report)
+		}
+	})
+
+	report, err := shield.NewZoneOnboarder(services, source, template).Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "hostname in use") || !strings.Contains(err.Error(), "upload scanning not in plan") {
+		t.Errorf("expected the broken and api failures, got %v", err)
+	}
+	if len(created) != 2 || created[0].PullZoneID != 2 || created[0].WAFProfileID != "p-strict" {
+		t.Errorf("unexpected zones created: %+v", created)
+	}
+	if !botUpdates["sz-cdn"] || !botUpdates["sz-api"] {
+		t.Errorf("expected bot detection on both new zones, got %v", botUpdates)
+	}
+	if len(rateLimits) != 2 || rateLimits[0].ShieldZoneID != "sz-cdn" || rateLimits[1].ShieldZoneID != "sz-api" {
+		t.Errorf("unexpected rate limits: %+v", rateLimits)
+	}
+	if report.Before != (shield.Coverage{PullZones: 4, Protected: 1}) || report.After != (shield.Coverage{PullZones: 4, Protected: 3}) {
+		t.Errorf("unexpected coverage: before %+v, after %+v", report.Before, report.After)
+	}
+	out := report.String()
+	for _, want := range []string{
+		"Coverage before: 1/4 pull zones (25.0%)",
+		"Coverage after:  3/4 pull zones (75.0%)",
+		"cdn (2) -> shield zone sz-cdn\n",
+		"api (3) -> shield zone sz-api: applying upload scanning",
+		"broken (4): creating shield zone",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	t.Run("missing per-zone service", func(t *testing.T) {
+		partial := services
+		partial.UploadScanning = nil
+		one := func(context.Context) ([]shield.PullZone, error) { return []shield.PullZone{{ID: 5, Name: "new"}}, nil }
+		_, err := shield.NewZoneOnboarder(partial, one, template).Run(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "no UploadScanning service") {
+			t.Errorf("expected the missing service reported, got %v", err)
+		}
+	})
+
+	template.WAFProfile = "missing"
+	if _, err := shield.NewZoneOnboarder(services, source, template).Run(context.Background()); err == nil {
+		t.Error("expected error for an unknown WAF profile")
+	}
+}
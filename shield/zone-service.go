@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // ZoneService provides methods for managing Shield zones.
@@ -26,6 +28,7 @@ func newZoneService(client httpClient) ZoneService {
 
 // List returns all Shield zones.
 func (s *zoneService) List(ctx context.Context) (*ZoneListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.ZoneService.List")
 	var resp ZoneListResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/zones", nil, &resp); err != nil {
 		return nil, err
@@ -35,6 +38,7 @@ func (s *zoneService) List(ctx context.Context) (*ZoneListResponse, error) {
 
 // Create creates a new Shield zone.
 func (s *zoneService) Create(ctx context.Context, req *CreateZoneRequest) (*ShieldZone, error) {
+	ctx = middleware.WithOperation(ctx, "shield.ZoneService.Create")
 	var zone ShieldZone
 	if err := s.client.do(ctx, http.MethodPost, "/shield/zone", req, &zone); err != nil {
 		return nil, err
@@ -44,6 +48,7 @@ func (s *zoneService) Create(ctx context.Context, req *CreateZoneRequest) (*Shie
 
 // Get returns a specific Shield zone by ID.
 func (s *zoneService) Get(ctx context.Context, zoneID string) (*ShieldZone, error) {
+	ctx = middleware.WithOperation(ctx, "shield.ZoneService.Get")
 	path := fmt.Sprintf("/shield/zone/%s", zoneID)
 	var zone ShieldZone
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &zone); err != nil {
@@ -54,6 +59,7 @@ func (s *zoneService) Get(ctx context.Context, zoneID string) (*ShieldZone, erro
 
 // Update updates a Shield zone (PATCH).
 func (s *zoneService) Update(ctx context.Context, zoneID string, req *UpdateZoneRequest) (*ShieldZone, error) {
+	ctx = middleware.WithOperation(ctx, "shield.ZoneService.Update")
 	path := fmt.Sprintf("/shield/zone/%s", zoneID)
 	var zone ShieldZone
 	if err := s.client.do(ctx, http.MethodPatch, path, req, &zone); err != nil {
@@ -64,6 +70,7 @@ func (s *zoneService) Update(ctx context.Context, zoneID string, req *UpdateZone
 
 // GetByPullZone returns the Shield zone associated with a Pull zone ID.
 func (s *zoneService) GetByPullZone(ctx context.Context, pullZoneID int64) (*ShieldZone, error) {
+	ctx = middleware.WithOperation(ctx, "shield.ZoneService.GetByPullZone")
 	path := fmt.Sprintf("/shield/zone/pullzone/%d", pullZoneID)
 	var zone ShieldZone
 	if err := s.client.do(ctx, http.MethodGet, path, nil, &zone); err != nil {
@@ -74,6 +81,7 @@ func (s *zoneService) GetByPullZone(ctx context.Context, pullZoneID int64) (*Shi
 
 // GetPullZoneMapping returns the Shield zone to Pull zone mapping.
 func (s *zoneService) GetPullZoneMapping(ctx context.Context) (*PullZoneMappingResponse, error) {
+	ctx = middleware.WithOperation(ctx, "shield.ZoneService.GetPullZoneMapping")
 	var resp PullZoneMappingResponse
 	if err := s.client.do(ctx, http.MethodGet, "/shield/zones/pullzone-mapping", nil, &resp); err != nil {
 		return nil, err
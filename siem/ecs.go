@@ -0,0 +1,148 @@
+package siem
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+// ecsVersion is the Elastic Common Schema version the documents follow.
+const ecsVersion = "8.11.0"
+
+// NewECSWriter writes events as Elastic Common Schema JSON documents, one
+// per line, ready for Filebeat or the Elasticsearch bulk API. Shield fields
+// map to source.ip, url.path, http.request.method,
+// http.response.status_code, rule.id, rule.name, event.action and
+// user_agent.original; the zone ID goes into labels.zone_id.
+func NewECSWriter(w io.Writer, opts ...Option) *Writer {
+	return newWriter(w, formatECS, opts)
+}
+
+type ecsDocument struct {
+	Timestamp string            `json:"@timestamp,omitempty"`
+	ECS       ecsVersionTag     `json:"ecs"`
+	Event     ecsEvent          `json:"event"`
+	Source    *ecsSource        `json:"source,omitempty"`
+	URL       *ecsURL           `json:"url,omitempty"`
+	HTTP      *ecsHTTP          `json:"http,omitempty"`
+	Rule      *ecsRule          `json:"rule,omitempty"`
+	UserAgent *ecsUA            `json:"user_agent,omitempty"`
+	Observer  ecsObserver       `json:"observer"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type ecsVersionTag struct {
+	Version string `json:"version"`
+}
+
+type ecsEvent struct {
+	ID       string   `json:"id,omitempty"`
+	Kind     string   `json:"kind"`
+	Category []string `json:"category"`
+	Type     []string `json:"type"`
+	Action   string   `json:"action,omitempty"`
+	Code     string   `json:"code,omitempty"`
+	Outcome  string   `json:"outcome,omitempty"`
+	Dataset  string   `json:"dataset"`
+}
+
+type ecsSource struct {
+	IP string `json:"ip"`
+}
+
+type ecsURL struct {
+	Path string `json:"path"`
+}
+
+type ecsHTTP struct {
+	Request  *ecsHTTPRequest  `json:"request,omitempty"`
+	Response *ecsHTTPResponse `json:"response,omitempty"`
+}
+
+type ecsHTTPRequest struct {
+	Method string `json:"method"`
+}
+
+type ecsHTTPResponse struct {
+	StatusCode int `json:"status_code"`
+}
+
+type ecsRule struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type ecsUA struct {
+	Original string `json:"original"`
+}
+
+type ecsObserver struct {
+	Vendor   string `json:"vendor,omitempty"`
+	Product  string `json:"product,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+	Type     string `json:"type"`
+}
+
+func formatECS(c *config, e *shield.EventLog) ([]byte, error) {
+	doc := ecsDocument{
+		ECS: ecsVersionTag{Version: ecsVersion},
+		Event: ecsEvent{
+			ID:       e.ID,
+			Kind:     "event",
+			Category: []string{"web", "network"},
+			Action:   e.Action,
+			Code:     e.EventType,
+			Dataset:  "bunny.shield",
+		},
+		Observer: ecsObserver{
+			Vendor:   c.vendor,
+			Product:  c.product,
+			Version:  c.version,
+			Hostname: c.hostname,
+			Type:     "waf",
+		},
+	}
+	if t, ok := eventTime(e); ok {
+		doc.Timestamp = t.Format(time.RFC3339Nano)
+	}
+	switch classify(e.Action) {
+	case outcomeDenied:
+		doc.Event.Type = []string{"denied"}
+		doc.Event.Outcome = "failure"
+	case outcomeChallenged:
+		doc.Event.Type = []string{"info"}
+		doc.Event.Outcome = "unknown"
+	default:
+		doc.Event.Type = []string{"allowed"}
+		doc.Event.Outcome = "success"
+	}
+	if e.SourceIP != "" {
+		doc.Source = &ecsSource{IP: e.SourceIP}
+	}
+	if e.Path != "" {
+		doc.URL = &ecsURL{Path: e.Path}
+	}
+	if e.Method != "" || e.StatusCode != 0 {
+		doc.HTTP = &ecsHTTP{}
+		if e.Method != "" {
+			doc.HTTP.Request = &ecsHTTPRequest{Method: strings.ToUpper(e.Method)}
+		}
+		if e.StatusCode != 0 {
+			doc.HTTP.Response = &ecsHTTPResponse{StatusCode: e.StatusCode}
+		}
+	}
+	if e.RuleID != "" || e.RuleName != "" {
+		doc.Rule = &ecsRule{ID: e.RuleID, Name: e.RuleName}
+	}
+	if e.UserAgent != "" {
+		doc.UserAgent = &ecsUA{Original: e.UserAgent}
+	}
+	if e.ZoneID != "" {
+		doc.Labels = map[string]string{"zone_id": e.ZoneID}
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,94 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Sender delivers syslog messages to a collector over TCP or UDP. Each
+// Write call is one message: over UDP it is sent as one datagram, over TCP
+// it is framed with octet counting (RFC 6587) so messages may contain
+// newlines. A trailing newline, as added by Writer, is dropped.
+//
+// A TCP connection that fails is redialled once before the write is
+// reported as failed. Sender is safe for concurrent use.
+type Sender struct {
+	mu      sync.Mutex
+	network string
+	addr    string
+	conn    net.Conn
+	stream  bool
+}
+
+// DialSyslog connects to a syslog collector. Network is "tcp", "tcp4",
+// "tcp6", "udp", "udp4" or "udp6".
+func DialSyslog(ctx context.Context, network, addr string) (*Sender, error) {
+	s := &Sender{network: network, addr: addr}
+	switch {
+	case strings.HasPrefix(network, "tcp"):
+		s.stream = true
+	case strings.HasPrefix(network, "udp"):
+	default:
+		return nil, fmt.Errorf("siem: unsupported syslog network %q", network)
+	}
+	if err := s.dial(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Sender) dial(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, s.network, s.addr)
+	if err != nil {
+		return fmt.Errorf("siem: dialing syslog %s %s: %w", s.network, s.addr, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// Write sends p as one syslog message.
+func (s *Sender) Write(p []byte) (int, error) {
+	msg := bytes.TrimSuffix(p, []byte("\n"))
+	frame := msg
+	if s.stream {
+		frame = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.dial(context.Background()); err != nil {
+			return 0, err
+		}
+	}
+	_, err := s.conn.Write(frame)
+	if err != nil && s.stream {
+		s.conn.Close()
+		s.conn = nil
+		if err = s.dial(context.Background()); err == nil {
+			_, err = s.conn.Write(frame)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("siem: sending syslog message: %w", err)
+	}
+	return len(p), nil
+}
+
+// Close closes the connection.
+func (s *Sender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,187 @@
+// Package siem exports Shield security events in the formats SIEM systems
+// ingest: ArcSight CEF, Elastic Common Schema JSON and RFC 5424 syslog.
+//
+// A Writer encodes one event per Write call on any io.Writer, so it can
+// target a file, stdout or a syslog Sender:
+//
+//	sender, err := siem.DialSyslog(ctx, "tcp", "siem.internal:6514")
+//	defer sender.Close()
+//	w := siem.NewSyslogWriter(sender, siem.WithHostname("edge-exporter"))
+//	err = w.WriteAll(shield.ListEventLogs(ctx, client.EventLogs(), &shield.EventLogListOptions{ZoneID: zoneID}))
+package siem
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/shield"
+)
+
+const (
+	defaultVendor  = "Bunny.net"
+	defaultProduct = "Shield"
+	defaultVersion = "1.0"
+	defaultAppName = "bunny-shield"
+)
+
+// Facility is a syslog facility code.
+type Facility int
+
+// Syslog facilities commonly used for security events, with their RFC 5424
+// codes.
+const (
+	FacilityAuth     Facility = 4  // security/authorization messages
+	FacilityAuthPriv Facility = 10 // private security/authorization messages
+	FacilityLogAudit Facility = 13 // log audit
+	FacilityLocal0   Facility = 16 // local use 0
+)
+
+// Writer encodes Shield events in one SIEM format and writes each event to
+// the underlying io.Writer with a single Write call. It is safe for
+// concurrent use.
+type Writer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format func(c *config, e *shield.EventLog) ([]byte, error)
+	config config
+}
+
+type config struct {
+	vendor   string
+	product  string
+	version  string
+	hostname string
+	appName  string
+	facility Facility
+}
+
+// Option configures a Writer.
+type Option func(*config)
+
+// WithProduct sets the vendor, product and version reported as the event
+// source: the CEF device fields and the ECS observer. The default is
+// Bunny.net Shield 1.0.
+func WithProduct(vendor, product, version string) Option {
+	return func(c *config) {
+		c.vendor, c.product, c.version = vendor, product, version
+	}
+}
+
+// WithHostname sets the syslog HOSTNAME and the ECS observer.hostname.
+// Syslog lines use "-" when it is not set.
+func WithHostname(hostname string) Option {
+	return func(c *config) {
+		c.hostname = hostname
+	}
+}
+
+// WithAppName sets the syslog APP-NAME. The default is "bunny-shield".
+func WithAppName(name string) Option {
+	return func(c *config) {
+		c.appName = name
+	}
+}
+
+// WithFacility sets the syslog facility. The default is FacilityLocal0.
+func WithFacility(f Facility) Option {
+	return func(c *config) {
+		c.facility = f
+	}
+}
+
+func newWriter(w io.Writer, format func(*config, *shield.EventLog) ([]byte, error), opts []Option) *Writer {
+	wr := &Writer{
+		w:      w,
+		format: format,
+		config: config{
+			vendor:   defaultVendor,
+			product:  defaultProduct,
+			version:  defaultVersion,
+			appName:  defaultAppName,
+			facility: FacilityLocal0,
+		},
+	}
+	for _, opt := range opts {
+		opt(&wr.config)
+	}
+	return wr
+}
+
+// WriteEvent encodes e and writes it as one line.
+func (w *Writer) WriteEvent(e shield.EventLog) error {
+	b, err := w.format(&w.config, &e)
+	if err != nil {
+		return fmt.Errorf("siem: encoding event %s: %w", e.ID, err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.w.Write(append(b, '\n'))
+	return err
+}
+
+// WriteAll writes every event from seq, such as shield.ListEventLogs,
+// and returns the number written. It stops at the first error.
+func (w *Writer) WriteAll(seq iter.Seq2[shield.EventLog, error]) (int, error) {
+	n := 0
+	for e, err := range seq {
+		if err != nil {
+			return n, err
+		}
+		if err := w.WriteEvent(e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// eventTime parses the event timestamp, reporting false when it is missing
+// or malformed.
+func eventTime(e *shield.EventLog) (time.Time, bool) {
+	var bt internal.BunnyTime
+	if err := bt.UnmarshalJSON([]byte(e.Timestamp)); err != nil || bt.IsZero() {
+		return time.Time{}, false
+	}
+	return bt.UTC(), true
+}
+
+// outcome classifies the Shield action for severity and ECS event.type.
+type outcome int
+
+const (
+	outcomeAllowed outcome = iota
+	outcomeChallenged
+	outcomeDenied
+)
+
+func classify(action string) outcome {
+	switch strings.ToLower(action) {
+	case "block", "blocked", "deny", "denied", "drop":
+		return outcomeDenied
+	case "challenge", "challenged", "captcha":
+		return outcomeChallenged
+	default:
+		return outcomeAllowed
+	}
+}
+
+// signature identifies the kind of event: the rule when one matched,
+// otherwise the event type.
+func signature(e *shield.EventLog) (id, name string) {
+	id, name = e.RuleID, e.RuleName
+	if id == "" {
+		id = e.EventType
+	}
+	if name == "" {
+		name = e.EventType
+	}
+	if name == "" {
+		name = id
+	}
+	return id, name
+}
@@ -0,0 +1,240 @@
+package siem_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/siem"
+)
+
+var blocked = shield.EventLog{
+	ID:         "e1",
+	ZoneID:     "z1",
+	Timestamp:  "2026-10-18T12:00:00Z",
+	EventType:  "waf",
+	RuleID:     "rule-7",
+	RuleName:   "Block admin | legacy",
+	SourceIP:   "203.0.113.7",
+	Path:       "/admin?a=b",
+	Method:     "GET",
+	Action:     "Block",
+	StatusCode: 403,
+	UserAgent:  "curl/8.0",
+}
+
+func TestCEFWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := siem.NewCEFWriter(&buf).WriteEvent(blocked); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `CEF:0|Bunny.net|Shield|1.0|rule-7|Block admin \| legacy|8|rt=1792324800000 src=203.0.113.7 ` +
+		`requestMethod=GET request=/admin?a\=b requestClientApplication=curl/8.0 act=Block externalId=e1 ` +
+		`cs1Label=ruleId cs1=rule-7 cs2Label=ruleName cs2=Block admin | legacy cs3Label=zoneId cs3=z1 ` +
+		"cn1Label=statusCode cn1=403\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CEF line:\n got %q\nwant %q", buf.String(), want)
+	}
+}
+
+func TestECSWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := siem.NewECSWriter(&buf, siem.WithHostname("exporter-1"))
+	n, err := w.WriteAll(func(yield func(shield.EventLog, error) bool) {
+		_ = yield(blocked, nil) && yield(shield.EventLog{ID: "e2", Action: "Allow"}, nil)
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 events written, got %d, %v", n, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one document per line, got %q", buf.String())
+	}
+	var doc struct {
+		Timestamp string `json:"@timestamp"`
+		Event     struct {
+			ID      string   `json:"id"`
+			Type    []string `json:"type"`
+			Action  string   `json:"action"`
+			Outcome string   `json:"outcome"`
+		} `json:"event"`
+		Source struct {
+			IP string `json:"ip"`
+		} `json:"source"`
+		URL struct {
+			Path string `json:"path"`
+		} `json:"url"`
+		HTTP struct {
+			Request struct {
+				Method string `json:"method"`
+			} `json:"request"`
+			Response struct {
+				StatusCode int `json:"status_code"`
+			} `json:"response"`
+		} `json:"http"`
+		Rule struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"rule"`
+		Observer struct {
+			Hostname string `json:"hostname"`
+		} `json:"observer"`
+		Labels map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Timestamp != "2026-10-18T12:00:00Z" || doc.Event.ID != "e1" || doc.Event.Type[0] != "denied" ||
+		doc.Event.Outcome != "failure" || doc.Event.Action != "Block" {
+		t.Errorf("unexpected event fields: %+v", doc)
+	}
+	if doc.Source.IP != "203.0.113.7" || doc.URL.Path != "/admin?a=b" || doc.HTTP.Request.Method != "GET" ||
+		doc.HTTP.Response.StatusCode != 403 || doc.Rule.ID != "rule-7" || doc.Labels["zone_id"] != "z1" {
+		t.Errorf("unexpected request fields: %+v", doc)
+	}
+	if doc.Observer.Hostname != "exporter-1" {
+		t.Errorf("expected observer hostname, got %+v", doc.Observer)
+	}
+	if strings.Contains(lines[1], `"source"`) || !strings.Contains(lines[1], `"type":["allowed"]`) {
+		t.Errorf("expected empty fields to be omitted, got %s", lines[1])
+	}
+
+	failing := errors.New("list failed")
+	n, err = w.WriteAll(func(yield func(shield.EventLog, error) bool) {
+		_ = yield(blocked, nil) && yield(shield.EventLog{}, failing)
+	})
+	if n != 1 || !errors.Is(err, failing) {
+		t.Errorf("expected to stop at the list error after 1 event, got %d, %v", n, err)
+	}
+}
+
+func TestSyslogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := siem.NewSyslogWriter(&buf, siem.WithHostname("edge"), siem.WithFacility(siem.FacilityLocal0))
+	if err := w.WriteEvent(blocked); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `<132>1 2026-10-18T12:00:00.000000Z edge bunny-shield - waf [shield@32473 id="e1" zone="z1" ` +
+		`src="203.0.113.7" method="GET" path="/admin?a=b" ruleId="rule-7" ruleName="Block admin | legacy" ` +
+		`action="Block" status="403" userAgent="curl/8.0"] Block GET /admin?a=b from 203.0.113.7 ` +
+		"(Block admin | legacy, rule-7)\n"
+	if buf.String() != want {
+		t.Errorf("unexpected syslog line:\n got %q\nwant %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := siem.NewSyslogWriter(&buf, siem.WithFacility(siem.FacilityAuth)).WriteEvent(blocked); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "<36>1 ") {
+		t.Errorf("expected the auth facility in the priority, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := siem.NewSyslogWriter(&buf).WriteEvent(shield.EventLog{RuleName: `quote " and ]`}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), `<134>1 - - bunny-shield - - [shield@32473 ruleName="quote \" and \]"]`) {
+		t.Errorf("unexpected minimal syslog line: %q", buf.String())
+	}
+}
+
+func TestSender(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("UDP sends one datagram per event", func(t *testing.T) {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer pc.Close()
+
+		sender, err := siem.DialSyslog(ctx, "udp", pc.LocalAddr().String())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer sender.Close()
+		if err := siem.NewSyslogWriter(sender).WriteEvent(blocked); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		buf := make([]byte, 2048)
+		_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		msg := string(buf[:n])
+		if !strings.HasPrefix(msg, "<132>1 ") || strings.HasSuffix(msg, "\n") {
+			t.Errorf("unexpected datagram %q", msg)
+		}
+	})
+
+	t.Run("TCP frames messages with octet counting", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer ln.Close()
+		received := make(chan []string, 1)
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			var msgs []string
+			for range 2 {
+				size, err := r.ReadString(' ')
+				if err != nil {
+					break
+				}
+				n, _ := strconv.Atoi(strings.TrimSpace(size))
+				msg := make([]byte, n)
+				if _, err := io.ReadFull(r, msg); err != nil {
+					break
+				}
+				msgs = append(msgs, string(msg))
+			}
+			received <- msgs
+		}()
+
+		sender, err := siem.DialSyslog(ctx, "tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer sender.Close()
+		w := siem.NewCEFWriter(sender)
+		second := blocked
+		second.ID, second.Path = "e2", "/multi\nline"
+		for _, e := range []shield.EventLog{blocked, second} {
+			if err := w.WriteEvent(e); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		select {
+		case msgs := <-received:
+			if len(msgs) != 2 || !strings.Contains(msgs[1], `request=/multi\nline`) || !strings.HasPrefix(msgs[0], "CEF:0|") {
+				t.Errorf("unexpected messages %q", msgs)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for messages")
+		}
+	})
+
+	if _, err := siem.DialSyslog(ctx, "unix", "/tmp/syslog"); err == nil {
+		t.Error("expected unsupported network error")
+	}
+}
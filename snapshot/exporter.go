@@ -0,0 +1,368 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/redact"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+const defaultPageSize = 1000
+
+// CollectionServiceFunc returns a CollectionService scoped to a stream library.
+// Collections are read with the library API key, so the caller supplies the scoped service.
+type CollectionServiceFunc func(libraryID int64) stream.CollectionService
+
+// ShieldClient is the subset of *shield.Client used by the exporter.
+type ShieldClient interface {
+	Zones() shield.ZoneService
+	WAF() shield.WAFService
+	RateLimits() shield.RateLimitService
+	AccessLists(zoneID string) shield.AccessListService
+	BotDetection(zoneID string) shield.BotDetectionService
+}
+
+// ScriptingClient is the subset of *scripting.Client used by the exporter.
+type ScriptingClient interface {
+	Scripts() scripting.ScriptService
+	Code(scriptID int64) scripting.CodeService
+	Releases(scriptID int64) scripting.ReleaseService
+	Secrets(scriptID int64) scripting.SecretService
+}
+
+// ContainersClient is the subset of *containers.Client used by the exporter.
+type ContainersClient interface {
+	Applications() containers.ApplicationService
+	Endpoints(appID string) containers.EndpointService
+}
+
+// Exporter reads account configuration into a Snapshot.
+type Exporter struct {
+	storageZones    storage.ZoneService
+	streamLibraries stream.LibraryService
+	collections     CollectionServiceFunc
+	shield          ShieldClient
+	scripting       ScriptingClient
+	containers      ContainersClient
+	releases        int
+	now             func() time.Time
+}
+
+// Option is a functional option for configuring the Exporter.
+type Option func(*Exporter)
+
+// WithStorage includes storage zones in the snapshot.
+func WithStorage(zones storage.ZoneService) Option {
+	return func(e *Exporter) {
+		e.storageZones = zones
+	}
+}
+
+// WithStream includes stream libraries in the snapshot.
+// collections may be nil to skip per-library collections.
+func WithStream(libraries stream.LibraryService, collections CollectionServiceFunc) Option {
+	return func(e *Exporter) {
+		e.streamLibraries = libraries
+		e.collections = collections
+	}
+}
+
+// WithShield includes shield zones, custom rules, rate limits, access lists and bot detection settings.
+func WithShield(client ShieldClient) Option {
+	return func(e *Exporter) {
+		e.shield = client
+	}
+}
+
+// WithScripting includes edge scripts with their code, variables, secret names and releases.
+func WithScripting(client ScriptingClient) Option {
+	return func(e *Exporter) {
+		e.scripting = client
+	}
+}
+
+// WithContainers includes container applications and their endpoints.
+func WithContainers(client ContainersClient) Option {
+	return func(e *Exporter) {
+		e.containers = client
+	}
+}
+
+// WithReleaseHistory limits how many releases are kept per edge script (default 20, 0 for none).
+func WithReleaseHistory(n int) Option {
+	return func(e *Exporter) {
+		e.releases = n
+	}
+}
+
+// WithClock sets the function used to timestamp snapshots.
+func WithClock(now func() time.Time) Option {
+	return func(e *Exporter) {
+		e.now = now
+	}
+}
+
+// NewExporter creates a new Exporter.
+// Only the services passed as options are exported.
+func NewExporter(opts ...Option) *Exporter {
+	e := &Exporter{
+		releases: 20,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Export reads the configured services and returns a redacted snapshot.
+func (e *Exporter) Export(ctx context.Context) (*Snapshot, error) {
+	s := &Snapshot{Version: FormatVersion, CreatedAt: e.now().UTC()}
+
+	if e.storageZones != nil {
+		zones, err := e.exportStorageZones(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: storage zones: %w", err)
+		}
+		s.StorageZones = zones
+	}
+	if e.streamLibraries != nil {
+		libraries, err := e.exportStreamLibraries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: stream libraries: %w", err)
+		}
+		s.StreamLibraries = libraries
+	}
+	if e.shield != nil {
+		zones, err := e.exportShieldZones(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: shield zones: %w", err)
+		}
+		s.ShieldZones = zones
+	}
+	if e.scripting != nil {
+		scripts, err := e.exportEdgeScripts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: edge scripts: %w", err)
+		}
+		s.EdgeScripts = scripts
+	}
+	if e.containers != nil {
+		apps, err := e.exportContainerApps(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: container apps: %w", err)
+		}
+		s.ContainerApps = apps
+	}
+	return s, nil
+}
+
+func (e *Exporter) exportStorageZones(ctx context.Context) ([]storage.Zone, error) {
+	var zones []storage.Zone
+	for page := 1; ; page++ {
+		resp, err := e.storageZones.List(ctx, &storage.ZoneListOptions{Page: page, PerPage: defaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, z := range resp.Items {
+			if z.Deleted {
+				continue
+			}
+			if z.Password != "" {
+				z.Password = Redacted
+			}
+			if z.ReadOnlyPassword != "" {
+				z.ReadOnlyPassword = Redacted
+			}
+			zones = append(zones, z)
+		}
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			return zones, nil
+		}
+	}
+}
+
+func (e *Exporter) exportStreamLibraries(ctx context.Context) ([]StreamLibrary, error) {
+	var libraries []StreamLibrary
+	for page := 1; ; page++ {
+		resp, err := e.streamLibraries.List(ctx, &stream.LibraryListOptions{Page: page, ItemsPerPage: defaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range resp.Items {
+			if l.APIKey != "" {
+				l.APIKey = Redacted
+			}
+			if l.ReadOnlyAPIKey != "" {
+				l.ReadOnlyAPIKey = Redacted
+			}
+			lib := StreamLibrary{Library: l}
+			if e.collections != nil {
+				lib.Collections, err = listCollections(ctx, e.collections(l.LibraryID))
+				if err != nil {
+					return nil, fmt.Errorf("library %d: %w", l.LibraryID, err)
+				}
+			}
+			libraries = append(libraries, lib)
+		}
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			return libraries, nil
+		}
+	}
+}
+
+func listCollections(ctx context.Context, svc stream.CollectionService) ([]stream.Collection, error) {
+	var collections []stream.Collection
+	for page := 1; ; page++ {
+		resp, err := svc.List(ctx, &stream.CollectionListOptions{Page: page, ItemsPerPage: defaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			return collections, nil
+		}
+	}
+}
+
+func (e *Exporter) exportShieldZones(ctx context.Context) ([]ShieldZone, error) {
+	zones, err := e.shield.Zones().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := e.shield.WAF().ListCustomRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := e.shield.RateLimits().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ShieldZone, 0, len(zones.Items))
+	for _, z := range zones.Items {
+		sz := ShieldZone{Zone: z}
+		for _, r := range rules.Items {
+			if r.ShieldZoneID == z.ID {
+				sz.CustomRules = append(sz.CustomRules, r)
+			}
+		}
+		for _, l := range limits.Items {
+			if l.ShieldZoneID == z.ID {
+				sz.RateLimits = append(sz.RateLimits, l)
+			}
+		}
+		if sz.AccessList, err = e.shield.AccessLists(z.ID).Get(ctx); err != nil {
+			return nil, fmt.Errorf("zone %s: access list: %w", z.ID, err)
+		}
+		if sz.BotDetection, err = e.shield.BotDetection(z.ID).Get(ctx); err != nil {
+			return nil, fmt.Errorf("zone %s: bot detection: %w", z.ID, err)
+		}
+		out = append(out, sz)
+	}
+	return out, nil
+}
+
+func (e *Exporter) exportEdgeScripts(ctx context.Context) ([]EdgeScript, error) {
+	var out []EdgeScript
+	for page := 1; ; page++ {
+		resp, err := e.scripting.Scripts().List(ctx, &scripting.ScriptListOptions{Page: page, PerPage: defaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Items {
+			if s.Deleted {
+				continue
+			}
+			script, err := e.exportEdgeScript(ctx, s)
+			if err != nil {
+				return nil, fmt.Errorf("script %d: %w", s.ID, err)
+			}
+			out = append(out, script)
+		}
+		if len(resp.Items) == 0 || !resp.HasMoreItems {
+			return out, nil
+		}
+	}
+}
+
+func (e *Exporter) exportEdgeScript(ctx context.Context, s scripting.EdgeScript) (EdgeScript, error) {
+	if s.DeploymentKey != nil {
+		redacted := Redacted
+		s.DeploymentKey = &redacted
+	}
+	script := EdgeScript{Script: s}
+
+	code, err := e.scripting.Code(s.ID).Get(ctx)
+	if err != nil {
+		return script, fmt.Errorf("code: %w", err)
+	}
+	if code.Code != nil {
+		script.Code = *code.Code
+	}
+
+	secrets, err := e.scripting.Secrets(s.ID).List(ctx)
+	if err != nil {
+		return script, fmt.Errorf("secrets: %w", err)
+	}
+	script.Secrets = secrets.Secrets
+
+	for page := 1; len(script.Releases) < e.releases; page++ {
+		resp, err := e.scripting.Releases(s.ID).List(ctx, &scripting.ReleaseListOptions{Page: page, PerPage: e.releases})
+		if err != nil {
+			return script, fmt.Errorf("releases: %w", err)
+		}
+		script.Releases = append(script.Releases, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMoreItems {
+			break
+		}
+	}
+	if len(script.Releases) > e.releases {
+		script.Releases = script.Releases[:e.releases]
+	}
+	return script, nil
+}
+
+func (e *Exporter) exportContainerApps(ctx context.Context) ([]ContainerApp, error) {
+	apps := e.containers.Applications()
+	var out []ContainerApp
+	opts := &containers.ListOptions{}
+	for {
+		resp, err := apps.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			app, err := apps.Get(ctx, item.ID)
+			if err != nil {
+				return nil, fmt.Errorf("app %s: %w", item.ID, err)
+			}
+			endpoints, err := e.containers.Endpoints(item.ID).List(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("app %s: endpoints: %w", item.ID, err)
+			}
+			redactEnvironment(app)
+			out = append(out, ContainerApp{Application: *app, Endpoints: endpoints.Items})
+		}
+		if resp.Cursor == "" || len(resp.Items) == 0 {
+			return out, nil
+		}
+		opts = &containers.ListOptions{NextCursor: resp.Cursor}
+	}
+}
+
+func redactEnvironment(app *containers.Application) {
+	for i := range app.ContainerTemplates {
+		for j, v := range app.ContainerTemplates[i].EnvironmentVariables {
+			if redact.SecretName(v.Name) {
+				app.ContainerTemplates[i].EnvironmentVariables[j].Value = Redacted
+			}
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/reconcile"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+// Spec converts the snapshot into a reconcile spec describing the same resources.
+//
+// Every section present in the snapshot is managed, so restoring with pruning
+// removes resources created after the snapshot was taken. Redacted container
+// environment variables are listed in KeepEnvironment, so patching a template
+// keeps their live values. Collections, bot detection settings and edge
+// script variables are restored by the steps RestorePlan adds after the plan.
+func (s *Snapshot) Spec() *reconcile.Spec {
+	spec := &reconcile.Spec{}
+
+	if s.StorageZones != nil {
+		spec.StorageZones = make([]reconcile.StorageZoneSpec, 0, len(s.StorageZones))
+		for _, z := range s.StorageZones {
+			rewrite := z.Rewrite404To200
+			spec.StorageZones = append(spec.StorageZones, reconcile.StorageZoneSpec{
+				Name:               z.Name,
+				Region:             z.Region,
+				ReplicationRegions: z.ReplicationRegions,
+				OriginURL:          z.OriginURL,
+				Custom404FilePath:  z.Custom404FilePath,
+				Rewrite404To200:    &rewrite,
+			})
+		}
+	}
+
+	if s.StreamLibraries != nil {
+		spec.StreamLibraries = make([]reconcile.StreamLibrarySpec, 0, len(s.StreamLibraries))
+		for _, l := range s.StreamLibraries {
+			days := l.Library.VideoCacheExpirationDays
+			spec.StreamLibraries = append(spec.StreamLibraries, reconcile.StreamLibrarySpec{
+				Name:                     l.Library.Name,
+				VideoCacheExpirationDays: &days,
+			})
+		}
+	}
+
+	if s.ShieldZones != nil {
+		spec.ShieldZones = make([]reconcile.ShieldZoneSpec, 0, len(s.ShieldZones))
+		for _, z := range s.ShieldZones {
+			spec.ShieldZones = append(spec.ShieldZones, shieldZoneSpec(z))
+		}
+	}
+
+	if s.EdgeScripts != nil {
+		spec.EdgeScripts = make([]reconcile.EdgeScriptSpec, 0, len(s.EdgeScripts))
+		for _, e := range s.EdgeScripts {
+			var name string
+			if e.Script.Name != nil {
+				name = *e.Script.Name
+			}
+			spec.EdgeScripts = append(spec.EdgeScripts, reconcile.EdgeScriptSpec{
+				Name: name,
+				Type: e.Script.ScriptType,
+				Code: e.Code,
+			})
+		}
+	}
+
+	if s.ContainerApps != nil {
+		spec.ContainerApps = make([]reconcile.ContainerAppSpec, 0, len(s.ContainerApps))
+		for _, a := range s.ContainerApps {
+			spec.ContainerApps = append(spec.ContainerApps, containerAppSpec(a.Application))
+		}
+	}
+	return spec
+}
+
+// BotDetectionClient is the subset of *shield.Client used to restore bot detection settings.
+type BotDetectionClient interface {
+	Zones() shield.ZoneService
+	BotDetection(zoneID string) shield.BotDetectionService
+}
+
+// VariableClient is the subset of *scripting.Client used to restore edge script variables.
+type VariableClient interface {
+	Scripts() scripting.ScriptService
+	Variables(scriptID int64) scripting.VariableService
+}
+
+// RestoreOption is a functional option for RestorePlan.
+type RestoreOption func(*restorer)
+
+type restorer struct {
+	libraries    stream.LibraryService
+	collections  CollectionServiceFunc
+	botDetection BotDetectionClient
+	variables    VariableClient
+}
+
+// WithCollectionRestore restores stream collections missing from their library.
+// Existing collections are kept, since deleting one would orphan its videos.
+func WithCollectionRestore(libraries stream.LibraryService, collections CollectionServiceFunc) RestoreOption {
+	return func(r *restorer) {
+		r.libraries = libraries
+		r.collections = collections
+	}
+}
+
+// WithBotDetectionRestore restores the bot detection settings of shield zones.
+func WithBotDetectionRestore(client BotDetectionClient) RestoreOption {
+	return func(r *restorer) {
+		r.botDetection = client
+	}
+}
+
+// WithVariableRestore restores edge script variables. Variables added after
+// the snapshot was taken are kept.
+func WithVariableRestore(client VariableClient) RestoreOption {
+	return func(r *restorer) {
+		r.variables = client
+	}
+}
+
+// Step restores snapshot content the reconcile spec cannot express.
+// Steps look their parent resource up by name when they run, so they also
+// cover libraries, zones and scripts created by the plan.
+type Step struct {
+	Kind string
+	Name string
+
+	apply func(ctx context.Context) error
+}
+
+// Address returns the address of the step, e.g. stream_collections.videos.
+func (s Step) Address() string {
+	return s.Kind + "." + s.Name
+}
+
+// Restore is the plan that brings an account back to a snapshot.
+type Restore struct {
+	// Plan is the reconcile plan for the resources Spec covers.
+	Plan *reconcile.Plan
+	// Steps run in order once Plan has been applied.
+	Steps []Step
+	// Unrestorable lists snapshot content that neither Plan nor Steps bring back.
+	Unrestorable []string
+
+	rec *reconcile.Reconciler
+}
+
+// Apply applies the reconcile plan and then runs the restore steps.
+func (r *Restore) Apply(ctx context.Context) error {
+	if err := r.rec.Apply(ctx, r.Plan); err != nil {
+		return err
+	}
+	for _, step := range r.Steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := step.apply(ctx); err != nil {
+			return fmt.Errorf("snapshot: restore %s: %w", step.Address(), err)
+		}
+	}
+	return nil
+}
+
+// Unrestorable lists the parts of the snapshot no restore path covers:
+// edge script secrets and releases, and container endpoints. Secret values
+// are not part of the snapshot, releases are immutable history, and
+// endpoints are managed separately from their application.
+func (s *Snapshot) Unrestorable() []string {
+	var items []string
+	add := func(owner string, n int, what string) {
+		if n > 0 {
+			items = append(items, fmt.Sprintf("%s: %d %s", owner, n, what))
+		}
+	}
+	for _, e := range s.EdgeScripts {
+		name := edgeScriptName(e.Script)
+		add(name, len(e.Secrets), "secrets")
+		add(name, len(e.Releases), "releases")
+	}
+	for _, a := range s.ContainerApps {
+		add("container app "+a.Application.Name, len(a.Endpoints), "endpoints")
+	}
+	return items
+}
+
+// RestorePlan validates the snapshot and returns what brings the account back to it.
+// Review the plan and steps, then call Apply.
+//
+// Collections, bot detection settings and edge script variables are restored
+// by steps when the matching option is given; otherwise they are listed in
+// Unrestorable together with the content no option covers. Callers decide
+// whether a partial restore is acceptable.
+func RestorePlan(ctx context.Context, rec *reconcile.Reconciler, s *Snapshot, opts ...RestoreOption) (*Restore, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	plan, err := rec.Plan(ctx, s.Spec())
+	if err != nil {
+		return nil, err
+	}
+	r := &restorer{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	restore := &Restore{Plan: plan, rec: rec}
+	skip := func(owner string, what string, option string) {
+		restore.Unrestorable = append(restore.Unrestorable, fmt.Sprintf("%s: %s (%s not configured)", owner, what, option))
+	}
+
+	for _, l := range s.StreamLibraries {
+		if len(l.Collections) == 0 {
+			continue
+		}
+		if r.collections == nil {
+			skip("stream library "+l.Library.Name, fmt.Sprintf("%d collections", len(l.Collections)), "WithCollectionRestore")
+			continue
+		}
+		restore.Steps = append(restore.Steps, r.collectionStep(l))
+	}
+	for _, z := range s.ShieldZones {
+		if z.BotDetection == nil {
+			continue
+		}
+		if r.botDetection == nil {
+			skip("shield zone "+z.Zone.Name, "bot detection settings", "WithBotDetectionRestore")
+			continue
+		}
+		restore.Steps = append(restore.Steps, r.botDetectionStep(z.Zone.Name, *z.BotDetection))
+	}
+	for _, e := range s.EdgeScripts {
+		if len(e.Script.EdgeScriptVariables) == 0 {
+			continue
+		}
+		if r.variables == nil {
+			skip(edgeScriptName(e.Script), fmt.Sprintf("%d variables", len(e.Script.EdgeScriptVariables)), "WithVariableRestore")
+			continue
+		}
+		restore.Steps = append(restore.Steps, r.variableStep(e.Script))
+	}
+	restore.Unrestorable = append(restore.Unrestorable, s.Unrestorable()...)
+	return restore, nil
+}
+
+func (r *restorer) collectionStep(l StreamLibrary) Step {
+	name := l.Library.Name
+	return Step{
+		Kind: "stream_collections",
+		Name: name,
+		apply: func(ctx context.Context) error {
+			library, err := findLibrary(ctx, r.libraries, name)
+			if err != nil {
+				return err
+			}
+			svc := r.collections(library.LibraryID)
+			live, err := listCollections(ctx, svc)
+			if err != nil {
+				return err
+			}
+			existing := make(map[string]bool, len(live))
+			for _, c := range live {
+				existing[c.Name] = true
+			}
+			for _, c := range l.Collections {
+				if existing[c.Name] {
+					continue
+				}
+				if _, err := svc.Create(ctx, &stream.CreateCollectionRequest{Name: c.Name}); err != nil {
+					return fmt.Errorf("collection %q: %w", c.Name, err)
+				}
+				existing[c.Name] = true
+			}
+			return nil
+		},
+	}
+}
+
+func (r *restorer) botDetectionStep(zoneName string, settings shield.BotDetectionSettings) Step {
+	enabled := settings.IsEnabled
+	req := &shield.UpdateBotDetectionRequest{
+		IsEnabled:      &enabled,
+		DetectionLevel: settings.DetectionLevel,
+		Action:         settings.Action,
+		AllowedBots:    settings.AllowedBots,
+		BlockedBots:    settings.BlockedBots,
+	}
+	return Step{
+		Kind: "shield_bot_detection",
+		Name: zoneName,
+		apply: func(ctx context.Context) error {
+			zones, err := r.botDetection.Zones().List(ctx)
+			if err != nil {
+				return err
+			}
+			for _, z := range zones.Items {
+				if z.Name == zoneName {
+					_, err := r.botDetection.BotDetection(z.ID).Update(ctx, req)
+					return err
+				}
+			}
+			return fmt.Errorf("shield zone %q not found", zoneName)
+		},
+	}
+}
+
+func (r *restorer) variableStep(script scripting.EdgeScript) Step {
+	var name string
+	if script.Name != nil {
+		name = *script.Name
+	}
+	reqs := make([]*scripting.UpsertVariableRequest, 0, len(script.EdgeScriptVariables))
+	for _, v := range script.EdgeScriptVariables {
+		if v.Name == nil {
+			continue
+		}
+		req := &scripting.UpsertVariableRequest{Name: *v.Name, Required: &v.Required}
+		if v.DefaultValue != nil {
+			req.DefaultValue = *v.DefaultValue
+		}
+		reqs = append(reqs, req)
+	}
+	return Step{
+		Kind: "edge_script_variables",
+		Name: name,
+		apply: func(ctx context.Context) error {
+			id, err := findScript(ctx, r.variables.Scripts(), name)
+			if err != nil {
+				return err
+			}
+			for _, req := range reqs {
+				if _, err := r.variables.Variables(id).Upsert(ctx, req); err != nil {
+					return fmt.Errorf("variable %q: %w", req.Name, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func findLibrary(ctx context.Context, libraries stream.LibraryService, name string) (*stream.Library, error) {
+	for page := 1; ; page++ {
+		resp, err := libraries.List(ctx, &stream.LibraryListOptions{Page: page, ItemsPerPage: defaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range resp.Items {
+			if l.Name == name {
+				return &l, nil
+			}
+		}
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			return nil, fmt.Errorf("stream library %q not found", name)
+		}
+	}
+}
+
+func findScript(ctx context.Context, scripts scripting.ScriptService, name string) (int64, error) {
+	for page := 1; ; page++ {
+		resp, err := scripts.List(ctx, &scripting.ScriptListOptions{Page: page, PerPage: defaultPageSize})
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range resp.Items {
+			if s.Name != nil && *s.Name == name && !s.Deleted {
+				return s.ID, nil
+			}
+		}
+		if len(resp.Items) == 0 || !resp.HasMoreItems {
+			return 0, fmt.Errorf("edge script %q not found", name)
+		}
+	}
+}
+
+func edgeScriptName(s scripting.EdgeScript) string {
+	if s.Name != nil {
+		return "edge script " + *s.Name
+	}
+	return "edge script " + strconv.FormatInt(s.ID, 10)
+}
+
+func shieldZoneSpec(z ShieldZone) reconcile.ShieldZoneSpec {
+	spec := reconcile.ShieldZoneSpec{
+		Name:        z.Zone.Name,
+		HostNames:   z.Zone.HostNames,
+		CustomRules: make([]reconcile.CustomRuleSpec, 0, len(z.CustomRules)),
+		RateLimits:  make([]reconcile.RateLimitSpec, 0, len(z.RateLimits)),
+	}
+	for _, r := range z.CustomRules {
+		active := r.IsActive
+		spec.CustomRules = append(spec.CustomRules, reconcile.CustomRuleSpec{
+			Name:        r.Name,
+			Description: r.Description,
+			Pattern:     r.Pattern,
+			Action:      r.Action,
+			Active:      &active,
+		})
+	}
+	for _, l := range z.RateLimits {
+		active := l.IsActive
+		spec.RateLimits = append(spec.RateLimits, reconcile.RateLimitSpec{
+			Name:              l.Name,
+			Path:              l.Path,
+			RequestsPerSecond: l.RequestsPerSecond,
+			RequestsPerMinute: l.RequestsPerMinute,
+			Action:            l.Action,
+			Active:            &active,
+		})
+	}
+	if z.AccessList != nil {
+		spec.AccessList = []reconcile.AccessEntrySpec{}
+		for _, group := range [][]shield.AccessListEntry{z.AccessList.Allowed, z.AccessList.Blocked, z.AccessList.Challenged} {
+			for _, e := range group {
+				spec.AccessList = append(spec.AccessList, reconcile.AccessEntrySpec{
+					Type:    e.Type,
+					Value:   e.Value,
+					Action:  e.Action,
+					Comment: e.Comment,
+				})
+			}
+		}
+	}
+	return spec
+}
+
+func containerAppSpec(app containers.Application) reconcile.ContainerAppSpec {
+	spec := reconcile.ContainerAppSpec{
+		Name:        app.Name,
+		RuntimeType: app.RuntimeType,
+		AutoScaling: app.AutoScaling,
+	}
+	if app.RegionSettings != nil {
+		spec.RegionSettings = &containers.CreateRegionSettingsRequest{
+			AllowedRegionIds:  app.RegionSettings.AllowedRegionIds,
+			RequiredRegionIds: app.RegionSettings.RequiredRegionIds,
+			MaxAllowedRegions: app.RegionSettings.MaxAllowedRegions,
+		}
+	}
+	for _, t := range app.ContainerTemplates {
+		tmpl := containers.CreateContainerTemplateRequest{
+			Name:            t.Name,
+			Image:           t.Image,
+			ImageName:       t.ImageName,
+			ImageNamespace:  t.ImageNamespace,
+			ImageTag:        t.ImageTag,
+			ImageRegistryID: t.ImageRegistryID,
+			ImageDigest:     t.ImageDigest,
+			ImagePullPolicy: t.ImagePullPolicy,
+			EntryPoint:      t.EntryPoint,
+			Probes:          t.Probes,
+		}
+		for _, v := range t.EnvironmentVariables {
+			switch {
+			case v.Value != Redacted:
+				tmpl.EnvironmentVariables = append(tmpl.EnvironmentVariables, v)
+			case !slices.Contains(spec.KeepEnvironment, v.Name):
+				spec.KeepEnvironment = append(spec.KeepEnvironment, v.Name)
+			}
+		}
+		spec.ContainerTemplates = append(spec.ContainerTemplates, tmpl)
+	}
+	return spec
+}
@@ -0,0 +1,112 @@
+// Package snapshot exports the configuration of a Bunny.net account to a
+// portable, versioned JSON archive and restores it through the reconcile package.
+//
+// Secrets are never written: storage zone passwords, edge script deployment
+// keys, edge script secret values and sensitive-looking container environment
+// variables are replaced with Redacted.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/redact"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+// FormatVersion is the archive format written by Encode.
+const FormatVersion = 1
+
+// Redacted replaces secret values in a snapshot.
+const Redacted = redact.Placeholder
+
+// Snapshot is a point-in-time copy of an account's configuration.
+type Snapshot struct {
+	Version         int             `json:"version"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	StorageZones    []storage.Zone  `json:"storageZones,omitempty"`
+	StreamLibraries []StreamLibrary `json:"streamLibraries,omitempty"`
+	ShieldZones     []ShieldZone    `json:"shieldZones,omitempty"`
+	EdgeScripts     []EdgeScript    `json:"edgeScripts,omitempty"`
+	ContainerApps   []ContainerApp  `json:"containerApps,omitempty"`
+}
+
+// StreamLibrary is a stream library and its collections.
+type StreamLibrary struct {
+	Library     stream.Library      `json:"library"`
+	Collections []stream.Collection `json:"collections,omitempty"`
+}
+
+// ShieldZone is a shield zone and its security configuration.
+type ShieldZone struct {
+	Zone         shield.ShieldZone            `json:"zone"`
+	CustomRules  []shield.CustomRule          `json:"customRules,omitempty"`
+	RateLimits   []shield.RateLimit           `json:"rateLimits,omitempty"`
+	AccessList   *shield.AccessList           `json:"accessList,omitempty"`
+	BotDetection *shield.BotDetectionSettings `json:"botDetection,omitempty"`
+}
+
+// EdgeScript is an edge script with its code, secrets and release history.
+// Secret values are not readable through the API, so only names are kept.
+type EdgeScript struct {
+	Script   scripting.EdgeScript          `json:"script"`
+	Code     string                        `json:"code"`
+	Secrets  []scripting.EdgeScriptSecret  `json:"secrets,omitempty"`
+	Releases []scripting.EdgeScriptRelease `json:"releases,omitempty"`
+}
+
+// ContainerApp is a container application with its endpoints.
+// Templates, autoscaling and region settings are part of Application.
+type ContainerApp struct {
+	Application containers.Application `json:"application"`
+	Endpoints   []containers.Endpoint  `json:"endpoints,omitempty"`
+}
+
+// Encode writes s to w as indented JSON.
+func Encode(w io.Writer, s *Snapshot) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("snapshot: encode: %w", err)
+	}
+	return nil
+}
+
+// Decode reads and validates a snapshot archive.
+// Unknown fields are rejected so that archives from a newer format are not silently truncated.
+func Decode(r io.Reader) (*Snapshot, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var s Snapshot
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("snapshot: decode: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate checks the format version and that the snapshot can be restored.
+func (s *Snapshot) Validate() error {
+	if s.Version != FormatVersion {
+		return fmt.Errorf("snapshot: unsupported format version %d (want %d)", s.Version, FormatVersion)
+	}
+	if s.CreatedAt.IsZero() {
+		return errors.New("snapshot: missing createdAt")
+	}
+	if err := s.Spec().Validate(); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/containers"
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/reconcile"
+	"github.com/geraldo/bunny-sdk-go/scripting"
+	"github.com/geraldo/bunny-sdk-go/shield"
+	"github.com/geraldo/bunny-sdk-go/snapshot"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+func routes(routes map[string]string) *testutil.MockHTTPClient {
+	return &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			body, ok := routes[req.Method+" "+req.URL.Path]
+			if !ok {
+				return testutil.NewMockResponse(404, `{"Message":"not found"}`), nil
+			}
+			return testutil.NewMockResponse(200, body), nil
+		},
+	}
+}
+
+var snapshotTime = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func export(t *testing.T) *snapshot.Snapshot {
+	t.Helper()
+	hc := routes(map[string]string{
+		"GET /storagezone": `{"Items":[{"Id":1,"Name":"assets","Region":"DE","Password":"pw","ReadOnlyPassword":"ro"},
+			{"Id":2,"Name":"gone","Deleted":true}],"TotalItems":2,"CurrentPage":1,"PageSize":1000}`,
+		"GET /shield/zones":                   `{"Items":[{"Id":"sz-1","Name":"web","HostNames":["example.com"]}],"TotalCount":1}`,
+		"GET /shield/waf/custom-rules":        `{"Items":[{"Id":"cr-1","Name":"block-admin","Action":"block","ShieldZoneId":"sz-1","IsActive":true},{"Id":"cr-2","Name":"other","ShieldZoneId":"sz-9"}],"TotalCount":2}`,
+		"GET /shield/rate-limits":             `{"Items":[],"TotalCount":0}`,
+		"GET /shield/zone/sz-1/access-lists":  `{"Blocked":[{"Type":"ip","Value":"203.0.113.7","Action":"block"}]}`,
+		"GET /shield/zone/sz-1/bot-detection": `{"IsEnabled":true,"DetectionLevel":"medium"}`,
+		"GET /compute/script":                 `{"Items":[{"Id":7,"Name":"router","ScriptType":"Middleware","DeploymentKey":"dk-secret"}],"CurrentPage":1,"TotalItems":1,"HasMoreItems":false}`,
+		"GET /compute/script/7/code":          `{"Code":"export default () => new Response(\"ok\")"}`,
+		"GET /compute/script/7/secrets":       `{"Secrets":[{"Id":1,"Name":"API_TOKEN"}]}`,
+		"GET /compute/script/7/releases":      `{"Items":[{"Id":3,"Note":"first"}],"CurrentPage":1,"TotalItems":1,"HasMoreItems":false}`,
+	})
+
+	exp := snapshot.NewExporter(
+		snapshot.WithStorage(storage.NewClient("key", storage.WithHTTPClient(hc)).Zones()),
+		snapshot.WithShield(shield.NewClient("key", shield.WithHTTPClient(hc))),
+		snapshot.WithScripting(scripting.NewClient("key", scripting.WithHTTPClient(hc))),
+		snapshot.WithClock(func() time.Time { return snapshotTime }),
+	)
+	s, err := exp.Export(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return s
+}
+
+func TestExporter_Export(t *testing.T) {
+	s := export(t)
+
+	if s.Version != snapshot.FormatVersion || !s.CreatedAt.Equal(snapshotTime) {
+		t.Errorf("unexpected header: version=%d createdAt=%s", s.Version, s.CreatedAt)
+	}
+	if len(s.StorageZones) != 1 {
+		t.Fatalf("expected deleted zones to be skipped, got %d zones", len(s.StorageZones))
+	}
+	if z := s.StorageZones[0]; z.Password != snapshot.Redacted || z.ReadOnlyPassword != snapshot.Redacted {
+		t.Errorf("expected zone passwords to be redacted, got %q/%q", z.Password, z.ReadOnlyPassword)
+	}
+	if len(s.ShieldZones) != 1 || len(s.ShieldZones[0].CustomRules) != 1 {
+		t.Fatalf("expected custom rules grouped by zone, got %+v", s.ShieldZones)
+	}
+	if s.ShieldZones[0].BotDetection == nil || !s.ShieldZones[0].BotDetection.IsEnabled {
+		t.Error("expected bot detection settings")
+	}
+	script := s.EdgeScripts[0]
+	if *script.Script.DeploymentKey != snapshot.Redacted {
+		t.Errorf("expected deployment key to be redacted, got %q", *script.Script.DeploymentKey)
+	}
+	if !strings.Contains(script.Code, "Response") || len(script.Secrets) != 1 || len(script.Releases) != 1 {
+		t.Errorf("unexpected edge script: %+v", script)
+	}
+	if s.StreamLibraries != nil || s.ContainerApps != nil {
+		t.Error("expected services without options to be left out")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	s := export(t)
+
+	var buf bytes.Buffer
+	if err := snapshot.Encode(&buf, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, secret := range []string{`"pw"`, "dk-secret"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("archive contains secret %s", secret)
+		}
+	}
+
+	decoded, err := snapshot.Decode(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.ShieldZones[0].Zone.Name != "web" || decoded.EdgeScripts[0].Code != s.EdgeScripts[0].Code {
+		t.Errorf("round trip mismatch: %+v", decoded)
+	}
+
+	tests := map[string]string{
+		"bad version":   `{"version":99,"createdAt":"2026-10-18T00:00:00Z"}`,
+		"no timestamp":  `{"version":1}`,
+		"unknown field": `{"version":1,"createdAt":"2026-10-18T00:00:00Z","pullZones":[]}`,
+		"duplicate":     `{"version":1,"createdAt":"2026-10-18T00:00:00Z","storageZones":[{"Name":"a"},{"Name":"a"}]}`,
+	}
+	for name, archive := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := snapshot.Decode(strings.NewReader(archive)); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestRestorePlan(t *testing.T) {
+	s := export(t)
+
+	spec := s.Spec()
+	if len(spec.ShieldZones[0].AccessList) != 1 || spec.EdgeScripts[0].Name != "router" {
+		t.Fatalf("unexpected spec: %+v", spec)
+	}
+
+	empty := routes(map[string]string{
+		"GET /storagezone":             `{"Items":[],"TotalItems":0}`,
+		"GET /shield/zones":            `{"Items":[],"TotalCount":0}`,
+		"GET /shield/waf/custom-rules": `{"Items":[],"TotalCount":0}`,
+		"GET /shield/rate-limits":      `{"Items":[],"TotalCount":0}`,
+		"GET /compute/script":          `{"Items":[],"HasMoreItems":false}`,
+	})
+	rec := reconcile.NewReconciler(
+		reconcile.WithStorage(storage.NewClient("key", storage.WithHTTPClient(empty)).Zones()),
+		reconcile.WithShield(shield.NewClient("key", shield.WithHTTPClient(empty))),
+		reconcile.WithScripting(scripting.NewClient("key", scripting.WithHTTPClient(empty))),
+	)
+	restore, err := snapshot.RestorePlan(context.Background(), rec, s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"shield zone web: bot detection settings (WithBotDetectionRestore not configured)",
+		"edge script router: 1 secrets",
+		"edge script router: 1 releases",
+	}
+	if !slices.Equal(restore.Unrestorable, want) {
+		t.Errorf("unrestorable = %q, want %q", restore.Unrestorable, want)
+	}
+	if len(restore.Steps) != 0 {
+		t.Errorf("expected no steps without restore options, got %v", restore.Steps)
+	}
+	// storage zone, shield zone, custom rule, access entry, edge script
+	if create, update, del := restore.Plan.Counts(); create != 5 || update != 0 || del != 0 {
+		t.Errorf("expected 5 creates, got %d/%d/%d\n%s", create, update, del, restore.Plan)
+	}
+}
+
+func TestRestore_Steps(t *testing.T) {
+	var calls []string
+	hc := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			route := req.Method + " " + req.URL.Path
+			switch route {
+			case "GET /videolibrary":
+				return testutil.NewMockResponse(200, `{"items":[{"libraryId":5,"name":"videos"}],"currentPage":1,"itemsPerPage":1000,"totalItems":1}`), nil
+			case "GET /library/5/collections":
+				return testutil.NewMockResponse(200, `{"items":[{"guid":"c1","name":"trailers"}],"currentPage":1,"itemsPerPage":1000,"totalItems":1}`), nil
+			case "GET /shield/zones":
+				return testutil.NewMockResponse(200, `{"Items":[{"Id":"sz-1","Name":"web"}],"TotalCount":1}`), nil
+			case "GET /compute/script":
+				return testutil.NewMockResponse(200, `{"Items":[{"Id":7,"Name":"router"}],"HasMoreItems":false}`), nil
+			case "GET /shield/waf/custom-rules", "GET /shield/rate-limits":
+				return testutil.NewMockResponse(200, `{"Items":[],"TotalCount":0}`), nil
+			}
+			b, _ := io.ReadAll(req.Body)
+			calls = append(calls, route+" "+string(b))
+			return testutil.NewMockResponse(200, `{}`), nil
+		},
+	}
+	name, varName, value := "router", "REGION", "eu"
+	s := &snapshot.Snapshot{
+		Version:   snapshot.FormatVersion,
+		CreatedAt: snapshotTime,
+		StreamLibraries: []snapshot.StreamLibrary{{
+			Library:     stream.Library{Name: "videos"},
+			Collections: []stream.Collection{{Name: "trailers"}, {Name: "episodes"}},
+		}},
+		ShieldZones: []snapshot.ShieldZone{{
+			Zone:         shield.ShieldZone{Name: "web"},
+			BotDetection: &shield.BotDetectionSettings{IsEnabled: false, DetectionLevel: "high"},
+		}},
+		EdgeScripts: []snapshot.EdgeScript{{Script: scripting.EdgeScript{
+			ID:                  7,
+			Name:                &name,
+			EdgeScriptVariables: []scripting.EdgeScriptVariable{{Name: &varName, Required: true, DefaultValue: &value}},
+		}}},
+	}
+
+	streamClient := stream.NewClient("key", stream.WithHTTPClient(hc))
+	shieldClient := shield.NewClient("key", shield.WithHTTPClient(hc))
+	scriptingClient := scripting.NewClient("key", scripting.WithHTTPClient(hc))
+	rec := reconcile.NewReconciler(
+		reconcile.WithStream(streamClient.Libraries()),
+		reconcile.WithShield(shieldClient),
+		reconcile.WithScripting(scriptingClient),
+	)
+	restore, err := snapshot.RestorePlan(context.Background(), rec, s,
+		snapshot.WithCollectionRestore(streamClient.Libraries(), streamClient.Collections),
+		snapshot.WithBotDetectionRestore(shieldClient),
+		snapshot.WithVariableRestore(scriptingClient),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(restore.Unrestorable) != 0 {
+		t.Errorf("unexpected unrestorable items: %q", restore.Unrestorable)
+	}
+	var addresses []string
+	for _, step := range restore.Steps {
+		addresses = append(addresses, step.Address())
+	}
+	if want := []string{"stream_collections.videos", "shield_bot_detection.web", "edge_script_variables.router"}; !slices.Equal(addresses, want) {
+		t.Errorf("steps = %q, want %q", addresses, want)
+	}
+
+	if err := restore.Apply(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`POST /library/5/collections {"name":"episodes"}`,
+		`PATCH /shield/zone/sz-1/bot-detection {"IsEnabled":false,"DetectionLevel":"high"}`,
+		`PUT /compute/script/7/variables {"Name":"REGION","Required":true,"DefaultValue":"eu"}`,
+	}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %q\nwant %q", calls, want)
+	}
+}
+
+func TestRestorePlan_KeepsRedactedEnvironment(t *testing.T) {
+	var patch string
+	hc := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			switch req.Method + " " + strings.TrimPrefix(req.URL.Path, "/mc") {
+			case "GET /apps":
+				return testutil.NewMockResponse(200, `{"items":[{"id":"a1","name":"api"}]}`), nil
+			case "GET /apps/a1":
+				return testutil.NewMockResponse(200, `{"id":"a1","name":"api","containerTemplates":[{"name":"web","imageName":"api","imageTag":"1.0",
+					"environmentVariables":[{"name":"DB_PASSWORD","value":"live-secret"},{"name":"LOG","value":"debug"}]}]}`), nil
+			case "PATCH /apps/a1":
+				b, _ := io.ReadAll(req.Body)
+				patch = string(b)
+				return testutil.NewMockResponse(200, `{"id":"a1","name":"api"}`), nil
+			}
+			return testutil.NewMockResponse(404, `{"Message":"not found"}`), nil
+		},
+	}
+	s := &snapshot.Snapshot{
+		Version:   snapshot.FormatVersion,
+		CreatedAt: snapshotTime,
+		ContainerApps: []snapshot.ContainerApp{{Application: containers.Application{
+			Name: "api",
+			ContainerTemplates: []containers.ContainerTemplate{{
+				Name: "web", ImageName: "api", ImageTag: "2.0",
+				EnvironmentVariables: []containers.EnvironmentVariable{{Name: "DB_PASSWORD", Value: snapshot.Redacted}, {Name: "LOG", Value: "info"}},
+			}},
+		}}},
+	}
+
+	rec := reconcile.NewReconciler(reconcile.WithContainers(containers.NewClient("key", containers.WithHTTPClient(hc)).Applications()))
+	restore, err := snapshot.RestorePlan(context.Background(), rec, s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := restore.Apply(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(patch, `{"name":"LOG","value":"info"}`) || !strings.Contains(patch, `{"name":"DB_PASSWORD","value":"live-secret"}`) {
+		t.Errorf("expected the snapshot value and the live secret in the patch, got %s", patch)
+	}
+	if strings.Contains(patch, snapshot.Redacted) {
+		t.Errorf("patch sent a redacted placeholder: %s", patch)
+	}
+}
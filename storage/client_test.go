@@ -2,10 +2,13 @@ package storage_test
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/geraldo/bunny-sdk-go/internal/testutil"
 	"github.com/geraldo/bunny-sdk-go/storage"
@@ -184,6 +187,105 @@ func TestFileService_List(t *testing.T) {
 	}
 }
 
+func TestFileService_ListIter(t *testing.T) {
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if !strings.HasSuffix(req.URL.Path, "/files/") {
+				t.Errorf("unexpected path: %s", req.URL.Path)
+			}
+			body := `[{"ObjectName":"a.txt","Length":1},{"ObjectName":"b","IsDirectory":true},{"ObjectName":"c.txt"}]`
+			return testutil.NewMockResponse(200, body), nil
+		},
+	}
+
+	fs := storage.NewFileService("test-zone", "zone-pass", storage.RegionFalkenstein,
+		storage.WithFileHTTPClient(mock))
+	var names []string
+	for f, err := range storage.ListIter(context.Background(), fs, "files") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names = append(names, f.ObjectName)
+		if len(names) == 2 {
+			break
+		}
+	}
+	if strings.Join(names, ",") != "a.txt,b" {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestFileService_ListIterReusable(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			paths = append(paths, req.URL.Path)
+			mu.Unlock()
+			return testutil.NewMockResponse(200, `[{"ObjectName":"a.txt"}]`), nil
+		},
+	}
+
+	fs := storage.NewFileService("test-zone", "zone-pass", storage.RegionFalkenstein,
+		storage.WithFileHTTPClient(mock))
+	seq := storage.ListIter(context.Background(), fs, "files")
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, err := range seq {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for _, p := range paths {
+		if p != "/test-zone/files/" {
+			t.Errorf("path = %q, want /test-zone/files/", p)
+		}
+	}
+}
+
+func TestFileService_ListIterError(t *testing.T) {
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return testutil.NewMockResponse(403, "Forbidden"), nil
+		},
+	}
+
+	fs := storage.NewFileService("test-zone", "zone-pass", storage.RegionFalkenstein,
+		storage.WithFileHTTPClient(mock))
+	var errs []error
+	for _, err := range storage.ListIter(context.Background(), fs, "path") {
+		errs = append(errs, err)
+	}
+	var apiErr *storage.APIError
+	if len(errs) != 1 || !errors.As(errs[0], &apiErr) || apiErr.StatusCode != 403 {
+		t.Errorf("errs = %v", errs)
+	}
+}
+
+func TestListIter_FallsBackToList(t *testing.T) {
+	files := newMemoryFiles()
+	files.put("docs/a.txt", time.Now(), "a")
+	files.put("docs/b.txt", time.Now(), "b")
+
+	var names []string
+	for f, err := range storage.ListIter(context.Background(), files, "docs") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names = append(names, f.ObjectName)
+	}
+	if strings.Join(names, ",") != "a.txt,b.txt" || files.lists != 1 {
+		t.Errorf("names = %v after %d listings", names, files.lists)
+	}
+}
+
 func TestFileService_Delete(t *testing.T) {
 	mock := &testutil.MockHTTPClient{
 		DoFunc: func(req *http.Request) (*http.Response, error) {
@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/geraldo/bunny-sdk-go/credential"
 	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/internal/pipeline"
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // FileService provides methods for file operations in a storage zone.
@@ -20,12 +25,41 @@ type FileService interface {
 	DeleteDirectory(ctx context.Context, path string) error
 }
 
+// FileIterator is implemented by file services that can stream a
+// listing; the services returned by NewFileService do. Use ListIter rather
+// than asserting it directly.
+type FileIterator interface {
+	ListIter(ctx context.Context, path string) iter.Seq2[File, error]
+}
+
+// ListIter lists files and directories at path one entry at a time. It
+// streams the listing when fs implements FileIterator and otherwise yields
+// the result of fs.List.
+func ListIter(ctx context.Context, fs FileService, path string) iter.Seq2[File, error] {
+	if it, ok := fs.(FileIterator); ok {
+		return it.ListIter(ctx, path)
+	}
+	return func(yield func(File, error) bool) {
+		files, err := fs.List(ctx, path)
+		if err != nil {
+			yield(File{}, err)
+			return
+		}
+		for _, f := range files {
+			if !yield(f, nil) {
+				return
+			}
+		}
+	}
+}
+
 type fileService struct {
 	httpClient HTTPClient
 	baseURL    string
 	zoneName   string
-	accessKey  string
+	password   *credential.Secret
 	userAgent  string
+	pipeline   pipeline.Config
 }
 
 // HTTPClient is an interface for making HTTP requests.
@@ -40,12 +74,16 @@ func NewFileService(zoneName, accessKey string, region Region, opts ...FileServi
 		httpClient: http.DefaultClient,
 		baseURL:    RegionBaseURL(region),
 		zoneName:   zoneName,
-		accessKey:  accessKey,
 		userAgent:  "bunny-sdk-go/1.0",
 	}
 	for _, opt := range opts {
 		opt(fs)
 	}
+	if fs.password == nil {
+		fs.password = credential.New(accessKey)
+	}
+	fs.pipeline.Middlewares = append(fs.pipeline.Middlewares, credential.Header("AccessKey", fs.password))
+	fs.httpClient = fs.pipeline.Wrap(fs.httpClient)
 	return fs
 }
 
@@ -66,10 +104,70 @@ func WithFileUserAgent(ua string) FileServiceOption {
 	}
 }
 
+// WithFileMiddleware adds middlewares around the HTTP client used for file
+// operations. The first one added is the outermost.
+func WithFileMiddleware(mws ...middleware.Middleware) FileServiceOption {
+	return func(fs *fileService) {
+		fs.pipeline.Middlewares = append(fs.pipeline.Middlewares, mws...)
+	}
+}
+
+// WithFileLogger logs every file operation to l. Credentials are always
+// redacted and file contents are never logged.
+func WithFileLogger(l *slog.Logger, opts ...middleware.LogOption) FileServiceOption {
+	return func(fs *fileService) {
+		fs.pipeline.Logger = middleware.Log(l, opts...)
+	}
+}
+
+// WithFileTracer starts a span for every file operation, such as
+// "storage.FileService.Download".
+func WithFileTracer(t middleware.Tracer) FileServiceOption {
+	return func(fs *fileService) {
+		fs.pipeline.Tracer = middleware.Trace(t)
+	}
+}
+
+// WithFileMetrics records request counts, latencies, bytes uploaded and
+// downloaded, and retries for every file operation.
+func WithFileMetrics(m middleware.Metrics) FileServiceOption {
+	return func(fs *fileService) {
+		fs.pipeline.Metrics = middleware.Measure(m)
+	}
+}
+
+// WithFileRateLimiter throttles file operations with l and retries 429
+// responses after the Retry-After period. Uploads from a plain io.Reader
+// cannot be replayed and return the 429 instead.
+func WithFileRateLimiter(l *middleware.RateLimiter) FileServiceOption {
+	return func(fs *fileService) {
+		fs.pipeline.RateLimiter = l.Middleware()
+	}
+}
+
+// WithFileCredential reads the zone password from s on every request
+// instead of using the accessKey passed to NewFileService, so it can be
+// replaced while the service is in use. Requests rejected while s is being
+// rotated, for example by a Rotator, are retried with the new password if
+// their body can be replayed.
+func WithFileCredential(s *credential.Secret) FileServiceOption {
+	return func(fs *fileService) {
+		fs.password = s
+	}
+}
+
+// WithFileBaseURL overrides the regional storage endpoint, e.g. to point at a test server.
+func WithFileBaseURL(url string) FileServiceOption {
+	return func(fs *fileService) {
+		fs.baseURL = url
+	}
+}
+
 // Upload uploads a file to the storage zone.
 // The path should not include the zone name (e.g., "documents/report.pdf").
 // Directories are created automatically.
 func (s *fileService) Upload(ctx context.Context, path string, reader io.Reader, opts *UploadOptions) error {
+	ctx = middleware.WithOperation(ctx, "storage.FileService.Upload")
 	fullURL := s.buildURL(path)
 
 	req, err := internal.NewRequest(ctx, http.MethodPut, fullURL, reader)
@@ -107,6 +205,7 @@ func (s *fileService) Upload(ctx context.Context, path string, reader io.Reader,
 // Download downloads a file from the storage zone.
 // The caller is responsible for closing the returned ReadCloser.
 func (s *fileService) Download(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx = middleware.WithOperation(ctx, "storage.FileService.Download")
 	fullURL := s.buildURL(path)
 
 	req, err := internal.NewRequest(ctx, http.MethodGet, fullURL, nil)
@@ -132,6 +231,7 @@ func (s *fileService) Download(ctx context.Context, path string) (io.ReadCloser,
 // List lists files and directories at the given path.
 // The path should be a directory path (trailing slash is added automatically).
 func (s *fileService) List(ctx context.Context, path string) ([]File, error) {
+	ctx = middleware.WithOperation(ctx, "storage.FileService.List")
 	// Ensure trailing slash for directory listing
 	if !strings.HasSuffix(path, "/") {
 		path = path + "/"
@@ -165,8 +265,54 @@ func (s *fileService) List(ctx context.Context, path string) ([]File, error) {
 	return files, nil
 }
 
+// ListIter lists files and directories at the given path like List, but
+// decodes the response one entry at a time as the loop consumes it, so
+// memory use stays flat for directories with many entries. The request is
+// sent when the loop starts; an error is yielded once and ends the loop.
+func (s *fileService) ListIter(ctx context.Context, path string) iter.Seq2[File, error] {
+	ctx = middleware.WithOperation(ctx, "storage.FileService.ListIter")
+	// Build the URL before returning: the iterator may be ranged over more
+	// than once, concurrently, and must not write to the captured path.
+	if !strings.HasSuffix(path, "/") {
+		path = path + "/"
+	}
+	fullURL := s.buildURL(path)
+
+	return func(yield func(File, error) bool) {
+		req, err := internal.NewRequest(ctx, http.MethodGet, fullURL, nil)
+		if err != nil {
+			yield(File{}, err)
+			return
+		}
+
+		s.setHeaders(req)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			yield(File{}, fmt.Errorf("list failed: %w", err))
+			return
+		}
+		if resp.StatusCode >= 400 {
+			yield(File{}, s.handleError(resp))
+			return
+		}
+		defer resp.Body.Close()
+
+		stopped := false
+		err = internal.DecodeArray(resp.Body, "", func(f File) bool {
+			stopped = !yield(f, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(File{}, err)
+		}
+	}
+}
+
 // Delete deletes a file from the storage zone.
 func (s *fileService) Delete(ctx context.Context, path string) error {
+	ctx = middleware.WithOperation(ctx, "storage.FileService.Delete")
 	// Ensure no trailing slash for file deletion
 	path = strings.TrimSuffix(path, "/")
 
@@ -194,6 +340,7 @@ func (s *fileService) Delete(ctx context.Context, path string) error {
 
 // DeleteDirectory deletes a directory and all its contents recursively.
 func (s *fileService) DeleteDirectory(ctx context.Context, path string) error {
+	ctx = middleware.WithOperation(ctx, "storage.FileService.DeleteDirectory")
 	// Ensure trailing slash for directory deletion
 	if !strings.HasSuffix(path, "/") {
 		path = path + "/"
@@ -228,7 +375,7 @@ func (s *fileService) buildURL(path string) string {
 }
 
 func (s *fileService) setHeaders(req *http.Request) {
-	req.Header.Set("AccessKey", s.accessKey)
+	req.Header.Set("AccessKey", s.password.Get())
 	req.Header.Set("User-Agent", s.userAgent)
 }
 
@@ -0,0 +1,492 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+)
+
+// LifecycleAction represents what happens to an object once a lifecycle rule expires it.
+type LifecycleAction string
+
+const (
+	LifecycleActionDelete  LifecycleAction = "delete"
+	LifecycleActionArchive LifecycleAction = "archive" // copy to the archive zone, then delete
+)
+
+// LifecycleAgeSource selects the timestamp used to compute an object's age.
+type LifecycleAgeSource string
+
+const (
+	AgeFromLastChanged LifecycleAgeSource = "lastChanged" // default
+	AgeFromDateCreated LifecycleAgeSource = "dateCreated"
+)
+
+const defaultLifecycleConcurrency = 4
+
+// LifecycleRule declares which objects expire and what to do with them.
+//
+// An object is expired when it is under Prefix, matches Glob, and is older than MaxAge.
+// When KeepLast is set, objects are grouped into versions by VersionPattern and the
+// newest KeepLast versions of each group are never expired.
+type LifecycleRule struct {
+	Name string
+
+	// Prefix is the directory scanned recursively; empty scans the whole zone.
+	Prefix string
+
+	// Glob is matched with path.Match against the object name, or against the
+	// zone-relative path when it contains a slash. Empty matches everything.
+	Glob string
+
+	// MaxAge expires objects older than this duration. Zero disables the age check,
+	// which is only valid together with KeepLast.
+	MaxAge  time.Duration
+	AgeFrom LifecycleAgeSource
+
+	// KeepLast protects the newest N versions of each object group.
+	KeepLast int
+
+	// VersionPattern groups objects into versions of the same logical object.
+	// The first capture group (or the group named "name") is the grouping key,
+	// e.g. `^(.*)-\d{8}\.tar\.gz$` groups nightly backups by their base name.
+	// Without a pattern every object in the same directory forms one group.
+	VersionPattern *regexp.Regexp
+
+	Action LifecycleAction
+}
+
+// Validate checks the rule for missing or conflicting settings.
+func (r *LifecycleRule) Validate() error {
+	if r.MaxAge <= 0 && r.KeepLast <= 0 {
+		return fmt.Errorf("lifecycle rule %q: MaxAge or KeepLast is required", r.Name)
+	}
+	if r.KeepLast < 0 {
+		return fmt.Errorf("lifecycle rule %q: KeepLast must not be negative", r.Name)
+	}
+	if r.Glob != "" {
+		if _, err := path.Match(r.Glob, ""); err != nil {
+			return fmt.Errorf("lifecycle rule %q: invalid glob: %w", r.Name, err)
+		}
+	}
+	switch r.Action {
+	case "", LifecycleActionDelete, LifecycleActionArchive:
+	default:
+		return fmt.Errorf("lifecycle rule %q: unknown action %q", r.Name, r.Action)
+	}
+	switch r.AgeFrom {
+	case "", AgeFromLastChanged, AgeFromDateCreated:
+	default:
+		return fmt.Errorf("lifecycle rule %q: unknown age source %q", r.Name, r.AgeFrom)
+	}
+	return nil
+}
+
+// LifecycleOperation records the outcome for a single expired object.
+type LifecycleOperation struct {
+	Rule   string          `json:"rule"`
+	Path   string          `json:"path"`
+	Action LifecycleAction `json:"action"`
+	Size   int64           `json:"size"`
+	Age    time.Duration   `json:"age"`
+	DryRun bool            `json:"dryRun,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// LifecycleResult summarizes a lifecycle run.
+type LifecycleResult struct {
+	Scanned    int                  `json:"scanned"`
+	Deleted    int                  `json:"deleted"`
+	Archived   int                  `json:"archived"`
+	Failed     int                  `json:"failed"`
+	BytesFreed int64                `json:"bytesFreed"`
+	Operations []LifecycleOperation `json:"operations"`
+}
+
+// Lifecycle applies lifecycle rules to a storage zone.
+type Lifecycle struct {
+	files         FileService
+	archive       FileService
+	archivePrefix string
+	dryRun        bool
+	concurrency   int
+	logger        *slog.Logger
+	now           func() time.Time
+}
+
+// LifecycleOption is a functional option for configuring Lifecycle.
+type LifecycleOption func(*Lifecycle)
+
+// WithLifecycleDryRun reports what would expire without changing the zone.
+func WithLifecycleDryRun(dryRun bool) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.dryRun = dryRun
+	}
+}
+
+// WithLifecycleConcurrency limits how many objects are deleted or archived at once.
+func WithLifecycleConcurrency(n int) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.concurrency = n
+	}
+}
+
+// WithLifecycleArchive sets the zone that archive rules copy expired objects into.
+// Objects keep their relative path under the given prefix.
+func WithLifecycleArchive(archive FileService, prefix string) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.archive = archive
+		l.archivePrefix = prefix
+	}
+}
+
+// WithLifecycleLogger sets the logger that records every expiration.
+func WithLifecycleLogger(logger *slog.Logger) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.logger = logger
+	}
+}
+
+// WithLifecycleClock sets the function used as the current time when computing ages.
+func WithLifecycleClock(now func() time.Time) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.now = now
+	}
+}
+
+// NewLifecycle creates a lifecycle engine for the zone behind files.
+func NewLifecycle(files FileService, opts ...LifecycleOption) *Lifecycle {
+	l := &Lifecycle{
+		files:       files,
+		concurrency: defaultLifecycleConcurrency,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.concurrency < 1 {
+		l.concurrency = 1
+	}
+	if l.logger == nil {
+		l.logger = internal.DiscardLogger()
+	}
+	return l
+}
+
+// lifecycleCandidate is an object selected for expiration by a rule.
+type lifecycleCandidate struct {
+	rule *LifecycleRule
+	path string
+	file File
+	age  time.Duration
+}
+
+// Apply evaluates every rule and deletes or archives the expired objects.
+// Each object is handled at most once, by the first rule that expires it.
+// Per-object failures are recorded in the result and returned joined as the error.
+func (l *Lifecycle) Apply(ctx context.Context, rules []LifecycleRule) (*LifecycleResult, error) {
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+		if rules[i].Action == LifecycleActionArchive && l.archive == nil {
+			return nil, fmt.Errorf("lifecycle rule %q: archive action requires WithLifecycleArchive", rules[i].Name)
+		}
+	}
+
+	result := &LifecycleResult{}
+	seen := make(map[string]bool)
+	var candidates []lifecycleCandidate
+
+	listings, err := l.listPrefixes(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	for _, files := range listings {
+		result.Scanned += len(files)
+	}
+
+	now := l.now()
+	for i := range rules {
+		rule := &rules[i]
+		files := filesUnder(listings, strings.Trim(rule.Prefix, "/"))
+		for _, c := range selectExpired(rule, files, now) {
+			if seen[c.path] {
+				continue
+			}
+			seen[c.path] = true
+			candidates = append(candidates, c)
+		}
+	}
+
+	ops := l.execute(ctx, candidates)
+
+	var errs []error
+	for _, op := range ops {
+		switch {
+		case op.Error != "":
+			result.Failed++
+			errs = append(errs, fmt.Errorf("%s: %s", op.Path, op.Error))
+		case op.DryRun:
+		case op.Action == LifecycleActionArchive:
+			result.Archived++
+			result.BytesFreed += op.Size
+		default:
+			result.Deleted++
+			result.BytesFreed += op.Size
+		}
+	}
+	result.Operations = ops
+	return result, errors.Join(errs...)
+}
+
+// listPrefixes walks each distinct rule prefix once. A prefix nested under
+// another rule's prefix is not listed separately; filesUnder serves it from
+// the enclosing listing.
+func (l *Lifecycle) listPrefixes(ctx context.Context, rules []LifecycleRule) (map[string][]lifecycleCandidate, error) {
+	prefixes := make(map[string]string) // prefix -> first rule name
+	for i := range rules {
+		p := strings.Trim(rules[i].Prefix, "/")
+		if _, ok := prefixes[p]; !ok {
+			prefixes[p] = rules[i].Name
+		}
+	}
+	listings := make(map[string][]lifecycleCandidate)
+	for p, name := range prefixes {
+		if _, nested := enclosingPrefix(prefixes, p); nested {
+			continue
+		}
+		files, err := l.walk(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("lifecycle rule %q: %w", name, err)
+		}
+		listings[p] = files
+	}
+	return listings, nil
+}
+
+// enclosingPrefix returns a prefix in set that strictly contains p.
+func enclosingPrefix[V any](set map[string]V, p string) (string, bool) {
+	for p != "" {
+		if i := strings.LastIndexByte(p, '/'); i >= 0 {
+			p = p[:i]
+		} else {
+			p = ""
+		}
+		if _, ok := set[p]; ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// filesUnder returns the listed files below prefix.
+func filesUnder(listings map[string][]lifecycleCandidate, prefix string) []lifecycleCandidate {
+	if files, ok := listings[prefix]; ok {
+		return files
+	}
+	parent, _ := enclosingPrefix(listings, prefix)
+	var out []lifecycleCandidate
+	for _, c := range listings[parent] {
+		if strings.HasPrefix(c.path, prefix+"/") {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// walk lists every file under dir recursively, returning zone-relative paths.
+func (l *Lifecycle) walk(ctx context.Context, dir string) ([]lifecycleCandidate, error) {
+	var out []lifecycleCandidate
+	pending := []string{strings.Trim(dir, "/")}
+
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		entries, err := l.files.List(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("list %q: %w", current, err)
+		}
+		for _, entry := range entries {
+			rel := path.Join(current, entry.ObjectName)
+			if entry.IsDirectory {
+				pending = append(pending, rel)
+				continue
+			}
+			out = append(out, lifecycleCandidate{path: rel, file: entry})
+		}
+	}
+	return out, nil
+}
+
+// selectExpired returns the files expired by rule, oldest first.
+func selectExpired(rule *LifecycleRule, files []lifecycleCandidate, now time.Time) []lifecycleCandidate {
+	groups := make(map[string][]lifecycleCandidate)
+	var order []string
+
+	for _, c := range files {
+		if !matchGlob(rule.Glob, c.path) {
+			continue
+		}
+		key, ok := versionKey(rule.VersionPattern, c.path)
+		if !ok {
+			continue
+		}
+		c.rule = rule
+		c.age = now.Sub(objectTime(rule.AgeFrom, c.file))
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], c)
+	}
+
+	var expired []lifecycleCandidate
+	for _, key := range order {
+		group := groups[key]
+		// Newest first, so the first KeepLast entries are the protected versions.
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].age < group[j].age
+		})
+		for i, c := range group {
+			if i < rule.KeepLast {
+				continue
+			}
+			if rule.MaxAge > 0 && c.age <= rule.MaxAge {
+				continue
+			}
+			expired = append(expired, c)
+		}
+	}
+
+	sort.SliceStable(expired, func(i, j int) bool {
+		return expired[i].age > expired[j].age
+	})
+	return expired
+}
+
+func matchGlob(glob, rel string) bool {
+	if glob == "" {
+		return true
+	}
+	target := path.Base(rel)
+	if strings.Contains(glob, "/") {
+		target = rel
+	}
+	ok, _ := path.Match(glob, target)
+	return ok
+}
+
+// versionKey returns the group an object belongs to. Objects that do not match
+// an explicit version pattern are not managed by the rule.
+func versionKey(pattern *regexp.Regexp, rel string) (string, bool) {
+	if pattern == nil {
+		return path.Dir(rel), true
+	}
+	m := pattern.FindStringSubmatch(rel)
+	if m == nil {
+		m = pattern.FindStringSubmatch(path.Base(rel))
+		if m == nil {
+			return "", false
+		}
+	}
+	if i := pattern.SubexpIndex("name"); i > 0 {
+		return path.Dir(rel) + "/" + m[i], true
+	}
+	if len(m) > 1 {
+		return path.Dir(rel) + "/" + m[1], true
+	}
+	return path.Dir(rel), true
+}
+
+func objectTime(source LifecycleAgeSource, f File) time.Time {
+	if source == AgeFromDateCreated && !f.DateCreated.IsZero() {
+		return f.DateCreated.Time
+	}
+	if !f.LastChanged.IsZero() {
+		return f.LastChanged.Time
+	}
+	return f.DateCreated.Time
+}
+
+// execute runs the expiration for every candidate with bounded concurrency.
+func (l *Lifecycle) execute(ctx context.Context, candidates []lifecycleCandidate) []LifecycleOperation {
+	ops := make([]LifecycleOperation, len(candidates))
+	sem := make(chan struct{}, l.concurrency)
+	var wg sync.WaitGroup
+
+	for i, c := range candidates {
+		action := c.rule.Action
+		if action == "" {
+			action = LifecycleActionDelete
+		}
+		ops[i] = LifecycleOperation{
+			Rule:   c.rule.Name,
+			Path:   c.path,
+			Action: action,
+			Size:   c.file.Length,
+			Age:    c.age,
+			DryRun: l.dryRun,
+		}
+
+		if l.dryRun {
+			l.logger.InfoContext(ctx, "lifecycle dry run", "rule", c.rule.Name, "path", c.path, "action", action, "age", c.age)
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			ops[i].Error = ctx.Err().Error()
+			continue
+		}
+
+		wg.Add(1)
+		go func(op *LifecycleOperation) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var err error
+			if op.Action == LifecycleActionArchive {
+				err = l.archiveObject(ctx, op.Path)
+			} else {
+				err = l.files.Delete(ctx, op.Path)
+			}
+			if err != nil {
+				op.Error = err.Error()
+				l.logger.ErrorContext(ctx, "lifecycle action failed", "rule", op.Rule, "path", op.Path, "action", op.Action, "error", err)
+				return
+			}
+			l.logger.InfoContext(ctx, "lifecycle action applied", "rule", op.Rule, "path", op.Path, "action", op.Action, "age", op.Age)
+		}(&ops[i])
+	}
+
+	wg.Wait()
+	return ops
+}
+
+// archiveObject copies an object into the archive zone and then deletes the original.
+func (l *Lifecycle) archiveObject(ctx context.Context, rel string) error {
+	body, err := l.files.Download(ctx, rel)
+	if err != nil {
+		return fmt.Errorf("archive download: %w", err)
+	}
+	defer body.Close()
+
+	if err := l.archive.Upload(ctx, path.Join(l.archivePrefix, rel), body, nil); err != nil {
+		return fmt.Errorf("archive upload: %w", err)
+	}
+	if err := l.files.Delete(ctx, rel); err != nil {
+		return fmt.Errorf("archive delete: %w", err)
+	}
+	return nil
+}
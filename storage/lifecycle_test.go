@@ -0,0 +1,310 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+// memoryFiles is an in-memory FileService keyed by zone-relative path.
+type memoryFiles struct {
+	mu        sync.Mutex
+	files     map[string]storage.File
+	data      map[string][]byte
+	deleted   []string
+	deleteErr map[string]error
+	lists     int
+}
+
+func newMemoryFiles() *memoryFiles {
+	return &memoryFiles{
+		files:     make(map[string]storage.File),
+		data:      make(map[string][]byte),
+		deleteErr: make(map[string]error),
+	}
+}
+
+func (m *memoryFiles) put(rel string, changed time.Time, content string) {
+	m.files[rel] = storage.File{
+		ObjectName:  path.Base(rel),
+		Length:      int64(len(content)),
+		LastChanged: internal.BunnyTime{Time: changed},
+		DateCreated: internal.BunnyTime{Time: changed},
+	}
+	m.data[rel] = []byte(content)
+}
+
+func (m *memoryFiles) Upload(ctx context.Context, p string, reader io.Reader, opts *storage.UploadOptions) error {
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(strings.Trim(p, "/"), time.Now(), string(b))
+	return nil
+}
+
+func (m *memoryFiles) Download(ctx context.Context, p string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.data[strings.Trim(p, "/")]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (m *memoryFiles) List(ctx context.Context, p string) ([]storage.File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lists++
+	dir := strings.Trim(p, "/")
+	dirs := make(map[string]bool)
+	var out []storage.File
+	for rel, f := range m.files {
+		parent := path.Dir(rel)
+		if parent == "." {
+			parent = ""
+		}
+		if parent == dir {
+			out = append(out, f)
+			continue
+		}
+		prefix := dir + "/"
+		if dir == "" {
+			prefix = ""
+		}
+		if strings.HasPrefix(rel, prefix) {
+			sub := strings.SplitN(strings.TrimPrefix(rel, prefix), "/", 2)[0]
+			if !dirs[sub] {
+				dirs[sub] = true
+				out = append(out, storage.File{ObjectName: sub, IsDirectory: true})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ObjectName < out[j].ObjectName })
+	return out, nil
+}
+
+func (m *memoryFiles) Delete(ctx context.Context, p string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rel := strings.Trim(p, "/")
+	if err := m.deleteErr[rel]; err != nil {
+		return err
+	}
+	delete(m.files, rel)
+	delete(m.data, rel)
+	m.deleted = append(m.deleted, rel)
+	return nil
+}
+
+func (m *memoryFiles) DeleteDirectory(ctx context.Context, p string) error {
+	return nil
+}
+
+func (m *memoryFiles) has(rel string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.files[rel]
+	return ok
+}
+
+var lifecycleNow = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) time.Time {
+	return lifecycleNow.AddDate(0, 0, -n)
+}
+
+func TestLifecycle_ApplyMaxAge(t *testing.T) {
+	files := newMemoryFiles()
+	files.put("logs/app/old.log", daysAgo(40), "old")
+	files.put("logs/app/new.log", daysAgo(5), "new")
+	files.put("logs/app/old.txt", daysAgo(40), "keep")
+	files.put("images/old.log", daysAgo(40), "outside prefix")
+
+	lc := storage.NewLifecycle(files, storage.WithLifecycleClock(func() time.Time { return lifecycleNow }))
+	result, err := lc.Apply(context.Background(), []storage.LifecycleRule{
+		{Name: "logs", Prefix: "logs", Glob: "*.log", MaxAge: 30 * 24 * time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Deleted != 1 || result.BytesFreed != 3 {
+		t.Errorf("expected 1 deletion freeing 3 bytes, got %+v", result)
+	}
+	if files.has("logs/app/old.log") {
+		t.Error("expected logs/app/old.log to be deleted")
+	}
+	for _, rel := range []string{"logs/app/new.log", "logs/app/old.txt", "images/old.log"} {
+		if !files.has(rel) {
+			t.Errorf("expected %s to be kept", rel)
+		}
+	}
+}
+
+func TestLifecycle_ApplyListsPrefixOnce(t *testing.T) {
+	files := newMemoryFiles()
+	files.put("logs/app/old.log", daysAgo(40), "old")
+	files.put("logs/app/old.tmp", daysAgo(40), "tmp")
+	files.put("logs/web/old.log", daysAgo(40), "web")
+	files.put("logs/web/new.log", daysAgo(2), "new")
+
+	lc := storage.NewLifecycle(files, storage.WithLifecycleClock(func() time.Time { return lifecycleNow }))
+	result, err := lc.Apply(context.Background(), []storage.LifecycleRule{
+		{Name: "logs", Prefix: "logs", Glob: "*.log", MaxAge: 30 * 24 * time.Hour},
+		{Name: "tmp", Prefix: "/logs/", Glob: "*.tmp", MaxAge: 24 * time.Hour},
+		{Name: "web", Prefix: "logs/web", MaxAge: 24 * time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// One walk of logs: logs, logs/app and logs/web.
+	if files.lists != 3 {
+		t.Errorf("expected the shared prefix to be walked once (3 listings), got %d", files.lists)
+	}
+	if result.Scanned != 4 || result.Deleted != 4 {
+		t.Errorf("expected 4 scanned and 4 deleted, got %+v", result)
+	}
+}
+
+func TestLifecycle_ApplyKeepLast(t *testing.T) {
+	files := newMemoryFiles()
+	files.put("backups/db-20261001.tar.gz", daysAgo(17), "a")
+	files.put("backups/db-20261010.tar.gz", daysAgo(8), "b")
+	files.put("backups/db-20261017.tar.gz", daysAgo(1), "c")
+	files.put("backups/web-20261001.tar.gz", daysAgo(17), "d")
+
+	lc := storage.NewLifecycle(files, storage.WithLifecycleClock(func() time.Time { return lifecycleNow }))
+	_, err := lc.Apply(context.Background(), []storage.LifecycleRule{
+		{
+			Name:           "backups",
+			Prefix:         "backups",
+			KeepLast:       1,
+			VersionPattern: regexp.MustCompile(`^(?P<name>[a-z]+)-\d{8}\.tar\.gz$`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if files.has("backups/db-20261001.tar.gz") || files.has("backups/db-20261010.tar.gz") {
+		t.Error("expected older db backups to be deleted")
+	}
+	if !files.has("backups/db-20261017.tar.gz") {
+		t.Error("expected newest db backup to be kept")
+	}
+	if !files.has("backups/web-20261001.tar.gz") {
+		t.Error("expected only web backup to be kept")
+	}
+}
+
+func TestLifecycle_ApplyDryRun(t *testing.T) {
+	files := newMemoryFiles()
+	files.put("tmp/a.bin", daysAgo(3), "data")
+
+	lc := storage.NewLifecycle(files,
+		storage.WithLifecycleDryRun(true),
+		storage.WithLifecycleClock(func() time.Time { return lifecycleNow }),
+	)
+	result, err := lc.Apply(context.Background(), []storage.LifecycleRule{
+		{Name: "tmp", Prefix: "tmp", MaxAge: 24 * time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Operations) != 1 || !result.Operations[0].DryRun {
+		t.Fatalf("expected one dry-run operation, got %+v", result.Operations)
+	}
+	if result.Deleted != 0 || !files.has("tmp/a.bin") {
+		t.Error("dry run must not delete objects")
+	}
+}
+
+func TestLifecycle_ApplyArchive(t *testing.T) {
+	files := newMemoryFiles()
+	files.put("reports/2025.csv", daysAgo(400), "year")
+	archive := newMemoryFiles()
+
+	lc := storage.NewLifecycle(files,
+		storage.WithLifecycleArchive(archive, "cold"),
+		storage.WithLifecycleConcurrency(2),
+		storage.WithLifecycleClock(func() time.Time { return lifecycleNow }),
+	)
+	result, err := lc.Apply(context.Background(), []storage.LifecycleRule{
+		{Name: "reports", Prefix: "reports", MaxAge: 365 * 24 * time.Hour, Action: storage.LifecycleActionArchive},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Archived != 1 {
+		t.Errorf("expected 1 archived object, got %d", result.Archived)
+	}
+	if files.has("reports/2025.csv") {
+		t.Error("expected source object to be deleted")
+	}
+	if !archive.has("cold/reports/2025.csv") {
+		t.Error("expected object in archive zone")
+	}
+}
+
+func TestLifecycle_ApplyFailure(t *testing.T) {
+	files := newMemoryFiles()
+	files.put("a.log", daysAgo(10), "a")
+	files.put("b.log", daysAgo(10), "b")
+	files.deleteErr["a.log"] = errors.New("boom")
+
+	lc := storage.NewLifecycle(files, storage.WithLifecycleClock(func() time.Time { return lifecycleNow }))
+	result, err := lc.Apply(context.Background(), []storage.LifecycleRule{
+		{Name: "all", MaxAge: time.Hour},
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected joined failure, got %v", err)
+	}
+	if result.Failed != 1 || result.Deleted != 1 {
+		t.Errorf("expected 1 failure and 1 deletion, got %+v", result)
+	}
+}
+
+func TestLifecycleRule_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule storage.LifecycleRule
+	}{
+		{"no criteria", storage.LifecycleRule{Name: "x"}},
+		{"bad glob", storage.LifecycleRule{Name: "x", MaxAge: time.Hour, Glob: "["}},
+		{"bad action", storage.LifecycleRule{Name: "x", MaxAge: time.Hour, Action: "shred"}},
+		{"bad age source", storage.LifecycleRule{Name: "x", MaxAge: time.Hour, AgeFrom: "mtime"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	lc := storage.NewLifecycle(newMemoryFiles())
+	_, err := lc.Apply(context.Background(), []storage.LifecycleRule{
+		{Name: "archive", MaxAge: time.Hour, Action: storage.LifecycleActionArchive},
+	})
+	if err == nil {
+		t.Error("expected error for archive rule without archive zone")
+	}
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/geraldo/bunny-sdk-go/credential"
+)
+
+// Kinds of storage zone keys reported to rotation hooks.
+const (
+	KindZonePassword         = "storage.zone.password"
+	KindZoneReadOnlyPassword = "storage.zone.readOnlyPassword"
+)
+
+// Rotator resets storage zone passwords without interrupting the file
+// services using them. File services created with WithFileCredential for a
+// watched secret switch to the new password as soon as it is known, and
+// requests rejected during the rotation are retried with it.
+type Rotator struct {
+	zones   ZoneService
+	rotator credential.Rotator
+}
+
+// NewRotator returns a Rotator that resets passwords through zones and calls
+// hooks after every rotation, for example to update a secret store.
+func NewRotator(zones ZoneService, hooks ...credential.Hook) *Rotator {
+	r := &Rotator{zones: zones}
+	for _, h := range hooks {
+		r.rotator.OnRotate(h)
+	}
+	return r
+}
+
+// OnRotate adds a hook called after every rotation.
+func (r *Rotator) OnRotate(h credential.Hook) {
+	r.rotator.OnRotate(h)
+}
+
+// Watch keeps s in sync with the password of a storage zone.
+func (r *Rotator) Watch(zoneID int64, s *credential.Secret) {
+	r.rotator.Watch(KindZonePassword, zoneID, s)
+}
+
+// WatchReadOnly keeps s in sync with the read-only password of a storage zone.
+func (r *Rotator) WatchReadOnly(zoneID int64, s *credential.Secret) {
+	r.rotator.Watch(KindZoneReadOnlyPassword, zoneID, s)
+}
+
+// RotatePassword resets the password of a storage zone and returns the new one.
+func (r *Rotator) RotatePassword(ctx context.Context, zoneID int64) (string, error) {
+	return r.rotator.Rotate(ctx, KindZonePassword, zoneID, func(ctx context.Context) (string, error) {
+		resp, err := r.zones.ResetPassword(ctx, zoneID)
+		if err != nil {
+			return "", err
+		}
+		if resp.Password != "" {
+			return resp.Password, nil
+		}
+		zone, err := r.zones.Get(ctx, zoneID)
+		if err != nil {
+			return "", fmt.Errorf("fetch new password: %w", err)
+		}
+		return zone.Password, nil
+	})
+}
+
+// RotateReadOnlyPassword resets the read-only password of a storage zone and
+// returns the new one.
+func (r *Rotator) RotateReadOnlyPassword(ctx context.Context, zoneID int64) (string, error) {
+	return r.rotator.Rotate(ctx, KindZoneReadOnlyPassword, zoneID, func(ctx context.Context) (string, error) {
+		resp, err := r.zones.ResetReadOnlyPassword(ctx, zoneID)
+		if err != nil {
+			return "", err
+		}
+		if resp.ReadOnlyPassword != "" {
+			return resp.ReadOnlyPassword, nil
+		}
+		zone, err := r.zones.Get(ctx, zoneID)
+		if err != nil {
+			return "", fmt.Errorf("fetch new read-only password: %w", err)
+		}
+		return zone.ReadOnlyPassword, nil
+	})
+}
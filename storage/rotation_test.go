@@ -0,0 +1,66 @@
+package storage_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/geraldo/bunny-sdk-go/bunnytest"
+	"github.com/geraldo/bunny-sdk-go/credential"
+	"github.com/geraldo/bunny-sdk-go/storage"
+)
+
+func TestRotatorSwapsFileServicePassword(t *testing.T) {
+	srv := bunnytest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := storage.NewClient("key", storage.WithBaseURL(srv.URL))
+	zone, err := client.Zones().Create(ctx, &storage.CreateZoneRequest{Name: "assets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	password := credential.New(zone.Password)
+	readOnly := credential.New(zone.ReadOnlyPassword)
+	files := storage.NewFileService(zone.Name, "", storage.RegionFalkenstein,
+		storage.WithFileBaseURL(srv.StorageURL()), storage.WithFileCredential(password))
+	reader := storage.NewFileService(zone.Name, "", storage.RegionFalkenstein,
+		storage.WithFileBaseURL(srv.StorageURL()), storage.WithFileCredential(readOnly))
+
+	vault := map[string]string{}
+	rotator := storage.NewRotator(client.Zones(), func(_ context.Context, r credential.Rotation) error {
+		vault[r.Kind] = r.Value
+		return nil
+	})
+	rotator.Watch(zone.ID, password)
+	rotator.WatchReadOnly(zone.ID, readOnly)
+
+	if err := files.Upload(ctx, "a.txt", strings.NewReader("a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	newPassword, err := rotator.RotatePassword(ctx, zone.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newPassword == zone.Password || password.Get() != newPassword || vault[storage.KindZonePassword] != newPassword {
+		t.Errorf("password = %q, secret = %q, vault = %v", newPassword, password.Get(), vault)
+	}
+	if err := files.Upload(ctx, "b.txt", strings.NewReader("b"), nil); err != nil {
+		t.Errorf("Upload after rotation: %v", err)
+	}
+
+	newReadOnly, err := rotator.RotateReadOnlyPassword(ctx, zone.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newReadOnly == zone.ReadOnlyPassword || readOnly.Get() != newReadOnly {
+		t.Errorf("read-only password = %q, secret = %q", newReadOnly, readOnly.Get())
+	}
+	list, err := reader.List(ctx, "/")
+	if err != nil {
+		t.Fatalf("List with rotated read-only password: %v", err)
+	}
+	if len(list) != 2 {
+		t.Errorf("listed %d files, want 2", len(list))
+	}
+}
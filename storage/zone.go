@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // httpClient is the internal interface for making API requests.
@@ -35,6 +37,7 @@ func newZoneService(client httpClient) ZoneService {
 
 // List returns a paginated list of storage zones.
 func (s *zoneService) List(ctx context.Context, opts *ZoneListOptions) (*ZoneListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.List")
 	path := "/storagezone"
 	if opts != nil {
 		path = path + "?" + buildZoneListQuery(opts)
@@ -49,6 +52,7 @@ func (s *zoneService) List(ctx context.Context, opts *ZoneListOptions) (*ZoneLis
 
 // Get returns a single storage zone by ID.
 func (s *zoneService) Get(ctx context.Context, zoneID int64) (*Zone, error) {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.Get")
 	path := fmt.Sprintf("/storagezone/%d", zoneID)
 
 	var zone Zone
@@ -60,6 +64,7 @@ func (s *zoneService) Get(ctx context.Context, zoneID int64) (*Zone, error) {
 
 // Create creates a new storage zone.
 func (s *zoneService) Create(ctx context.Context, req *CreateZoneRequest) (*Zone, error) {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.Create")
 	var zone Zone
 	if err := s.client.do(ctx, http.MethodPost, "/storagezone", req, &zone); err != nil {
 		return nil, err
@@ -69,6 +74,7 @@ func (s *zoneService) Create(ctx context.Context, req *CreateZoneRequest) (*Zone
 
 // Update updates a storage zone's settings.
 func (s *zoneService) Update(ctx context.Context, zoneID int64, req *UpdateZoneRequest) (*Zone, error) {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.Update")
 	path := fmt.Sprintf("/storagezone/%d", zoneID)
 
 	var zone Zone
@@ -80,12 +86,14 @@ func (s *zoneService) Update(ctx context.Context, zoneID int64, req *UpdateZoneR
 
 // Delete permanently deletes a storage zone.
 func (s *zoneService) Delete(ctx context.Context, zoneID int64) error {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.Delete")
 	path := fmt.Sprintf("/storagezone/%d", zoneID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // CheckAvailability checks if a storage zone name is available.
 func (s *zoneService) CheckAvailability(ctx context.Context, name string) (*AvailabilityResponse, error) {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.CheckAvailability")
 	path := fmt.Sprintf("/storagezone/checkavailability/%s", url.PathEscape(name))
 
 	var resp AvailabilityResponse
@@ -97,6 +105,7 @@ func (s *zoneService) CheckAvailability(ctx context.Context, name string) (*Avai
 
 // ResetPassword resets the main password for a storage zone.
 func (s *zoneService) ResetPassword(ctx context.Context, zoneID int64) (*ResetPasswordResponse, error) {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.ResetPassword")
 	path := fmt.Sprintf("/storagezone/%d/resetPassword", zoneID)
 
 	var resp ResetPasswordResponse
@@ -109,6 +118,7 @@ func (s *zoneService) ResetPassword(ctx context.Context, zoneID int64) (*ResetPa
 
 // ResetReadOnlyPassword resets the read-only password for a storage zone.
 func (s *zoneService) ResetReadOnlyPassword(ctx context.Context, zoneID int64) (*ResetReadOnlyPasswordResponse, error) {
+	ctx = middleware.WithOperation(ctx, "storage.ZoneService.ResetReadOnlyPassword")
 	path := fmt.Sprintf("/storagezone/%d/resetReadOnlyPassword", zoneID)
 
 	var resp ResetReadOnlyPasswordResponse
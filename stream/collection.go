@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // CollectionService provides methods for managing collections within a library.
@@ -41,6 +43,7 @@ func newCollectionService(client httpClient, libraryID int64) CollectionService
 
 // List returns a paginated list of collections in the library.
 func (s *collectionService) List(ctx context.Context, opts *CollectionListOptions) (*CollectionListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.CollectionService.List")
 	path := fmt.Sprintf("/library/%d/collections", s.libraryID)
 	if opts != nil {
 		path = path + "?" + buildCollectionListQuery(opts)
@@ -55,6 +58,7 @@ func (s *collectionService) List(ctx context.Context, opts *CollectionListOption
 
 // Get returns a single collection by ID.
 func (s *collectionService) Get(ctx context.Context, collectionID string) (*Collection, error) {
+	ctx = middleware.WithOperation(ctx, "stream.CollectionService.Get")
 	path := fmt.Sprintf("/library/%d/collections/%s", s.libraryID, collectionID)
 
 	var collection Collection
@@ -66,6 +70,7 @@ func (s *collectionService) Get(ctx context.Context, collectionID string) (*Coll
 
 // Create creates a new collection in the library.
 func (s *collectionService) Create(ctx context.Context, req *CreateCollectionRequest) (*Collection, error) {
+	ctx = middleware.WithOperation(ctx, "stream.CollectionService.Create")
 	path := fmt.Sprintf("/library/%d/collections", s.libraryID)
 
 	var collection Collection
@@ -77,6 +82,7 @@ func (s *collectionService) Create(ctx context.Context, req *CreateCollectionReq
 
 // Update updates a collection's name.
 func (s *collectionService) Update(ctx context.Context, collectionID string, req *UpdateCollectionRequest) (*Collection, error) {
+	ctx = middleware.WithOperation(ctx, "stream.CollectionService.Update")
 	path := fmt.Sprintf("/library/%d/collections/%s", s.libraryID, collectionID)
 
 	var collection Collection
@@ -88,6 +94,7 @@ func (s *collectionService) Update(ctx context.Context, collectionID string, req
 
 // Delete permanently deletes a collection.
 func (s *collectionService) Delete(ctx context.Context, collectionID string) error {
+	ctx = middleware.WithOperation(ctx, "stream.CollectionService.Delete")
 	path := fmt.Sprintf("/library/%d/collections/%s", s.libraryID, collectionID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
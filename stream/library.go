@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // LibraryService provides methods for managing video libraries.
@@ -46,6 +48,7 @@ func newLibraryService(client basicHTTPClient) LibraryService {
 
 // List returns a paginated list of video libraries.
 func (s *libraryService) List(ctx context.Context, opts *LibraryListOptions) (*LibraryListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.LibraryService.List")
 	path := "/videolibrary"
 	if opts != nil {
 		path = path + "?" + buildLibraryListQuery(opts)
@@ -60,6 +63,7 @@ func (s *libraryService) List(ctx context.Context, opts *LibraryListOptions) (*L
 
 // Get returns a single library by ID.
 func (s *libraryService) Get(ctx context.Context, libraryID int64) (*Library, error) {
+	ctx = middleware.WithOperation(ctx, "stream.LibraryService.Get")
 	path := fmt.Sprintf("/videolibrary/%d", libraryID)
 
 	var library Library
@@ -71,6 +75,7 @@ func (s *libraryService) Get(ctx context.Context, libraryID int64) (*Library, er
 
 // Create creates a new video library.
 func (s *libraryService) Create(ctx context.Context, req *CreateLibraryRequest) (*Library, error) {
+	ctx = middleware.WithOperation(ctx, "stream.LibraryService.Create")
 	var library Library
 	if err := s.client.do(ctx, http.MethodPost, "/videolibrary", req, &library); err != nil {
 		return nil, err
@@ -80,6 +85,7 @@ func (s *libraryService) Create(ctx context.Context, req *CreateLibraryRequest)
 
 // Update updates a library's settings.
 func (s *libraryService) Update(ctx context.Context, libraryID int64, req *UpdateLibraryRequest) (*Library, error) {
+	ctx = middleware.WithOperation(ctx, "stream.LibraryService.Update")
 	path := fmt.Sprintf("/videolibrary/%d", libraryID)
 
 	var library Library
@@ -91,12 +97,14 @@ func (s *libraryService) Update(ctx context.Context, libraryID int64, req *Updat
 
 // Delete permanently deletes a library and all its videos.
 func (s *libraryService) Delete(ctx context.Context, libraryID int64) error {
+	ctx = middleware.WithOperation(ctx, "stream.LibraryService.Delete")
 	path := fmt.Sprintf("/videolibrary/%d", libraryID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // GetStatistics returns statistics for a library.
 func (s *libraryService) GetStatistics(ctx context.Context, libraryID int64, opts *StatisticsOptions) (*LibraryStatistics, error) {
+	ctx = middleware.WithOperation(ctx, "stream.LibraryService.GetStatistics")
 	path := fmt.Sprintf("/videolibrary/%d/statistics", libraryID)
 	if opts != nil {
 		path = path + "?" + buildStatisticsQuery(opts)
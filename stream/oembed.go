@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // OEmbedService provides methods for oEmbed video embedding.
@@ -22,6 +24,7 @@ func newOEmbedService(client httpClient) OEmbedService {
 
 // Get retrieves oEmbed data for a video.
 func (s *oembedService) Get(ctx context.Context, opts *OEmbedOptions) (*OEmbedResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.OEmbedService.Get")
 	path := "/OEmbed"
 	if opts != nil {
 		path = path + "?" + buildOEmbedQuery(opts)
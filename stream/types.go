@@ -87,6 +87,8 @@ type Library struct {
 	Collections              int       `json:"collections,omitempty"`
 	Region                   string    `json:"region,omitempty"`
 	ReplicationRegions       []string  `json:"replicationRegions,omitempty"`
+	APIKey                   string    `json:"apiKey,omitempty"`         // key for video and collection operations
+	ReadOnlyAPIKey           string    `json:"readOnlyApiKey,omitempty"` // read-only variant of APIKey
 }
 
 // Collection represents a collection of videos within a library.
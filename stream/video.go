@@ -11,6 +11,7 @@ import (
 	"strconv"
 
 	"github.com/geraldo/bunny-sdk-go/internal"
+	"github.com/geraldo/bunny-sdk-go/middleware"
 )
 
 // VideoService provides methods for managing videos in a library.
@@ -68,6 +69,7 @@ func newVideoService(client httpClient, libraryID int64) VideoService {
 
 // List returns a paginated list of videos in the library.
 func (s *videoService) List(ctx context.Context, opts *VideoListOptions) (*VideoListResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.List")
 	path := fmt.Sprintf("/library/%d/videos", s.libraryID)
 	if opts != nil {
 		path = path + "?" + buildVideoListQuery(opts)
@@ -82,6 +84,7 @@ func (s *videoService) List(ctx context.Context, opts *VideoListOptions) (*Video
 
 // Get returns a single video by ID.
 func (s *videoService) Get(ctx context.Context, videoID string) (*Video, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Get")
 	path := fmt.Sprintf("/library/%d/videos/%s", s.libraryID, videoID)
 
 	var video Video
@@ -93,6 +96,7 @@ func (s *videoService) Get(ctx context.Context, videoID string) (*Video, error)
 
 // Create creates a new video entry (before uploading the actual video file).
 func (s *videoService) Create(ctx context.Context, req *CreateVideoRequest) (*Video, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Create")
 	path := fmt.Sprintf("/library/%d/videos", s.libraryID)
 
 	var video Video
@@ -104,6 +108,7 @@ func (s *videoService) Create(ctx context.Context, req *CreateVideoRequest) (*Vi
 
 // Update updates video metadata.
 func (s *videoService) Update(ctx context.Context, videoID string, req *UpdateVideoRequest) (*Video, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Update")
 	path := fmt.Sprintf("/library/%d/videos/%s", s.libraryID, videoID)
 
 	var video Video
@@ -115,18 +120,21 @@ func (s *videoService) Update(ctx context.Context, videoID string, req *UpdateVi
 
 // Delete permanently deletes a video.
 func (s *videoService) Delete(ctx context.Context, videoID string) error {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Delete")
 	path := fmt.Sprintf("/library/%d/videos/%s", s.libraryID, videoID)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // Upload uploads a video file to an existing video entry.
 func (s *videoService) Upload(ctx context.Context, videoID string, reader io.Reader) error {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Upload")
 	path := fmt.Sprintf("/library/%d/videos/%s", s.libraryID, videoID)
 	return s.client.doRaw(ctx, http.MethodPut, path, reader, "application/octet-stream")
 }
 
 // FetchFromURL fetches a video from a remote URL.
 func (s *videoService) FetchFromURL(ctx context.Context, req *FetchVideoRequest) (*FetchVideoResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.FetchFromURL")
 	path := fmt.Sprintf("/library/%d/videos/fetch", s.libraryID)
 
 	var resp FetchVideoResponse
@@ -138,24 +146,28 @@ func (s *videoService) FetchFromURL(ctx context.Context, req *FetchVideoRequest)
 
 // Reencode triggers re-encoding of a video with optional resolution settings.
 func (s *videoService) Reencode(ctx context.Context, videoID string, req *ReencodeRequest) error {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Reencode")
 	path := fmt.Sprintf("/library/%d/videos/%s/reencode", s.libraryID, videoID)
 	return s.client.do(ctx, http.MethodPost, path, req, nil)
 }
 
 // AddCaption adds a caption track to a video.
 func (s *videoService) AddCaption(ctx context.Context, videoID string, req *AddCaptionRequest) error {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.AddCaption")
 	path := fmt.Sprintf("/library/%d/videos/%s/captions", s.libraryID, videoID)
 	return s.client.do(ctx, http.MethodPost, path, req, nil)
 }
 
 // DeleteCaption removes a caption track from a video.
 func (s *videoService) DeleteCaption(ctx context.Context, videoID, srclang string) error {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.DeleteCaption")
 	path := fmt.Sprintf("/library/%d/videos/%s/captions/%s", s.libraryID, videoID, srclang)
 	return s.client.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
 // SetThumbnail sets the video thumbnail from a specific timestamp.
 func (s *videoService) SetThumbnail(ctx context.Context, videoID string, req *SetThumbnailRequest) (*SetThumbnailResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.SetThumbnail")
 	path := fmt.Sprintf("/library/%d/videos/%s/thumbnail", s.libraryID, videoID)
 
 	var resp SetThumbnailResponse
@@ -167,6 +179,7 @@ func (s *videoService) SetThumbnail(ctx context.Context, videoID string, req *Se
 
 // GetHeatmap returns engagement heatmap data for a video.
 func (s *videoService) GetHeatmap(ctx context.Context, videoID string) (*HeatmapData, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.GetHeatmap")
 	path := fmt.Sprintf("/library/%d/videos/%s/heatmap", s.libraryID, videoID)
 
 	var resp HeatmapData
@@ -178,6 +191,7 @@ func (s *videoService) GetHeatmap(ctx context.Context, videoID string) (*Heatmap
 
 // GetStatistics returns statistics for a video.
 func (s *videoService) GetStatistics(ctx context.Context, videoID string, opts *StatisticsOptions) (*VideoStatistics, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.GetStatistics")
 	path := fmt.Sprintf("/library/%d/videos/%s/statistics", s.libraryID, videoID)
 	if opts != nil {
 		path = path + "?" + buildStatisticsQuery(opts)
@@ -192,6 +206,7 @@ func (s *videoService) GetStatistics(ctx context.Context, videoID string, opts *
 
 // GetPlaybackInfo returns playback URLs for a video.
 func (s *videoService) GetPlaybackInfo(ctx context.Context, videoID string) (*PlaybackInfo, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.GetPlaybackInfo")
 	path := fmt.Sprintf("/library/%d/videos/%s/play", s.libraryID, videoID)
 
 	var resp PlaybackInfo
@@ -203,6 +218,7 @@ func (s *videoService) GetPlaybackInfo(ctx context.Context, videoID string) (*Pl
 
 // AddOutputCodec adds an output codec to a video.
 func (s *videoService) AddOutputCodec(ctx context.Context, videoID string, codec OutputCodec) (*Video, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.AddOutputCodec")
 	path := fmt.Sprintf("/library/%d/videos/%s/outputs/%d", s.libraryID, videoID, codec)
 
 	var video Video
@@ -214,6 +230,7 @@ func (s *videoService) AddOutputCodec(ctx context.Context, videoID string, codec
 
 // CleanupUnconfiguredResolutions cleans up unconfigured video resolutions.
 func (s *videoService) CleanupUnconfiguredResolutions(ctx context.Context, videoID string, opts *CleanupResolutionsOptions) (*StatusResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.CleanupUnconfiguredResolutions")
 	path := fmt.Sprintf("/library/%d/videos/%s/resolutions/cleanup", s.libraryID, videoID)
 	if opts != nil {
 		path = path + "?" + buildCleanupResolutionsQuery(opts)
@@ -228,6 +245,7 @@ func (s *videoService) CleanupUnconfiguredResolutions(ctx context.Context, video
 
 // GetHeatmapData returns video playback data with heatmap.
 func (s *videoService) GetHeatmapData(ctx context.Context, videoID string, opts *HeatmapDataOptions) (*VideoPlayData, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.GetHeatmapData")
 	path := fmt.Sprintf("/library/%d/videos/%s/play/heatmap", s.libraryID, videoID)
 	if opts != nil {
 		path = path + "?" + buildHeatmapDataQuery(opts)
@@ -242,6 +260,7 @@ func (s *videoService) GetHeatmapData(ctx context.Context, videoID string, opts
 
 // GetStorageSizeInfo returns storage size breakdown for a video.
 func (s *videoService) GetStorageSizeInfo(ctx context.Context, videoID string) (*StorageSizeResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.GetStorageSizeInfo")
 	path := fmt.Sprintf("/library/%d/videos/%s/storage", s.libraryID, videoID)
 
 	var resp StorageSizeResponse
@@ -253,6 +272,7 @@ func (s *videoService) GetStorageSizeInfo(ctx context.Context, videoID string) (
 
 // Repackage repackages a video.
 func (s *videoService) Repackage(ctx context.Context, videoID string, opts *RepackageOptions) (*Video, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Repackage")
 	path := fmt.Sprintf("/library/%d/videos/%s/repackage", s.libraryID, videoID)
 	if opts != nil {
 		path = path + "?" + buildRepackageQuery(opts)
@@ -267,6 +287,7 @@ func (s *videoService) Repackage(ctx context.Context, videoID string, opts *Repa
 
 // Transcribe triggers video transcription.
 func (s *videoService) Transcribe(ctx context.Context, videoID string, req *TranscribeRequest, opts *TranscribeOptions) (*StatusResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.Transcribe")
 	path := fmt.Sprintf("/library/%d/videos/%s/transcribe", s.libraryID, videoID)
 	if opts != nil && opts.Force {
 		path = path + "?force=true"
@@ -281,6 +302,7 @@ func (s *videoService) Transcribe(ctx context.Context, videoID string, req *Tran
 
 // TriggerSmartActions triggers smart actions on a video.
 func (s *videoService) TriggerSmartActions(ctx context.Context, videoID string, req *SmartActionsRequest) (*StatusResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.TriggerSmartActions")
 	path := fmt.Sprintf("/library/%d/videos/%s/smart", s.libraryID, videoID)
 
 	var resp StatusResponse
@@ -292,6 +314,7 @@ func (s *videoService) TriggerSmartActions(ctx context.Context, videoID string,
 
 // GetResolutionsInfo returns resolution information for a video.
 func (s *videoService) GetResolutionsInfo(ctx context.Context, videoID string) (*ResolutionsInfoResponse, error) {
+	ctx = middleware.WithOperation(ctx, "stream.VideoService.GetResolutionsInfo")
 	path := fmt.Sprintf("/library/%d/videos/%s/resolutions", s.libraryID, videoID)
 
 	var resp ResolutionsInfoResponse
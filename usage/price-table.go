@@ -0,0 +1,51 @@
+package usage
+
+import "strings"
+
+// bytesPerGB is the divisor used to convert bytes to billable gigabytes.
+const bytesPerGB = 1 << 30
+
+// PriceTable holds monthly per-GB storage prices keyed by lowercase region code.
+// Regions missing from a map fall back to the matching default price.
+type PriceTable struct {
+	Currency            string
+	StoragePerGB        map[string]float64
+	DefaultStoragePerGB float64
+	StreamPerGB         map[string]float64
+	DefaultStreamPerGB  float64
+}
+
+// DefaultPriceTable returns a flat price table approximating Bunny.net list prices.
+// Override it with WithPriceTable when the report must match an invoice.
+func DefaultPriceTable() PriceTable {
+	return PriceTable{
+		Currency:            "USD",
+		DefaultStoragePerGB: 0.01,
+		DefaultStreamPerGB:  0.01,
+	}
+}
+
+// storagePrice returns the per-GB price of a storage zone replica in the given region.
+func (p PriceTable) storagePrice(region string) float64 {
+	if price, ok := p.StoragePerGB[normalizeRegion(region)]; ok {
+		return price
+	}
+	return p.DefaultStoragePerGB
+}
+
+// streamPrice returns the per-GB price of a stream library replica in the given region.
+func (p PriceTable) streamPrice(region string) float64 {
+	if price, ok := p.StreamPerGB[normalizeRegion(region)]; ok {
+		return price
+	}
+	return p.DefaultStreamPerGB
+}
+
+// cost returns the monthly cost of storing the given number of bytes at a per-GB price.
+func cost(bytes int64, pricePerGB float64) float64 {
+	return float64(bytes) / bytesPerGB * pricePerGB
+}
+
+func normalizeRegion(region string) string {
+	return strings.ToLower(strings.TrimSpace(region))
+}
@@ -0,0 +1,339 @@
+package usage
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+)
+
+const (
+	defaultTopN     = 10
+	defaultPageSize = 1000
+	unknownRegion   = "unknown"
+)
+
+// VideoServiceFunc returns a VideoService scoped to a stream library.
+// It is used to fetch per-video storage breakdowns, which require the library API key.
+type VideoServiceFunc func(libraryID int64) stream.VideoService
+
+// Reporter walks storage zones and stream libraries and produces usage reports.
+type Reporter struct {
+	zones          storage.ZoneService
+	libraries      stream.LibraryService
+	videos         VideoServiceFunc
+	prices         PriceTable
+	topN           int
+	includeDeleted bool
+	now            func() time.Time
+}
+
+// Option is a functional option for configuring the Reporter.
+type Option func(*Reporter)
+
+// WithPriceTable sets the price table used to compute costs.
+func WithPriceTable(prices PriceTable) Option {
+	return func(r *Reporter) {
+		r.prices = prices
+	}
+}
+
+// WithTopN sets how many resources are listed as largest consumers.
+func WithTopN(n int) Option {
+	return func(r *Reporter) {
+		r.topN = n
+	}
+}
+
+// WithDeletedZones includes deleted storage zones that still report usage.
+func WithDeletedZones() Option {
+	return func(r *Reporter) {
+		r.includeDeleted = true
+	}
+}
+
+// WithVideoBreakdown enables a per-resolution breakdown for every stream library.
+// This issues one GetStorageSizeInfo call per video, so it is slow on large libraries.
+func WithVideoBreakdown(videos VideoServiceFunc) Option {
+	return func(r *Reporter) {
+		r.videos = videos
+	}
+}
+
+// WithClock sets the function used to timestamp reports.
+func WithClock(now func() time.Time) Option {
+	return func(r *Reporter) {
+		r.now = now
+	}
+}
+
+// NewReporter creates a new Reporter.
+// Either service may be nil to leave that resource kind out of the report.
+func NewReporter(zones storage.ZoneService, libraries stream.LibraryService, opts ...Option) *Reporter {
+	r := &Reporter{
+		zones:     zones,
+		libraries: libraries,
+		prices:    DefaultPriceTable(),
+		topN:      defaultTopN,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Generate walks every storage zone and stream library and builds a usage report.
+func (r *Reporter) Generate(ctx context.Context) (*Report, error) {
+	var resources []ResourceUsage
+
+	if r.zones != nil {
+		zones, err := r.listZones(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list storage zones: %w", err)
+		}
+		for _, zone := range zones {
+			if zone.Deleted && !r.includeDeleted {
+				continue
+			}
+			resources = append(resources, r.zoneUsage(zone))
+		}
+	}
+
+	if r.libraries != nil {
+		libraries, err := r.listLibraries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list stream libraries: %w", err)
+		}
+		for _, library := range libraries {
+			usage := r.libraryUsage(library)
+			if r.videos != nil {
+				breakdown, err := r.libraryBreakdown(ctx, library.LibraryID)
+				if err != nil {
+					return nil, fmt.Errorf("stream library %d breakdown: %w", library.LibraryID, err)
+				}
+				usage.Breakdown = breakdown
+			}
+			resources = append(resources, usage)
+		}
+	}
+
+	return r.buildReport(resources), nil
+}
+
+func (r *Reporter) listZones(ctx context.Context) ([]storage.Zone, error) {
+	var zones []storage.Zone
+	for page := 1; ; page++ {
+		resp, err := r.zones.List(ctx, &storage.ZoneListOptions{
+			Page:           page,
+			PerPage:        defaultPageSize,
+			IncludeDeleted: r.includeDeleted,
+		})
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMore() || len(zones) >= resp.TotalItems {
+			return zones, nil
+		}
+	}
+}
+
+func (r *Reporter) listLibraries(ctx context.Context) ([]stream.Library, error) {
+	var libraries []stream.Library
+	for page := 1; ; page++ {
+		resp, err := r.libraries.List(ctx, &stream.LibraryListOptions{
+			Page:         page,
+			ItemsPerPage: defaultPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		libraries = append(libraries, resp.Items...)
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			return libraries, nil
+		}
+	}
+}
+
+func (r *Reporter) libraryBreakdown(ctx context.Context, libraryID int64) (map[string]int64, error) {
+	videos := r.videos(libraryID)
+	breakdown := make(map[string]int64)
+
+	for page := 1; ; page++ {
+		resp, err := videos.List(ctx, &stream.VideoListOptions{Page: page, ItemsPerPage: defaultPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, video := range resp.Items {
+			info, err := videos.GetStorageSizeInfo(ctx, video.VideoID)
+			if err != nil {
+				return nil, fmt.Errorf("video %s: %w", video.VideoID, err)
+			}
+			addStorageSize(breakdown, info.Data)
+		}
+		if len(resp.Items) == 0 || !resp.HasMore() {
+			return breakdown, nil
+		}
+	}
+}
+
+// addStorageSize accumulates a video's storage breakdown into the library totals.
+func addStorageSize(breakdown map[string]int64, data *stream.StorageSizeData) {
+	if data == nil {
+		return
+	}
+	for _, encoded := range data.Encoded {
+		key := encoded.Resolution
+		if encoded.Codec != "" {
+			key = encoded.Codec + "/" + encoded.Resolution
+		}
+		breakdown[key] += encoded.Size
+	}
+	breakdown["thumbnails"] += data.Thumbnails
+	breakdown["previews"] += data.Previews
+	breakdown["originals"] += data.Originals
+	breakdown["mp4Fallback"] += data.Mp4Fallback
+	breakdown["miscellaneous"] += data.Miscellaneous
+}
+
+func (r *Reporter) zoneUsage(zone storage.Zone) ResourceUsage {
+	regions := replicaRegions(zone.Region, zone.ReplicationRegions)
+	var total float64
+	for _, region := range regions {
+		total += cost(zone.StorageUsed, r.prices.storagePrice(region))
+	}
+	return ResourceUsage{
+		Kind:               ResourceStorageZone,
+		ID:                 zone.ID,
+		Name:               zone.Name,
+		Region:             regions[0],
+		ReplicationRegions: regions[1:],
+		Bytes:              zone.StorageUsed,
+		Files:              zone.FilesStored,
+		Replicas:           len(regions),
+		BilledBytes:        zone.StorageUsed * int64(len(regions)),
+		Cost:               total,
+	}
+}
+
+func (r *Reporter) libraryUsage(library stream.Library) ResourceUsage {
+	regions := replicaRegions(library.Region, library.ReplicationRegions)
+	var total float64
+	for _, region := range regions {
+		total += cost(library.StorageUsed, r.prices.streamPrice(region))
+	}
+	return ResourceUsage{
+		Kind:               ResourceStreamLibrary,
+		ID:                 library.LibraryID,
+		Name:               library.Name,
+		Region:             regions[0],
+		ReplicationRegions: regions[1:],
+		Bytes:              library.StorageUsed,
+		Replicas:           len(regions),
+		BilledBytes:        library.StorageUsed * int64(len(regions)),
+		Cost:               total,
+	}
+}
+
+// replicaRegions returns the normalized primary region followed by each distinct replication region.
+func replicaRegions(primary string, replication []string) []string {
+	primary = normalizeRegion(primary)
+	if primary == "" {
+		primary = unknownRegion
+	}
+	regions := []string{primary}
+	seen := map[string]bool{primary: true}
+	for _, region := range replication {
+		region = normalizeRegion(region)
+		if region == "" || seen[region] {
+			continue
+		}
+		seen[region] = true
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+func (r *Reporter) buildReport(resources []ResourceUsage) *Report {
+	report := &Report{
+		GeneratedAt: r.now().UTC(),
+		Currency:    r.prices.Currency,
+		Resources:   resources,
+	}
+
+	byRegion := make(map[string]*RegionUsage)
+	for _, res := range resources {
+		report.TotalBytes += res.Bytes
+		report.TotalBilledBytes += res.BilledBytes
+		report.TotalCost += res.Cost
+
+		for _, region := range append([]string{res.Region}, res.ReplicationRegions...) {
+			ru, ok := byRegion[region]
+			if !ok {
+				ru = &RegionUsage{Region: region}
+				byRegion[region] = ru
+			}
+			ru.TotalBytes += res.Bytes
+			if res.Kind == ResourceStorageZone {
+				ru.StorageZoneBytes += res.Bytes
+				ru.Cost += cost(res.Bytes, r.prices.storagePrice(region))
+			} else {
+				ru.StreamLibraryBytes += res.Bytes
+				ru.Cost += cost(res.Bytes, r.prices.streamPrice(region))
+			}
+		}
+	}
+
+	for _, ru := range byRegion {
+		report.Regions = append(report.Regions, *ru)
+	}
+	sort.Slice(report.Regions, func(i, j int) bool {
+		return report.Regions[i].Region < report.Regions[j].Region
+	})
+
+	largest := make([]ResourceUsage, len(resources))
+	copy(largest, resources)
+	sort.SliceStable(largest, func(i, j int) bool {
+		return largest[i].BilledBytes > largest[j].BilledBytes
+	})
+	if r.topN >= 0 && len(largest) > r.topN {
+		largest = largest[:r.topN]
+	}
+	report.LargestConsumers = largest
+
+	return report
+}
+
+// WriteCSV writes one row per resource, suitable for spreadsheet import.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"kind", "id", "name", "region", "replicas", "bytes", "billed_bytes", "files", "cost_" + r.Currency}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, res := range r.Resources {
+		row := []string{
+			string(res.Kind),
+			strconv.FormatInt(res.ID, 10),
+			res.Name,
+			res.Region,
+			strconv.Itoa(res.Replicas),
+			strconv.FormatInt(res.Bytes, 10),
+			strconv.FormatInt(res.BilledBytes, 10),
+			strconv.FormatInt(res.Files, 10),
+			strconv.FormatFloat(res.Cost, 'f', 4, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
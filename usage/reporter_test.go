@@ -0,0 +1,206 @@
+package usage_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geraldo/bunny-sdk-go/internal/testutil"
+	"github.com/geraldo/bunny-sdk-go/storage"
+	"github.com/geraldo/bunny-sdk-go/stream"
+	"github.com/geraldo/bunny-sdk-go/usage"
+)
+
+const gb = 1 << 30
+
+func newZoneService(t *testing.T, body string) storage.ZoneService {
+	t.Helper()
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/storagezone" {
+				t.Errorf("unexpected path %s", req.URL.Path)
+			}
+			return testutil.NewMockResponse(200, body), nil
+		},
+	}
+	return storage.NewClient("key", storage.WithHTTPClient(mock)).Zones()
+}
+
+func newLibraryService(t *testing.T, body string) stream.LibraryService {
+	t.Helper()
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/videolibrary" {
+				t.Errorf("unexpected path %s", req.URL.Path)
+			}
+			return testutil.NewMockResponse(200, body), nil
+		},
+	}
+	return stream.NewClient("key", stream.WithHTTPClient(mock)).Libraries()
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestReporter_Generate(t *testing.T) {
+	zones := newZoneService(t, `{"Items":[
+		{"Id":1,"Name":"assets","Region":"DE","ReplicationRegions":["NY","LA"],"StorageUsed":2147483648,"FilesStored":10},
+		{"Id":2,"Name":"backups","Region":"NY","StorageUsed":5368709120,"FilesStored":3},
+		{"Id":3,"Name":"gone","Region":"DE","StorageUsed":1073741824,"Deleted":true}
+	],"TotalItems":3,"CurrentPage":0,"PageSize":1000}`)
+	libraries := newLibraryService(t, `{"items":[
+		{"libraryId":9,"name":"videos","storageUsed":1073741824,"region":"DE","replicationRegions":["SG"]}
+	],"totalItems":1,"currentPage":1,"itemsPerPage":1000}`)
+
+	prices := usage.PriceTable{
+		Currency:            "EUR",
+		StoragePerGB:        map[string]float64{"ny": 0.02},
+		DefaultStoragePerGB: 0.01,
+		DefaultStreamPerGB:  0.005,
+	}
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := usage.NewReporter(zones, libraries,
+		usage.WithPriceTable(prices),
+		usage.WithTopN(2),
+		usage.WithClock(func() time.Time { return now }),
+	).Generate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Resources) != 3 {
+		t.Fatalf("expected 3 resources (deleted zone skipped), got %d", len(report.Resources))
+	}
+	if !report.GeneratedAt.Equal(now) {
+		t.Errorf("expected generatedAt %v, got %v", now, report.GeneratedAt)
+	}
+	if report.TotalBytes != 8*gb {
+		t.Errorf("expected 8GB logical, got %d", report.TotalBytes)
+	}
+	// assets: 2GB x 3 replicas, backups: 5GB x 1, videos: 1GB x 2
+	if report.TotalBilledBytes != 13*gb {
+		t.Errorf("expected 13GB billed, got %d", report.TotalBilledBytes)
+	}
+
+	assets := report.Resources[0]
+	if assets.Replicas != 3 || assets.Region != "de" {
+		t.Errorf("unexpected assets usage: %+v", assets)
+	}
+	// de 2GB@0.01 + ny 2GB@0.02 + la 2GB@0.01
+	if !almostEqual(assets.Cost, 0.08) {
+		t.Errorf("expected assets cost 0.08, got %f", assets.Cost)
+	}
+	// 0.08 + backups 5GB@0.02 + videos 2GB@0.005
+	if !almostEqual(report.TotalCost, 0.19) {
+		t.Errorf("expected total cost 0.19, got %f", report.TotalCost)
+	}
+
+	if len(report.Regions) != 4 {
+		t.Fatalf("expected 4 regions, got %d", len(report.Regions))
+	}
+	for _, region := range report.Regions {
+		if region.Region == "ny" {
+			if region.StorageZoneBytes != 7*gb {
+				t.Errorf("expected 7GB in ny, got %d", region.StorageZoneBytes)
+			}
+			if !almostEqual(region.Cost, 0.14) {
+				t.Errorf("expected ny cost 0.14, got %f", region.Cost)
+			}
+		}
+		if region.Region == "de" && region.StreamLibraryBytes != gb {
+			t.Errorf("expected 1GB stream in de, got %d", region.StreamLibraryBytes)
+		}
+	}
+
+	if len(report.LargestConsumers) != 2 {
+		t.Fatalf("expected 2 largest consumers, got %d", len(report.LargestConsumers))
+	}
+	if report.LargestConsumers[0].Name != "assets" || report.LargestConsumers[1].Name != "backups" {
+		t.Errorf("unexpected largest consumers order: %s, %s",
+			report.LargestConsumers[0].Name, report.LargestConsumers[1].Name)
+	}
+}
+
+func TestReporter_Generate_VideoBreakdown(t *testing.T) {
+	libraries := newLibraryService(t, `{"items":[{"libraryId":9,"name":"videos","storageUsed":300}],"totalItems":1,"currentPage":1,"itemsPerPage":1000}`)
+
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			switch {
+			case req.URL.Path == "/library/9/videos":
+				return testutil.NewMockResponse(200, `{"items":[{"videoId":"a"},{"videoId":"b"}],"totalItems":2,"currentPage":1,"itemsPerPage":1000}`), nil
+			case strings.HasSuffix(req.URL.Path, "/storage"):
+				return testutil.NewMockResponse(200, `{"success":true,"data":{"encoded":[{"codec":"x264","resolution":"720p","size":100}],"originals":50}}`), nil
+			}
+			t.Errorf("unexpected path %s", req.URL.Path)
+			return testutil.NewMockResponse(404, ""), nil
+		},
+	}
+	videos := stream.NewClient("library-key", stream.WithHTTPClient(mock))
+
+	report, err := usage.NewReporter(nil, libraries,
+		usage.WithVideoBreakdown(videos.Videos),
+	).Generate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	breakdown := report.Resources[0].Breakdown
+	if breakdown["x264/720p"] != 200 {
+		t.Errorf("expected 200 bytes for x264/720p, got %d", breakdown["x264/720p"])
+	}
+	if breakdown["originals"] != 100 {
+		t.Errorf("expected 100 bytes for originals, got %d", breakdown["originals"])
+	}
+	if report.Resources[0].Region != "unknown" {
+		t.Errorf("expected unknown region, got %s", report.Resources[0].Region)
+	}
+}
+
+func TestReporter_Generate_Error(t *testing.T) {
+	mock := &testutil.MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return testutil.NewMockResponse(401, `{"Message":"unauthorized"}`), nil
+		},
+	}
+	zones := storage.NewClient("bad", storage.WithHTTPClient(mock)).Zones()
+
+	_, err := usage.NewReporter(zones, nil).Generate(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "list storage zones") {
+		t.Errorf("expected wrapped error, got %v", err)
+	}
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	report := &usage.Report{
+		Currency: "USD",
+		Resources: []usage.ResourceUsage{
+			{Kind: usage.ResourceStorageZone, ID: 1, Name: "assets", Region: "de", Replicas: 2, Bytes: 10, BilledBytes: 20, Files: 4, Cost: 1.5},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if lines[0] != "kind,id,name,region,replicas,bytes,billed_bytes,files,cost_USD" {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if lines[1] != "storage_zone,1,assets,de,2,10,20,4,1.5000" {
+		t.Errorf("unexpected row: %s", lines[1])
+	}
+}
@@ -0,0 +1,48 @@
+// Package usage builds storage usage and cost reports across Bunny.net storage zones and stream libraries.
+package usage
+
+import "time"
+
+// ResourceKind identifies the kind of resource a usage entry belongs to.
+type ResourceKind string
+
+const (
+	ResourceStorageZone   ResourceKind = "storage_zone"
+	ResourceStreamLibrary ResourceKind = "stream_library"
+)
+
+// ResourceUsage represents the storage consumed by a single storage zone or stream library.
+type ResourceUsage struct {
+	Kind               ResourceKind     `json:"kind"`
+	ID                 int64            `json:"id"`
+	Name               string           `json:"name"`
+	Region             string           `json:"region"`
+	ReplicationRegions []string         `json:"replicationRegions,omitempty"`
+	Bytes              int64            `json:"bytes"`               // logical size, stored once
+	Files              int64            `json:"files,omitempty"`     // storage zones only
+	Replicas           int              `json:"replicas"`            // primary region plus replication regions
+	BilledBytes        int64            `json:"billedBytes"`         // Bytes multiplied by Replicas
+	Cost               float64          `json:"cost"`                // monthly cost across all regions
+	Breakdown          map[string]int64 `json:"breakdown,omitempty"` // stream libraries only, per resolution/asset type
+}
+
+// RegionUsage represents the storage billed in a single region.
+type RegionUsage struct {
+	Region             string  `json:"region"`
+	StorageZoneBytes   int64   `json:"storageZoneBytes"`
+	StreamLibraryBytes int64   `json:"streamLibraryBytes"`
+	TotalBytes         int64   `json:"totalBytes"`
+	Cost               float64 `json:"cost"`
+}
+
+// Report is a point-in-time usage and cost report for an account.
+type Report struct {
+	GeneratedAt      time.Time       `json:"generatedAt"`
+	Currency         string          `json:"currency"`
+	TotalBytes       int64           `json:"totalBytes"`
+	TotalBilledBytes int64           `json:"totalBilledBytes"`
+	TotalCost        float64         `json:"totalCost"`
+	Regions          []RegionUsage   `json:"regions"`
+	Resources        []ResourceUsage `json:"resources"`
+	LargestConsumers []ResourceUsage `json:"largestConsumers"`
+}